@@ -1,25 +1,39 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	"app/config"
-	"app/docs"
 	"app/internal"
+	"app/internal/apikeys"
 	"app/internal/auth"
+	"app/internal/buildinfo"
 	"app/internal/cache"
+	"app/internal/cities"
 	"app/internal/db"
+	"app/internal/debug"
 	"app/internal/example"
+	"app/internal/folders"
+	"app/internal/health"
 	"app/internal/logger"
+	"app/internal/metrics"
 	custommiddleware "app/internal/middleware"
 	"app/internal/redis"
 	"app/internal/scheduler"
+	"app/internal/swagger"
 	"app/internal/uploads"
+	"app/internal/webhook"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
 // @title           Gogo API Template
@@ -43,13 +57,20 @@ import (
 // @description Type "Bearer" followed by a space and JWT token.
 
 func main() {
+	// Captured first so /health reports the uptime of the whole process
+	startedAt := time.Now()
+
 	// Config
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatal("Failed to load configuration:", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
-	// Logger
+	// Logger - the request log sampler is made first, the logger variable shadows the package
+	requestLogSampler := logger.NewSampler(cfg.LogSamplePerSec)
 	logger, err := logger.New(logger.Config{
 		Level:     cfg.LogLevel,
 		Format:    cfg.LogFormat,
@@ -61,23 +82,46 @@ func main() {
 		log.Fatal("Failed to initialize logger:", err)
 	}
 
-	logger.Info("Starting application",
+	logger.Info("Starting application", append([]any{
 		"app_name", cfg.AppName,
 		"version", cfg.AppVersion,
 		"environment", cfg.Environment,
 		"debug", cfg.Debug,
-	)
+	}, buildinfo.LogAttrs()...)...)
 
 	// DB
-	database, err := db.NewConnection(cfg)
+	database, err := db.NewConnection(cfg, logger)
 	if err != nil {
 		logger.Error("Failed to connect to database", "error", err)
 		log.Fatal("Failed to connect to database:", err)
 	}
 	defer database.Close()
 
+	// Read replica - without one every query goes to the primary
+	var queryDB db.DBTX = database
+	replica, err := db.NewReplicaConnection(cfg, logger)
+	if err != nil {
+		logger.Error("Failed to connect to read replica", "error", err)
+		log.Fatal("Failed to connect to read replica:", err)
+	}
+	if replica != nil {
+		defer replica.Close()
+		queryDB = db.NewRouter(database, replica)
+		logger.Info("Routing read-only queries to the read replica")
+	}
+
+	// Pool stats logging, deferred after the pools so it stops before they close
+	if cfg.DBPoolStatsInterval > 0 {
+		poolStatsCtx, stopPoolStats := context.WithCancel(context.Background())
+		defer stopPoolStats()
+		go db.LogPoolStats(poolStatsCtx, "primary", database, logger, cfg.DBPoolStatsInterval)
+		if replica != nil {
+			go db.LogPoolStats(poolStatsCtx, "replica", replica, logger, cfg.DBPoolStatsInterval)
+		}
+	}
+
 	// Redis
-	redisClient, err := redis.NewConnection(cfg)
+	redisClient, err := redis.NewConnection(cfg, logger)
 	if err != nil {
 		logger.Error("Failed to connect to Redis", "error", err)
 		log.Fatal("Failed to connect to Redis:", err)
@@ -85,56 +129,112 @@ func main() {
 	defer redisClient.Close()
 
 	// Cache
-	cacheService := cache.NewRedisCache(redisClient, cfg.AppName+":")
+	var cacheService cache.Cache = cache.NewRedisCache(redisClient, cfg.AppName+":")
+
+	// Metrics - pool gauges are read at scrape time, cache lookups are counted through a wrapper
+	var appMetrics *metrics.Metrics
+	if cfg.Features.EnableMetrics {
+		appMetrics = metrics.New()
+		appMetrics.RegisterDBPool(database)
+		appMetrics.RegisterRedisPool(redisClient)
+		cacheService = cache.NewInstrumentedCache(cacheService, appMetrics)
+	}
+
+	// Cache stats for GET /api/v1/debug/cache-stats, only collected in debug mode
+	var cacheStats *cache.StatsCache
+	if cfg.Debug {
+		cacheStats = cache.NewStatsCache(cacheService)
+		cacheService = cacheStats
+	}
+
+	// Response encoding - IDs as strings for clients that can't hold 64-bit integers,
+	// timestamps as RFC 3339 strings or epoch milliseconds
+	internal.SetStringIDs(cfg.Features.JSONStringIDs)
+	internal.SetTimestampFormat(cfg.TimestampFormat)
 
 	// Gin
 	r := gin.New()
 
+	// Trailing slashes are trimmed from GET/HEAD paths before routing instead, see TrimTrailingSlash
 	r.RedirectTrailingSlash = false
+	r.HandleMethodNotAllowed = true
+
+	// Only trusted proxies may set the client IP through X-Forwarded-For, see TRUSTED_PROXIES
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		logger.Error("Invalid trusted proxies", "error", err)
+		log.Fatal("Invalid trusted proxies:", err)
+	}
 
 	// Middleware
 	r.Use(custommiddleware.RequestID(logger))
+	r.Use(custommiddleware.SampledRequestLogging(logger, requestLogSampler))
+	r.Use(custommiddleware.SecurityHeaders(custommiddleware.SecurityHeadersConfig{
+		HSTS: cfg.IsProduction(),
+	}))
+	if appMetrics != nil {
+		r.Use(appMetrics.Middleware())
+	}
+	r.Use(custommiddleware.Compression(custommiddleware.CompressionConfig{
+		MinSize:      cfg.CompressionMinSize,
+		EnableBrotli: cfg.CompressionBrotli,
+	}))
+	if cfg.Features.DebugCaptureBodies {
+		r.Use(custommiddleware.CaptureBodies(logger))
+	}
 	r.Use(custommiddleware.Recovery(logger))
-	// r.Use(custommiddleware.RequestLogging(logger))
+	r.Use(custommiddleware.MaxBodySize(cfg.MaxBodySize))
 	r.Use(custommiddleware.ErrorHandler(logger))
-	r.Use(cors.Default())
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowAllOrigins = true
+	corsConfig.ExposeHeaders = append(slices.Clone(internal.PaginationHeaders), custommiddleware.APIVersionHeader)
+	corsConfig.AddAllowHeaders(custommiddleware.APIVersionHeader)
+	r.Use(cors.New(corsConfig))
+	r.Use(custommiddleware.PaginationLimits(cfg.PaginationMaxOffset))
+	r.Use(custommiddleware.Locale(cfg.DefaultLocale))
+	r.Use(custommiddleware.ProblemJSON(cfg.ProblemJSONErrors))
+	r.Use(custommiddleware.Timeout(cfg.RequestTimeout))
 
 	// Health check endpoint
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"app":     cfg.AppName,
-			"version": cfg.AppVersion,
-			"env":     cfg.Environment,
-		})
-	})
+	healthService := health.NewHealthService(database, cacheService)
+	healthHandler := health.NewHandler(cfg, healthService, logger, startedAt)
+	health.RegisterRoutes(r, healthHandler)
+
+	// API routes live under API_BASE_PATH, /api/v1 unless a gateway needs another prefix
+	api := r.Group(cfg.APIBasePath)
+	api.Use(custommiddleware.APIVersion(custommiddleware.SupportedAPIVersions...))
 
-	api := r.Group("/api/v1")
+	// Webhook dispatcher, shared by modules that emit events and drained on shutdown
+	webhooks := webhook.NewDispatcher(webhook.Config{
+		URL:    cfg.WebhookURL,
+		Secret: cfg.WebhookSecret,
+	}, logger)
 
 	app := &internal.App{
+		Config:   cfg,
+		DB:       database,
+		Queries:  db.New(queryDB),
+		Cache:    cacheService,
+		Logger:   logger,
+		Api:      api,
+		Webhooks: webhooks,
+	}
+
+	// Scheduler - jobs are always registered so they can be triggered manually
+	deps := &scheduler.Dependencies{
 		Config:  cfg,
 		DB:      database,
-		Queries: db.New(database),
-		Cache:   cacheService,
+		Queries: app.Queries,
 		Logger:  logger,
-		Api:     api,
 	}
 
-	// Initialize scheduler if enabled
-	if cfg.EnableScheduler {
-		deps := &scheduler.Dependencies{
-			Config:  cfg,
-			DB:      database,
-			Queries: app.Queries,
-			Logger:  logger,
-		}
-
-		cronScheduler := scheduler.NewScheduler(deps)
-		if err := cronScheduler.RegisterJobs(); err != nil {
-			logger.Error("Failed to register scheduler jobs", "error", err)
-			log.Fatal("Failed to register scheduler jobs:", err)
-		}
+	cronScheduler := scheduler.NewScheduler(deps)
+	if err := cronScheduler.RegisterJobs(); err != nil {
+		logger.Error("Failed to register scheduler jobs", "error", err)
+		log.Fatal("Failed to register scheduler jobs:", err)
+	}
 
+	// Start scheduler if enabled
+	if cfg.Features.EnableScheduler {
 		cronScheduler.Start()
 		logger.Info("Scheduler started in integrated mode")
 
@@ -142,32 +242,94 @@ func main() {
 		defer cronScheduler.Stop()
 	}
 
+	// Upload service, shared by the upload routes and account deletion
+	uploadService := uploads.NewService(app)
+
 	// Initialize auth service
-	if cfg.JWTSecret == "" {
-		logger.Error("JWT_SECRET is required")
-		log.Fatal("JWT_SECRET environment variable is required")
+	authService, err := auth.NewAuthServiceFromConfig(cfg, app.Queries, app.Cache, uploadService, logger)
+	if err != nil {
+		logger.Error("Failed to initialize auth service", "error", err)
+		log.Fatal(err)
 	}
-	authService := auth.NewAuthService(app.Queries, []byte(cfg.JWTSecret), logger)
 	authHandler := auth.NewAuthHandler(authService, logger)
 
 	// Register auth routes
 	auth.RegisterRoutes(api, authHandler, authService)
 
+	// Register API key routes, keys also authenticate examples and uploads
+	apiKeyService := apikeys.NewAPIKeyService(app.Queries)
+	apikeys.RegisterRoutes(app, authService, apiKeyService)
+
 	// Register example routes
-	example.RegisterRoutes(app, authService)
+	example.RegisterRoutes(app, authService, apiKeyService)
+
+	// Register folder routes
+	folders.RegisterRoutes(app, authService)
 
 	// Register uploads routes
-	uploads.RegisterRoutes(app, authService)
+	uploads.RegisterRoutes(app, authService, apiKeyService, uploadService)
+
+	// Register scheduler routes
+	scheduler.RegisterRoutes(app, authService, cronScheduler)
+
+	// Register cities routes
+	cities.RegisterRoutes(app, authService)
+
+	// Register debug routes
+	if cacheStats != nil {
+		debug.RegisterRoutes(app, authService, cacheStats)
+	}
+
+	// Prometheus scrape endpoint, keep it off the public internet at the proxy
+	if appMetrics != nil {
+		r.GET("/metrics", appMetrics.Handler())
+	}
 
-	// Swagger route - set host dynamically
-	docs.SwaggerInfo.Host = cfg.AppURL
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	// Swagger route - host and scheme come from config, off in production unless ENABLE_SWAGGER=true
+	swagger.RegisterRoutes(r, cfg)
+
+	// JSON errors for unknown paths and wrong methods, matching every other error response
+	r.NoRoute(custommiddleware.NoRoute())
+	r.NoMethod(custommiddleware.NoMethod())
 
 	// Start server
 	address := ":" + cfg.Port
-	logger.Info("Server starting", "address", address)
-	if err := r.Run(address); err != nil {
-		logger.Error("Server failed to start", "error", err, "address", address)
-		log.Fatal(err)
+	server := &http.Server{
+		Addr:    address,
+		Handler: custommiddleware.TrimTrailingSlash(r),
+		// Streamed downloads extend their own deadline per chunk, see internal.StreamWithDeadline
+		WriteTimeout: cfg.WriteTimeout,
+	}
+
+	go func() {
+		logger.Info("Server starting", "address", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("Server failed to start", "error", err, "address", address)
+			log.Fatal(err)
+		}
+	}()
+
+	// Wait for shutdown signal
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	logger.Info("Shutdown signal received, draining connections", "timeout", cfg.ShutdownTimeout.String())
+	healthHandler.MarkShuttingDown()
+
+	// Graceful shutdown - in-flight requests get until the timeout to finish
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Error("Server forced to shutdown", "error", err)
 	}
+
+	// Webhooks dispatched by finished requests get what is left of the timeout to land
+	if err := app.Webhooks.WaitContext(ctx); err != nil {
+		logger.Error("Webhook deliveries still running at shutdown", "error", err)
+	}
+
+	// Deferred scheduler, Redis and database cleanup runs after this point
+	logger.Info("Server stopped gracefully")
 }
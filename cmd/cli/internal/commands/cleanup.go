@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"app/cmd/cli/internal"
+	"app/internal/scheduler/jobs"
+)
+
+// RunCleanup runs data cleanup commands
+func RunCleanup(app *internal.CLIApp, args []string) {
+	fs := flag.NewFlagSet("cleanup", flag.ExitOnError)
+	fs.Bool("test", false, "Use TEST_DATABASE_URL")
+	fs.Usage = func() {
+		fmt.Println("Usage: go run cmd/cli cleanup COMMAND")
+		fmt.Println()
+		fmt.Println("Delete data that is no longer needed")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  tokens               Delete expired refresh tokens (also runs daily as the token-cleanup job)")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  go run cmd/cli cleanup tokens")
+		fmt.Println("  go run cmd/cli cleanup --test tokens    # Use test database")
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return
+	}
+
+	command := fs.Arg(0)
+
+	switch command {
+	case "tokens":
+		deleted, err := jobs.NewTokenCleanupJob(app.Queries, app.Logger).Prune(context.Background())
+		if err != nil {
+			log.Fatalf("Cleanup failed: %v", err)
+		}
+		fmt.Printf("Deleted %d expired refresh tokens\n", deleted)
+
+	default:
+		fmt.Printf("Unknown cleanup command: %s\n", command)
+		fs.Usage()
+	}
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+
+	"app/cmd/cli/internal"
+	"app/internal/scheduler"
+)
+
+// RunJob runs scheduler job commands
+func RunJob(app *internal.CLIApp, args []string) {
+	fs := flag.NewFlagSet("job", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println("Usage: go run cmd/cli job COMMAND")
+		fmt.Println()
+		fmt.Println("Manage scheduler jobs")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  run NAME             Execute a registered job immediately")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  go run cmd/cli job run example-job")
+		fmt.Println("  go run cmd/cli --test job run example-job    # Use test database")
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return
+	}
+
+	cronScheduler := scheduler.NewScheduler(&scheduler.Dependencies{
+		Config:  app.Config,
+		DB:      app.Database,
+		Queries: app.Queries,
+		Logger:  app.Logger,
+	})
+	if err := cronScheduler.RegisterJobs(); err != nil {
+		log.Fatalf("Failed to register jobs: %v", err)
+	}
+
+	command := fs.Arg(0)
+
+	switch command {
+	case "run":
+		if fs.NArg() < 2 {
+			fmt.Println("Error: job name is required")
+			fmt.Println("Usage: go run cmd/cli job run NAME")
+			fmt.Printf("Available jobs: %s\n", strings.Join(cronScheduler.JobNames(), ", "))
+			return
+		}
+		name := fs.Arg(1)
+		if err := cronScheduler.RunJob(context.Background(), name); err != nil {
+			if err == scheduler.ErrJobNotFound {
+				fmt.Printf("Available jobs: %s\n", strings.Join(cronScheduler.JobNames(), ", "))
+			}
+			log.Fatalf("Job %s failed: %v", name, err)
+		}
+		fmt.Printf("Job %s completed successfully\n", name)
+
+	default:
+		fmt.Printf("Unknown job command: %s\n", command)
+		fs.Usage()
+	}
+}
@@ -1,12 +1,17 @@
 package commands
 
 import (
+	"context"
+	"database/sql"
 	"flag"
 	"fmt"
 	"log"
+	"os"
 
 	"app/cmd/cli/internal"
+	"app/internal/db"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/stdlib"
 	"github.com/pressly/goose/v3"
 )
@@ -19,6 +24,8 @@ const (
 // RunMigrate runs database migrations
 func RunMigrate(app *internal.CLIApp, args []string) {
 	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	fs.Bool("test", false, "Use TEST_DATABASE_URL")
+	compareSchema := fs.Bool("schema", false, "verify: also compare the schema with migrations applied to TEST_DATABASE_URL")
 	fs.Usage = func() {
 		fmt.Println("Usage: go run cmd/cli migrate [OPTIONS] COMMAND")
 		fmt.Println()
@@ -31,6 +38,7 @@ func RunMigrate(app *internal.CLIApp, args []string) {
 		fmt.Println("  version              Print the current version of the database")
 		fmt.Println("  create NAME          Create a new migration file")
 		fmt.Println("  reset                Roll back all migrations")
+		fmt.Println("  verify               Check the DB is at the latest migration and every migration has a down")
 		fmt.Println()
 		fmt.Println("Options:")
 		fs.PrintDefaults()
@@ -40,6 +48,7 @@ func RunMigrate(app *internal.CLIApp, args []string) {
 		fmt.Println("  go run cmd/cli migrate status")
 		fmt.Println("  go run cmd/cli migrate create add_users_table")
 		fmt.Println("  go run cmd/cli migrate --test up    # Use test database")
+		fmt.Println("  go run cmd/cli migrate --schema verify    # Also diff the schema against a scratch DB")
 	}
 
 	fs.Parse(args)
@@ -103,8 +112,89 @@ func RunMigrate(app *internal.CLIApp, args []string) {
 		}
 		fmt.Println("All migrations rolled back successfully")
 
+	case "verify":
+		if !verifyMigrations(app, sqlDB, *compareSchema) {
+			os.Exit(1)
+		}
+
 	default:
 		fmt.Printf("Unknown migration command: %s\n", command)
 		fs.Usage()
 	}
 }
+
+// verifyMigrations reports drift between the database and the migrations on disk and
+// returns false if there is any. Migrations without a down only get a warning.
+func verifyMigrations(app *internal.CLIApp, sqlDB *sql.DB, compareSchema bool) bool {
+	files, err := db.ReadMigrations(migrationsDir)
+	if err != nil {
+		log.Fatalf("Failed to read migrations: %v", err)
+	}
+	version, err := goose.GetDBVersion(sqlDB)
+	if err != nil {
+		log.Fatalf("Failed to get database version: %v", err)
+	}
+
+	status := db.CheckMigrations(version, files)
+	fmt.Printf("Database version: %d, latest migration: %d\n", status.DBVersion, status.LatestVersion)
+
+	inSync := status.UpToDate()
+	if status.Ahead() {
+		fmt.Printf("DRIFT: the database is at version %d, which has no migration file (deleted or renamed?)\n", status.DBVersion)
+	}
+	for _, file := range status.Pending {
+		fmt.Printf("DRIFT: %s is not applied\n", file.Name)
+	}
+	for _, file := range status.MissingDown {
+		fmt.Printf("WARNING: %s has no down migration\n", file.Name)
+	}
+
+	if compareSchema {
+		diff := diffScratchSchema(app)
+		for _, line := range diff {
+			fmt.Printf("DRIFT: %s\n", line)
+		}
+		inSync = inSync && len(diff) == 0
+	}
+
+	if inSync {
+		fmt.Println("Migrations match the database")
+	}
+	return inSync
+}
+
+// diffScratchSchema applies the migrations to TEST_DATABASE_URL and diffs its schema
+// against the database's, which catches changes made directly in the database
+func diffScratchSchema(app *internal.CLIApp) []string {
+	ctx := context.Background()
+	scratchURL := app.Config.TestDatabaseURL
+	if scratchURL == "" || scratchURL == app.Config.DatabaseURL {
+		log.Fatal("Schema verification needs TEST_DATABASE_URL set to a scratch database other than the one being verified")
+	}
+
+	scratchConfig, err := pgx.ParseConfig(scratchURL)
+	if err != nil {
+		log.Fatalf("Invalid TEST_DATABASE_URL: %v", err)
+	}
+	scratchSQL := stdlib.OpenDB(*scratchConfig)
+	defer scratchSQL.Close()
+	if err := goose.Up(scratchSQL, migrationsDir); err != nil {
+		log.Fatalf("Failed to migrate the scratch database: %v", err)
+	}
+
+	scratch, err := pgx.ConnectConfig(ctx, scratchConfig)
+	if err != nil {
+		log.Fatalf("Failed to connect to the scratch database: %v", err)
+	}
+	defer scratch.Close(ctx)
+
+	expected, err := db.LoadSchema(ctx, scratch)
+	if err != nil {
+		log.Fatalf("Failed to read the scratch schema: %v", err)
+	}
+	actual, err := db.LoadSchema(ctx, app.Database)
+	if err != nil {
+		log.Fatalf("Failed to read the database schema: %v", err)
+	}
+	return db.DiffSchemas(expected, actual)
+}
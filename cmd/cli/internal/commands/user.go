@@ -0,0 +1,91 @@
+package commands
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"app/cmd/cli/internal"
+	"app/internal/auth"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// RunUser runs user management commands
+func RunUser(app *internal.CLIApp, args []string) {
+	fs := flag.NewFlagSet("user", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println("Usage: go run cmd/cli user COMMAND [OPTIONS]")
+		fmt.Println()
+		fmt.Println("Manage users")
+		fmt.Println()
+		fmt.Println("Commands:")
+		fmt.Println("  create               Create a user, e.g. the first admin of a fresh deployment")
+		fmt.Println()
+		fmt.Println("Examples:")
+		fmt.Println("  go run cmd/cli user create --email admin@example.com --name Admin --password s3cretpass --role admin")
+		fmt.Println("  go run cmd/cli user create --test --email user@example.com --name User --password s3cretpass    # Use test database")
+	}
+
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return
+	}
+
+	command := fs.Arg(0)
+
+	switch command {
+	case "create":
+		runUserCreate(app, fs.Args()[1:])
+
+	default:
+		fmt.Printf("Unknown user command: %s\n", command)
+		fs.Usage()
+	}
+}
+
+func runUserCreate(app *internal.CLIApp, args []string) {
+	fs := flag.NewFlagSet("user create", flag.ExitOnError)
+	email := fs.String("email", "", "Email address (required)")
+	name := fs.String("name", "", "Display name (required)")
+	password := fs.String("password", "", "Password, at least 8 characters with a letter and a digit (required)")
+	role := fs.String("role", "", "Extra role to grant, e.g. "+middleware.RoleAdmin)
+	fs.Bool("test", false, "Use TEST_DATABASE_URL")
+	fs.Usage = func() {
+		fmt.Println("Usage: go run cmd/cli user create --email EMAIL --name NAME --password PASSWORD [--role ROLE]")
+		fmt.Println()
+		fmt.Println("Options:")
+		fs.PrintDefaults()
+	}
+
+	fs.Parse(args)
+
+	authService, err := auth.NewAuthServiceFromConfig(app.Config, app.Queries, nil, nil, app.Logger)
+	if err != nil {
+		log.Fatalf("Failed to create auth service: %v", err)
+	}
+
+	// Hold CLI users to the same rules as registration
+	req := auth.RegisterRequest{Email: *email, Name: *name, Password: *password}
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		fs.Usage()
+		log.Fatalf("Invalid user: %v", err)
+	}
+
+	// Extra roles are granted on top of the default one
+	var roles []string
+	if *role != "" && *role != auth.DefaultRole {
+		roles = []string{auth.DefaultRole, *role}
+	}
+
+	user, err := authService.CreateUser(context.Background(), req, roles)
+	if err != nil {
+		log.Fatalf("Failed to create user: %v", err)
+	}
+
+	fmt.Printf("Created user %d (%s) with roles %v\n", user.ID, user.Email, user.Roles)
+}
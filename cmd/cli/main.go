@@ -8,6 +8,7 @@ import (
 	"app/cmd/cli/internal"
 	"app/cmd/cli/internal/commands"
 	"app/config"
+	"app/internal/buildinfo"
 	"app/internal/db"
 	"app/internal/logger"
 )
@@ -40,6 +41,12 @@ func main() {
 		commands.RunMigrate(app, args)
 	case "test":
 		commands.RunTest(app, args)
+	case "job":
+		commands.RunJob(app, args)
+	case "user":
+		commands.RunUser(app, args)
+	case "cleanup":
+		commands.RunCleanup(app, args)
 	default:
 		fmt.Printf("Unknown command: %s\n\n", commandName)
 		printUsage()
@@ -73,6 +80,10 @@ func initializeApp() (*internal.CLIApp, error) {
 		log.Println("Using TEST_DATABASE_URL for database connection")
 	}
 
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Initialize logger
 	appLogger, err := logger.New(logger.Config{
 		Level:     cfg.LogLevel,
@@ -85,8 +96,10 @@ func initializeApp() (*internal.CLIApp, error) {
 		return nil, fmt.Errorf("failed to initialize logger: %w", err)
 	}
 
+	appLogger.Info("Starting Gogo CLI", buildinfo.LogAttrs()...)
+
 	// Initialize database
-	database, err := db.NewConnection(cfg)
+	database, err := db.NewConnection(cfg, appLogger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -111,12 +124,19 @@ func printUsage() {
 	fmt.Println("Available Commands:")
 	fmt.Println("  migrate              Run database migrations")
 	fmt.Println("  test                 Run various tests")
+	fmt.Println("  job                  Run scheduler jobs")
+	fmt.Println("  user                 Manage users")
+	fmt.Println("  cleanup              Delete expired data")
 	fmt.Println("  help                 Show this help message")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  go run cmd/cli migrate up")
 	fmt.Println("  go run cmd/cli migrate status")
+	fmt.Println("  go run cmd/cli migrate verify")
 	fmt.Println("  go run cmd/cli test")
+	fmt.Println("  go run cmd/cli job run example-job")
+	fmt.Println("  go run cmd/cli cleanup tokens")
+	fmt.Println("  go run cmd/cli user create --email admin@example.com --name Admin --password s3cretpass --role admin")
 	fmt.Println()
 	fmt.Println("For more information on a specific command:")
 	fmt.Println("  go run cmd/cli <command> --help")
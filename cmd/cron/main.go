@@ -8,6 +8,7 @@ import (
 	"syscall"
 
 	"app/config"
+	"app/internal/buildinfo"
 	"app/internal/db"
 	"app/internal/logger"
 	"app/internal/scheduler"
@@ -35,6 +36,10 @@ func main() {
 		log.Println("Using TEST_DATABASE_URL for database connection")
 	}
 
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	// Initialize logger
 	appLogger, err := logger.New(logger.Config{
 		Level:     cfg.LogLevel,
@@ -47,10 +52,10 @@ func main() {
 		log.Fatalf("Failed to initialize logger: %v", err)
 	}
 
-	appLogger.Info("Starting Gogo Cron Server")
+	appLogger.Info("Starting Gogo Cron Server", buildinfo.LogAttrs()...)
 
 	// Initialize database
-	database, err := db.NewConnection(cfg)
+	database, err := db.NewConnection(cfg, appLogger)
 	if err != nil {
 		appLogger.Error("Failed to connect to database", "error", err)
 		os.Exit(1)
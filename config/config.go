@@ -1,9 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -24,13 +28,101 @@ type Config struct {
 	LogLevel        string
 	LogFormat       string
 	LogOutput       string
+	// Successful requests logged per second per route, 0 logs every request
+	LogSamplePerSec int
 	JWTSecret       string
+	SignedURLSecret string
 	AppURL          string
 	FilesBaseURL    string
 	UploadFolder    string
+	ShutdownTimeout time.Duration
+	RequestTimeout  time.Duration
 
-	// Scheduler configuration
-	EnableScheduler bool
+	// Path every API route is served under, DefaultAPIBasePath unless a gateway needs another
+	APIBasePath string
+
+	// Proxies (IPs or CIDRs) whose X-Forwarded-For and X-Real-IP headers are believed when
+	// resolving the client IP for logs and rate limits; empty trusts no proxy
+	TrustedProxies []string
+
+	// JWT signing keys for rotation (see ParseJWTKeys); tokens are signed with JWTActiveKID
+	// and tokens without a kid header are still verified with JWTSecret
+	JWTKeys      string
+	JWTActiveKID string
+
+	// Default statement timeout for every database query, 0 leaves queries unbounded
+	DBQueryTimeout time.Duration
+	// How often pool stats are logged at debug level, 0 disables the logging
+	DBPoolStatsInterval time.Duration
+
+	// Optional read replica; read-only queries are sent there when set
+	ReplicaDatabaseURL string
+
+	// Startup connection retries for Postgres and Redis, so the app can start before they are ready.
+	// Attempts below 1 mean a single attempt.
+	ConnectMaxAttempts int
+	ConnectMaxDelay    time.Duration
+
+	// /health fails once more goroutines than this are running, 0 disables the check
+	HealthMaxGoroutines int
+
+	// Largest request body accepted outside of routes with their own limit (uploads)
+	MaxBodySize int64
+
+	// Per-user upload quotas, 0 means unlimited
+	UploadMaxBytesPerUser int64
+	UploadMaxFilesPerUser int64
+
+	// Store identical files uploaded by the same user only once
+	UploadDeduplicate bool
+
+	// How long the signed_url of an upload stays valid
+	UploadSignedURLTTL time.Duration
+
+	// Replaces RequestTimeout and WriteTimeout on upload routes, whose body is read inside the handler
+	UploadTimeout time.Duration
+
+	// Webhook notified about events such as upload.created, empty URL disables it
+	WebhookURL    string
+	WebhookSecret string
+
+	// Redis connection pool tuning
+	RedisPoolSize        int
+	RedisMinIdleConns    int
+	RedisMaxIdleConns    int
+	RedisConnMaxLifetime time.Duration
+	RedisConnMaxIdleTime time.Duration
+	RedisDialTimeout     time.Duration
+	RedisReadTimeout     time.Duration
+	RedisWriteTimeout    time.Duration
+
+	// HTTP server write limits - WriteTimeout bounds ordinary responses,
+	// StreamChunkTimeout bounds each chunk of a streamed download instead
+	WriteTimeout       time.Duration
+	StreamChunkTimeout time.Duration
+
+	// Pagination - deepest row offset page-based listings will serve
+	PaginationMaxOffset int64
+
+	// Response compression
+	CompressionMinSize int
+	CompressionBrotli  bool
+
+	// Locale used for messages when Accept-Language has no supported match
+	DefaultLocale string
+
+	// Emit RFC 7807 problem+json error bodies for every request
+	ProblemJSONErrors bool
+
+	// Algorithm new password hashes use, "bcrypt" or "argon2id". Hashes made by the
+	// other one still verify and are upgraded on the user's next login.
+	PasswordHasher string
+
+	// How response timestamps are rendered, "rfc3339" strings or "unix_ms" numbers
+	TimestampFormat string
+
+	// Feature flags
+	Features Features
 }
 
 // Load loads configuration from environment
@@ -53,16 +145,163 @@ func Load() (*Config, error) {
 		LogLevel:        getEnv("LOG_LEVEL", "info"),
 		LogFormat:       getEnv("LOG_FORMAT", "json"),
 		LogOutput:       getEnv("LOG_OUTPUT", "both"),
+		LogSamplePerSec: getEnvInt("LOG_SAMPLE_PER_SEC", 0),
 		JWTSecret:       getEnv("JWT_SECRET", ""),
+		SignedURLSecret: getEnv("SIGNED_URL_SECRET", getEnv("JWT_SECRET", "")),
 		AppURL:          getEnv("APP_URL", "localhost:8181"),
 		FilesBaseURL:    getEnv("FILES_BASE_URL", fmt.Sprintf("http://localhost:%s/api/files", getEnv("PORT", "8181"))),
 		UploadFolder:    getEnv("UPLOAD_FOLDER", "./uploads"),
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+		RequestTimeout:  getEnvDuration("REQUEST_TIMEOUT", 30*time.Second),
+		APIBasePath:     getEnv("API_BASE_PATH", DefaultAPIBasePath),
+		TrustedProxies:  ParseTrustedProxies(getEnv("TRUSTED_PROXIES", "127.0.0.1,::1")),
+		MaxBodySize:     int64(getEnvInt("MAX_BODY_SIZE", 1<<20)),
+		DBQueryTimeout:  getEnvDuration("DB_QUERY_TIMEOUT", 30*time.Second),
 
-		// Scheduler configuration
-		EnableScheduler: getEnvBool("ENABLE_SCHEDULER", true),
+		DBPoolStatsInterval: getEnvDuration("DB_POOL_STATS_INTERVAL", time.Minute),
+
+		// JWT key rotation
+		JWTKeys:      getEnv("JWT_KEYS", ""),
+		JWTActiveKID: getEnv("JWT_ACTIVE_KID", ""),
+
+		// Read replica
+		ReplicaDatabaseURL: getEnv("REPLICA_DATABASE_URL", ""),
+
+		// Startup connection retries
+		ConnectMaxAttempts: getEnvInt("CONNECT_MAX_ATTEMPTS", 5),
+		ConnectMaxDelay:    getEnvDuration("CONNECT_MAX_DELAY", 10*time.Second),
+
+		// Liveness self-check
+		HealthMaxGoroutines: getEnvInt("HEALTH_MAX_GOROUTINES", 0),
+
+		// Upload quotas
+		UploadMaxBytesPerUser: int64(getEnvInt("UPLOAD_MAX_BYTES_PER_USER", 0)),
+		UploadMaxFilesPerUser: int64(getEnvInt("UPLOAD_MAX_FILES_PER_USER", 0)),
+		UploadDeduplicate:     getEnvBool("UPLOAD_DEDUPLICATE", false),
+		UploadSignedURLTTL:    getEnvDuration("UPLOAD_SIGNED_URL_TTL", 15*time.Minute),
+		UploadTimeout:         getEnvDuration("UPLOAD_TIMEOUT", 10*time.Minute),
+
+		// Webhooks
+		WebhookURL:    getEnv("WEBHOOK_URL", ""),
+		WebhookSecret: getEnv("WEBHOOK_SECRET", ""),
+
+		// Redis connection pool
+		RedisPoolSize:        getEnvInt("REDIS_POOL_SIZE", 20),
+		RedisMinIdleConns:    getEnvInt("REDIS_MIN_IDLE", 5),
+		RedisMaxIdleConns:    getEnvInt("REDIS_MAX_IDLE", 10),
+		RedisConnMaxLifetime: getEnvDuration("REDIS_CONN_MAX_LIFETIME", 5*time.Minute),
+		RedisConnMaxIdleTime: getEnvDuration("REDIS_CONN_MAX_IDLE_TIME", 5*time.Minute),
+		RedisDialTimeout:     getEnvDuration("REDIS_DIAL_TIMEOUT", 5*time.Second),
+		RedisReadTimeout:     getEnvDuration("REDIS_READ_TIMEOUT", 3*time.Second),
+		RedisWriteTimeout:    getEnvDuration("REDIS_WRITE_TIMEOUT", 3*time.Second),
+
+		// HTTP server write limits
+		WriteTimeout:       getEnvDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		StreamChunkTimeout: getEnvDuration("STREAM_CHUNK_TIMEOUT", 30*time.Second),
+
+		// Pagination
+		PaginationMaxOffset: int64(getEnvInt("PAGINATION_MAX_OFFSET", 10000)),
+
+		// Response compression
+		CompressionMinSize: getEnvInt("COMPRESSION_MIN_SIZE", 1024),
+		CompressionBrotli:  getEnvBool("COMPRESSION_BROTLI", true),
+
+		// Localization
+		DefaultLocale: getEnv("DEFAULT_LOCALE", "en"),
+
+		// Error format
+		ProblemJSONErrors: getEnvBool("PROBLEM_JSON_ERRORS", false),
+
+		// Password hashing
+		PasswordHasher: getEnv("PASSWORD_HASHER", "bcrypt"),
+
+		// Response timestamps
+		TimestampFormat: getEnv("TIMESTAMP_FORMAT", "rfc3339"),
+
+		// Feature flags
+		Features: loadFeatures(),
 	}, nil
 }
 
+// DefaultAPIBasePath is the path API routes are served under when API_BASE_PATH is unset
+const DefaultAPIBasePath = "/api/v1"
+
+// ParseTrustedProxies splits TRUSTED_PROXIES, a comma-separated list of IPs and CIDRs.
+// "none" trusts no proxy, so forwarding headers are always ignored.
+func ParseTrustedProxies(value string) []string {
+	if strings.TrimSpace(value) == "none" {
+		return nil
+	}
+
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// MinProductionJWTSecretLength is the minimum JWT secret size (in bytes) accepted in production
+const MinProductionJWTSecretLength = 32
+
+// Validate checks that required configuration is present, reporting every problem at once
+func (c *Config) Validate() error {
+	var problems []error
+
+	if c.DatabaseURL == "" {
+		problems = append(problems, errors.New("DATABASE_URL is required"))
+	}
+
+	if c.JWTSecret == "" {
+		problems = append(problems, errors.New("JWT_SECRET is required"))
+	} else if c.IsProduction() && len(c.JWTSecret) < MinProductionJWTSecretLength {
+		problems = append(problems, fmt.Errorf("JWT_SECRET must be at least %d bytes in production, got %d", MinProductionJWTSecretLength, len(c.JWTSecret)))
+	}
+
+	problems = append(problems, c.validateJWTKeys()...)
+
+	if c.WebhookURL != "" && c.WebhookSecret == "" {
+		problems = append(problems, errors.New("WEBHOOK_SECRET is required when WEBHOOK_URL is set"))
+	}
+
+	if c.IsProduction() && c.Features.DebugCaptureBodies {
+		problems = append(problems, errors.New("DEBUG_CAPTURE_BODIES must not be enabled in production"))
+	}
+
+	switch c.PasswordHasher {
+	case "", "bcrypt", "argon2id":
+	default:
+		problems = append(problems, fmt.Errorf("PASSWORD_HASHER must be bcrypt or argon2id, got %q", c.PasswordHasher))
+	}
+
+	if c.APIBasePath != "" && (!strings.HasPrefix(c.APIBasePath, "/") || strings.HasSuffix(c.APIBasePath, "/")) {
+		problems = append(problems, fmt.Errorf("API_BASE_PATH must start with / and not end with one, got %q", c.APIBasePath))
+	}
+
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			problems = append(problems, fmt.Errorf("TRUSTED_PROXIES entry %q is not an IP or CIDR", proxy))
+		}
+	}
+
+	switch c.TimestampFormat {
+	case "", "rfc3339", "unix_ms":
+	default:
+		problems = append(problems, fmt.Errorf("TIMESTAMP_FORMAT must be rfc3339 or unix_ms, got %q", c.TimestampFormat))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(problems...))
+	}
+	return nil
+}
+
+// IsProduction reports whether the app runs with APP_ENV=production
+func (c *Config) IsProduction() bool {
+	return c.Environment == "production"
+}
+
 // Helper functions
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -79,3 +318,21 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
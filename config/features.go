@@ -0,0 +1,39 @@
+package config
+
+// Features holds the on/off switches of the app. Add new flags here and to
+// loadFeatures so every toggle is parsed and documented in one place.
+type Features struct {
+	// EnableScheduler runs the cron jobs inside the api process (ENABLE_SCHEDULER).
+	// Turn it off when cmd/cron runs them instead, or jobs run twice.
+	EnableScheduler bool
+
+	// EnableMetrics serves Prometheus metrics on /metrics (ENABLE_METRICS)
+	EnableMetrics bool
+
+	// EnableSwagger serves the API docs on /swagger (ENABLE_SWAGGER), on by default
+	// everywhere but APP_ENV=production
+	EnableSwagger bool
+
+	// DebugCaptureBodies logs redacted JSON request and response bodies at debug level
+	// (DEBUG_CAPTURE_BODIES). Config.Validate refuses it in production.
+	DebugCaptureBodies bool
+
+	// RequireEmailVerification is meant to block logins until the address is confirmed
+	// (REQUIRE_EMAIL_VERIFICATION). Nothing sends verification emails yet, so it is not enforced.
+	RequireEmailVerification bool
+
+	// JSONStringIDs serializes IDs in responses as JSON strings (JSON_STRING_IDS), for
+	// JavaScript clients that lose precision on integers above 2^53
+	JSONStringIDs bool
+}
+
+func loadFeatures() Features {
+	return Features{
+		EnableScheduler:          getEnvBool("ENABLE_SCHEDULER", true),
+		EnableMetrics:            getEnvBool("ENABLE_METRICS", false),
+		EnableSwagger:            getEnvBool("ENABLE_SWAGGER", getEnv("APP_ENV", "development") != "production"),
+		DebugCaptureBodies:       getEnvBool("DEBUG_CAPTURE_BODIES", false),
+		RequireEmailVerification: getEnvBool("REQUIRE_EMAIL_VERIFICATION", false),
+		JSONStringIDs:            getEnvBool("JSON_STRING_IDS", false),
+	}
+}
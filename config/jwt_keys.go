@@ -0,0 +1,69 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ParseJWTKeys parses JWT_KEYS, a comma-separated list of id:secret pairs such as
+// "2024-06:new-secret,2024-01:old-secret". Secrets may contain colons but not commas.
+func ParseJWTKeys(spec string) (map[string]string, error) {
+	keys := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		kid, secret, ok := strings.Cut(pair, ":")
+		kid = strings.TrimSpace(kid)
+		if !ok || kid == "" || secret == "" {
+			return nil, fmt.Errorf("JWT_KEYS entry %q must look like id:secret", redactJWTKey(pair))
+		}
+		if _, exists := keys[kid]; exists {
+			return nil, fmt.Errorf("JWT_KEYS lists key %q twice", kid)
+		}
+		keys[kid] = secret
+	}
+	return keys, nil
+}
+
+// redactJWTKey keeps a malformed entry recognizable in errors without printing its secret
+func redactJWTKey(pair string) string {
+	kid, _, ok := strings.Cut(pair, ":")
+	if !ok {
+		return "<redacted>"
+	}
+	return kid + ":<redacted>"
+}
+
+// validateJWTKeys checks JWT_KEYS and JWT_ACTIVE_KID against each other
+func (c *Config) validateJWTKeys() []error {
+	if c.JWTKeys == "" {
+		if c.JWTActiveKID != "" {
+			return []error{errors.New("JWT_ACTIVE_KID is set but JWT_KEYS is empty")}
+		}
+		return nil
+	}
+
+	keys, err := ParseJWTKeys(c.JWTKeys)
+	if err != nil {
+		return []error{err}
+	}
+
+	var problems []error
+	if c.JWTActiveKID == "" {
+		problems = append(problems, errors.New("JWT_ACTIVE_KID is required when JWT_KEYS is set"))
+	} else if _, ok := keys[c.JWTActiveKID]; !ok {
+		problems = append(problems, fmt.Errorf("JWT_ACTIVE_KID %q is not in JWT_KEYS", c.JWTActiveKID))
+	}
+	if c.IsProduction() {
+		for kid, secret := range keys {
+			if len(secret) < MinProductionJWTSecretLength {
+				problems = append(problems, fmt.Errorf("JWT_KEYS key %q must be at least %d bytes in production, got %d", kid, MinProductionJWTSecretLength, len(secret)))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,124 @@
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"app/internal/db"
+	"app/internal/errs"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Error variables - define all service errors at the top of the file
+var (
+	ErrAPIKeyNotFound = errs.NewNotFoundError(errs.ErrKeyAPIKeyNotFound, "API key not found")
+	ErrAPIKeyInvalid  = errs.NewUnauthorizedError(errs.ErrKeyAPIKeyInvalid, "Invalid API key")
+)
+
+const (
+	// KeyPrefix marks API keys so they are easy to recognise in configs and secret scanners
+	KeyPrefix = "gk_"
+	// keyRandomBytes is the entropy of a generated key
+	keyRandomBytes = 32
+	// displayPrefixLength is how many characters of the key are stored in clear for listings
+	displayPrefixLength = len(KeyPrefix) + 8
+)
+
+// APIKeyService manages per-user API keys.
+// Only a SHA-256 hash of each key is stored; the plaintext is returned once, on creation.
+type APIKeyService struct {
+	queries *db.Queries
+}
+
+// NewAPIKeyService creates a new API key service
+func NewAPIKeyService(queries *db.Queries) *APIKeyService {
+	return &APIKeyService{
+		queries: queries,
+	}
+}
+
+// GenerateAPIKey returns a new random plaintext key and its display prefix
+func GenerateAPIKey() (key, prefix string, err error) {
+	randomBytes := make([]byte, keyRandomBytes)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", "", err
+	}
+
+	key = KeyPrefix + hex.EncodeToString(randomBytes)
+	return key, key[:displayPrefixLength], nil
+}
+
+// HashAPIKey returns the hex SHA-256 digest stored for a key.
+// Keys are long random strings, so a fast hash is enough and allows lookups by hash.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey creates a key for the user and returns it with the plaintext, which is not stored
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, userID int32, name string) (*db.ApiKey, string, error) {
+	key, prefix, err := GenerateAPIKey()
+	if err != nil {
+		return nil, "", errs.WrapInternal(errs.ErrKeyInternalError, "failed to generate API key", err)
+	}
+
+	apiKey, err := s.queries.CreateAPIKey(ctx, db.CreateAPIKeyParams{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: HashAPIKey(key),
+	})
+	if err != nil {
+		return nil, "", errs.WrapInternal(errs.ErrKeyInternalError, "failed to create API key", err)
+	}
+
+	return &apiKey, key, nil
+}
+
+// ListAPIKeys lists the user's keys, newest first
+func (s *APIKeyService) ListAPIKeys(ctx context.Context, userID int32) ([]db.ApiKey, error) {
+	keys, err := s.queries.ListAPIKeysByUserID(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list API keys", err)
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey deletes one of the user's keys so it can no longer authenticate
+// Returns ErrAPIKeyNotFound if the key doesn't exist or belongs to another user
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, keyID, userID int32) error {
+	deleted, err := s.queries.DeleteAPIKey(ctx, db.DeleteAPIKeyParams{
+		ID:     keyID,
+		UserID: userID,
+	})
+	if err != nil {
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to revoke API key", err)
+	}
+	if deleted == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// VerifyAPIKey returns the ID of the user owning key
+// Implements middleware.APIKeyVerifier
+func (s *APIKeyService) VerifyAPIKey(ctx context.Context, key string) (int32, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return 0, ErrAPIKeyInvalid
+	}
+
+	apiKey, err := s.queries.GetAPIKeyByHash(ctx, HashAPIKey(key))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrAPIKeyInvalid
+		}
+		return 0, errs.WrapInternal(errs.ErrKeyInternalError, "failed to verify API key", err)
+	}
+
+	return apiKey.UserID, nil
+}
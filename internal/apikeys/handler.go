@@ -0,0 +1,147 @@
+package apikeys
+
+import (
+	"net/http"
+	"strconv"
+
+	"app/internal"
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/logger"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *APIKeyService
+	logger  *logger.Logger
+}
+
+func NewHandler(service *APIKeyService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// CreateAPIKey creates a new API key for the authenticated user
+//
+//	@Summary		Create API key
+//	@Description	Create an API key; the plaintext key is only returned in this response
+//	@Tags			api-keys
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		CreateAPIKeyRequest	true	"API key details"
+//	@Success		201		{object}	CreatedAPIKeyDataResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/me/api-keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	apiKey, key, err := h.service.CreateAPIKey(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to create API key", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreatedAPIKeyDataResponse{
+		Data: CreatedAPIKeyResponse{
+			APIKeyResponse: toAPIKeyResponse(apiKey),
+			Key:            key,
+		},
+	})
+}
+
+// ListAPIKeys lists the authenticated user's API keys
+//
+//	@Summary		List API keys
+//	@Description	List API keys of the authenticated user; only key prefixes are returned
+//	@Tags			api-keys
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	APIKeysListResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/me/api-keys [get]
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	keys, err := h.service.ListAPIKeys(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to list API keys", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := make([]APIKeyResponse, len(keys))
+	for i := range keys {
+		response[i] = toAPIKeyResponse(&keys[i])
+	}
+
+	c.JSON(http.StatusOK, APIKeysListResponse{Data: response})
+}
+
+// RevokeAPIKey revokes one of the authenticated user's API keys
+//
+//	@Summary		Revoke API key
+//	@Description	Revoke an API key so it can no longer authenticate
+//	@Tags			api-keys
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int	true	"API key ID"
+//	@Success		200	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/me/api-keys/{id} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid API key ID")
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(c.Request.Context(), int32(id), userID); err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	var response MessageResponse
+	response.Data.Message = "API key revoked successfully"
+	c.JSON(http.StatusOK, response)
+}
+
+func toAPIKeyResponse(apiKey *db.ApiKey) APIKeyResponse {
+	return APIKeyResponse{
+		ID:        apiKey.ID,
+		Name:      apiKey.Name,
+		Prefix:    apiKey.Prefix,
+		CreatedAt: internal.FormatTimestamp(apiKey.CreatedAt),
+	}
+}
@@ -0,0 +1,24 @@
+package apikeys
+
+import (
+	"app/internal"
+	"app/internal/middleware"
+)
+
+// RegisterRoutes registers API key management routes.
+// Listing accepts a key, but creating and revoking need a user token: otherwise a leaked
+// key could mint new keys that outlive its own revocation.
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier, service *APIKeyService) {
+	handler := NewHandler(service, app.Logger)
+
+	apiKeys := app.Api.Group("/me/api-keys")
+	apiKeys.GET("", middleware.UserOrAPIKeyAuthMiddleware(authService, service), handler.ListAPIKeys)
+
+	manage := apiKeys.Group("")
+	manage.Use(middleware.UserAuthMiddleware(authService))
+	manage.Use(middleware.RequireJSON())
+	{
+		manage.POST("", handler.CreateAPIKey)
+		manage.DELETE("/:id", handler.RevokeAPIKey)
+	}
+}
@@ -0,0 +1,44 @@
+package apikeys
+
+import "app/internal"
+
+// CreateAPIKeyRequest represents the request to create an API key
+type CreateAPIKeyRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// APIKeyResponse represents a stored API key; only the prefix of the key is ever shown
+type APIKeyResponse struct {
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	Prefix    string             `json:"prefix"`
+	CreatedAt internal.Timestamp `json:"created_at"`
+}
+
+// CreatedAPIKeyResponse includes the plaintext key, which is only returned on creation
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+// CreatedAPIKeyDataResponse wraps a newly created API key in response
+type CreatedAPIKeyDataResponse struct {
+	Data CreatedAPIKeyResponse `json:"data"`
+}
+
+// APIKeysListResponse wraps the API key list in response
+type APIKeysListResponse struct {
+	Data []APIKeyResponse `json:"data"`
+}
+
+// MessageResponse wraps a simple message in response
+type MessageResponse struct {
+	Data struct {
+		Message string `json:"message"`
+	} `json:"data"`
+}
+
+// ErrorResponse represents error response structure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
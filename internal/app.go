@@ -6,6 +6,7 @@ import (
 	"app/internal/cache"
 	"app/internal/db"
 	"app/internal/logger"
+	"app/internal/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,4 +19,6 @@ type App struct {
 	Cache   cache.Cache
 	Logger  *logger.Logger
 	Api     *gin.RouterGroup
+	// Webhooks delivers events in the background; main waits for it on shutdown
+	Webhooks *webhook.Dispatcher
 }
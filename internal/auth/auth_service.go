@@ -1,10 +1,14 @@
 package auth
 
 import (
+	"app/config"
+	"app/internal/cache"
 	"app/internal/db"
 	"app/internal/errs"
+	"app/internal/example"
 	"app/internal/logger"
 	"app/internal/middleware"
+	"app/internal/uploads"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
@@ -16,16 +20,29 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService struct {
-	queries   *db.Queries
-	jwtSecret []byte
-	logger    *logger.Logger
+	queries *db.Queries
+	cache   cache.Cache
+	jwtKeys *JWTKeySet
+	logger  *logger.Logger
+	// passwords hashes with PASSWORD_HASHER and verifies hashes of every supported algorithm
+	passwords *MultiHasher
+	// uploads and examples delete a user's rows in DeleteAccount's transaction,
+	// and uploads removes their files afterwards
+	uploads  *uploads.UploadService
+	examples *example.ExampleService
 }
 
+// DefaultRole is the role every account gets on registration (the users.roles column default)
+const DefaultRole = "user"
+
+// userCacheTTL bounds how stale a cached user can be if an invalidation is missed
+const userCacheTTL = time.Minute
+
 type TokenPair struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -35,7 +52,7 @@ type TokenPair struct {
 type RegisterRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Name     string `json:"name" binding:"required"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required,strongpassword"`
 }
 
 // LoginRequest represents the request structure for user login
@@ -44,57 +61,186 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// UpdateProfileRequest represents the request structure for updating the current user's profile
+type UpdateProfileRequest struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Email string `json:"email" binding:"required,email,max=255"`
+}
+
+// ChangePasswordRequest represents the request structure for changing the current user's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,strongpassword"`
+}
+
+// DeleteAccountRequest represents the request structure for deleting the current user's account
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// EmailAvailableRequest represents the query for the email availability check
+type EmailAvailableRequest struct {
+	Email string `form:"email" binding:"required,email"`
+}
+
 var (
 	ErrInvalidCredentials = errs.NewUnauthorizedError(errs.ErrKeyAuthInvalidCredentials, "Invalid email or password")
 	ErrUserNotFound       = errs.NewNotFoundError(errs.ErrKeyAuthUserNotFound, "User not found")
 	ErrInvalidToken       = errs.NewUnauthorizedError(errs.ErrKeyAuthInvalidToken, "Invalid token")
 	ErrTokenExpired       = errs.NewUnauthorizedError(errs.ErrKeyAuthInvalidToken, "Token expired")
 	ErrUserAlreadyExists  = errs.NewBadRequestError(errs.ErrKeyAuthUserExists, "User with this email already exists")
+	ErrInvalidEmail       = errs.NewBadRequestError(errs.ErrKeyAuthInvalidEmail, "Email address is not valid")
+	ErrSessionNotFound    = errs.NewNotFoundError(errs.ErrKeyAuthSessionNotFound, "Session not found")
 )
 
-func NewAuthService(queries *db.Queries, jwtSecret []byte, logger *logger.Logger) *AuthService {
+// NewAuthServiceFromConfig creates an auth service that signs and verifies tokens with cfg.JWTSecret,
+// or with the JWT_KEYS key set when one is configured (JWT_SECRET then verifies tokens without a kid).
+// It fails fast instead of handing out tokens signed with an empty key. uploadService is the
+// configured upload service DeleteAccount removes files with; nil leaves them on disk.
+func NewAuthServiceFromConfig(cfg *config.Config, queries *db.Queries, userCache cache.Cache, uploadService *uploads.UploadService, logger *logger.Logger) (*AuthService, error) {
+	if cfg.JWTSecret == "" {
+		return nil, errors.New("JWT_SECRET is required to create the auth service")
+	}
+	passwords, err := NewPasswordHasher(cfg.PasswordHasher)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.JWTKeys == "" {
+		service := NewAuthService(queries, userCache, []byte(cfg.JWTSecret), logger)
+		service.passwords = passwords
+		service.useUploads(uploadService)
+		return service, nil
+	}
+
+	secrets, err := config.ParseJWTKeys(cfg.JWTKeys)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string][]byte, len(secrets))
+	for kid, secret := range secrets {
+		keys[kid] = []byte(secret)
+	}
+	keySet, err := NewJWTKeySet(cfg.JWTActiveKID, keys, []byte(cfg.JWTSecret))
+	if err != nil {
+		return nil, err
+	}
+	service := NewAuthServiceWithKeys(queries, userCache, keySet, logger)
+	service.passwords = passwords
+	service.useUploads(uploadService)
+	return service, nil
+}
+
+// NewAuthService creates an auth service signing tokens with a single secret and no kid,
+// hashing passwords with bcrypt. userCache may be nil to always read users from the database.
+func NewAuthService(queries *db.Queries, userCache cache.Cache, jwtSecret []byte, logger *logger.Logger) *AuthService {
+	return NewAuthServiceWithKeys(queries, userCache, singleKeySet(jwtSecret), logger)
+}
+
+// NewAuthServiceWithKeys creates an auth service that signs and verifies tokens with a rotating key set
+func NewAuthServiceWithKeys(queries *db.Queries, userCache cache.Cache, jwtKeys *JWTKeySet, logger *logger.Logger) *AuthService {
+	passwords, _ := NewPasswordHasher(HasherBcrypt)
 	return &AuthService{
 		queries:   queries,
-		jwtSecret: jwtSecret,
+		cache:     userCache,
+		jwtKeys:   jwtKeys,
 		logger:    logger,
+		passwords: passwords,
+		// Without an upload folder only the rows are deleted, files stay on disk
+		uploads:  uploads.NewUploadService(queries, uploads.DefaultUploadConfig("", "")),
+		examples: example.NewExampleService(queries),
 	}
 }
 
+// useUploads swaps in the configured upload service, keeping the row-only default when nil
+func (s *AuthService) useUploads(uploadService *uploads.UploadService) {
+	if uploadService != nil {
+		s.uploads = uploadService
+	}
+}
+
+// UserCacheKey returns the cache key GetUserFromContext stores a user under
+func UserCacheKey(userID int32) string {
+	return fmt.Sprintf("user:%d", userID)
+}
+
 // Register creates a new user account
 func (s *AuthService) Register(ctx context.Context, req RegisterRequest) (*TokenPair, *db.User, error) {
 	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	hashedPassword, err := s.passwords.Hash(req.Password)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	// Create user (let DB enforce uniqueness to avoid race conditions)
-	user, err := s.queries.CreateUser(ctx, db.CreateUserParams{
-		Email:    req.Email,
-		Name:     req.Name,
-		Password: string(hashedPassword),
+	// Create the user and their first refresh token together, so a failure
+	// never leaves an account behind that the client has no tokens for
+	var user db.User
+	var tokenPair *TokenPair
+	err = db.WithTx(ctx, s.queries.TxBeginner(), func(q *db.Queries) error {
+		user, err = createUser(ctx, q, req, hashedPassword)
+		if err != nil {
+			return err
+		}
+
+		// Generate token pair
+		tokenPair, err = s.generateTokenPair(ctx, q, user)
+		if err != nil {
+			return fmt.Errorf("failed to generate tokens: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
-		// Map unique violations to a stable error
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
-			return nil, nil, ErrUserAlreadyExists
+		return nil, nil, err
+	}
+
+	return tokenPair, &user, nil
+}
+
+// CreateUser creates an account with the given roles without issuing tokens, for
+// operators bootstrapping users from the CLI. Empty roles keep DefaultRole.
+func (s *AuthService) CreateUser(ctx context.Context, req RegisterRequest, roles []string) (*db.User, error) {
+	hashedPassword, err := s.passwords.Hash(req.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	var user db.User
+	err = db.WithTx(ctx, s.queries.TxBeginner(), func(q *db.Queries) error {
+		user, err = createUser(ctx, q, req, hashedPassword)
+		if err != nil || len(roles) == 0 {
+			return err
 		}
-		// Fallback for driver/driver-text wrapped errors
-		msg := err.Error()
-		if strings.Contains(msg, "SQLSTATE 23505") || strings.Contains(msg, "duplicate key value") {
-			return nil, nil, ErrUserAlreadyExists
+
+		user, err = q.UpdateUserRoles(ctx, db.UpdateUserRolesParams{ID: user.ID, Roles: roles})
+		if err != nil {
+			return fmt.Errorf("failed to set user roles: %w", err)
 		}
-		return nil, nil, fmt.Errorf("failed to create user: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Generate token pair
-	tokenPair, err := s.generateTokenPair(ctx, user)
+	user.Password = ""
+	return &user, nil
+}
+
+// createUser inserts the user through q, which may be transaction-scoped. Hash the password
+// before starting the transaction, hashing is slow enough to matter while it holds a connection.
+func createUser(ctx context.Context, q *db.Queries, req RegisterRequest, hashedPassword string) (db.User, error) {
+	// Let the DB enforce uniqueness to avoid race conditions
+	user, err := q.CreateUser(ctx, db.CreateUserParams{
+		Email:    req.Email,
+		Name:     req.Name,
+		Password: hashedPassword,
+	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
+		// Map unique violations to a stable error
+		if isUniqueViolation(err) {
+			return db.User{}, ErrUserAlreadyExists
+		}
+		return db.User{}, fmt.Errorf("failed to create user: %w", err)
 	}
-
-	return tokenPair, &user, nil
+	return user, nil
 }
 
 // Login authenticates a user and returns tokens
@@ -106,13 +252,15 @@ func (s *AuthService) Login(ctx context.Context, req LoginRequest) (*TokenPair,
 	}
 
 	// Verify password
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
-	if err != nil {
+	if !s.verifyPassword(ctx, req.Password, user.Password) {
 		return nil, nil, ErrInvalidCredentials
 	}
+	if s.passwords.NeedsRehash(user.Password) {
+		s.rehashPassword(ctx, user.ID, req.Password)
+	}
 
 	// Generate token pair
-	tokenPair, err := s.generateTokenPair(ctx, user)
+	tokenPair, err := s.generateTokenPair(ctx, s.queries, user)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to generate tokens: %w", err)
 	}
@@ -120,40 +268,87 @@ func (s *AuthService) Login(ctx context.Context, req LoginRequest) (*TokenPair,
 	return tokenPair, &user, nil
 }
 
-// RefreshToken generates a new token pair using a refresh token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
-	// Get refresh token from database
-	dbToken, err := s.queries.GetRefreshToken(ctx, refreshToken)
+// verifyPassword reports whether password matches the stored hash. A hash that can't be
+// checked (corrupt, or an unknown algorithm) is logged and treated as a mismatch.
+func (s *AuthService) verifyPassword(ctx context.Context, password, hash string) bool {
+	ok, err := s.passwords.Verify(password, hash)
 	if err != nil {
-		return nil, ErrInvalidToken
+		s.logger.ErrorContext(ctx, "Failed to verify password hash", "error", err)
+		return false
 	}
+	return ok
+}
 
-	// Get user
-	user, err := s.queries.GetUserByID(ctx, dbToken.UserID)
+// rehashPassword replaces a hash made by another algorithm (or with other parameters) with
+// one from PASSWORD_HASHER, using the password the user just logged in with. Failures are
+// only logged, the old hash keeps working and the upgrade is retried on the next login.
+func (s *AuthService) rehashPassword(ctx context.Context, userID int32, password string) {
+	hashedPassword, err := s.passwords.Hash(password)
+	if err == nil {
+		err = s.queries.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{ID: userID, Password: hashedPassword})
+	}
 	if err != nil {
-		return nil, ErrUserNotFound
+		s.logger.WarnContext(ctx, "Failed to rehash password", "error", err, "user_id", userID)
 	}
+}
 
-	// Revoke old refresh token
-	err = s.queries.RevokeRefreshToken(ctx, refreshToken)
+// IsEmailAvailable reports whether no account is registered with email
+func (s *AuthService) IsEmailAvailable(ctx context.Context, email string) (bool, error) {
+	_, err := s.queries.GetUserByEmail(ctx, email)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return true, nil
+	}
 	if err != nil {
-		// Log error but continue
+		return false, errs.WrapInternal(errs.ErrKeyInternalError, "failed to look up email", err)
 	}
+	return false, nil
+}
+
+// RefreshToken generates a new token pair using a refresh token
+// Revoking the old token and storing the new one happen in one transaction,
+// so a failure leaves the old token usable instead of locking the client out
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*TokenPair, error) {
+	var tokenPair *TokenPair
+	err := db.WithTx(ctx, s.queries.TxBeginner(), func(q *db.Queries) error {
+		// Get refresh token from database
+		dbToken, err := q.GetRefreshToken(ctx, refreshToken)
+		if err != nil {
+			return ErrInvalidToken
+		}
+
+		// Get user
+		user, err := q.GetUserByID(ctx, dbToken.UserID)
+		if err != nil {
+			return ErrUserNotFound
+		}
 
-	// Generate new token pair
-	tokenPair, err := s.generateTokenPair(ctx, user)
+		// Revoke old refresh token
+		if err := q.RevokeRefreshToken(ctx, refreshToken); err != nil {
+			return fmt.Errorf("failed to revoke refresh token: %w", err)
+		}
+
+		// Generate new token pair
+		tokenPair, err = s.generateTokenPair(ctx, q, user)
+		if err != nil {
+			return fmt.Errorf("failed to generate tokens: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate tokens: %w", err)
+		return nil, err
 	}
 
 	return tokenPair, nil
 }
 
-func (s *AuthService) generateTokenPair(ctx context.Context, user db.User) (*TokenPair, error) {
+// generateTokenPair signs an access token and stores a new refresh token through q,
+// which may be transaction-scoped
+func (s *AuthService) generateTokenPair(ctx context.Context, q *db.Queries, user db.User) (*TokenPair, error) {
 	// Generate access token (7 days)
 	accessClaims := &middleware.Claims{
 		UserID: user.ID,
 		Email:  user.Email,
+		Roles:  user.Roles,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(7 * 24 * time.Hour)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -161,7 +356,7 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user db.User) (*Tok
 	}
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(s.jwtSecret)
+	accessTokenString, err := s.jwtKeys.sign(accessToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to sign access token: %w", err)
 	}
@@ -173,12 +368,19 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user db.User) (*Tok
 	}
 	refreshTokenString := hex.EncodeToString(refreshTokenBytes)
 
-	// Store refresh token in database
+	// Store refresh token in database, with the client it was issued to when known
+	client := clientInfoFromContext(ctx)
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
 	expiresAt := time.Now().Add(30 * 24 * time.Hour)
-	_, err = s.queries.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+	_, err = q.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
 		UserID:    user.ID,
 		Token:     refreshTokenString,
 		ExpiresAt: pgtype.Timestamp{Time: expiresAt, Valid: true},
+		UserAgent: nullableText(userAgent),
+		IpAddress: nullableText(client.IPAddress),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to store refresh token: %w", err)
@@ -190,13 +392,9 @@ func (s *AuthService) generateTokenPair(ctx context.Context, user db.User) (*Tok
 	}, nil
 }
 
+// VerifyJWT validates an access token with the key named by its kid header
 func (s *AuthService) VerifyJWT(tokenString string) (*jwt.Token, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &middleware.Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenString, &middleware.Claims{}, s.jwtKeys.verificationKey)
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
@@ -208,10 +406,190 @@ func (s *AuthService) VerifyJWT(tokenString string) (*jwt.Token, error) {
 	return token, nil
 }
 
+// GetUserFromContext loads the authenticated user, served from the cache for up to userCacheTTL.
+// The password hash is never cached, so the returned user always has an empty Password.
 func (s *AuthService) GetUserFromContext(ctx context.Context, userID int32) (*db.User, error) {
-	user, err := s.queries.GetUserByID(ctx, userID)
+	load := func() (interface{}, error) {
+		user, err := s.queries.GetUserByID(ctx, userID)
+		if err != nil {
+			return nil, ErrUserNotFound
+		}
+		user.Password = ""
+		return user, nil
+	}
+
+	if s.cache == nil {
+		user, err := load()
+		if err != nil {
+			return nil, err
+		}
+		dbUser := user.(db.User)
+		return &dbUser, nil
+	}
+
+	var user db.User
+	if err := s.cache.Remember(ctx, UserCacheKey(userID), userCacheTTL, load, &user); err != nil {
+		if errs.IsDomainError(err) {
+			return nil, err
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to cache user", err)
+	}
+	return &user, nil
+}
+
+// UpdateProfile changes the user's name and email. Taking an email that belongs to
+// another account returns ErrUserAlreadyExists.
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int32, name, email string) (*db.User, error) {
+	user, err := s.queries.UpdateUserProfile(ctx, db.UpdateUserProfileParams{
+		ID:    userID,
+		Email: email,
+		Name:  name,
+	})
 	if err != nil {
-		return nil, ErrUserNotFound
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		if isUniqueViolation(err) {
+			return nil, ErrUserAlreadyExists
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to update profile", err)
 	}
+
+	s.forgetUser(ctx, userID)
+	user.Password = ""
 	return &user, nil
 }
+
+// ChangePassword replaces the user's password after checking the current one, and revokes
+// every refresh token so sessions on other devices have to log in again
+func (s *AuthService) ChangePassword(ctx context.Context, userID int32, currentPassword, newPassword string) error {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to get user", err)
+	}
+
+	if !s.verifyPassword(ctx, currentPassword, user.Password) {
+		return ErrInvalidCredentials
+	}
+
+	hashedPassword, err := s.passwords.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return db.WithTx(ctx, s.queries.TxBeginner(), func(q *db.Queries) error {
+		if err := q.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+			ID:       userID,
+			Password: hashedPassword,
+		}); err != nil {
+			return errs.WrapInternal(errs.ErrKeyInternalError, "failed to update password", err)
+		}
+		if err := q.RevokeAllUserRefreshTokens(ctx, userID); err != nil {
+			return errs.WrapInternal(errs.ErrKeyInternalError, "failed to revoke refresh tokens", err)
+		}
+		return nil
+	})
+}
+
+// ListSessions returns the user's active sessions, one per refresh token that is neither
+// revoked nor expired, newest first
+func (s *AuthService) ListSessions(ctx context.Context, userID int32) ([]db.RefreshToken, error) {
+	sessions, err := s.queries.ListRefreshTokensByUser(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list sessions", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the user's refresh tokens, so it can no longer be refreshed.
+// Access tokens already issued for it stay valid until they expire.
+// Returns ErrSessionNotFound if the session isn't active or belongs to another user
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID int32) error {
+	revoked, err := s.queries.RevokeRefreshTokenByID(ctx, db.RevokeRefreshTokenByIDParams{
+		ID:     sessionID,
+		UserID: userID,
+	})
+	if err != nil {
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to revoke session", err)
+	}
+	if revoked == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// DeleteAccount permanently deletes the user after checking their password. Uploads, examples,
+// refresh tokens and the user row are deleted in one transaction; the uploaded files are
+// removed from disk afterwards, so a failed removal leaves an orphaned file, never a row
+// pointing at a missing one.
+func (s *AuthService) DeleteAccount(ctx context.Context, userID int32, password string) error {
+	user, err := s.queries.GetUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to get user", err)
+	}
+
+	if !s.verifyPassword(ctx, password, user.Password) {
+		return ErrInvalidCredentials
+	}
+
+	// The upload and example services join this transaction through WithTx
+	var paths []string
+	err = s.queries.InTx(ctx, func(q *db.Queries) error {
+		var err error
+		if paths, err = s.uploads.WithTx(q).DeleteUserUploads(ctx, userID); err != nil {
+			return err
+		}
+		if err := s.examples.WithTx(q).DeleteUserExamples(ctx, userID); err != nil {
+			return err
+		}
+		if err := q.DeleteRefreshTokensByUserID(ctx, userID); err != nil {
+			return errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete refresh tokens", err)
+		}
+		if err := q.DeleteUser(ctx, userID); err != nil {
+			return errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete user", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.forgetUser(ctx, userID)
+	s.removeUploadFiles(ctx, userID, paths)
+	return nil
+}
+
+// removeUploadFiles deletes the files of a deleted account's uploads; failures are logged
+// since the rows are gone
+func (s *AuthService) removeUploadFiles(ctx context.Context, userID int32, paths []string) {
+	if err := s.uploads.RemoveFiles(paths); err != nil {
+		s.logger.WarnContext(ctx, "Failed to remove files of deleted account", "error", err, "user_id", userID)
+	}
+}
+
+// forgetUser drops a cached user after their profile changes
+func (s *AuthService) forgetUser(ctx context.Context, userID int32) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Forget(ctx, UserCacheKey(userID)); err != nil {
+		s.logger.WarnContext(ctx, "Failed to invalidate cached user", "error", err, "user_id", userID)
+	}
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation {
+		return true
+	}
+	// Fallback for driver/driver-text wrapped errors
+	msg := err.Error()
+	return strings.Contains(msg, "SQLSTATE 23505") || strings.Contains(msg, "duplicate key value")
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxUserAgentLength bounds the stored user agent, clients control it and may send anything
+const maxUserAgentLength = 512
+
+// ClientInfo describes the client a session was started from
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type clientInfoContextKey struct{}
+
+// WithClientInfo returns a copy of ctx carrying info. Refresh tokens issued with it are
+// stored with the user agent and IP address; without it both are left NULL, so callers
+// outside an HTTP request need not set it.
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoContextKey{}, info)
+}
+
+// clientInfoFromContext returns the client stored in ctx, or a zero ClientInfo when there is none
+func clientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoContextKey{}).(ClientInfo)
+	return info
+}
+
+// nullableText maps an empty string to NULL
+func nullableText(value string) pgtype.Text {
+	return pgtype.Text{String: value, Valid: value != ""}
+}
@@ -1,11 +1,15 @@
 package auth
 
 import (
+	"app/internal"
+	"app/internal/db"
 	"app/internal/errs"
 	"app/internal/logger"
 	"app/internal/middleware"
+	"context"
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -41,9 +45,9 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	tokenPair, user, err := h.service.Register(c.Request.Context(), req)
+	tokenPair, user, err := h.service.Register(clientContext(c), req)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to register user", "error", err, "email", req.Email)
+		h.logger.FailureContext(c.Request.Context(), "Failed to register user", err, "email", req.Email)
 
 		switch err {
 		case ErrUserAlreadyExists:
@@ -58,7 +62,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
 		User: UserResponse{
-			ID:    user.ID,
+			ID:    internal.JSONID(user.ID),
 			Email: user.Email,
 			Name:  user.Name,
 		},
@@ -87,9 +91,9 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	tokenPair, user, err := h.service.Login(c.Request.Context(), req)
+	tokenPair, user, err := h.service.Login(clientContext(c), req)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to login", "error", err, "email", req.Email)
+		h.logger.FailureContext(c.Request.Context(), "Failed to login", err, "email", req.Email)
 
 		switch err {
 		case ErrInvalidCredentials:
@@ -104,7 +108,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		AccessToken:  tokenPair.AccessToken,
 		RefreshToken: tokenPair.RefreshToken,
 		User: UserResponse{
-			ID:    user.ID,
+			ID:    internal.JSONID(user.ID),
 			Email: user.Email,
 			Name:  user.Name,
 		},
@@ -133,9 +137,9 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	tokenPair, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
+	tokenPair, err := h.service.RefreshToken(clientContext(c), req.RefreshToken)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to refresh token", "error", err)
+		h.logger.FailureContext(c.Request.Context(), "Failed to refresh token", err)
 
 		switch err {
 		case ErrInvalidToken:
@@ -156,6 +160,34 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	c.JSON(http.StatusOK, RefreshTokenDataResponse{Data: response})
 }
 
+// EmailAvailable checks whether an email address is free to register
+//	@Summary		Check email availability
+//	@Description	Check whether an account already uses the given email. Rate limited per client IP.
+//	@Tags			auth
+//	@Produce		json
+//	@Param			email	query		string	true	"Email address"
+//	@Success		200		{object}	EmailAvailableDataResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		429		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/email-available [get]
+func (h *AuthHandler) EmailAvailable(c *gin.Context) {
+	var req EmailAvailableRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		errs.RespondWithError(c, ErrInvalidEmail)
+		return
+	}
+
+	available, err := h.service.IsEmailAvailable(c.Request.Context(), req.Email)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to check email availability", err)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, EmailAvailableDataResponse{Data: EmailAvailableResponse{Available: available}})
+}
+
 // GetMe returns the current authenticated user's information
 //	@Summary		Get current user info
 //	@Description	Get information about the currently authenticated user
@@ -180,13 +212,59 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 
 	user, err := h.service.GetUserFromContext(c.Request.Context(), userIDInt32)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to get user", "error", err, "user_id", userIDInt32)
+		h.logger.FailureContext(c.Request.Context(), "Failed to get user", err, "user_id", userIDInt32)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := UserResponse{
+		ID:    internal.JSONID(user.ID),
+		Email: user.Email,
+		Name:  user.Name,
+	}
+
+	c.JSON(http.StatusOK, UserDataResponse{Data: response})
+}
+
+// UpdateMe updates the current authenticated user's name and email
+//	@Summary		Update current user
+//	@Description	Update the name and email of the currently authenticated user
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		UpdateProfileRequest	true	"Profile update request"
+//	@Success		200		{object}	UserDataResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/me [put]
+func (h *AuthHandler) UpdateMe(c *gin.Context) {
+	userIDInt32, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		if errors.Is(err, middleware.ErrUserNotAuthenticated) {
+			errs.RespondWithUnauthorized(c, "Unauthorized")
+		} else {
+			errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid user ID format")
+		}
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	user, err := h.service.UpdateProfile(c.Request.Context(), userIDInt32, req.Name, req.Email)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to update profile", err, "user_id", userIDInt32)
 		errs.RespondWithError(c, err)
 		return
 	}
 
 	response := UserResponse{
-		ID:    user.ID,
+		ID:    internal.JSONID(user.ID),
 		Email: user.Email,
 		Name:  user.Name,
 	}
@@ -194,6 +272,161 @@ func (h *AuthHandler) GetMe(c *gin.Context) {
 	c.JSON(http.StatusOK, UserDataResponse{Data: response})
 }
 
+// ChangePassword changes the current authenticated user's password
+//	@Summary		Change password
+//	@Description	Change the password of the currently authenticated user and log out all other sessions
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		ChangePasswordRequest	true	"Change password request"
+//	@Success		200		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/change-password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	userIDInt32, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		if errors.Is(err, middleware.ErrUserNotAuthenticated) {
+			errs.RespondWithUnauthorized(c, "Unauthorized")
+		} else {
+			errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid user ID format")
+		}
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	if err := h.service.ChangePassword(c.Request.Context(), userIDInt32, req.CurrentPassword, req.NewPassword); err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to change password", err, "user_id", userIDInt32)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	var response MessageResponse
+	response.Data.Message = "Password changed successfully"
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteMe permanently deletes the current authenticated user's account
+//	@Summary		Delete account
+//	@Description	Permanently delete the current user together with their uploads, examples and sessions. The current password confirms the deletion.
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		DeleteAccountRequest	true	"Delete account request"
+//	@Success		200		{object}	MessageResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/auth/me [delete]
+func (h *AuthHandler) DeleteMe(c *gin.Context) {
+	userIDInt32, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		if errors.Is(err, middleware.ErrUserNotAuthenticated) {
+			errs.RespondWithUnauthorized(c, "Unauthorized")
+		} else {
+			errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid user ID format")
+		}
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	if err := h.service.DeleteAccount(c.Request.Context(), userIDInt32, req.Password); err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to delete account", err, "user_id", userIDInt32)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Account deleted", "user_id", userIDInt32)
+
+	var response MessageResponse
+	response.Data.Message = "Account deleted successfully"
+	c.JSON(http.StatusOK, response)
+}
+
+// ListSessions lists the current user's active sessions
+//	@Summary		List sessions
+//	@Description	List the active sessions of the current user, one per refresh token that is neither revoked nor expired, newest first
+//	@Tags			auth
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	SessionsListResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to list sessions", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := make([]SessionResponse, len(sessions))
+	for i := range sessions {
+		response[i] = toSessionResponse(&sessions[i])
+	}
+
+	c.JSON(http.StatusOK, SessionsListResponse{Data: response})
+}
+
+// RevokeSession revokes one of the current user's sessions
+//	@Summary		Revoke session
+//	@Description	Revoke a session so its refresh token can no longer be used. Access tokens already issued for it stay valid until they expire.
+//	@Tags			auth
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int	true	"Session ID"
+//	@Success		200	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	sessionID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid session ID")
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), userID, int32(sessionID)); err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Session revoked", "user_id", userID, "session_id", sessionID)
+
+	var response MessageResponse
+	response.Data.Message = "Session revoked successfully"
+	c.JSON(http.StatusOK, response)
+}
+
 // Logout logs out the current user
 //	@Summary		Logout user
 //	@Description	Logout the currently authenticated user
@@ -209,3 +442,22 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	response.Data.Message = "Logged out successfully"
 	c.JSON(http.StatusOK, response)
 }
+
+func toSessionResponse(token *db.RefreshToken) SessionResponse {
+	return SessionResponse{
+		ID:        token.ID,
+		UserAgent: token.UserAgent.String,
+		IPAddress: token.IpAddress.String,
+		CreatedAt: internal.FormatTimestamp(token.CreatedAt),
+		ExpiresAt: internal.FormatTimestamp(token.ExpiresAt),
+	}
+}
+
+// clientContext returns the request context carrying the client's user agent and IP,
+// so the refresh tokens issued for the request record where the session started
+func clientContext(c *gin.Context) context.Context {
+	return WithClientInfo(c.Request.Context(), ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTKeySet holds the HMAC keys access tokens are signed and verified with.
+// New tokens are signed with the active key and carry its ID in the "kid" header;
+// a token is verified with the key its kid names, so tokens signed with a previous
+// key stay valid for as long as that key is listed. Tokens without a kid are
+// verified with the legacy key, if one is set.
+type JWTKeySet struct {
+	activeKID string
+	keys      map[string][]byte
+	legacy    []byte
+}
+
+// NewJWTKeySet creates a key set signing with keys[activeKID]. legacy verifies tokens
+// issued before key IDs were introduced and may be nil.
+func NewJWTKeySet(activeKID string, keys map[string][]byte, legacy []byte) (*JWTKeySet, error) {
+	if _, ok := keys[activeKID]; !ok {
+		return nil, fmt.Errorf("active JWT key %q is not in the key set", activeKID)
+	}
+	return &JWTKeySet{activeKID: activeKID, keys: keys, legacy: legacy}, nil
+}
+
+// singleKeySet signs and verifies kid-less tokens with one secret
+func singleKeySet(secret []byte) *JWTKeySet {
+	return &JWTKeySet{legacy: secret}
+}
+
+var errUnknownKID = errors.New("unknown JWT key id")
+
+// sign signs the token with the active key, setting its kid header
+func (k *JWTKeySet) sign(token *jwt.Token) (string, error) {
+	if k.activeKID == "" {
+		return token.SignedString(k.legacy)
+	}
+	token.Header["kid"] = k.activeKID
+	return token.SignedString(k.keys[k.activeKID])
+}
+
+// verificationKey is the jwt.Keyfunc picking the key named by the token's kid
+func (k *JWTKeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+
+	kid, hasKID := token.Header["kid"]
+	if !hasKID {
+		if k.legacy == nil {
+			return nil, errUnknownKID
+		}
+		return k.legacy, nil
+	}
+
+	id, _ := kid.(string)
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, errUnknownKID
+	}
+	return key, nil
+}
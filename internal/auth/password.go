@@ -0,0 +1,238 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes passwords and checks them against stored hashes. Hashes carry
+// their algorithm as a prefix ("$2a$" for bcrypt, "$argon2id$" for argon2id), so a
+// stored hash can always be verified by the algorithm that produced it.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	// Verify reports whether password matches hash. A mismatch is (false, nil),
+	// an error means the hash itself couldn't be used.
+	Verify(password, hash string) (bool, error)
+}
+
+// Supported PASSWORD_HASHER values
+const (
+	HasherBcrypt   = "bcrypt"
+	HasherArgon2id = "argon2id"
+)
+
+// ErrUnknownPasswordHash is returned when a stored hash has no supported algorithm prefix
+var ErrUnknownPasswordHash = errors.New("unknown password hash format")
+
+// BcryptHasher hashes with bcrypt, the default algorithm
+type BcryptHasher struct {
+	// Cost is the bcrypt work factor, defaults to bcrypt.DefaultCost
+	Cost int
+}
+
+func (h BcryptHasher) cost() int {
+	if h.Cost == 0 {
+		return bcrypt.DefaultCost
+	}
+	return h.Cost
+}
+
+func (h BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost())
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h BcryptHasher) Verify(password, hash string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// NeedsRehash reports whether hash was made with a different cost
+func (h BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err != nil || cost != h.cost()
+}
+
+// Argon2idHasher hashes with argon2id and stores the result in the PHC string format,
+// "$argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>", so the parameters travel with the hash
+type Argon2idHasher struct {
+	// Memory in KiB, defaults to 64 MiB
+	Memory uint32
+	// Iterations defaults to 3
+	Iterations uint32
+	// Parallelism defaults to 4
+	Parallelism uint8
+}
+
+const (
+	argon2idPrefix     = "$argon2id$"
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+	// argon2idMaxMemory caps the memory (KiB) a stored hash may ask for, 4x the default,
+	// so a tampered row can't make every login attempt allocate gigabytes
+	argon2idMaxMemory = 256 * 1024
+)
+
+// argon2idParams are the parameters a hash was made with, parsed from its PHC string
+type argon2idParams struct {
+	memory      uint32
+	iterations  uint32
+	parallelism uint8
+	salt        []byte
+	key         []byte
+}
+
+func (h Argon2idHasher) params() argon2idParams {
+	p := argon2idParams{memory: h.Memory, iterations: h.Iterations, parallelism: h.Parallelism}
+	if p.memory == 0 {
+		p.memory = 64 * 1024
+	}
+	if p.iterations == 0 {
+		p.iterations = 3
+	}
+	if p.parallelism == 0 {
+		p.parallelism = 4
+	}
+	return p
+}
+
+func (h Argon2idHasher) Hash(password string) (string, error) {
+	p := h.params()
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+	key := argon2.IDKey([]byte(password), salt, p.iterations, p.memory, p.parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version,
+		p.memory, p.iterations, p.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (h Argon2idHasher) Verify(password, hash string) (bool, error) {
+	p, err := parseArgon2idHash(hash)
+	if err != nil {
+		return false, err
+	}
+	key := argon2.IDKey([]byte(password), p.salt, p.iterations, p.memory, p.parallelism, uint32(len(p.key)))
+	return subtle.ConstantTimeCompare(key, p.key) == 1, nil
+}
+
+// NeedsRehash reports whether hash was made with different parameters
+func (h Argon2idHasher) NeedsRehash(hash string) bool {
+	stored, err := parseArgon2idHash(hash)
+	if err != nil {
+		return true
+	}
+	want := h.params()
+	return stored.memory != want.memory || stored.iterations != want.iterations || stored.parallelism != want.parallelism
+}
+
+func parseArgon2idHash(hash string) (argon2idParams, error) {
+	var p argon2idParams
+	parts := strings.Split(strings.TrimPrefix(hash, argon2idPrefix), "$")
+	if !strings.HasPrefix(hash, argon2idPrefix) || len(parts) != 4 {
+		return p, ErrUnknownPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil {
+		return p, fmt.Errorf("invalid argon2id version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &p.memory, &p.iterations, &p.parallelism); err != nil {
+		return p, fmt.Errorf("invalid argon2id parameters: %w", err)
+	}
+	// argon2.IDKey panics on zero parallelism, and zero memory or iterations is no hash at all
+	if p.memory == 0 || p.iterations == 0 || p.parallelism == 0 {
+		return p, fmt.Errorf("invalid argon2id parameters m=%d,t=%d,p=%d", p.memory, p.iterations, p.parallelism)
+	}
+	if p.memory > argon2idMaxMemory {
+		return p, fmt.Errorf("argon2id memory %d KiB exceeds the %d KiB limit", p.memory, argon2idMaxMemory)
+	}
+
+	var err error
+	if p.salt, err = base64.RawStdEncoding.DecodeString(parts[2]); err != nil {
+		return p, fmt.Errorf("invalid argon2id salt: %w", err)
+	}
+	if p.key, err = base64.RawStdEncoding.DecodeString(parts[3]); err != nil || len(p.key) == 0 {
+		return p, errors.New("invalid argon2id key")
+	}
+	return p, nil
+}
+
+// MultiHasher hashes new passwords with the configured algorithm and verifies hashes made
+// by any supported one, so changing PASSWORD_HASHER doesn't lock existing users out.
+// NeedsRehash tells the caller when to upgrade a hash after a successful login.
+type MultiHasher struct {
+	current string
+	hashers map[string]PasswordHasher
+}
+
+// NewPasswordHasher returns a MultiHasher hashing with the named algorithm, bcrypt when name is empty
+func NewPasswordHasher(name string) (*MultiHasher, error) {
+	if name == "" {
+		name = HasherBcrypt
+	}
+	hashers := map[string]PasswordHasher{
+		HasherBcrypt:   BcryptHasher{},
+		HasherArgon2id: Argon2idHasher{},
+	}
+	if _, ok := hashers[name]; !ok {
+		return nil, fmt.Errorf("unknown password hasher %q, expected %s or %s", name, HasherBcrypt, HasherArgon2id)
+	}
+	return &MultiHasher{current: name, hashers: hashers}, nil
+}
+
+// Hash hashes password with the configured algorithm
+func (h *MultiHasher) Hash(password string) (string, error) {
+	return h.hashers[h.current].Hash(password)
+}
+
+// Verify checks password with the algorithm named by the hash prefix
+func (h *MultiHasher) Verify(password, hash string) (bool, error) {
+	algorithm := hashAlgorithm(hash)
+	if algorithm == "" {
+		return false, ErrUnknownPasswordHash
+	}
+	return h.hashers[algorithm].Verify(password, hash)
+}
+
+// NeedsRehash reports whether hash was made by another algorithm, or by the configured
+// one with other parameters
+func (h *MultiHasher) NeedsRehash(hash string) bool {
+	if hashAlgorithm(hash) != h.current {
+		return true
+	}
+	rehasher, ok := h.hashers[h.current].(interface{ NeedsRehash(string) bool })
+	return ok && rehasher.NeedsRehash(hash)
+}
+
+// hashAlgorithm names the algorithm a stored hash was made with, "" when it isn't recognised
+func hashAlgorithm(hash string) string {
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		return HasherArgon2id
+	case strings.HasPrefix(hash, "$2a$"), strings.HasPrefix(hash, "$2b$"), strings.HasPrefix(hash, "$2y$"):
+		return HasherBcrypt
+	}
+	return ""
+}
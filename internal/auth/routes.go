@@ -2,24 +2,38 @@ package auth
 
 import (
 	"app/internal/middleware"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Email availability lookups per client IP per minute, low enough to make scraping for registered addresses impractical
+const emailAvailableRequestsPerMinute = 10
+
 func RegisterRoutes(r *gin.RouterGroup, handler *AuthHandler, authService *AuthService) {
+	emailLimiter := middleware.NewRateLimiter(emailAvailableRequestsPerMinute, time.Minute)
+
 	// Public routes (no authentication required)
 	auth := r.Group("/auth")
+	auth.Use(middleware.RequireJSON())
 	{
 		auth.POST("/register", handler.Register)
 		auth.POST("/login", handler.Login)
 		auth.POST("/refresh", handler.RefreshToken)
+		auth.GET("/email-available", middleware.RateLimit(emailLimiter), handler.EmailAvailable)
 	}
 
 	// Protected routes (require user authentication)
 	userAuth := r.Group("/auth")
 	userAuth.Use(middleware.UserAuthMiddleware(authService))
+	userAuth.Use(middleware.RequireJSON())
 	{
 		userAuth.GET("/me", handler.GetMe)
+		userAuth.PUT("/me", handler.UpdateMe)
+		userAuth.DELETE("/me", handler.DeleteMe)
+		userAuth.POST("/change-password", handler.ChangePassword)
 		userAuth.POST("/logout", handler.Logout)
+		userAuth.GET("/sessions", handler.ListSessions)
+		userAuth.DELETE("/sessions/:id", handler.RevokeSession)
 	}
 }
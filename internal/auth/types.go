@@ -1,10 +1,12 @@
 package auth
 
+import "app/internal"
+
 // UserResponse represents user information
 type UserResponse struct {
-	ID    int32  `json:"id"`
-	Email string `json:"email"`
-	Name  string `json:"name"`
+	ID    internal.JSONID `json:"id"`
+	Email string          `json:"email"`
+	Name  string          `json:"name"`
 }
 
 // RegisterResponse represents the response structure for register endpoint
@@ -32,6 +34,20 @@ type RefreshTokenResponse struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
+// EmailAvailableResponse tells whether an email can be used to register
+type EmailAvailableResponse struct {
+	Available bool `json:"available"`
+}
+
+// SessionResponse represents an active session; the refresh token itself is never returned
+type SessionResponse struct {
+	ID        int32              `json:"id"`
+	UserAgent string             `json:"user_agent,omitempty"`
+	IPAddress string             `json:"ip_address,omitempty"`
+	CreatedAt internal.Timestamp `json:"created_at"`
+	ExpiresAt internal.Timestamp `json:"expires_at"`
+}
+
 // ErrorResponse represents error response structure
 type ErrorResponse struct {
 	Error string `json:"error"`
@@ -63,3 +79,13 @@ type MessageResponse struct {
 type UserDataResponse struct {
 	Data UserResponse `json:"data"`
 }
+
+// EmailAvailableDataResponse wraps the email availability result in data field
+type EmailAvailableDataResponse struct {
+	Data EmailAvailableResponse `json:"data"`
+}
+
+// SessionsListResponse wraps the session list in data field
+type SessionsListResponse struct {
+	Data []SessionResponse `json:"data"`
+}
@@ -0,0 +1,43 @@
+// Package buildinfo holds version details injected at build time, e.g.
+//
+//	go build -ldflags "-X app/internal/buildinfo.Version=v1.2.0 -X app/internal/buildinfo.Commit=$(git rev-parse --short HEAD)"
+//
+// The defaults apply to go run and untagged builds.
+package buildinfo
+
+import "runtime"
+
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get returns the build details of the running binary
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// LogAttrs returns the build details as key/value pairs for startup log lines
+func LogAttrs() []any {
+	info := Get()
+	return []any{
+		"build_version", info.Version,
+		"commit", info.Commit,
+		"build_time", info.BuildTime,
+		"go_version", info.GoVersion,
+	}
+}
@@ -12,11 +12,16 @@ import (
 type Cache interface {
 	Get(ctx context.Context, key string, dest interface{}) error
 	Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+	// SetNX stores value only if key is absent, atomically, and reports whether it did
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
 	Delete(ctx context.Context, key string) error
 	Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error), dest interface{}) error
 	Forget(ctx context.Context, key string) error
 	Flush(ctx context.Context) error
+	FlushPrefix(ctx context.Context, prefix string) error
 	Has(ctx context.Context, key string) (bool, error)
+	// Ping reports whether the cache backend is reachable
+	Ping(ctx context.Context) error
 }
 
 // RedisCache implements Cache interface using Redis
@@ -59,6 +64,16 @@ func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl
 	return c.client.Set(ctx, c.key(key), data, ttl).Err()
 }
 
+// SetNX stores a value with TTL only if the key doesn't exist yet (SET NX)
+func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	return c.client.SetNX(ctx, c.key(key), data, ttl).Result()
+}
+
 // Delete removes a key from cache
 func (c *RedisCache) Delete(ctx context.Context, key string) error {
 	return c.client.Del(ctx, c.key(key)).Err()
@@ -115,6 +130,25 @@ func (c *RedisCache) Flush(ctx context.Context) error {
 	return nil
 }
 
+// FlushPrefix removes every entry whose key starts with prefix (e.g. all pages of a cached list)
+func (c *RedisCache) FlushPrefix(ctx context.Context, prefix string) error {
+	iter := c.client.Scan(ctx, 0, c.key(prefix)+"*", 100).Iterator()
+
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if len(keys) > 0 {
+		return c.client.Del(ctx, keys...).Err()
+	}
+
+	return nil
+}
+
 // Has checks if a key exists in cache
 func (c *RedisCache) Has(ctx context.Context, key string) (bool, error) {
 	result := c.client.Exists(ctx, c.key(key))
@@ -122,6 +156,22 @@ func (c *RedisCache) Has(ctx context.Context, key string) (bool, error) {
 	return count > 0, err
 }
 
+// Ping checks that Redis answers a PING
+func (c *RedisCache) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// CountKeys counts the keys under the cache prefix. It scans the keyspace, so keep it off hot paths.
+func (c *RedisCache) CountKeys(ctx context.Context) (int64, error) {
+	iter := c.client.Scan(ctx, 0, c.prefix+"*", 100).Iterator()
+
+	var count int64
+	for iter.Next(ctx) {
+		count++
+	}
+	return count, iter.Err()
+}
+
 // key adds the prefix to the key
 func (c *RedisCache) key(key string) string {
 	return c.prefix + key
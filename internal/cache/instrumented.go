@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Observer is notified of every cache lookup made through an InstrumentedCache
+type Observer interface {
+	CacheHit()
+	CacheMiss()
+}
+
+// InstrumentedCache wraps a Cache and reports Get and Remember lookups to an Observer.
+// Everything else is passed straight through to the wrapped cache.
+type InstrumentedCache struct {
+	Cache
+	observer Observer
+}
+
+// NewInstrumentedCache wraps inner so its lookups are reported to observer
+func NewInstrumentedCache(inner Cache, observer Observer) *InstrumentedCache {
+	return &InstrumentedCache{
+		Cache:    inner,
+		observer: observer,
+	}
+}
+
+// Get retrieves a value and records a hit, or a miss when the key is absent
+func (c *InstrumentedCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := c.Cache.Get(ctx, key, dest)
+	switch {
+	case err == nil:
+		c.observer.CacheHit()
+	case errors.Is(err, ErrKeyNotFound):
+		c.observer.CacheMiss()
+	}
+	return err
+}
+
+// Remember goes through Get so the lookup is counted, then fills the cache on a miss
+func (c *InstrumentedCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error), dest interface{}) error {
+	err := c.Get(ctx, key, dest)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+
+	value, err := callback()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Cache.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dest)
+}
+
+// CountKeys passes through to the wrapped cache, so a StatsCache on top can still report keys
+func (c *InstrumentedCache) CountKeys(ctx context.Context) (int64, error) {
+	counter, ok := c.Cache.(KeyCounter)
+	if !ok {
+		return 0, errors.ErrUnsupported
+	}
+	return counter.CountKeys(ctx)
+}
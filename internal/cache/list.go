@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Params are the query parameters a cached list depends on, e.g. page and page size
+type Params map[string]interface{}
+
+// ListKey returns the cache key of a list in namespace for params: the namespace followed
+// by the parameters sorted by name, "cities:list:page=1:size=20", or "cities:list:all"
+// without parameters. Every key of the namespace starts with "namespace:".
+func ListKey(namespace string, params Params) string {
+	if len(params) == 0 {
+		return namespace + ":all"
+	}
+
+	parts := make([]string, 0, len(params)+1)
+	parts = append(parts, namespace)
+	for _, name := range slices.Sorted(maps.Keys(params)) {
+		parts = append(parts, fmt.Sprintf("%s=%v", name, params[name]))
+	}
+	return strings.Join(parts, ":")
+}
+
+// CachedList is cache-aside for list queries: results are cached per set of Params under
+// one namespace for a fixed TTL, and Invalidate drops every cached set after a mutation.
+// Lists sharing a namespace are invalidated together.
+type CachedList[T any] struct {
+	cache     Cache
+	namespace string
+	ttl       time.Duration
+}
+
+// NewCachedList creates a list cache storing results of type T under namespace for ttl
+func NewCachedList[T any](cache Cache, namespace string, ttl time.Duration) *CachedList[T] {
+	return &CachedList[T]{
+		cache:     cache,
+		namespace: namespace,
+		ttl:       ttl,
+	}
+}
+
+// Key returns the cache key for params, see ListKey
+func (l *CachedList[T]) Key(params Params) string {
+	return ListKey(l.namespace, params)
+}
+
+// Get returns the list cached for params, calling load and caching its result on a miss.
+// Errors from load are returned unchanged and nothing is cached for them.
+func (l *CachedList[T]) Get(ctx context.Context, params Params, load func() (T, error)) (T, error) {
+	var result T
+	err := l.cache.Remember(ctx, l.Key(params), l.ttl, func() (interface{}, error) {
+		return load()
+	}, &result)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return result, nil
+}
+
+// Invalidate drops every cached list in the namespace
+func (l *CachedList[T]) Invalidate(ctx context.Context) error {
+	return l.cache.FlushPrefix(ctx, l.namespace+":")
+}
@@ -0,0 +1,174 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MemoryCache implements Cache interface in process memory.
+// Values are stored JSON-encoded so behaviour matches RedisCache; useful for tests and single-instance setups.
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time // zero means no expiry
+}
+
+func (e memoryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// NewMemoryCache creates a new in-memory cache instance
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryEntry),
+	}
+}
+
+// Get retrieves a value from cache and unmarshals it to dest
+func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok || entry.expired(time.Now()) {
+		return ErrKeyNotFound
+	}
+
+	return json.Unmarshal(entry.data, dest)
+}
+
+// Set stores a value in cache with TTL (0 means no expiry)
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	entry := memoryEntry{data: data}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	c.mu.Lock()
+	c.entries[key] = entry
+	c.mu.Unlock()
+
+	return nil
+}
+
+// SetNX stores a value with TTL only if the key is absent or expired
+func (c *MemoryCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	entry := memoryEntry{data: data}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if existing, ok := c.entries[key]; ok && !existing.expired(now) {
+		return false, nil
+	}
+	c.entries[key] = entry
+	return true, nil
+}
+
+// Delete removes a key from cache
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.entries, key)
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Remember gets a value from cache or stores it if it doesn't exist
+func (c *MemoryCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error), dest interface{}) error {
+	err := c.Get(ctx, key, dest)
+	if err == nil {
+		return nil
+	}
+	if err != ErrKeyNotFound {
+		return err
+	}
+
+	value, err := callback()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	return c.Get(ctx, key, dest)
+}
+
+// Forget is an alias for Delete (Laravel-style)
+func (c *MemoryCache) Forget(ctx context.Context, key string) error {
+	return c.Delete(ctx, key)
+}
+
+// Flush clears all cache entries
+func (c *MemoryCache) Flush(ctx context.Context) error {
+	c.mu.Lock()
+	c.entries = make(map[string]memoryEntry)
+	c.mu.Unlock()
+
+	return nil
+}
+
+// FlushPrefix removes every entry whose key starts with prefix
+func (c *MemoryCache) FlushPrefix(ctx context.Context, prefix string) error {
+	c.mu.Lock()
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(c.entries, key)
+		}
+	}
+	c.mu.Unlock()
+
+	return nil
+}
+
+// Has checks if a key exists in cache
+func (c *MemoryCache) Has(ctx context.Context, key string) (bool, error) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	return ok && !entry.expired(time.Now()), nil
+}
+
+// Ping always succeeds, the cache lives in process memory
+func (c *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
+
+// CountKeys returns the number of entries that haven't expired
+func (c *MemoryCache) CountKeys(ctx context.Context) (int64, error) {
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var count int64
+	for _, entry := range c.entries {
+		if !entry.expired(now) {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,142 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Operations whose lookups StatsCache counts
+const (
+	OperationGet      = "get"
+	OperationHas      = "has"
+	OperationRemember = "remember"
+)
+
+// KeyCounter is implemented by caches that can count their live keys
+type KeyCounter interface {
+	CountKeys(ctx context.Context) (int64, error)
+}
+
+// OperationStats counts the hits and misses of one operation
+type OperationStats struct {
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+}
+
+// CacheStats is a snapshot of the counters of a StatsCache
+type CacheStats struct {
+	Hits       int64                     `json:"hits"`
+	Misses     int64                     `json:"misses"`
+	Operations map[string]OperationStats `json:"operations"`
+	// Keys is the number of live keys, nil when the wrapped cache can't count them
+	Keys *int64 `json:"keys"`
+}
+
+// StatsCache wraps a Cache and counts hits and misses of Get, Has and Remember for debugging.
+// It works over any Cache, including an InstrumentedCache; other calls pass straight through.
+type StatsCache struct {
+	Cache
+	mu         sync.Mutex
+	operations map[string]*OperationStats
+}
+
+// NewStatsCache wraps inner so its lookups are counted
+func NewStatsCache(inner Cache) *StatsCache {
+	return &StatsCache{
+		Cache:      inner,
+		operations: make(map[string]*OperationStats),
+	}
+}
+
+// Get retrieves a value and records a hit, or a miss when the key is absent
+func (c *StatsCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := c.Cache.Get(ctx, key, dest)
+	c.recordLookup(OperationGet, err)
+	return err
+}
+
+// Has checks if a key exists and records a hit or a miss
+func (c *StatsCache) Has(ctx context.Context, key string) (bool, error) {
+	exists, err := c.Cache.Has(ctx, key)
+	if err == nil {
+		c.record(OperationHas, exists)
+	}
+	return exists, err
+}
+
+// Remember counts its lookup under "remember", not "get", then fills the cache on a miss
+func (c *StatsCache) Remember(ctx context.Context, key string, ttl time.Duration, callback func() (interface{}, error), dest interface{}) error {
+	err := c.Cache.Get(ctx, key, dest)
+	c.recordLookup(OperationRemember, err)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		return err
+	}
+
+	value, err := callback()
+	if err != nil {
+		return err
+	}
+
+	if err := c.Cache.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, dest)
+}
+
+// Stats returns the counters so far and, when the wrapped cache is a KeyCounter, its key count
+func (c *StatsCache) Stats() CacheStats {
+	c.mu.Lock()
+	stats := CacheStats{Operations: make(map[string]OperationStats, len(c.operations))}
+	for name, operation := range c.operations {
+		stats.Operations[name] = *operation
+		stats.Hits += operation.Hits
+		stats.Misses += operation.Misses
+	}
+	c.mu.Unlock()
+
+	if counter, ok := c.Cache.(KeyCounter); ok {
+		if keys, err := counter.CountKeys(context.Background()); err == nil {
+			stats.Keys = &keys
+		}
+	}
+
+	return stats
+}
+
+// recordLookup records a hit for a nil error and a miss for ErrKeyNotFound, other errors aren't counted
+func (c *StatsCache) recordLookup(operation string, err error) {
+	switch {
+	case err == nil:
+		c.record(operation, true)
+	case errors.Is(err, ErrKeyNotFound):
+		c.record(operation, false)
+	}
+}
+
+func (c *StatsCache) record(operation string, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats, ok := c.operations[operation]
+	if !ok {
+		stats = &OperationStats{}
+		c.operations[operation] = stats
+	}
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+}
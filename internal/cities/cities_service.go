@@ -0,0 +1,244 @@
+package cities
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"app/internal"
+	"app/internal/cache"
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/logger"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrCityNameRequired  = errs.NewBadRequestError(errs.ErrKeyCityNameRequired, "City name is required")
+	ErrCityAlreadyExists = errs.NewBadRequestError(errs.ErrKeyCityAlreadyExists, "A city with this name already exists")
+	ErrInvalidPage       = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid page parameter")
+	ErrInvalidPageSize   = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid page size parameter")
+	ErrInvalidLimit      = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid limit parameter")
+)
+
+const (
+	// listCacheNamespace groups every cached list so a mutation can drop them all at once
+	listCacheNamespace = "cities:list"
+	listCacheTTL       = time.Minute
+
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// likeEscaper escapes LIKE wildcards so user input only ever matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// PaginatedCitiesResult represents paginated city results from service layer
+type PaginatedCitiesResult struct {
+	Data     []db.City `json:"data"`
+	Total    int64     `json:"total"`
+	Page     int32     `json:"page"`
+	PageSize int32     `json:"page_size"`
+}
+
+// CitiesService contains business logic for city operations
+type CitiesService struct {
+	queries *db.Queries
+	logger  *logger.Logger
+	// Cities rarely change, so the full list and each page are cached until the next mutation
+	all   *cache.CachedList[[]db.City]
+	pages *cache.CachedList[PaginatedCitiesResult]
+}
+
+// NewCitiesService creates a new cities service
+func NewCitiesService(queries *db.Queries, cacheService cache.Cache, logger *logger.Logger) *CitiesService {
+	return &CitiesService{
+		queries: queries,
+		logger:  logger,
+		all:     cache.NewCachedList[[]db.City](cacheService, listCacheNamespace, listCacheTTL),
+		pages:   cache.NewCachedList[PaginatedCitiesResult](cacheService, listCacheNamespace, listCacheTTL),
+	}
+}
+
+// ListCacheKey returns the cache key for one page of the cities list
+func ListCacheKey(page, pageSize int32) string {
+	return cache.ListKey(listCacheNamespace, pageCacheParams(page, pageSize))
+}
+
+// AllCacheKey returns the cache key for the full cities list
+func AllCacheKey() string {
+	return cache.ListKey(listCacheNamespace, nil)
+}
+
+func pageCacheParams(page, pageSize int32) cache.Params {
+	return cache.Params{"page": page, "size": pageSize}
+}
+
+// CreateCity adds a city. Names are unique, so creating one that exists returns ErrCityAlreadyExists;
+// use EnsureCity to get the existing city instead.
+func (s *CitiesService) CreateCity(ctx context.Context, name string) (*db.City, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrCityNameRequired
+	}
+
+	// Let the unique constraint catch duplicates, a lookup first would race with concurrent creates
+	city, err := s.queries.CreateCity(ctx, name)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return nil, ErrCityAlreadyExists
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to create city", err)
+	}
+
+	// The city is committed, so a stale list is no reason to report the create as failed
+	if err := s.invalidateListCache(ctx); err != nil {
+		s.logger.FailureContext(ctx, "Failed to invalidate cities cache after create", err, "city_id", city.ID)
+	}
+	return &city, nil
+}
+
+// EnsureCity returns the city with the given name, creating it if it doesn't exist yet.
+// created reports whether this call inserted the row. Safe to call concurrently:
+// the insert is a no-op on conflict and the existing row is read back instead.
+func (s *CitiesService) EnsureCity(ctx context.Context, name string) (*db.City, bool, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, false, ErrCityNameRequired
+	}
+
+	city, err := s.queries.InsertCityIfNotExists(ctx, name)
+	if err == nil {
+		// The city is committed, so a stale list is no reason to report the insert as failed
+		if err := s.invalidateListCache(ctx); err != nil {
+			s.logger.FailureContext(ctx, "Failed to invalidate cities cache after insert", err, "city_id", city.ID)
+		}
+		return &city, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, false, errs.WrapInternal(errs.ErrKeyInternalError, "failed to insert city", err)
+	}
+
+	city, err = s.queries.GetCityByName(ctx, name)
+	if err != nil {
+		return nil, false, errs.WrapInternal(errs.ErrKeyInternalError, "failed to get existing city", err)
+	}
+
+	return &city, false, nil
+}
+
+// ListCities retrieves every city ordered by name, cached until a city is added
+func (s *CitiesService) ListCities(ctx context.Context) ([]db.City, error) {
+	cities, err := s.all.Get(ctx, nil, func() ([]db.City, error) {
+		cities, err := s.queries.ListCities(ctx)
+		if err != nil {
+			return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list cities", err)
+		}
+		if cities == nil {
+			return []db.City{}, nil
+		}
+		return cities, nil
+	})
+	if err != nil {
+		return nil, cacheError(err)
+	}
+
+	return cities, nil
+}
+
+// SearchCities returns up to limit cities whose name contains query (case-insensitive), ordered by name
+func (s *CitiesService) SearchCities(ctx context.Context, query string, limit int32) ([]db.City, error) {
+	if limit < 1 || limit > MaxSearchLimit {
+		return nil, ErrInvalidLimit
+	}
+
+	cities, err := s.queries.SearchCities(ctx, db.SearchCitiesParams{
+		Query:      likeEscaper.Replace(strings.TrimSpace(query)),
+		MaxResults: limit,
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to search cities", err)
+	}
+
+	if cities == nil {
+		return []db.City{}, nil
+	}
+
+	return cities, nil
+}
+
+// ListCitiesPaginated retrieves a page of cities ordered by name, caching each page/size combination
+func (s *CitiesService) ListCitiesPaginated(ctx context.Context, page, pageSize int32) (*PaginatedCitiesResult, error) {
+	if page < 1 {
+		return nil, ErrInvalidPage
+	}
+	if pageSize < 1 || pageSize > 100 {
+		return nil, ErrInvalidPageSize
+	}
+
+	result, err := s.pages.Get(ctx, pageCacheParams(page, pageSize), func() (PaginatedCitiesResult, error) {
+		return s.loadCitiesPage(ctx, page, pageSize)
+	})
+	if err != nil {
+		return nil, cacheError(err)
+	}
+
+	return &result, nil
+}
+
+func (s *CitiesService) loadCitiesPage(ctx context.Context, page, pageSize int32) (PaginatedCitiesResult, error) {
+	offset, err := internal.PaginationOffset(page, pageSize)
+	if err != nil {
+		return PaginatedCitiesResult{}, err
+	}
+
+	cities, err := s.queries.ListCitiesPaginated(ctx, db.ListCitiesPaginatedParams{
+		Limit:  pageSize,
+		Offset: offset,
+	})
+	if err != nil {
+		return PaginatedCitiesResult{}, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list cities", err)
+	}
+
+	total, err := s.queries.CountCities(ctx)
+	if err != nil {
+		return PaginatedCitiesResult{}, errs.WrapInternal(errs.ErrKeyInternalError, "failed to count cities", err)
+	}
+
+	if cities == nil {
+		cities = []db.City{}
+	}
+
+	return PaginatedCitiesResult{
+		Data:     cities,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// invalidateListCache drops the cached full list and every cached page; call after any city mutation
+func (s *CitiesService) invalidateListCache(ctx context.Context) error {
+	if err := s.pages.Invalidate(ctx); err != nil {
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to invalidate cities cache", err)
+	}
+	return nil
+}
+
+// cacheError passes through domain errors from loading a list and wraps cache failures
+func cacheError(err error) error {
+	if errs.IsDomainError(err) {
+		return err
+	}
+	return errs.WrapInternal(errs.ErrKeyInternalError, "failed to cache cities list", err)
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.UniqueViolation
+}
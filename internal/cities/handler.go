@@ -0,0 +1,165 @@
+package cities
+
+import (
+	"net/http"
+
+	"app/internal"
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/logger"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *CitiesService
+	logger  *logger.Logger
+}
+
+func NewHandler(service *CitiesService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListCities lists cities with pagination, or filtered by a name search
+//
+//	@Summary		List cities
+//	@Description	List cities ordered by name with pagination. With q and/or limit, returns cities whose name contains q (case-insensitive). all=true returns every city unpaginated (for dropdowns). Search and all=true respond with CitiesListResponse
+//	@Tags			cities
+//	@Produce		json
+//	@Param			page		query		int		false	"Page number (default: 1)"					default(1)
+//	@Param			page_size	query		int		false	"Page size (default: 20, min: 1, max: 100)"	default(20)
+//	@Param			all			query		bool	false	"Return all cities without pagination"
+//	@Param			q			query		string	false	"Case-insensitive name search"
+//	@Param			limit		query		int		false	"Max results when searching (default: 20, max: 100)"
+//	@Param			If-None-Match	header		string	false	"ETag of a previous response"
+//	@Success		200			{object}	PaginatedCitiesResponse
+//	@Header			200			{string}	ETag	"Weak ETag of the listed cities"
+//	@Success		304			"Cities unchanged since the ETag in If-None-Match"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/cities [get]
+func (h *Handler) ListCities(c *gin.Context) {
+	var query ListCitiesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	_, hasQ := c.GetQuery("q")
+	_, hasLimit := c.GetQuery("limit")
+
+	switch {
+	case hasQ || hasLimit:
+		limit := query.Limit
+		if limit == 0 {
+			limit = DefaultSearchLimit
+		}
+		cities, err := h.service.SearchCities(c.Request.Context(), query.Q, limit)
+		if err != nil {
+			h.logger.FailureContext(c.Request.Context(), "Failed to search cities", err, "q", query.Q)
+			errs.RespondWithError(c, err)
+			return
+		}
+		if internal.NotModified(c, citiesETag(cities)) {
+			return
+		}
+		c.JSON(http.StatusOK, CitiesListResponse{Data: toCityResponses(cities)})
+
+	case query.All:
+		cities, err := h.service.ListCities(c.Request.Context())
+		if err != nil {
+			h.logger.FailureContext(c.Request.Context(), "Failed to list cities", err)
+			errs.RespondWithError(c, err)
+			return
+		}
+		if internal.NotModified(c, citiesETag(cities)) {
+			return
+		}
+		c.JSON(http.StatusOK, CitiesListResponse{Data: toCityResponses(cities)})
+
+	default:
+		pagination, err := middleware.GetPaginationParamsFromContext(c, 20, 1, 100)
+		if err != nil {
+			errs.RespondWithError(c, err)
+			return
+		}
+
+		result, err := h.service.ListCitiesPaginated(c.Request.Context(), pagination.Page, pagination.PageSize)
+		if err != nil {
+			h.logger.FailureContext(c.Request.Context(), "Failed to list cities", err)
+			errs.RespondWithError(c, err)
+			return
+		}
+		// The total goes into the ETag too, it changes the pagination block
+		if internal.NotModified(c, citiesETag(result.Data, result.Total)) {
+			return
+		}
+
+		c.JSON(http.StatusOK, PaginatedCitiesResponse{
+			Data:       toCityResponses(result.Data),
+			Pagination: internal.NewPaginationMeta(result.Total, result.Page, result.PageSize),
+		})
+	}
+}
+
+// CreateCity adds a city
+//
+//	@Summary		Create city
+//	@Description	Add a city (admin only). City names are unique
+//	@Tags			cities
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		CreateCityRequest	true	"City details"
+//	@Success		201		{object}	CityDataResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/cities [post]
+func (h *Handler) CreateCity(c *gin.Context) {
+	var req CreateCityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	city, err := h.service.CreateCity(c.Request.Context(), req.Name)
+	if err != nil {
+		if errs.ExtractDomainError(err).Status >= http.StatusInternalServerError {
+			h.logger.FailureContext(c.Request.Context(), "Failed to create city", err, "name", req.Name)
+		}
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, CityDataResponse{Data: toCityResponses([]db.City{*city})[0]})
+}
+
+// citiesETag identifies a list of cities by the rows on it and when each last changed
+func citiesETag(cities []db.City, extra ...interface{}) string {
+	parts := make([]interface{}, 0, len(extra)+2*len(cities))
+	parts = append(parts, extra...)
+	for _, city := range cities {
+		parts = append(parts, city.ID, city.UpdatedAt.Time.UnixNano())
+	}
+	return internal.WeakETag(parts...)
+}
+
+// toCityResponses converts db.City rows to response types
+func toCityResponses(cities []db.City) []CityResponse {
+	response := make([]CityResponse, len(cities))
+	for i, city := range cities {
+		response[i] = CityResponse{
+			ID:        city.ID,
+			Name:      city.Name,
+			CreatedAt: internal.FormatTimestamp(city.CreatedAt),
+			UpdatedAt: internal.FormatTimestamp(city.UpdatedAt),
+		}
+	}
+	return response
+}
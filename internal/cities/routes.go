@@ -0,0 +1,27 @@
+package cities
+
+import (
+	"app/internal"
+	"app/internal/middleware"
+)
+
+// RegisterRoutes registers city routes
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier) {
+	service := NewCitiesService(app.Queries, app.Cache, app.Logger)
+	handler := NewHandler(service, app.Logger)
+
+	// Public routes - cities are reference data
+	cities := app.Api.Group("/cities")
+	{
+		cities.GET("", handler.ListCities)
+	}
+
+	// Admin routes (require authentication and admin role)
+	admin := app.Api.Group("/cities")
+	admin.Use(middleware.UserAuthMiddleware(authService))
+	admin.Use(middleware.RequireRole(middleware.RoleAdmin))
+	admin.Use(middleware.RequireJSON())
+	{
+		admin.POST("", handler.CreateCity)
+	}
+}
@@ -0,0 +1,46 @@
+package cities
+
+import (
+	"app/internal"
+)
+
+// ListCitiesQuery represents the optional search parameters for listing cities
+type ListCitiesQuery struct {
+	Q     string `form:"q"`
+	Limit int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+	All   bool   `form:"all"`
+}
+
+// CreateCityRequest represents the request to create a city
+type CreateCityRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// CityResponse represents city information
+type CityResponse struct {
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	CreatedAt internal.Timestamp `json:"created_at"`
+	UpdatedAt internal.Timestamp `json:"updated_at"`
+}
+
+// CityDataResponse wraps a single city in response
+type CityDataResponse struct {
+	Data CityResponse `json:"data"`
+}
+
+// CitiesListResponse wraps cities list in response
+type CitiesListResponse struct {
+	Data []CityResponse `json:"data"`
+}
+
+// PaginatedCitiesResponse wraps paginated cities in response
+type PaginatedCitiesResponse struct {
+	Data       []CityResponse          `json:"data"`
+	Pagination internal.PaginationMeta `json:"pagination"`
+}
+
+// ErrorResponse represents error response structure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -6,31 +6,61 @@ import (
 	"time"
 
 	"app/config"
+	"app/internal/logger"
+	"app/internal/retry"
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewConnection(cfg *config.Config) (*pgxpool.Pool, error) {
+// NewConnection opens the primary pool. Postgres may still be starting when the app does,
+// so the first ping is retried CONNECT_MAX_ATTEMPTS times with backoff up to CONNECT_MAX_DELAY.
+func NewConnection(cfg *config.Config, log *logger.Logger) (*pgxpool.Pool, error) {
 	if cfg.DatabaseURL == "" {
 		return nil, fmt.Errorf("DATABASE_URL is required")
 	}
 
-	// Configure pgxpool
-	poolConfig, err := pgxpool.ParseConfig(cfg.DatabaseURL)
+	poolConfig, err := NewPoolConfig(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+		return nil, err
 	}
 
-	// Configure connection pool for production
-	configureConnectionPool(poolConfig)
+	return connect(poolConfig, cfg, log, "primary")
+}
+
+// NewReplicaConnection opens the read replica pool, or returns nil when REPLICA_DATABASE_URL is unset
+func NewReplicaConnection(cfg *config.Config, log *logger.Logger) (*pgxpool.Pool, error) {
+	if cfg.ReplicaDatabaseURL == "" {
+		return nil, nil
+	}
+
+	poolConfig, err := newPoolConfig(cfg.ReplicaDatabaseURL, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+
+	pool, err := connect(poolConfig, cfg, log, "replica")
+	if err != nil {
+		return nil, fmt.Errorf("replica: %w", err)
+	}
+	return pool, nil
+}
 
+func connect(poolConfig *pgxpool.Config, cfg *config.Config, log *logger.Logger, name string) (*pgxpool.Pool, error) {
 	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create connection pool: %w", err)
 	}
 
-	if err := pool.Ping(context.Background()); err != nil {
+	err = retry.Do(context.Background(), cfg.ConnectMaxAttempts, retry.ConnectBackoff(cfg.ConnectMaxDelay),
+		func(ctx context.Context) error {
+			return pool.Ping(ctx)
+		},
+		func(attempt int, delay time.Duration, err error) {
+			log.Warn("Database not reachable yet, retrying", "pool", name, "attempt", attempt, "retry_in", delay, "error", err)
+		},
+	)
+	if err != nil {
 		pool.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
@@ -38,6 +68,30 @@ func NewConnection(cfg *config.Config) (*pgxpool.Pool, error) {
 	return pool, nil
 }
 
+// NewPoolConfig parses DATABASE_URL and applies the pool settings and default statement timeout
+func NewPoolConfig(cfg *config.Config) (*pgxpool.Config, error) {
+	return newPoolConfig(cfg.DatabaseURL, cfg)
+}
+
+func newPoolConfig(databaseURL string, cfg *config.Config) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse database URL: %w", err)
+	}
+
+	// Configure connection pool for production
+	configureConnectionPool(poolConfig)
+
+	// Postgres cancels any statement running longer than this, so a slow query
+	// can't hold a connection forever. Override per call with WithQueryTimeout or
+	// SetLocalStatementTimeout.
+	if cfg.DBQueryTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = StatementTimeout(cfg.DBQueryTimeout)
+	}
+
+	return poolConfig, nil
+}
+
 // configureConnectionPool sets up production-ready connection pool settings
 func configureConnectionPool(config *pgxpool.Config) {
 	// Maximum number of connections in the pool
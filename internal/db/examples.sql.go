@@ -23,13 +23,51 @@ func (q *Queries) CountExamplesForUser(ctx context.Context, userID int32) (int64
 	return count, err
 }
 
+const countExamplesForUserCreatedBetween = `-- name: CountExamplesForUserCreatedBetween :one
+SELECT COUNT(*) FROM examples
+WHERE user_id = $1
+  AND ($2::timestamp IS NULL OR created_at >= $2::timestamp)
+  AND ($3::timestamp IS NULL OR created_at < $3::timestamp)
+`
+
+type CountExamplesForUserCreatedBetweenParams struct {
+	UserID        int32            `db:"user_id" json:"user_id"`
+	CreatedAfter  pgtype.Timestamp `db:"created_after" json:"created_after"`
+	CreatedBefore pgtype.Timestamp `db:"created_before" json:"created_before"`
+}
+
+func (q *Queries) CountExamplesForUserCreatedBetween(ctx context.Context, arg CountExamplesForUserCreatedBetweenParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countExamplesForUserCreatedBetween, arg.UserID, arg.CreatedAfter, arg.CreatedBefore)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchExamplesForUser = `-- name: CountSearchExamplesForUser :one
+SELECT COUNT(*) FROM examples
+WHERE user_id = $1
+  AND to_tsvector('english', title || ' ' || coalesce(description, '')) @@ plainto_tsquery('english', $2)
+`
+
+type CountSearchExamplesForUserParams struct {
+	UserID int32  `db:"user_id" json:"user_id"`
+	Query  string `db:"query" json:"query"`
+}
+
+func (q *Queries) CountSearchExamplesForUser(ctx context.Context, arg CountSearchExamplesForUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchExamplesForUser, arg.UserID, arg.Query)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createExample = `-- name: CreateExample :one
 INSERT INTO examples (
     user_id, title, description
 ) VALUES (
     $1, $2, $3
 )
-RETURNING id, user_id, title, description, created_at, updated_at
+RETURNING id, user_id, title, description, created_at, updated_at, public
 `
 
 type CreateExampleParams struct {
@@ -48,10 +86,54 @@ func (q *Queries) CreateExample(ctx context.Context, arg CreateExampleParams) (E
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Public,
 	)
 	return i, err
 }
 
+const createExamplesBatch = `-- name: CreateExamplesBatch :many
+INSERT INTO examples (
+    user_id, title, description
+)
+SELECT $1::int, item.title, NULLIF(item.description, '')
+FROM unnest($2::text[], $3::text[]) AS item(title, description)
+RETURNING id, user_id, title, description, created_at, updated_at, public
+`
+
+type CreateExamplesBatchParams struct {
+	UserID       int32    `db:"user_id" json:"user_id"`
+	Titles       []string `db:"titles" json:"titles"`
+	Descriptions []string `db:"descriptions" json:"descriptions"`
+}
+
+func (q *Queries) CreateExamplesBatch(ctx context.Context, arg CreateExamplesBatchParams) ([]Example, error) {
+	rows, err := q.db.Query(ctx, createExamplesBatch, arg.UserID, arg.Titles, arg.Descriptions)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Example
+	for rows.Next() {
+		var i Example
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Public,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const deleteExample = `-- name: DeleteExample :exec
 DELETE FROM examples
 WHERE id = $1 AND user_id = $2
@@ -67,8 +149,18 @@ func (q *Queries) DeleteExample(ctx context.Context, arg DeleteExampleParams) er
 	return err
 }
 
+const deleteExamplesByUserID = `-- name: DeleteExamplesByUserID :exec
+DELETE FROM examples
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteExamplesByUserID(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, deleteExamplesByUserID, userID)
+	return err
+}
+
 const getExampleByID = `-- name: GetExampleByID :one
-SELECT id, user_id, title, description, created_at, updated_at FROM examples 
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples 
 WHERE id = $1 AND user_id = $2 LIMIT 1
 `
 
@@ -87,12 +179,101 @@ func (q *Queries) GetExampleByID(ctx context.Context, arg GetExampleByIDParams)
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Public,
 	)
 	return i, err
 }
 
+const getPublicExampleByID = `-- name: GetPublicExampleByID :one
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
+WHERE id = $1 AND (public OR user_id = $2::int)
+LIMIT 1
+`
+
+type GetPublicExampleByIDParams struct {
+	ID     int32       `db:"id" json:"id"`
+	UserID pgtype.Int4 `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) GetPublicExampleByID(ctx context.Context, arg GetPublicExampleByIDParams) (Example, error) {
+	row := q.db.QueryRow(ctx, getPublicExampleByID, arg.ID, arg.UserID)
+	var i Example
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Public,
+	)
+	return i, err
+}
+
+const getSharedExampleByID = `-- name: GetSharedExampleByID :one
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetSharedExampleByID(ctx context.Context, id int32) (Example, error) {
+	row := q.db.QueryRow(ctx, getSharedExampleByID, id)
+	var i Example
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Description,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Public,
+	)
+	return i, err
+}
+
+const listExamplesAfterCursor = `-- name: ListExamplesAfterCursor :many
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
+WHERE user_id = $1
+  AND ($2::int IS NULL OR id < $2::int)
+ORDER BY id DESC
+LIMIT $3
+`
+
+type ListExamplesAfterCursorParams struct {
+	UserID    int32       `db:"user_id" json:"user_id"`
+	AfterID   pgtype.Int4 `db:"after_id" json:"after_id"`
+	PageLimit int32       `db:"page_limit" json:"page_limit"`
+}
+
+func (q *Queries) ListExamplesAfterCursor(ctx context.Context, arg ListExamplesAfterCursorParams) ([]Example, error) {
+	rows, err := q.db.Query(ctx, listExamplesAfterCursor, arg.UserID, arg.AfterID, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Example
+	for rows.Next() {
+		var i Example
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Public,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listExamplesForUser = `-- name: ListExamplesForUser :many
-SELECT id, user_id, title, description, created_at, updated_at FROM examples
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
@@ -113,6 +294,58 @@ func (q *Queries) ListExamplesForUser(ctx context.Context, userID int32) ([]Exam
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Public,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listExamplesForUserCreatedBetween = `-- name: ListExamplesForUserCreatedBetween :many
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
+WHERE user_id = $1
+  AND ($2::timestamp IS NULL OR created_at >= $2::timestamp)
+  AND ($3::timestamp IS NULL OR created_at < $3::timestamp)
+ORDER BY created_at DESC
+LIMIT $4 OFFSET $5
+`
+
+type ListExamplesForUserCreatedBetweenParams struct {
+	UserID        int32            `db:"user_id" json:"user_id"`
+	CreatedAfter  pgtype.Timestamp `db:"created_after" json:"created_after"`
+	CreatedBefore pgtype.Timestamp `db:"created_before" json:"created_before"`
+	PageLimit     int32            `db:"page_limit" json:"page_limit"`
+	PageOffset    int32            `db:"page_offset" json:"page_offset"`
+}
+
+func (q *Queries) ListExamplesForUserCreatedBetween(ctx context.Context, arg ListExamplesForUserCreatedBetweenParams) ([]Example, error) {
+	rows, err := q.db.Query(ctx, listExamplesForUserCreatedBetween,
+		arg.UserID,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Example
+	for rows.Next() {
+		var i Example
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Public,
 		); err != nil {
 			return nil, err
 		}
@@ -125,7 +358,7 @@ func (q *Queries) ListExamplesForUser(ctx context.Context, userID int32) ([]Exam
 }
 
 const listExamplesForUserPaginated = `-- name: ListExamplesForUserPaginated :many
-SELECT id, user_id, title, description, created_at, updated_at FROM examples
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
 WHERE user_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
@@ -153,6 +386,57 @@ func (q *Queries) ListExamplesForUserPaginated(ctx context.Context, arg ListExam
 			&i.Description,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Public,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchExamplesForUser = `-- name: SearchExamplesForUser :many
+SELECT id, user_id, title, description, created_at, updated_at, public FROM examples
+WHERE user_id = $1
+  AND to_tsvector('english', title || ' ' || coalesce(description, '')) @@ plainto_tsquery('english', $2)
+ORDER BY ts_rank(to_tsvector('english', title || ' ' || coalesce(description, '')), plainto_tsquery('english', $2)) DESC,
+    created_at DESC
+LIMIT $3 OFFSET $4
+`
+
+type SearchExamplesForUserParams struct {
+	UserID     int32  `db:"user_id" json:"user_id"`
+	Query      string `db:"query" json:"query"`
+	PageLimit  int32  `db:"page_limit" json:"page_limit"`
+	PageOffset int32  `db:"page_offset" json:"page_offset"`
+}
+
+// The search expression must match idx_examples_search for the index to be used
+func (q *Queries) SearchExamplesForUser(ctx context.Context, arg SearchExamplesForUserParams) ([]Example, error) {
+	rows, err := q.db.Query(ctx, searchExamplesForUser,
+		arg.UserID,
+		arg.Query,
+		arg.PageLimit,
+		arg.PageOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Example
+	for rows.Next() {
+		var i Example
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Public,
 		); err != nil {
 			return nil, err
 		}
@@ -167,26 +451,29 @@ func (q *Queries) ListExamplesForUserPaginated(ctx context.Context, arg ListExam
 const updateExample = `-- name: UpdateExample :one
 UPDATE examples
 SET
-    title = $3,
-    description = $4,
+    title = $1,
+    description = $2,
+    public = COALESCE($3, public),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $2
-RETURNING id, user_id, title, description, created_at, updated_at
+WHERE id = $4 AND user_id = $5
+RETURNING id, user_id, title, description, created_at, updated_at, public
 `
 
 type UpdateExampleParams struct {
-	ID          int32       `db:"id" json:"id"`
-	UserID      int32       `db:"user_id" json:"user_id"`
 	Title       string      `db:"title" json:"title"`
 	Description pgtype.Text `db:"description" json:"description"`
+	Public      pgtype.Bool `db:"public" json:"public"`
+	ID          int32       `db:"id" json:"id"`
+	UserID      int32       `db:"user_id" json:"user_id"`
 }
 
 func (q *Queries) UpdateExample(ctx context.Context, arg UpdateExampleParams) (Example, error) {
 	row := q.db.QueryRow(ctx, updateExample,
-		arg.ID,
-		arg.UserID,
 		arg.Title,
 		arg.Description,
+		arg.Public,
+		arg.ID,
+		arg.UserID,
 	)
 	var i Example
 	err := row.Scan(
@@ -196,6 +483,7 @@ func (q *Queries) UpdateExample(ctx context.Context, arg UpdateExampleParams) (E
 		&i.Description,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Public,
 	)
 	return i, err
 }
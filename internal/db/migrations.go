@@ -0,0 +1,197 @@
+package db
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// migrationFilePattern matches goose SQL migration files, e.g. 013_create_folders_table.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.sql$`)
+
+// MigrationFile is a goose SQL migration on disk
+type MigrationFile struct {
+	Version int64
+	Name    string // File name, e.g. "013_create_folders_table.sql"
+	HasDown bool   // Whether the -- +goose Down section has any statement
+}
+
+// ReadMigrations lists the goose SQL migrations in dir ordered by version
+func ReadMigrations(dir string) ([]MigrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations dir: %w", err)
+	}
+
+	var files []MigrationFile
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		hasDown, err := migrationHasDown(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, MigrationFile{Version: version, Name: entry.Name(), HasDown: hasDown})
+	}
+
+	slices.SortFunc(files, func(a, b MigrationFile) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return files, nil
+}
+
+// migrationHasDown reports whether the Down section of a migration has a statement,
+// not just annotations and comments
+func migrationHasDown(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read migration: %w", err)
+	}
+	defer file.Close()
+
+	inDown := false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case strings.HasPrefix(line, "-- +goose Down"):
+			inDown = true
+		case strings.HasPrefix(line, "-- +goose Up"):
+			inDown = false
+		case inDown && line != "" && !strings.HasPrefix(line, "--"):
+			return true, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read migration %s: %w", path, err)
+	}
+	return false, nil
+}
+
+// MigrationStatus compares the goose version of a database with the migrations on disk
+type MigrationStatus struct {
+	DBVersion     int64
+	LatestVersion int64
+	// Migrations on disk newer than the database
+	Pending []MigrationFile
+	// Migrations that can't be rolled back
+	MissingDown []MigrationFile
+}
+
+// CheckMigrations builds the MigrationStatus of a database at dbVersion against files
+func CheckMigrations(dbVersion int64, files []MigrationFile) MigrationStatus {
+	status := MigrationStatus{DBVersion: dbVersion}
+	for _, file := range files {
+		status.LatestVersion = max(status.LatestVersion, file.Version)
+		if file.Version > dbVersion {
+			status.Pending = append(status.Pending, file)
+		}
+		if !file.HasDown {
+			status.MissingDown = append(status.MissingDown, file)
+		}
+	}
+	return status
+}
+
+// Ahead reports whether the database has a version no migration file has, i.e. a migration
+// was applied and then deleted or renamed
+func (s MigrationStatus) Ahead() bool {
+	return s.DBVersion > s.LatestVersion
+}
+
+// UpToDate reports whether the database is exactly at the latest migration
+func (s MigrationStatus) UpToDate() bool {
+	return s.DBVersion == s.LatestVersion
+}
+
+// Schema describes the public tables of a database: one entry per column and per index,
+// keyed by "column <table>.<column>" or "index <name>", with its definition as the value
+type Schema map[string]string
+
+// LoadSchema reads the Schema of the database behind db, leaving out goose's version table
+func LoadSchema(ctx context.Context, db DBTX) (Schema, error) {
+	schema := Schema{}
+
+	rows, err := db.Query(ctx, `
+		SELECT table_name, column_name, data_type, is_nullable, COALESCE(column_default, '')
+		FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name <> 'goose_db_version'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	for rows.Next() {
+		var table, column, dataType, nullable, columnDefault string
+		if err := rows.Scan(&table, &column, &dataType, &nullable, &columnDefault); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to read columns: %w", err)
+		}
+		definition := dataType
+		if nullable == "NO" {
+			definition += " NOT NULL"
+		}
+		if columnDefault != "" {
+			definition += " DEFAULT " + columnDefault
+		}
+		schema["column "+table+"."+column] = definition
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	rows, err = db.Query(ctx, `
+		SELECT indexname, indexdef
+		FROM pg_indexes
+		WHERE schemaname = 'public' AND tablename <> 'goose_db_version'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("failed to read indexes: %w", err)
+		}
+		schema["index "+name] = definition
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	return schema, nil
+}
+
+// DiffSchemas lists how actual differs from expected, one sorted line per column or index
+// that is missing, unexpected or defined differently. Nil means the schemas match.
+func DiffSchemas(expected, actual Schema) []string {
+	var diff []string
+	for key, want := range expected {
+		got, ok := actual[key]
+		switch {
+		case !ok:
+			diff = append(diff, fmt.Sprintf("missing %s (%s)", key, want))
+		case got != want:
+			diff = append(diff, fmt.Sprintf("changed %s: %s, migrations give %s", key, got, want))
+		}
+	}
+	for key, got := range actual {
+		if _, ok := expected[key]; !ok {
+			diff = append(diff, fmt.Sprintf("unexpected %s (%s)", key, got))
+		}
+	}
+	slices.Sort(diff)
+	return diff
+}
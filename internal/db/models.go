@@ -8,6 +8,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKey struct {
+	ID        int32            `db:"id" json:"id"`
+	UserID    int32            `db:"user_id" json:"user_id"`
+	Name      string           `db:"name" json:"name"`
+	Prefix    string           `db:"prefix" json:"prefix"`
+	KeyHash   string           `db:"key_hash" json:"key_hash"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type City struct {
+	ID        int32            `db:"id" json:"id"`
+	Name      string           `db:"name" json:"name"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
 type Example struct {
 	ID          int32            `db:"id" json:"id"`
 	UserID      int32            `db:"user_id" json:"user_id"`
@@ -15,6 +31,26 @@ type Example struct {
 	Description pgtype.Text      `db:"description" json:"description"`
 	CreatedAt   pgtype.Timestamp `db:"created_at" json:"created_at"`
 	UpdatedAt   pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	Public      bool             `db:"public" json:"public"`
+}
+
+type Folder struct {
+	ID        int32            `db:"id" json:"id"`
+	UserID    int32            `db:"user_id" json:"user_id"`
+	Name      string           `db:"name" json:"name"`
+	IsRoot    bool             `db:"is_root" json:"is_root"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type JobRun struct {
+	ID         int32            `db:"id" json:"id"`
+	JobName    string           `db:"job_name" json:"job_name"`
+	Status     string           `db:"status" json:"status"`
+	StartedAt  pgtype.Timestamp `db:"started_at" json:"started_at"`
+	FinishedAt pgtype.Timestamp `db:"finished_at" json:"finished_at"`
+	DurationMs pgtype.Int8      `db:"duration_ms" json:"duration_ms"`
+	Error      pgtype.Text      `db:"error" json:"error"`
 }
 
 type RefreshToken struct {
@@ -24,6 +60,8 @@ type RefreshToken struct {
 	ExpiresAt pgtype.Timestamp `db:"expires_at" json:"expires_at"`
 	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
 	IsRevoked pgtype.Bool      `db:"is_revoked" json:"is_revoked"`
+	UserAgent pgtype.Text      `db:"user_agent" json:"user_agent"`
+	IpAddress pgtype.Text      `db:"ip_address" json:"ip_address"`
 }
 
 type Upload struct {
@@ -37,6 +75,9 @@ type Upload struct {
 	MimeType         pgtype.Text      `db:"mime_type" json:"mime_type"`
 	CreatedAt        pgtype.Timestamp `db:"created_at" json:"created_at"`
 	UpdatedAt        pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	Checksum         pgtype.Text      `db:"checksum" json:"checksum"`
+	Caption          pgtype.Text      `db:"caption" json:"caption"`
+	MissingAt        pgtype.Timestamp `db:"missing_at" json:"missing_at"`
 }
 
 type User struct {
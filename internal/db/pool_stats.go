@@ -0,0 +1,46 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"app/internal/logger"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// LogPoolStats logs the connection counts of pool at debug level every interval until ctx
+// is done. avg_acquire_wait is the mean time Acquire waited during the last interval, the
+// number to watch when deciding whether the pool is too small.
+func LogPoolStats(ctx context.Context, name string, pool *pgxpool.Pool, logger *logger.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := pool.Stat()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		stat := pool.Stat()
+		var avgWait time.Duration
+		if acquires := stat.AcquireCount() - previous.AcquireCount(); acquires > 0 {
+			avgWait = (stat.AcquireDuration() - previous.AcquireDuration()) / time.Duration(acquires)
+		}
+
+		logger.DebugContext(ctx, "Database pool stats",
+			"pool", name,
+			"acquired_conns", stat.AcquiredConns(),
+			"idle_conns", stat.IdleConns(),
+			"total_conns", stat.TotalConns(),
+			"max_conns", stat.MaxConns(),
+			"acquire_count", stat.AcquireCount(),
+			"empty_acquire_count", stat.EmptyAcquireCount(),
+			"acquire_duration", stat.AcquireDuration(),
+			"avg_acquire_wait", avgWait,
+		)
+		previous = stat
+	}
+}
@@ -0,0 +1,20 @@
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (
+    user_id, name, prefix, key_hash
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetAPIKeyByHash :one
+SELECT * FROM api_keys
+WHERE key_hash = $1 LIMIT 1;
+
+-- name: ListAPIKeysByUserID :many
+SELECT * FROM api_keys
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteAPIKey :execrows
+DELETE FROM api_keys
+WHERE id = $1 AND user_id = $2;
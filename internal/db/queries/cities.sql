@@ -0,0 +1,38 @@
+-- name: GetCityByName :one
+SELECT * FROM cities
+WHERE name = $1 LIMIT 1;
+
+-- name: CreateCity :one
+INSERT INTO cities (
+    name
+) VALUES (
+    $1
+)
+RETURNING *;
+
+-- name: InsertCityIfNotExists :one
+INSERT INTO cities (
+    name
+) VALUES (
+    $1
+)
+ON CONFLICT (name) DO NOTHING
+RETURNING *;
+
+-- name: ListCitiesPaginated :many
+SELECT * FROM cities
+ORDER BY name ASC, id ASC
+LIMIT $1 OFFSET $2;
+
+-- name: CountCities :one
+SELECT COUNT(*) FROM cities;
+
+-- name: ListCities :many
+SELECT * FROM cities
+ORDER BY name ASC, id ASC;
+
+-- name: SearchCities :many
+SELECT * FROM cities
+WHERE name ILIKE '%' || sqlc.arg(query)::text || '%'
+ORDER BY name ASC, id ASC
+LIMIT sqlc.arg(max_results);
@@ -2,6 +2,15 @@
 SELECT * FROM examples 
 WHERE id = $1 AND user_id = $2 LIMIT 1;
 
+-- name: GetPublicExampleByID :one
+SELECT * FROM examples
+WHERE id = @id AND (public OR user_id = sqlc.narg('user_id')::int)
+LIMIT 1;
+
+-- name: GetSharedExampleByID :one
+SELECT * FROM examples
+WHERE id = $1 LIMIT 1;
+
 -- name: CreateExample :one
 INSERT INTO examples (
     user_id, title, description
@@ -10,19 +19,32 @@ INSERT INTO examples (
 )
 RETURNING *;
 
+-- name: CreateExamplesBatch :many
+INSERT INTO examples (
+    user_id, title, description
+)
+SELECT @user_id::int, item.title, NULLIF(item.description, '')
+FROM unnest(@titles::text[], @descriptions::text[]) AS item(title, description)
+RETURNING *;
+
 -- name: UpdateExample :one
 UPDATE examples
 SET
-    title = $3,
-    description = $4,
+    title = @title,
+    description = @description,
+    public = COALESCE(sqlc.narg('public'), public),
     updated_at = CURRENT_TIMESTAMP
-WHERE id = $1 AND user_id = $2
+WHERE id = @id AND user_id = @user_id
 RETURNING *;
 
 -- name: DeleteExample :exec
 DELETE FROM examples
 WHERE id = $1 AND user_id = $2;
 
+-- name: DeleteExamplesByUserID :exec
+DELETE FROM examples
+WHERE user_id = $1;
+
 -- name: ListExamplesForUser :many
 SELECT * FROM examples
 WHERE user_id = $1
@@ -34,6 +56,41 @@ WHERE user_id = $1
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
+-- name: ListExamplesAfterCursor :many
+SELECT * FROM examples
+WHERE user_id = @user_id
+  AND (sqlc.narg('after_id')::int IS NULL OR id < sqlc.narg('after_id')::int)
+ORDER BY id DESC
+LIMIT @page_limit;
+
 -- name: CountExamplesForUser :one
 SELECT COUNT(*) FROM examples
 WHERE user_id = $1;
+
+-- name: ListExamplesForUserCreatedBetween :many
+SELECT * FROM examples
+WHERE user_id = @user_id
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR created_at >= sqlc.narg('created_after')::timestamp)
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR created_at < sqlc.narg('created_before')::timestamp)
+ORDER BY created_at DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountExamplesForUserCreatedBetween :one
+SELECT COUNT(*) FROM examples
+WHERE user_id = @user_id
+  AND (sqlc.narg('created_after')::timestamp IS NULL OR created_at >= sqlc.narg('created_after')::timestamp)
+  AND (sqlc.narg('created_before')::timestamp IS NULL OR created_at < sqlc.narg('created_before')::timestamp);
+
+-- The search expression must match idx_examples_search for the index to be used
+-- name: SearchExamplesForUser :many
+SELECT * FROM examples
+WHERE user_id = @user_id
+  AND to_tsvector('english', title || ' ' || coalesce(description, '')) @@ plainto_tsquery('english', @query)
+ORDER BY ts_rank(to_tsvector('english', title || ' ' || coalesce(description, '')), plainto_tsquery('english', @query)) DESC,
+    created_at DESC
+LIMIT @page_limit OFFSET @page_offset;
+
+-- name: CountSearchExamplesForUser :one
+SELECT COUNT(*) FROM examples
+WHERE user_id = @user_id
+  AND to_tsvector('english', title || ' ' || coalesce(description, '')) @@ plainto_tsquery('english', @query);
@@ -0,0 +1,35 @@
+-- name: CreateFolder :one
+INSERT INTO folders (
+    user_id, name
+) VALUES (
+    $1, $2
+)
+RETURNING *;
+
+-- name: EnsureRootFolder :one
+INSERT INTO folders (
+    user_id, name, is_root
+) VALUES (
+    $1, 'root', TRUE
+)
+ON CONFLICT (user_id) WHERE is_root DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING *;
+
+-- name: GetFolderByIDAndUserID :one
+SELECT * FROM folders
+WHERE id = $1 AND user_id = $2 LIMIT 1;
+
+-- name: ListFoldersByUserID :many
+SELECT * FROM folders
+WHERE user_id = $1
+ORDER BY is_root DESC, name, id;
+
+-- name: UpdateFolderName :one
+UPDATE folders
+SET name = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: DeleteFolder :execrows
+DELETE FROM folders
+WHERE id = $1 AND user_id = $2 AND NOT is_root;
@@ -0,0 +1,19 @@
+-- name: CreateJobRun :one
+INSERT INTO job_runs (
+    job_name
+) VALUES (
+    $1
+)
+RETURNING *;
+
+-- name: FinishJobRun :one
+UPDATE job_runs
+SET status = $2, error = $3, duration_ms = $4, finished_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: ListJobRuns :many
+SELECT * FROM job_runs
+WHERE job_name = $1
+ORDER BY started_at DESC, id DESC
+LIMIT sqlc.arg(max_results);
@@ -1,8 +1,8 @@
 -- name: CreateUpload :one
 INSERT INTO uploads (
-    user_id, folder_id, type, relative_path, original_filename, file_size, mime_type
+    user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, checksum, caption
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -28,6 +28,50 @@ ORDER BY created_at DESC;
 DELETE FROM uploads
 WHERE id = $1 AND user_id = $2;
 
+-- name: DeleteUploadsByIDs :many
+DELETE FROM uploads
+WHERE user_id = @user_id AND id = ANY(@ids::int[])
+RETURNING id, relative_path;
+
+-- name: DeleteUploadsByUserID :many
+DELETE FROM uploads
+WHERE user_id = $1
+RETURNING relative_path;
+
+-- name: GetUploadByChecksum :one
+SELECT * FROM uploads
+WHERE user_id = $1 AND checksum = $2
+ORDER BY id
+LIMIT 1;
+
+-- name: CountUploadsByPath :one
+SELECT COUNT(*) FROM uploads
+WHERE relative_path = $1;
+
 -- name: GetUploadByPath :one
 SELECT * FROM uploads
 WHERE relative_path = $1 LIMIT 1;
+
+-- name: UpdateUploadFilename :one
+UPDATE uploads
+SET
+    original_filename = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: LockUserUploads :exec
+-- Held until the transaction ends, serializing a user's quota checks and shared-file
+-- lookups with their inserts and deletes
+SELECT pg_advisory_xact_lock(hashtext('uploads'), @user_id::int);
+
+-- name: MarkUploadMissing :one
+UPDATE uploads
+SET missing_at = COALESCE(missing_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING *;
+
+-- name: SumUploadSizesByUserID :one
+SELECT COUNT(*)::bigint AS file_count, COALESCE(SUM(file_size), 0)::bigint AS total_bytes
+FROM uploads
+WHERE user_id = $1;
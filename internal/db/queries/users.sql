@@ -24,12 +24,40 @@ SET
 WHERE id = $1
 RETURNING *;
 
+-- name: UpdateUserPassword :exec
+UPDATE users
+SET
+    password = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: UpdateUserRoles :one
+UPDATE users
+SET
+    roles = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET
+    email = $2,
+    name = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
+
 -- Refresh Token Queries
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
+
 -- name: CreateRefreshToken :one
 INSERT INTO refresh_tokens (
-    user_id, token, expires_at
+    user_id, token, expires_at, user_agent, ip_address
 ) VALUES (
-    $1, $2, $3
+    $1, $2, $3, $4, $5
 )
 RETURNING *;
 
@@ -43,11 +71,25 @@ UPDATE refresh_tokens
 SET is_revoked = TRUE
 WHERE token = $1;
 
+-- name: ListRefreshTokensByUser :many
+SELECT * FROM refresh_tokens
+WHERE user_id = $1 AND expires_at > NOW() AND is_revoked = FALSE
+ORDER BY created_at DESC, id DESC;
+
+-- name: RevokeRefreshTokenByID :execrows
+UPDATE refresh_tokens
+SET is_revoked = TRUE
+WHERE id = $1 AND user_id = $2 AND is_revoked = FALSE;
+
 -- name: RevokeAllUserRefreshTokens :exec
 UPDATE refresh_tokens
 SET is_revoked = TRUE
 WHERE user_id = $1;
 
--- name: DeleteExpiredRefreshTokens :exec
+-- name: DeleteExpiredRefreshTokens :execrows
 DELETE FROM refresh_tokens
 WHERE expires_at < NOW();
+
+-- name: DeleteRefreshTokensByUserID :exec
+DELETE FROM refresh_tokens
+WHERE user_id = $1;
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// readQueryPrefixes are the sqlc query name prefixes that only read data
+var readQueryPrefixes = []string{"Get", "List", "Count", "Search", "Sum"}
+
+// Router sends read-only sqlc queries to a replica and everything else to the primary.
+// A query is read-only when its "-- name:" header starts with one of readQueryPrefixes;
+// SQL without that header always goes to the primary. Transactions are begun on the
+// primary, so every query inside db.WithTx reads its own writes.
+//
+// Replicas lag behind the primary, so a read right after a write outside a transaction
+// may not see it yet.
+type Router struct {
+	primary DBTX
+	replica DBTX
+}
+
+// NewRouter routes reads to replica and writes to primary
+func NewRouter(primary, replica DBTX) *Router {
+	return &Router{primary: primary, replica: replica}
+}
+
+func (r *Router) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return r.route(sql).Exec(ctx, sql, args...)
+}
+
+func (r *Router) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return r.route(sql).Query(ctx, sql, args...)
+}
+
+func (r *Router) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return r.route(sql).QueryRow(ctx, sql, args...)
+}
+
+// Begin starts a transaction on the primary
+func (r *Router) Begin(ctx context.Context) (pgx.Tx, error) {
+	beginner, ok := r.primary.(TxBeginner)
+	if !ok {
+		return nil, ErrNoTxSupport
+	}
+	return beginner.Begin(ctx)
+}
+
+func (r *Router) route(sql string) DBTX {
+	if IsReadQuery(sql) {
+		return r.replica
+	}
+	return r.primary
+}
+
+// IsReadQuery reports whether sql is a sqlc query whose name marks it as read-only
+func IsReadQuery(sql string) bool {
+	header, ok := strings.CutPrefix(sql, "-- name: ")
+	if !ok {
+		return false
+	}
+	name, _, _ := strings.Cut(header, " ")
+	for _, prefix := range readQueryPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// StatementTimeout formats d as a Postgres statement_timeout value in milliseconds
+func StatementTimeout(d time.Duration) string {
+	return strconv.FormatInt(d.Milliseconds(), 10)
+}
+
+// WithQueryTimeout bounds the queries run with the returned context to d, for calls
+// that should give up sooner than DB_QUERY_TIMEOUT. pgx cancels the running statement
+// when the deadline passes. A non-positive d only adds cancellation.
+func WithQueryTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, d)
+}
+
+// SetLocalStatementTimeout changes statement_timeout until the surrounding transaction
+// ends, the way to give a known-slow query more time than DB_QUERY_TIMEOUT. Outside a
+// transaction it has no lasting effect. 0 disables the timeout.
+func (q *Queries) SetLocalStatementTimeout(ctx context.Context, d time.Duration) error {
+	_, err := q.db.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", StatementTimeout(d))
+	return err
+}
+
+// IsQueryTimeout reports whether err comes from a query cut off by statement_timeout
+// or by its context deadline
+func IsQueryTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.QueryCanceled
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrNoTxSupport is returned by WithTx when there is nothing to begin a transaction on
+var ErrNoTxSupport = errors.New("db: connection does not support transactions")
+
+// TxBeginner starts transactions. *pgxpool.Pool and *pgx.Conn satisfy it,
+// and so does pgx.Tx, where Begin creates a savepoint inside the outer transaction
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// TxBeginner returns the connection q runs on if it can start transactions, nil otherwise.
+// Services use it to open a transaction on whatever their queries were built with:
+// the pool in production, or the test transaction in tests.
+func (q *Queries) TxBeginner() TxBeginner {
+	if q == nil {
+		return nil
+	}
+	beginner, _ := q.db.(TxBeginner)
+	return beginner
+}
+
+// Transactor runs fn in one transaction with queries bound to it. *Queries is a Transactor
+// over whatever it was built with. To make several services' writes commit or roll back
+// together, run them in InTx and hand each one q through its WithTx method, which returns
+// a copy of the service bound to the transaction.
+type Transactor interface {
+	InTx(ctx context.Context, fn func(q *Queries) error) error
+}
+
+// InTx runs fn with WithTx on the connection q runs on. Called on transaction-scoped
+// queries, it opens a savepoint inside that transaction.
+func (q *Queries) InTx(ctx context.Context, fn func(q *Queries) error) error {
+	return WithTx(ctx, q.TxBeginner(), fn)
+}
+
+// WithTx runs fn with transaction-scoped queries. The transaction commits when fn
+// returns nil and rolls back when fn returns an error or panics; panics are re-raised
+// after the rollback.
+func WithTx(ctx context.Context, pool TxBeginner, fn func(q *Queries) error) (err error) {
+	if pool == nil {
+		return ErrNoTxSupport
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	defer func() {
+		// Roll back even if ctx was cancelled, so the connection goes back to the pool clean
+		if p := recover(); p != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+			panic(p)
+		}
+		if err != nil {
+			_ = tx.Rollback(context.WithoutCancel(ctx))
+		}
+	}()
+
+	if err = fn(New(tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -11,13 +11,25 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countUploadsByPath = `-- name: CountUploadsByPath :one
+SELECT COUNT(*) FROM uploads
+WHERE relative_path = $1
+`
+
+func (q *Queries) CountUploadsByPath(ctx context.Context, relativePath string) (int64, error) {
+	row := q.db.QueryRow(ctx, countUploadsByPath, relativePath)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createUpload = `-- name: CreateUpload :one
 INSERT INTO uploads (
-    user_id, folder_id, type, relative_path, original_filename, file_size, mime_type
+    user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, checksum, caption
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at
+RETURNING id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at
 `
 
 type CreateUploadParams struct {
@@ -28,6 +40,8 @@ type CreateUploadParams struct {
 	OriginalFilename string      `db:"original_filename" json:"original_filename"`
 	FileSize         int64       `db:"file_size" json:"file_size"`
 	MimeType         pgtype.Text `db:"mime_type" json:"mime_type"`
+	Checksum         pgtype.Text `db:"checksum" json:"checksum"`
+	Caption          pgtype.Text `db:"caption" json:"caption"`
 }
 
 func (q *Queries) CreateUpload(ctx context.Context, arg CreateUploadParams) (Upload, error) {
@@ -39,6 +53,8 @@ func (q *Queries) CreateUpload(ctx context.Context, arg CreateUploadParams) (Upl
 		arg.OriginalFilename,
 		arg.FileSize,
 		arg.MimeType,
+		arg.Checksum,
+		arg.Caption,
 	)
 	var i Upload
 	err := row.Scan(
@@ -52,6 +68,9 @@ func (q *Queries) CreateUpload(ctx context.Context, arg CreateUploadParams) (Upl
 		&i.MimeType,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
 	)
 	return i, err
 }
@@ -71,8 +90,103 @@ func (q *Queries) DeleteUpload(ctx context.Context, arg DeleteUploadParams) erro
 	return err
 }
 
+const deleteUploadsByIDs = `-- name: DeleteUploadsByIDs :many
+DELETE FROM uploads
+WHERE user_id = $1 AND id = ANY($2::int[])
+RETURNING id, relative_path
+`
+
+type DeleteUploadsByIDsParams struct {
+	UserID int32   `db:"user_id" json:"user_id"`
+	Ids    []int32 `db:"ids" json:"ids"`
+}
+
+type DeleteUploadsByIDsRow struct {
+	ID           int32  `db:"id" json:"id"`
+	RelativePath string `db:"relative_path" json:"relative_path"`
+}
+
+func (q *Queries) DeleteUploadsByIDs(ctx context.Context, arg DeleteUploadsByIDsParams) ([]DeleteUploadsByIDsRow, error) {
+	rows, err := q.db.Query(ctx, deleteUploadsByIDs, arg.UserID, arg.Ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeleteUploadsByIDsRow
+	for rows.Next() {
+		var i DeleteUploadsByIDsRow
+		if err := rows.Scan(&i.ID, &i.RelativePath); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteUploadsByUserID = `-- name: DeleteUploadsByUserID :many
+DELETE FROM uploads
+WHERE user_id = $1
+RETURNING relative_path
+`
+
+func (q *Queries) DeleteUploadsByUserID(ctx context.Context, userID int32) ([]string, error) {
+	rows, err := q.db.Query(ctx, deleteUploadsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var relative_path string
+		if err := rows.Scan(&relative_path); err != nil {
+			return nil, err
+		}
+		items = append(items, relative_path)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getUploadByChecksum = `-- name: GetUploadByChecksum :one
+SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at FROM uploads
+WHERE user_id = $1 AND checksum = $2
+ORDER BY id
+LIMIT 1
+`
+
+type GetUploadByChecksumParams struct {
+	UserID   int32       `db:"user_id" json:"user_id"`
+	Checksum pgtype.Text `db:"checksum" json:"checksum"`
+}
+
+func (q *Queries) GetUploadByChecksum(ctx context.Context, arg GetUploadByChecksumParams) (Upload, error) {
+	row := q.db.QueryRow(ctx, getUploadByChecksum, arg.UserID, arg.Checksum)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FolderID,
+		&i.Type,
+		&i.RelativePath,
+		&i.OriginalFilename,
+		&i.FileSize,
+		&i.MimeType,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
+	)
+	return i, err
+}
+
 const getUploadByID = `-- name: GetUploadByID :one
-SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at FROM uploads
+SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at FROM uploads
 WHERE id = $1 LIMIT 1
 `
 
@@ -90,12 +204,15 @@ func (q *Queries) GetUploadByID(ctx context.Context, id int32) (Upload, error) {
 		&i.MimeType,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
 	)
 	return i, err
 }
 
 const getUploadByIDAndUserID = `-- name: GetUploadByIDAndUserID :one
-SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at FROM uploads
+SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at FROM uploads
 WHERE id = $1 AND user_id = $2 LIMIT 1
 `
 
@@ -118,12 +235,15 @@ func (q *Queries) GetUploadByIDAndUserID(ctx context.Context, arg GetUploadByIDA
 		&i.MimeType,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
 	)
 	return i, err
 }
 
 const getUploadByPath = `-- name: GetUploadByPath :one
-SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at FROM uploads
+SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at FROM uploads
 WHERE relative_path = $1 LIMIT 1
 `
 
@@ -141,12 +261,15 @@ func (q *Queries) GetUploadByPath(ctx context.Context, relativePath string) (Upl
 		&i.MimeType,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
 	)
 	return i, err
 }
 
 const listUploadsByFolderID = `-- name: ListUploadsByFolderID :many
-SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at FROM uploads
+SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at FROM uploads
 WHERE folder_id = $1
 ORDER BY created_at DESC
 `
@@ -171,6 +294,9 @@ func (q *Queries) ListUploadsByFolderID(ctx context.Context, folderID int32) ([]
 			&i.MimeType,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Checksum,
+			&i.Caption,
+			&i.MissingAt,
 		); err != nil {
 			return nil, err
 		}
@@ -183,7 +309,7 @@ func (q *Queries) ListUploadsByFolderID(ctx context.Context, folderID int32) ([]
 }
 
 const listUploadsByUserID = `-- name: ListUploadsByUserID :many
-SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at FROM uploads
+SELECT id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at FROM uploads
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
@@ -208,6 +334,9 @@ func (q *Queries) ListUploadsByUserID(ctx context.Context, userID int32) ([]Uplo
 			&i.MimeType,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.Checksum,
+			&i.Caption,
+			&i.MissingAt,
 		); err != nil {
 			return nil, err
 		}
@@ -218,3 +347,101 @@ func (q *Queries) ListUploadsByUserID(ctx context.Context, userID int32) ([]Uplo
 	}
 	return items, nil
 }
+
+const lockUserUploads = `-- name: LockUserUploads :exec
+SELECT pg_advisory_xact_lock(hashtext('uploads'), $1::int)
+`
+
+// Held until the transaction ends, serializing a user's quota checks and shared-file
+// lookups with their inserts and deletes
+func (q *Queries) LockUserUploads(ctx context.Context, userID int32) error {
+	_, err := q.db.Exec(ctx, lockUserUploads, userID)
+	return err
+}
+
+const markUploadMissing = `-- name: MarkUploadMissing :one
+UPDATE uploads
+SET missing_at = COALESCE(missing_at, CURRENT_TIMESTAMP)
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at
+`
+
+type MarkUploadMissingParams struct {
+	ID     int32 `db:"id" json:"id"`
+	UserID int32 `db:"user_id" json:"user_id"`
+}
+
+func (q *Queries) MarkUploadMissing(ctx context.Context, arg MarkUploadMissingParams) (Upload, error) {
+	row := q.db.QueryRow(ctx, markUploadMissing, arg.ID, arg.UserID)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FolderID,
+		&i.Type,
+		&i.RelativePath,
+		&i.OriginalFilename,
+		&i.FileSize,
+		&i.MimeType,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
+	)
+	return i, err
+}
+
+const sumUploadSizesByUserID = `-- name: SumUploadSizesByUserID :one
+SELECT COUNT(*)::bigint AS file_count, COALESCE(SUM(file_size), 0)::bigint AS total_bytes
+FROM uploads
+WHERE user_id = $1
+`
+
+type SumUploadSizesByUserIDRow struct {
+	FileCount  int64 `db:"file_count" json:"file_count"`
+	TotalBytes int64 `db:"total_bytes" json:"total_bytes"`
+}
+
+func (q *Queries) SumUploadSizesByUserID(ctx context.Context, userID int32) (SumUploadSizesByUserIDRow, error) {
+	row := q.db.QueryRow(ctx, sumUploadSizesByUserID, userID)
+	var i SumUploadSizesByUserIDRow
+	err := row.Scan(&i.FileCount, &i.TotalBytes)
+	return i, err
+}
+
+const updateUploadFilename = `-- name: UpdateUploadFilename :one
+UPDATE uploads
+SET
+    original_filename = $3,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2
+RETURNING id, user_id, folder_id, type, relative_path, original_filename, file_size, mime_type, created_at, updated_at, checksum, caption, missing_at
+`
+
+type UpdateUploadFilenameParams struct {
+	ID               int32  `db:"id" json:"id"`
+	UserID           int32  `db:"user_id" json:"user_id"`
+	OriginalFilename string `db:"original_filename" json:"original_filename"`
+}
+
+func (q *Queries) UpdateUploadFilename(ctx context.Context, arg UpdateUploadFilenameParams) (Upload, error) {
+	row := q.db.QueryRow(ctx, updateUploadFilename, arg.ID, arg.UserID, arg.OriginalFilename)
+	var i Upload
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.FolderID,
+		&i.Type,
+		&i.RelativePath,
+		&i.OriginalFilename,
+		&i.FileSize,
+		&i.MimeType,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Checksum,
+		&i.Caption,
+		&i.MissingAt,
+	)
+	return i, err
+}
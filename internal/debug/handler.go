@@ -0,0 +1,32 @@
+package debug
+
+import (
+	"net/http"
+
+	"app/internal/cache"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	stats *cache.StatsCache
+}
+
+func NewHandler(stats *cache.StatsCache) *Handler {
+	return &Handler{stats: stats}
+}
+
+// CacheStats returns cache hit and miss counters since startup
+//
+//	@Summary		Cache stats
+//	@Description	Hits and misses per cache operation since startup, and the number of cached keys (admin only, APP_DEBUG)
+//	@Tags			debug
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	CacheStatsDataResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Router			/api/v1/debug/cache-stats [get]
+func (h *Handler) CacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, CacheStatsDataResponse{Data: h.stats.Stats()})
+}
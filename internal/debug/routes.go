@@ -0,0 +1,19 @@
+package debug
+
+import (
+	"app/internal"
+	"app/internal/cache"
+	"app/internal/middleware"
+)
+
+// RegisterRoutes registers the debug routes. They are admin only; callers register them only with APP_DEBUG.
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier, stats *cache.StatsCache) {
+	handler := NewHandler(stats)
+
+	debug := app.Api.Group("/debug")
+	debug.Use(middleware.UserAuthMiddleware(authService))
+	debug.Use(middleware.RequireRole(middleware.RoleAdmin))
+	{
+		debug.GET("/cache-stats", handler.CacheStats)
+	}
+}
@@ -0,0 +1,13 @@
+package debug
+
+import "app/internal/cache"
+
+// CacheStatsDataResponse wraps cache stats in response
+type CacheStatsDataResponse struct {
+	Data cache.CacheStats `json:"data"`
+}
+
+// ErrorResponse represents error response structure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -1,10 +1,14 @@
 package errs
 
 import (
+	"context"
 	"errors"
 	"net/http"
 )
 
+// StatusClientClosedRequest is the non-standard (nginx) status for requests the client abandoned
+const StatusClientClosedRequest = 499
+
 // DomainError represents a structured application error with an error key
 type DomainError struct {
 	Key     string                 // Error key (e.g., "examples.not_found")
@@ -63,6 +67,27 @@ func ExtractDomainError(err error) *DomainError {
 		return nil
 	}
 
+	// Cancellation wins over any wrapping: a service may have wrapped the
+	// cancelled query as an internal error, but it's the request that ended.
+	// An expired deadline is the server's own time limit (middleware.Timeout),
+	// a cancelled context is the client going away.
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &DomainError{
+			Key:     ErrKeyRequestTimeout,
+			Message: "Request timed out",
+			Status:  http.StatusServiceUnavailable,
+			Err:     err,
+		}
+	}
+	if IsContextCancellation(err) {
+		return &DomainError{
+			Key:     ErrKeyRequestCancelled,
+			Message: "Request cancelled",
+			Status:  StatusClientClosedRequest,
+			Err:     err,
+		}
+	}
+
 	var domainErr *DomainError
 	if errors.As(err, &domainErr) {
 		return domainErr
@@ -78,6 +103,22 @@ func ExtractDomainError(err error) *DomainError {
 	}
 }
 
+// IsContextCancellation reports whether err was caused by a cancelled or expired context
+func IsContextCancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// AsRequestTooLarge converts the error http.MaxBytesReader returns once a body goes over
+// its limit into a 413 request_too_large error. ok is false for any other error.
+func AsRequestTooLarge(err error) (*DomainError, bool) {
+	var maxBytesErr *http.MaxBytesError
+	if !errors.As(err, &maxBytesErr) {
+		return nil, false
+	}
+	return WrapDomainError(ErrKeyRequestTooLarge, "Request body is too large", http.StatusRequestEntityTooLarge, err).
+		WithDetails(map[string]interface{}{"max_bytes": maxBytesErr.Limit}), true
+}
+
 // IsDomainError checks if an error is a DomainError
 func IsDomainError(err error) bool {
 	var domainErr *DomainError
@@ -121,7 +162,12 @@ func WrapBadRequest(key, message string, err error) *DomainError {
 	return WrapDomainError(key, message, http.StatusBadRequest, err)
 }
 
-// WrapInternal wraps an error as an internal server error
+// WrapInternal wraps an error as an internal server error. A cancelled or expired
+// request context is not one: it becomes request_cancelled (499) or request_timeout (503)
+// as in ExtractDomainError, so callers checking Status see why the request ended.
 func WrapInternal(key, message string, err error) *DomainError {
+	if IsContextCancellation(err) {
+		return ExtractDomainError(err)
+	}
 	return WrapDomainError(key, message, http.StatusInternalServerError, err)
 }
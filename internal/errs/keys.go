@@ -2,12 +2,28 @@ package errs
 
 // Common error keys
 const (
-	ErrKeyUnauthorized  = "unauthorized"
-	ErrKeyForbidden     = "forbidden"
-	ErrKeyNotFound      = "not_found"
-	ErrKeyBadRequest    = "bad_request"
-	ErrKeyInternalError = "internal_error"
-	ErrKeyInvalidFormat = "invalid_format"
+	ErrKeyUnauthorized     = "unauthorized"
+	ErrKeyForbidden        = "forbidden"
+	ErrKeyNotFound         = "not_found"
+	ErrKeyMethodNotAllowed = "method_not_allowed"
+	ErrKeyBadRequest       = "bad_request"
+	ErrKeyInternalError    = "internal_error"
+	ErrKeyInvalidFormat    = "invalid_format"
+
+	ErrKeyRequestCancelled = "request_cancelled"
+	ErrKeyRequestTimeout   = "request_timeout"
+	ErrKeyRateLimited      = "rate_limited"
+	ErrKeyRequestTooLarge  = "request_too_large"
+
+	ErrKeyUnsupportedAPIVersion = "unsupported_api_version"
+	ErrKeyUnsupportedMediaType  = "unsupported_media_type"
+)
+
+// Database constraint error keys, see FromPgError
+const (
+	ErrKeyDBUniqueViolation     = "db.unique_violation"
+	ErrKeyDBForeignKeyViolation = "db.foreign_key_violation"
+	ErrKeyDBNotNullViolation    = "db.not_null_violation"
 )
 
 // Auth error keys
@@ -17,36 +33,85 @@ const (
 	ErrKeyAuthInvalidCredentials = "auth.invalid_credentials"
 	ErrKeyAuthTokenRequired      = "auth.token_required"
 	ErrKeyAuthUserExists         = "auth.user_exists"
+	ErrKeyAuthInvalidEmail       = "auth.invalid_email"
+	ErrKeyAuthSessionNotFound    = "auth.session_not_found"
+)
+
+// API key error keys
+const (
+	ErrKeyAPIKeyNotFound = "api_keys.not_found"
+	ErrKeyAPIKeyInvalid  = "api_keys.invalid"
+)
+
+// Idempotency error keys
+const (
+	ErrKeyIdempotencyKeyInvalid = "idempotency.key_invalid"
+	ErrKeyIdempotencyInProgress = "idempotency.in_progress"
+	ErrKeyIdempotencyKeyReused  = "idempotency.key_reused"
 )
 
 // Example error keys
 const (
-	ErrKeyExampleNotFound  = "examples.not_found"
-	ErrKeyExampleInvalidID = "examples.invalid_id"
+	ErrKeyExampleNotFound      = "examples.not_found"
+	ErrKeyExampleInvalidID     = "examples.invalid_id"
+	ErrKeyExampleBatchTooLarge = "examples.batch_too_large"
 )
 
 // Upload error keys
 const (
-	ErrKeyUploadNotFound  = "uploads.not_found"
-	ErrKeyValidationError = "validation.error"
+	ErrKeyUploadNotFound        = "uploads.not_found"
+	ErrKeyUploadStorageFull     = "uploads.storage_full"
+	ErrKeyUploadQuotaExceeded   = "uploads.quota_exceeded"
+	ErrKeyUploadInvalidFilename = "uploads.invalid_filename"
+	ErrKeyUploadTooManyFiles    = "uploads.too_many_files"
+	ErrKeyUploadFolderNotFound  = "uploads.folder_not_found"
+	ErrKeyUploadFileGone        = "uploads.file_gone"
+	ErrKeyValidationError       = "validation.error"
+)
+
+// Folder error keys
+const (
+	ErrKeyFolderNotFound      = "folders.not_found"
+	ErrKeyFolderNotEmpty      = "folders.not_empty"
+	ErrKeyFolderRootProtected = "folders.root_protected"
+)
+
+// Signed URL error keys
+const (
+	ErrKeySignedURLInvalid = "signed_url.invalid"
+	ErrKeySignedURLExpired = "signed_url.expired"
+)
+
+// City error keys
+const (
+	ErrKeyCityNameRequired  = "cities.name_required"
+	ErrKeyCityAlreadyExists = "cities.already_exists"
+)
+
+// Scheduler error keys
+const (
+	ErrKeySchedulerJobNotFound = "scheduler.job_not_found"
 )
 
 // Validation error keys
 const (
-	ErrKeyValidationFailed       = "validation.failed"
-	ErrKeyValidationRequired     = "validation.required"
-	ErrKeyValidationEmail        = "validation.email"
-	ErrKeyValidationMin          = "validation.min"
-	ErrKeyValidationMax          = "validation.max"
-	ErrKeyValidationOneOf        = "validation.oneof"
-	ErrKeyValidationNumeric      = "validation.numeric"
-	ErrKeyValidationAlpha        = "validation.alpha"
-	ErrKeyValidationAlphanum     = "validation.alphanum"
-	ErrKeyValidationURL          = "validation.url"
-	ErrKeyValidationUUID         = "validation.uuid"
-	ErrKeyValidationInvalid      = "validation.invalid"
-	ErrKeyValidationBodyInvalid  = "validation.body_invalid"
-	ErrKeyValidationTypeMismatch = "validation.type_mismatch"
+	ErrKeyValidationFailed         = "validation.failed"
+	ErrKeyValidationRequired       = "validation.required"
+	ErrKeyValidationEmail          = "validation.email"
+	ErrKeyValidationMin            = "validation.min"
+	ErrKeyValidationMax            = "validation.max"
+	ErrKeyValidationOneOf          = "validation.oneof"
+	ErrKeyValidationNumeric        = "validation.numeric"
+	ErrKeyValidationAlpha          = "validation.alpha"
+	ErrKeyValidationAlphanum       = "validation.alphanum"
+	ErrKeyValidationURL            = "validation.url"
+	ErrKeyValidationUUID           = "validation.uuid"
+	ErrKeyValidationStrongPassword = "validation.strongpassword"
+	ErrKeyValidationInvalid        = "validation.invalid"
+	ErrKeyValidationBodyInvalid    = "validation.body_invalid"
+	ErrKeyValidationTypeMismatch   = "validation.type_mismatch"
+	ErrKeyValidationPageTooDeep    = "validation.page_too_deep"
+	ErrKeyValidationCursor         = "validation.cursor_invalid"
 )
 
 // GetValidationErrorKey returns the error key for a validation rule
@@ -72,6 +137,8 @@ func GetValidationErrorKey(rule string) string {
 		return ErrKeyValidationURL
 	case "uuid":
 		return ErrKeyValidationUUID
+	case "strongpassword":
+		return ErrKeyValidationStrongPassword
 	default:
 		return ErrKeyValidationInvalid
 	}
@@ -112,5 +179,8 @@ func GetFieldValidationErrorKey(field, rule string) string {
 	if baseKey == ErrKeyValidationUUID {
 		return "validation." + field + ".uuid"
 	}
+	if baseKey == ErrKeyValidationStrongPassword {
+		return "validation." + field + ".strongpassword"
+	}
 	return "validation." + field + ".invalid"
 }
@@ -0,0 +1,78 @@
+package errs
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales for user-facing messages
+const (
+	LocaleEnglish = "en"
+	LocaleSpanish = "es"
+
+	DefaultLocale = LocaleEnglish
+)
+
+type localeContextKey struct{}
+
+// IsSupportedLocale reports whether messages are available for locale
+func IsSupportedLocale(locale string) bool {
+	return defaultTranslator.HasLocale(locale)
+}
+
+// WithLocale stores the resolved locale in the context
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeContextKey{}, locale)
+}
+
+// LocaleFromContext returns the locale stored by WithLocale, or DefaultLocale
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeContextKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// ParseAcceptLanguage picks the most preferred supported locale from an Accept-Language header
+// Region subtags are ignored ("es-MX" matches "es"); fallback is returned when nothing matches
+func ParseAcceptLanguage(header, fallback string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		base, _, _ := strings.Cut(tag, "-")
+		if q > 0 && IsSupportedLocale(base) {
+			candidates = append(candidates, candidate{locale: base, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return fallback
+	}
+
+	// Stable sort keeps header order for equal weights
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
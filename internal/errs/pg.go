@@ -0,0 +1,47 @@
+package errs
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// FromPgError maps a Postgres constraint violation anywhere in err's chain to a domain error
+// that keeps the original as its cause, with the violated constraint (and column, when
+// Postgres names one) in Details:
+//   - unique_violation      -> 409 db.unique_violation
+//   - foreign_key_violation -> 400 db.foreign_key_violation
+//   - not_null_violation    -> 400 db.not_null_violation
+//
+// It returns nil for anything else, so callers fall back to their own handling:
+//
+//	if domainErr := errs.FromPgError(err); domainErr != nil {
+//		return nil, domainErr
+//	}
+//	return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to create example", err)
+func FromPgError(err error) *DomainError {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return nil
+	}
+
+	var domainErr *DomainError
+	switch pgErr.Code {
+	case pgerrcode.UniqueViolation:
+		domainErr = WrapDomainError(ErrKeyDBUniqueViolation, "A record with these values already exists", http.StatusConflict, err)
+	case pgerrcode.ForeignKeyViolation:
+		domainErr = WrapBadRequest(ErrKeyDBForeignKeyViolation, "A referenced record does not exist", err)
+	case pgerrcode.NotNullViolation:
+		domainErr = WrapBadRequest(ErrKeyDBNotNullViolation, "A required value is missing", err)
+	default:
+		return nil
+	}
+
+	details := map[string]interface{}{"constraint": pgErr.ConstraintName}
+	if pgErr.ColumnName != "" {
+		details["column"] = pgErr.ColumnName
+	}
+	return domainErr.WithDetails(details)
+}
@@ -0,0 +1,79 @@
+package errs
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// ContentTypeProblemJSON is the RFC 7807 media type for error bodies
+	ContentTypeProblemJSON = "application/problem+json"
+	// ProblemTypePrefix turns an error key into the problem "type" URI
+	ProblemTypePrefix = "urn:problem-type:"
+	// ProblemJSONKey is the gin context key that forces problem+json for the request
+	ProblemJSONKey = "problem_json"
+)
+
+// ProblemDetails is an RFC 7807 error body.
+// error_key and details are extension members so clients can keep branching on keys.
+type ProblemDetails struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	ErrorKey string                 `json:"error_key"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// NewProblemDetails builds the problem+json body for a domain error
+func NewProblemDetails(domainErr *DomainError, status int, instance string) ProblemDetails {
+	return ProblemDetails{
+		Type:     ProblemTypePrefix + domainErr.Key,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   domainErr.Message,
+		Instance: instance,
+		ErrorKey: domainErr.Key,
+		Details:  domainErr.Details,
+	}
+}
+
+// WantsProblemJSON reports whether the error body should be problem+json,
+// either because it was enabled for the request or because the client asked for it
+func WantsProblemJSON(c *gin.Context) bool {
+	if c.GetBool(ProblemJSONKey) {
+		return true
+	}
+	return acceptsProblemJSON(c.GetHeader("Accept"))
+}
+
+// acceptsProblemJSON checks an Accept header for application/problem+json with a non-zero weight
+func acceptsProblemJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(mediaType), ContentTypeProblemJSON) {
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if q, err := strconv.ParseFloat(value, 64); err == nil && q == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// respondWithProblem writes domainErr as application/problem+json with detail as the message
+func respondWithProblem(c *gin.Context, domainErr *DomainError, status int, detail string) {
+	problem := NewProblemDetails(domainErr, status, c.Request.URL.Path)
+	problem.Detail = detail
+
+	c.Header("Content-Type", ContentTypeProblemJSON)
+	c.JSON(status, problem)
+}
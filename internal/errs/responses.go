@@ -16,12 +16,27 @@ type ErrorResponse struct {
 }
 
 // RespondWithError sends a structured error response
+// The body is RFC 7807 problem+json when WantsProblemJSON is true for the request.
+// The message is translated for the request locale when the key has a catalog entry.
 func RespondWithError(c *gin.Context, err error) {
 	domainErr := ExtractDomainError(err)
+	message := localizedMessage(c, domainErr)
+	if WantsProblemJSON(c) {
+		respondWithProblem(c, domainErr, domainErr.Status, message)
+		return
+	}
 
+	// Set status code
+	c.JSON(domainErr.Status, NewErrorResponse(c, err))
+}
+
+// NewErrorResponse builds the body RespondWithError would send for err, for reporting
+// errors inside a larger response such as the per-item results of a batch
+func NewErrorResponse(c *gin.Context, err error) ErrorResponse {
+	domainErr := ExtractDomainError(err)
 	response := ErrorResponse{
 		ErrorKey:  domainErr.Key,
-		Message:   domainErr.Message,
+		Message:   localizedMessage(c, domainErr),
 		Status:    domainErr.Status,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 	}
@@ -29,18 +44,21 @@ func RespondWithError(c *gin.Context, err error) {
 	if len(domainErr.Details) > 0 {
 		response.Details = domainErr.Details
 	}
-
-	// Set status code
-	c.JSON(domainErr.Status, response)
+	return response
 }
 
 // RespondWithErrorAndStatus sends a structured error response with explicit status
 func RespondWithErrorAndStatus(c *gin.Context, err error, status int) {
 	domainErr := ExtractDomainError(err)
+	message := localizedMessage(c, domainErr)
+	if WantsProblemJSON(c) {
+		respondWithProblem(c, domainErr, status, message)
+		return
+	}
 
 	response := ErrorResponse{
 		ErrorKey:  domainErr.Key,
-		Message:   domainErr.Message,
+		Message:   message,
 		Status:    status,
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
 	}
@@ -52,6 +70,12 @@ func RespondWithErrorAndStatus(c *gin.Context, err error, status int) {
 	c.JSON(status, response)
 }
 
+// localizedMessage translates the error for the locale stored by the Locale middleware,
+// keeping the built-in message when there is no translation
+func localizedMessage(c *gin.Context, domainErr *DomainError) string {
+	return defaultTranslator.Message(LocaleFromContext(c.Request.Context()), domainErr.Key, domainErr.Message)
+}
+
 // RespondWithUnauthorized sends an unauthorized error response
 func RespondWithUnauthorized(c *gin.Context, message string) {
 	RespondWithError(c, NewUnauthorizedError(ErrKeyUnauthorized, message))
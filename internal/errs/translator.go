@@ -0,0 +1,121 @@
+package errs
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// defaultTranslator serves the catalogs embedded in the binary
+var defaultTranslator = mustNewTranslator(localeFiles, "locales")
+
+// Catalog holds the user-facing messages of one locale.
+// Errors maps error keys to messages; keys reused with different messages
+// (bad_request, internal_error, ...) are left out so their specific built-in message is kept.
+// Validation maps validator rules to templates where :field and :param are substituted.
+type Catalog struct {
+	Errors     map[string]string `json:"errors"`
+	Validation map[string]string `json:"validation"`
+}
+
+// Translator looks up localized messages by locale and key
+type Translator struct {
+	catalogs map[string]Catalog
+}
+
+// NewTranslator loads one catalog per <locale>.json file in dir
+func NewTranslator(fsys fs.FS, dir string) (*Translator, error) {
+	files, err := fs.Glob(fsys, path.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	catalogs := make(map[string]Catalog, len(files))
+	for _, file := range files {
+		data, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", file, err)
+		}
+		catalogs[strings.TrimSuffix(path.Base(file), ".json")] = catalog
+	}
+
+	if _, ok := catalogs[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("missing catalog for default locale %q", DefaultLocale)
+	}
+
+	return &Translator{catalogs: catalogs}, nil
+}
+
+func mustNewTranslator(fsys fs.FS, dir string) *Translator {
+	translator, err := NewTranslator(fsys, dir)
+	if err != nil {
+		panic(fmt.Sprintf("errs: load locale catalogs: %v", err))
+	}
+	return translator
+}
+
+// DefaultTranslator returns the translator backed by the embedded locale files
+func DefaultTranslator() *Translator {
+	return defaultTranslator
+}
+
+// Locales returns the available locales, sorted
+func (t *Translator) Locales() []string {
+	locales := make([]string, 0, len(t.catalogs))
+	for locale := range t.catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// HasLocale reports whether a catalog exists for locale
+func (t *Translator) HasLocale(locale string) bool {
+	_, ok := t.catalogs[locale]
+	return ok
+}
+
+// Catalog returns the messages for locale
+func (t *Translator) Catalog(locale string) (Catalog, bool) {
+	catalog, ok := t.catalogs[locale]
+	return catalog, ok
+}
+
+// Message returns the localized message for an error key, or fallback when the locale has none
+func (t *Translator) Message(locale, key, fallback string) string {
+	if message, ok := t.catalogs[locale].Errors[key]; ok {
+		return message
+	}
+	return fallback
+}
+
+// ValidationMessage renders the template for a validator rule in locale, falling back to
+// the default locale and then to the generic "invalid" template
+func (t *Translator) ValidationMessage(locale, rule, field, param string) string {
+	templates := t.catalogs[locale].Validation
+	if templates == nil {
+		templates = t.catalogs[DefaultLocale].Validation
+	}
+
+	template, ok := templates[rule]
+	if !ok {
+		template, ok = t.catalogs[DefaultLocale].Validation[rule]
+	}
+	if !ok {
+		template = templates["invalid"]
+	}
+
+	return strings.NewReplacer(":field", field, ":param", param).Replace(template)
+}
@@ -2,10 +2,11 @@ package errs
 
 import (
 	"errors"
-	"fmt"
 	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -23,19 +24,59 @@ func init() {
 			}
 			return name
 		})
+		if err := v.RegisterValidation("strongpassword", validateStrongPassword); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// StrongPasswordMinLength is the shortest password the strongpassword tag accepts
+const StrongPasswordMinLength = 8
+
+// validateStrongPassword implements the strongpassword tag: at least StrongPasswordMinLength
+// characters with at least one letter and one digit
+func validateStrongPassword(fl validator.FieldLevel) bool {
+	password := fl.Field().String()
+	if len([]rune(password)) < StrongPasswordMinLength {
+		return false
+	}
+
+	hasLetter, hasDigit := false, false
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
 	}
+	return hasLetter && hasDigit
 }
 
 type ValidationErrorResponse struct {
 	Message  string              `json:"message"`
 	ErrorKey string              `json:"error_key"`
 	Errors   map[string][]string `json:"errors"`
+	Messages map[string][]string `json:"messages,omitempty"`
+	// Params holds the rule parameters of each failed field, keyed by field then rule, e.g.
+	// {"status": {"oneof": ["draft", "published"]}, "name": {"min": 3}}. Rules without a
+	// parameter (required, email) are left out, and so is the whole map when none failed.
+	Params map[string]map[string]any `json:"params,omitempty"`
 }
 
 // FormatValidationError formats validation errors into a Laravel-style response with error keys
+// Human-readable messages use the default locale; see FormatValidationErrorForLocale
 func FormatValidationError(err error) ValidationErrorResponse {
+	return FormatValidationErrorForLocale(err, DefaultLocale)
+}
+
+// FormatValidationErrorForLocale formats validation errors with messages in the given locale
+// The error keys in Errors are stable across locales, only Message and Messages are translated
+func FormatValidationErrorForLocale(err error, locale string) ValidationErrorResponse {
 	validationErrors := make(map[string][]string)
-	errorMessage := "The given data was invalid."
+	messages := make(map[string][]string)
+	params := make(map[string]map[string]any)
+	errorMessage := defaultTranslator.Message(locale, ErrKeyValidationFailed, "The given data was invalid.")
 
 	if err == nil {
 		return ValidationErrorResponse{
@@ -53,57 +94,88 @@ func FormatValidationError(err error) ValidationErrorResponse {
 			// Get error key instead of message
 			errorKey := GetFieldValidationErrorKey(fieldName, fieldError.Tag())
 
-			if _, exists := validationErrors[fieldName]; !exists {
-				validationErrors[fieldName] = []string{}
-			}
 			validationErrors[fieldName] = append(validationErrors[fieldName], errorKey)
+			messages[fieldName] = append(messages[fieldName], getUserFriendlyMessage(fieldError, locale))
+			if value, ok := validationParam(fieldError); ok {
+				if params[fieldName] == nil {
+					params[fieldName] = make(map[string]any)
+				}
+				params[fieldName][fieldError.Tag()] = value
+			}
 		}
 	} else {
 		handleNonValidationError(err, validationErrors)
+		for fieldName, keys := range validationErrors {
+			for _, key := range keys {
+				messages[fieldName] = append(messages[fieldName], nonValidationMessage(fieldName, key, locale))
+			}
+		}
 	}
 
 	return ValidationErrorResponse{
 		Message:  errorMessage,
 		ErrorKey: ErrKeyValidationFailed,
 		Errors:   validationErrors,
+		Messages: messages,
+		Params:   params,
 	}
 }
 
+// validationParam returns the parameter of a failed rule in a form clients can use directly:
+// the allowed values for oneof, a number for numeric bounds (min, max, len, ...), the
+// minimum length for strongpassword, and the raw string otherwise
+func validationParam(fieldError validator.FieldError) (any, bool) {
+	param := fieldError.Param()
+	switch {
+	case fieldError.Tag() == "strongpassword":
+		return StrongPasswordMinLength, true
+	case param == "":
+		return nil, false
+	case fieldError.Tag() == "oneof":
+		return strings.Fields(param), true
+	}
+
+	if n, err := strconv.ParseInt(param, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(param, 64); err == nil {
+		return f, true
+	}
+	return param, true
+}
+
 // getUserFriendlyMessage creates user-friendly error messages from validator.FieldError
-func getUserFriendlyMessage(fieldError validator.FieldError) string {
+func getUserFriendlyMessage(fieldError validator.FieldError, locale string) string {
 	fieldName := formatFieldNameForDisplay(fieldError.Field())
 	tag := fieldError.Tag()
 	param := fieldError.Param()
 
 	switch tag {
-	case "required":
-		return fmt.Sprintf("The %s field is required.", fieldName)
-	case "email":
-		return fmt.Sprintf("The %s must be a valid email address.", fieldName)
-	case "min":
-		if fieldError.Type().Kind().String() == "string" {
-			return fmt.Sprintf("The %s must be at least %s characters.", fieldName, param)
-		}
-		return fmt.Sprintf("The %s must be at least %s.", fieldName, param)
-	case "max":
+	case "min", "max":
 		if fieldError.Type().Kind().String() == "string" {
-			return fmt.Sprintf("The %s may not be greater than %s characters.", fieldName, param)
+			return defaultTranslator.ValidationMessage(locale, tag+".string", fieldName, param)
 		}
-		return fmt.Sprintf("The %s may not be greater than %s.", fieldName, param)
+		return defaultTranslator.ValidationMessage(locale, tag, fieldName, param)
 	case "oneof":
-		return fmt.Sprintf("The %s must be one of: %s.", fieldName, strings.ReplaceAll(param, " ", ", "))
-	case "numeric":
-		return fmt.Sprintf("The %s must be a number.", fieldName)
-	case "alpha":
-		return fmt.Sprintf("The %s may only contain letters.", fieldName)
-	case "alphanum":
-		return fmt.Sprintf("The %s may only contain letters and numbers.", fieldName)
-	case "url":
-		return fmt.Sprintf("The %s must be a valid URL.", fieldName)
-	case "uuid":
-		return fmt.Sprintf("The %s must be a valid UUID.", fieldName)
+		return defaultTranslator.ValidationMessage(locale, tag, fieldName, strings.ReplaceAll(param, " ", ", "))
+	case "strongpassword":
+		return defaultTranslator.ValidationMessage(locale, tag, fieldName, strconv.Itoa(StrongPasswordMinLength))
 	default:
-		return fmt.Sprintf("The %s field is invalid.", fieldName)
+		return defaultTranslator.ValidationMessage(locale, tag, fieldName, param)
+	}
+}
+
+// nonValidationMessage renders a message for errors produced by handleNonValidationError
+func nonValidationMessage(fieldName, key, locale string) string {
+	displayName := formatFieldNameForDisplay(fieldName)
+
+	switch {
+	case key == ErrKeyValidationBodyInvalid:
+		return defaultTranslator.ValidationMessage(locale, "body_invalid", displayName, "")
+	case strings.HasSuffix(key, ".type_mismatch"):
+		return defaultTranslator.ValidationMessage(locale, "type_mismatch", displayName, "")
+	default:
+		return defaultTranslator.ValidationMessage(locale, "invalid", displayName, "")
 	}
 }
 
@@ -227,7 +299,14 @@ func extractJSONErrorMessage(errMsg string) string {
 }
 
 // RespondWithValidationError sends a validation error response with error keys
+// Messages are rendered in the locale resolved by the Locale middleware
+// Bodies cut off by MaxBodySize get a 413 instead
 func RespondWithValidationError(c *gin.Context, err error) {
-	validationError := FormatValidationError(err)
+	if tooLarge, ok := AsRequestTooLarge(err); ok {
+		RespondWithError(c, tooLarge)
+		return
+	}
+
+	validationError := FormatValidationErrorForLocale(err, LocaleFromContext(c.Request.Context()))
 	c.JSON(http.StatusBadRequest, validationError)
 }
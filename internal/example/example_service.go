@@ -1,9 +1,12 @@
 package example
 
 import (
+	"app/internal"
 	"app/internal/db"
 	"app/internal/errs"
 	"context"
+	"strings"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -11,11 +14,37 @@ import (
 // Error variables - define all service errors at the top of the file
 // Use error keys from errs package and descriptive messages
 var (
-	ErrExampleNotFound = errs.NewNotFoundError(errs.ErrKeyExampleNotFound, "Example not found")
-	ErrInvalidPage     = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid page parameter")
-	ErrInvalidPageSize = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid page size parameter")
+	ErrExampleNotFound     = errs.NewNotFoundError(errs.ErrKeyExampleNotFound, "Example not found")
+	ErrInvalidPage         = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid page parameter")
+	ErrInvalidPageSize     = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid page size parameter")
+	ErrInvalidCreatedRange = errs.NewBadRequestError(errs.ErrKeyBadRequest, "created_after must be earlier than created_before")
+	ErrSearchQueryRequired = errs.NewBadRequestError(errs.ErrKeyBadRequest, "Search query is required")
+	ErrBatchTooLarge       = errs.NewBadRequestError(errs.ErrKeyExampleBatchTooLarge, "Too many examples in one batch").
+				WithDetails(map[string]interface{}{"max_items": MaxExamplesBatchSize})
 )
 
+// MaxExamplesBatchSize is the most examples CreateExamplesBatch accepts in one call
+const MaxExamplesBatchSize = 100
+
+// ExampleInput holds the fields of one example to create
+type ExampleInput struct {
+	Title       string
+	Description string
+}
+
+// ExampleListFilter narrows ListExamplesPaginated, nil bounds are not applied
+type ExampleListFilter struct {
+	CreatedAfter  *time.Time // inclusive
+	CreatedBefore *time.Time // exclusive
+}
+
+// CursorExamplesResult is one page of ListExamplesAfterCursor, NextAfterID is nil on the last page
+type CursorExamplesResult struct {
+	Data        []db.Example
+	NextAfterID *int32
+	Limit       int32
+}
+
 // PaginatedExamplesResult represents paginated example results from service layer
 type PaginatedExamplesResult struct {
 	Data     []db.Example
@@ -36,6 +65,12 @@ func NewExampleService(queries *db.Queries) *ExampleService {
 	}
 }
 
+// WithTx returns a copy of the service running on q, so its writes join the transaction
+// q belongs to (see db.Transactor)
+func (s *ExampleService) WithTx(q *db.Queries) *ExampleService {
+	return &ExampleService{queries: q}
+}
+
 // CreateExample creates a new example
 // Error handling example: Wrap database errors as internal errors
 func (s *ExampleService) CreateExample(ctx context.Context, userID int32, title, description string) (*db.Example, error) {
@@ -45,13 +80,46 @@ func (s *ExampleService) CreateExample(ctx context.Context, userID int32, title,
 		Description: pgtype.Text{String: description, Valid: description != ""},
 	})
 	if err != nil {
-		// Wrap database errors - preserves error chain for logging
+		// Constraint violations become 4xx errors naming the constraint
+		if domainErr := errs.FromPgError(err); domainErr != nil {
+			return nil, domainErr
+		}
+		// Wrap other database errors - preserves error chain for logging
 		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to create example", err)
 	}
 
 	return &example, nil
 }
 
+// CreateExamplesBatch creates several examples with a single multi-row insert
+// The insert is one statement, so either every example is created or none are
+func (s *ExampleService) CreateExamplesBatch(ctx context.Context, userID int32, items []ExampleInput) ([]db.Example, error) {
+	if len(items) > MaxExamplesBatchSize {
+		return nil, ErrBatchTooLarge
+	}
+	if len(items) == 0 {
+		return []db.Example{}, nil
+	}
+
+	titles := make([]string, len(items))
+	descriptions := make([]string, len(items))
+	for i, item := range items {
+		titles[i] = item.Title
+		descriptions[i] = item.Description
+	}
+
+	examples, err := s.queries.CreateExamplesBatch(ctx, db.CreateExamplesBatchParams{
+		UserID:       userID,
+		Titles:       titles,
+		Descriptions: descriptions,
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to create examples", err)
+	}
+
+	return examples, nil
+}
+
 // GetExample retrieves an example by ID for a specific user
 // Error handling example: Return domain error directly for business logic errors
 func (s *ExampleService) GetExample(ctx context.Context, exampleID, userID int32) (*db.Example, error) {
@@ -67,14 +135,71 @@ func (s *ExampleService) GetExample(ctx context.Context, exampleID, userID int32
 	return &example, nil
 }
 
+// GetSharedExample retrieves an example by ID regardless of owner
+// Only call this behind a check that grants access, such as a signed share link
+func (s *ExampleService) GetSharedExample(ctx context.Context, exampleID int32) (*db.Example, error) {
+	example, err := s.queries.GetSharedExampleByID(ctx, exampleID)
+	if err != nil {
+		return nil, ErrExampleNotFound
+	}
+
+	return &example, nil
+}
+
+// GetExampleAdmin retrieves an example by ID regardless of owner, for admin routes.
+// Only call this behind RequireRole(RoleAdmin); user-facing lookups go through GetExample
+// so other users' examples stay a 404.
+func (s *ExampleService) GetExampleAdmin(ctx context.Context, exampleID int32) (*db.Example, error) {
+	example, err := s.queries.GetSharedExampleByID(ctx, exampleID)
+	if err != nil {
+		return nil, ErrExampleNotFound
+	}
+
+	return &example, nil
+}
+
+// GetPublicExample retrieves an example that is public or owned by userID.
+// userID is nil for anonymous callers, who only see public examples.
+// Private examples of other users return ErrExampleNotFound so their existence isn't revealed.
+func (s *ExampleService) GetPublicExample(ctx context.Context, exampleID int32, userID *int32) (*db.Example, error) {
+	params := db.GetPublicExampleByIDParams{ID: exampleID}
+	if userID != nil {
+		params.UserID = pgtype.Int4{Int32: *userID, Valid: true}
+	}
+
+	example, err := s.queries.GetPublicExampleByID(ctx, params)
+	if err != nil {
+		return nil, ErrExampleNotFound
+	}
+
+	return &example, nil
+}
+
+// GetExampleOwnerID returns the id of the user who owns an example
+// Used to let admins act on examples through the owner-scoped methods
+func (s *ExampleService) GetExampleOwnerID(ctx context.Context, exampleID int32) (int32, error) {
+	example, err := s.GetSharedExample(ctx, exampleID)
+	if err != nil {
+		return 0, err
+	}
+
+	return example.UserID, nil
+}
+
 // UpdateExample updates an existing example
-func (s *ExampleService) UpdateExample(ctx context.Context, exampleID, userID int32, title, description string) (*db.Example, error) {
-	example, err := s.queries.UpdateExample(ctx, db.UpdateExampleParams{
+// A nil public keeps the example's current visibility
+func (s *ExampleService) UpdateExample(ctx context.Context, exampleID, userID int32, title, description string, public *bool) (*db.Example, error) {
+	params := db.UpdateExampleParams{
 		ID:          exampleID,
 		UserID:      userID,
 		Title:       title,
 		Description: pgtype.Text{String: description, Valid: description != ""},
-	})
+	}
+	if public != nil {
+		params.Public = pgtype.Bool{Bool: *public, Valid: true}
+	}
+
+	example, err := s.queries.UpdateExample(ctx, params)
 	if err != nil {
 		return nil, ErrExampleNotFound
 	}
@@ -105,6 +230,14 @@ func (s *ExampleService) DeleteExample(ctx context.Context, exampleID, userID in
 	return nil
 }
 
+// DeleteUserExamples deletes every example of a user, e.g. when their account is deleted
+func (s *ExampleService) DeleteUserExamples(ctx context.Context, userID int32) error {
+	if err := s.queries.DeleteExamplesByUserID(ctx, userID); err != nil {
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete examples", err)
+	}
+	return nil
+}
+
 // ListExamples retrieves all examples for a user
 func (s *ExampleService) ListExamples(ctx context.Context, userID int32) ([]db.Example, error) {
 	examples, err := s.queries.ListExamplesForUser(ctx, userID)
@@ -119,9 +252,9 @@ func (s *ExampleService) ListExamples(ctx context.Context, userID int32) ([]db.E
 	return examples, nil
 }
 
-// ListExamplesPaginated retrieves paginated examples for a user
+// ListExamplesPaginated retrieves paginated examples for a user, optionally limited to a creation time range
 // Error handling example: Validate input and return domain errors
-func (s *ExampleService) ListExamplesPaginated(ctx context.Context, userID, page, pageSize int32) (*PaginatedExamplesResult, error) {
+func (s *ExampleService) ListExamplesPaginated(ctx context.Context, userID, page, pageSize int32, filter ExampleListFilter) (*PaginatedExamplesResult, error) {
 	// Input validation - return domain errors for invalid input
 	if page < 1 {
 		return nil, ErrInvalidPage
@@ -129,19 +262,35 @@ func (s *ExampleService) ListExamplesPaginated(ctx context.Context, userID, page
 	if pageSize < 1 || pageSize > 100 {
 		return nil, ErrInvalidPageSize
 	}
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		return nil, ErrInvalidCreatedRange
+	}
+
+	offset, err := internal.PaginationOffset(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
 
-	offset := (page - 1) * pageSize
+	createdAfter := toTimestamp(filter.CreatedAfter)
+	createdBefore := toTimestamp(filter.CreatedBefore)
 
-	examples, err := s.queries.ListExamplesForUserPaginated(ctx, db.ListExamplesForUserPaginatedParams{
-		UserID: userID,
-		Limit:  pageSize,
-		Offset: offset,
+	examples, err := s.queries.ListExamplesForUserCreatedBetween(ctx, db.ListExamplesForUserCreatedBetweenParams{
+		UserID:        userID,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+		PageLimit:     pageSize,
+		PageOffset:    offset,
 	})
 	if err != nil {
 		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list examples", err)
 	}
 
-	total, err := s.queries.CountExamplesForUser(ctx, userID)
+	// Count with the same bounds so the pagination metadata matches the filtered list
+	total, err := s.queries.CountExamplesForUserCreatedBetween(ctx, db.CountExamplesForUserCreatedBetweenParams{
+		UserID:        userID,
+		CreatedAfter:  createdAfter,
+		CreatedBefore: createdBefore,
+	})
 	if err != nil {
 		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to count examples", err)
 	}
@@ -157,3 +306,97 @@ func (s *ExampleService) ListExamplesPaginated(ctx context.Context, userID, page
 		PageSize: pageSize,
 	}, nil
 }
+
+// ListExamplesAfterCursor lists a user's examples newest first, starting after the example
+// with ID afterID (from the first one when nil). Unlike offset pages, rows created between
+// two calls don't shift later pages, so nothing is skipped or repeated.
+func (s *ExampleService) ListExamplesAfterCursor(ctx context.Context, userID int32, afterID *int32, limit int32) (*CursorExamplesResult, error) {
+	if limit < 1 || limit > 100 {
+		return nil, ErrInvalidPageSize
+	}
+
+	after := pgtype.Int4{}
+	if afterID != nil {
+		after = pgtype.Int4{Int32: *afterID, Valid: true}
+	}
+
+	// One extra row tells whether there is a next page
+	examples, err := s.queries.ListExamplesAfterCursor(ctx, db.ListExamplesAfterCursorParams{
+		UserID:    userID,
+		AfterID:   after,
+		PageLimit: limit + 1,
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list examples", err)
+	}
+
+	result := &CursorExamplesResult{Data: examples, Limit: limit}
+	if len(examples) > int(limit) {
+		result.Data = examples[:limit]
+		nextAfterID := result.Data[limit-1].ID
+		result.NextAfterID = &nextAfterID
+	}
+	if result.Data == nil {
+		result.Data = []db.Example{}
+	}
+
+	return result, nil
+}
+
+// SearchExamples finds a user's examples whose title or description matches query,
+// best matches first and newest first among equally relevant ones
+func (s *ExampleService) SearchExamples(ctx context.Context, userID int32, query string, page, pageSize int32) (*PaginatedExamplesResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrSearchQueryRequired
+	}
+	if page < 1 {
+		return nil, ErrInvalidPage
+	}
+	if pageSize < 1 || pageSize > 100 {
+		return nil, ErrInvalidPageSize
+	}
+
+	offset, err := internal.PaginationOffset(page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	examples, err := s.queries.SearchExamplesForUser(ctx, db.SearchExamplesForUserParams{
+		UserID:     userID,
+		Query:      query,
+		PageLimit:  pageSize,
+		PageOffset: offset,
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to search examples", err)
+	}
+
+	total, err := s.queries.CountSearchExamplesForUser(ctx, db.CountSearchExamplesForUserParams{
+		UserID: userID,
+		Query:  query,
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to count search results", err)
+	}
+
+	if examples == nil {
+		examples = []db.Example{}
+	}
+
+	return &PaginatedExamplesResult{
+		Data:     examples,
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+// toTimestamp converts an optional bound to a nullable timestamp.
+// created_at is stored without a time zone in UTC, so bounds are compared in UTC.
+func toTimestamp(t *time.Time) pgtype.Timestamp {
+	if t == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: t.UTC(), Valid: true}
+}
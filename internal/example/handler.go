@@ -5,21 +5,32 @@ import (
 	"app/internal/errs"
 	"app/internal/logger"
 	"app/internal/middleware"
+	"context"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shareLinkTTL is how long a generated share link stays valid
+const shareLinkTTL = 24 * time.Hour
+
 type Handler struct {
-	service *ExampleService
-	logger  *logger.Logger
+	service     *ExampleService
+	logger      *logger.Logger
+	shareSecret []byte
+	sharePath   string
 }
 
-func NewHandler(service *ExampleService, logger *logger.Logger) *Handler {
+// NewHandler creates an example handler; shareSecret signs share links pointing below sharePath
+func NewHandler(service *ExampleService, logger *logger.Logger, shareSecret []byte, sharePath string) *Handler {
 	return &Handler{
-		service: service,
-		logger:  logger,
+		service:     service,
+		logger:      logger,
+		shareSecret: shareSecret,
+		sharePath:   sharePath,
 	}
 }
 
@@ -54,37 +65,97 @@ func (h *Handler) CreateExample(c *gin.Context) {
 	// Service error handling example: Use RespondWithError for domain errors
 	example, err := h.service.CreateExample(c.Request.Context(), userID, req.Title, req.Description)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to create example", "error", err, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to create example", err, "user_id", userID)
 		errs.RespondWithError(c, err) // Automatically formats domain error
 		return
 	}
 
 	response := ExampleResponse{
-		ID:          example.ID,
-		UserID:      example.UserID,
+		ID:          internal.JSONID(example.ID),
+		UserID:      internal.JSONID(example.UserID),
 		Title:       example.Title,
 		Description: example.Description.String,
-		CreatedAt:   example.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   example.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		Public:      example.Public,
+		CreatedAt:   internal.FormatTimestamp(example.CreatedAt),
+		UpdatedAt:   internal.FormatTimestamp(example.UpdatedAt),
 	}
 
 	c.JSON(http.StatusOK, ExampleDataResponse{Data: &response})
 }
 
+// CreateExamplesBatch creates up to MaxExamplesBatchSize examples in one request
+//
+//	@Summary		Create examples in batch
+//	@Description	Create several examples for the authenticated user at once; if any item is invalid nothing is created
+//	@Tags			examples
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		CreateExamplesBatchRequest	true	"Examples to create (max 100)"
+//	@Success		200		{object}	ExamplesListResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/examples/batch [post]
+func (h *Handler) CreateExamplesBatch(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	// Binding validates every item, so one invalid item rejects the whole batch before any insert
+	var req CreateExamplesBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	items := make([]ExampleInput, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = ExampleInput{Title: item.Title, Description: item.Description}
+	}
+
+	created, err := h.service.CreateExamplesBatch(c.Request.Context(), userID, items)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to create examples batch", err, "user_id", userID, "items", len(items))
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	examples := make([]ExampleResponse, len(created))
+	for i, ex := range created {
+		examples[i] = ExampleResponse{
+			ID:          internal.JSONID(ex.ID),
+			UserID:      internal.JSONID(ex.UserID),
+			Title:       ex.Title,
+			Description: ex.Description.String,
+			Public:      ex.Public,
+			CreatedAt:   internal.FormatTimestamp(ex.CreatedAt),
+			UpdatedAt:   internal.FormatTimestamp(ex.UpdatedAt),
+		}
+	}
+
+	c.JSON(http.StatusOK, ExamplesListResponse{Data: examples})
+}
+
 // GetExample retrieves an example by ID
 //
 //	@Summary		Get example
-//	@Description	Get an example by ID for the authenticated user
+//	@Description	Get an example by ID for the authenticated user (admins can read any example)
 //	@Tags			examples
 //	@Accept			json
 //	@Produce		json
 //	@Security		Bearer
-//	@Param			id	path		int	true	"Example ID"
-//	@Success		200	{object}	ExampleDataResponse
-//	@Failure		400	{object}	ErrorResponse
-//	@Failure		401	{object}	ErrorResponse
-//	@Failure		404	{object}	ErrorResponse
-//	@Failure		500	{object}	ErrorResponse
+//	@Param			id				path		int		true	"Example ID"
+//	@Param			If-None-Match	header		string	false	"ETag of a previous response"
+//	@Success		200				{object}	ExampleDataResponse
+//	@Header			200				{string}	ETag	"Weak ETag of the example"
+//	@Success		304				"Example unchanged since the ETag in If-None-Match"
+//	@Failure		400				{object}	ErrorResponse
+//	@Failure		401				{object}	ErrorResponse
+//	@Failure		404				{object}	ErrorResponse
+//	@Failure		500				{object}	ErrorResponse
 //	@Router			/api/v1/examples/{id} [get]
 func (h *Handler) GetExample(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
@@ -100,21 +171,74 @@ func (h *Handler) GetExample(c *gin.Context) {
 		return
 	}
 
+	// Admins may read any user's example
+	ownerID, err := middleware.OwnerOrAdmin(c, userID, h.exampleOwner(int32(id)))
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
 	// Domain error handling example: Service returns domain error, handler just passes it through
-	example, err := h.service.GetExample(c.Request.Context(), int32(id), userID)
+	example, err := h.service.GetExample(c.Request.Context(), int32(id), ownerID)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to get example", "error", err, "example_id", id, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to get example", err, "example_id", id, "user_id", userID)
 		errs.RespondWithError(c, err) // Domain error automatically formatted
 		return
 	}
 
+	// Conditional GET: clients revalidate with If-None-Match and get a 304 while the example is unchanged
+	if internal.NotModified(c, internal.WeakETag(example.ID, example.UpdatedAt.Time.UnixNano())) {
+		return
+	}
+
 	response := ExampleResponse{
-		ID:          example.ID,
-		UserID:      example.UserID,
+		ID:          internal.JSONID(example.ID),
+		UserID:      internal.JSONID(example.UserID),
 		Title:       example.Title,
 		Description: example.Description.String,
-		CreatedAt:   example.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   example.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		Public:      example.Public,
+		CreatedAt:   internal.FormatTimestamp(example.CreatedAt),
+		UpdatedAt:   internal.FormatTimestamp(example.UpdatedAt),
+	}
+
+	c.JSON(http.StatusOK, ExampleDataResponse{Data: &response})
+}
+
+// GetExampleAdmin returns any user's example to an admin
+//
+//	@Summary		Get example (admin)
+//	@Description	Get an example by ID regardless of owner. Non-admins get 403 here, while GET /api/v1/examples/{id} keeps returning 404 for other users' examples
+//	@Tags			examples
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int	true	"Example ID"
+//	@Success		200	{object}	ExampleDataResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/api/v1/admin/examples/{id} [get]
+func (h *Handler) GetExampleAdmin(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid example ID")
+		return
+	}
+
+	example, err := h.service.GetExampleAdmin(c.Request.Context(), int32(id))
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := ExampleResponse{
+		ID:          internal.JSONID(example.ID),
+		UserID:      internal.JSONID(example.UserID),
+		Title:       example.Title,
+		Description: example.Description.String,
+		Public:      example.Public,
+		CreatedAt:   internal.FormatTimestamp(example.CreatedAt),
+		UpdatedAt:   internal.FormatTimestamp(example.UpdatedAt),
 	}
 
 	c.JSON(http.StatusOK, ExampleDataResponse{Data: &response})
@@ -123,14 +247,23 @@ func (h *Handler) GetExample(c *gin.Context) {
 // ListExamples lists all examples for the authenticated user with pagination
 //
 //	@Summary		List examples (paginated)
-//	@Description	Get all examples for the authenticated user with pagination
+//	@Description	Get all examples for the authenticated user with pagination, optionally limited to a creation time range.
+//	@Description	Passing cursor (empty for the first page) switches to cursor pagination: examples come newest first, limit sets the page size,
+//	@Description	and the body is a CursorExamplesResponse whose pagination.next_cursor fetches the next page (null on the last one). Cursor pages stay stable while examples are added;
+//	@Description	page, page_size and the created_* filters don't apply to them.
 //	@Tags			examples
 //	@Accept			json
 //	@Produce		json
 //	@Security		Bearer
-//	@Param			page		query		int		false	"Page number (default: 1)"					default(1)
-//	@Param			page_size	query		int		false	"Page size (default: 20, min: 1, max: 100)"	default(20)
+//	@Param			page			query		int		false	"Page number (default: 1)"					default(1)
+//	@Param			page_size		query		int		false	"Page size (default: 20, min: 1, max: 100)"	default(20)
+//	@Param			created_after	query		string	false	"Only examples created at or after this RFC 3339 time"
+//	@Param			created_before	query		string	false	"Only examples created before this RFC 3339 time"
+//	@Param			cursor			query		string	false	"Cursor pagination: next_cursor of the previous page, empty for the first page"
+//	@Param			limit			query		int		false	"Cursor page size (default: 20, min: 1, max: 100)"	default(20)
 //	@Success		200			{object}	PaginatedExamplesResponse
+//	@Header			200			{int}		X-Total-Count	"Total number of matching examples"
+//	@Header			200			{string}	Link			"RFC 5988 links to the next, prev and last pages"
 //	@Failure		400			{object}	ErrorResponse
 //	@Failure		401			{object}	ErrorResponse
 //	@Failure		500			{object}	ErrorResponse
@@ -142,15 +275,30 @@ func (h *Handler) ListExamples(c *gin.Context) {
 		return
 	}
 
+	if middleware.WantsCursorPagination(c) {
+		h.listExamplesByCursor(c, userID)
+		return
+	}
+
 	pagination, err := middleware.GetPaginationParamsFromContext(c, 20, 1, 100)
 	if err != nil {
-		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, err.Error())
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	var filter ExampleListFilter
+	if filter.CreatedAfter, err = parseTimeQuery(c, "created_after"); err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+	if filter.CreatedBefore, err = parseTimeQuery(c, "created_before"); err != nil {
+		errs.RespondWithError(c, err)
 		return
 	}
 
-	result, err := h.service.ListExamplesPaginated(c.Request.Context(), userID, pagination.Page, pagination.PageSize)
+	result, err := h.service.ListExamplesPaginated(c.Request.Context(), userID, pagination.Page, pagination.PageSize, filter)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to list examples", "error", err, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to list examples", err, "user_id", userID)
 		errs.RespondWithError(c, err)
 		return
 	}
@@ -159,12 +307,13 @@ func (h *Handler) ListExamples(c *gin.Context) {
 	examples := make([]ExampleResponse, len(result.Data))
 	for i, ex := range result.Data {
 		examples[i] = ExampleResponse{
-			ID:          ex.ID,
-			UserID:      ex.UserID,
+			ID:          internal.JSONID(ex.ID),
+			UserID:      internal.JSONID(ex.UserID),
 			Title:       ex.Title,
 			Description: ex.Description.String,
-			CreatedAt:   ex.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:   ex.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+			Public:      ex.Public,
+			CreatedAt:   internal.FormatTimestamp(ex.CreatedAt),
+			UpdatedAt:   internal.FormatTimestamp(ex.UpdatedAt),
 		}
 	}
 
@@ -173,13 +322,114 @@ func (h *Handler) ListExamples(c *gin.Context) {
 		Pagination: internal.NewPaginationMeta(result.Total, result.Page, result.PageSize),
 	}
 
+	internal.SetPaginationHeaders(c, response.Pagination)
+	c.JSON(http.StatusOK, response)
+}
+
+// listExamplesByCursor serves ListExamples when a cursor is passed
+func (h *Handler) listExamplesByCursor(c *gin.Context, userID int32) {
+	params, err := middleware.GetCursorParamsFromContext(c, 20, 1, 100)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	result, err := h.service.ListExamplesAfterCursor(c.Request.Context(), userID, params.AfterID, params.Limit)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to list examples", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	examples := make([]ExampleResponse, len(result.Data))
+	for i, ex := range result.Data {
+		examples[i] = ExampleResponse{
+			ID:          internal.JSONID(ex.ID),
+			UserID:      internal.JSONID(ex.UserID),
+			Title:       ex.Title,
+			Description: ex.Description.String,
+			Public:      ex.Public,
+			CreatedAt:   internal.FormatTimestamp(ex.CreatedAt),
+			UpdatedAt:   internal.FormatTimestamp(ex.UpdatedAt),
+		}
+	}
+
+	response := CursorExamplesResponse{
+		Data:       examples,
+		Pagination: internal.CursorMeta{Limit: result.Limit},
+	}
+	if result.NextAfterID != nil {
+		nextCursor := internal.EncodeCursor(*result.NextAfterID)
+		response.Pagination.NextCursor = &nextCursor
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// SearchExamples runs a full-text search over the authenticated user's examples
+//
+//	@Summary		Search examples
+//	@Description	Full-text search over the titles and descriptions of the authenticated user's examples, ordered by relevance then recency
+//	@Tags			examples
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			q			query		string	true	"Search terms"
+//	@Param			page		query		int		false	"Page number (default: 1)"					default(1)
+//	@Param			page_size	query		int		false	"Page size (default: 20, min: 1, max: 100)"	default(20)
+//	@Success		200			{object}	PaginatedExamplesResponse
+//	@Header			200			{int}		X-Total-Count	"Total number of matching examples"
+//	@Header			200			{string}	Link			"RFC 5988 links to the next, prev and last pages"
+//	@Failure		400			{object}	ErrorResponse
+//	@Failure		401			{object}	ErrorResponse
+//	@Failure		500			{object}	ErrorResponse
+//	@Router			/api/v1/examples/search [get]
+func (h *Handler) SearchExamples(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	pagination, err := middleware.GetPaginationParamsFromContext(c, 20, 1, 100)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	result, err := h.service.SearchExamples(c.Request.Context(), userID, c.Query("q"), pagination.Page, pagination.PageSize)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to search examples", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	examples := make([]ExampleResponse, len(result.Data))
+	for i, ex := range result.Data {
+		examples[i] = ExampleResponse{
+			ID:          internal.JSONID(ex.ID),
+			UserID:      internal.JSONID(ex.UserID),
+			Title:       ex.Title,
+			Description: ex.Description.String,
+			Public:      ex.Public,
+			CreatedAt:   internal.FormatTimestamp(ex.CreatedAt),
+			UpdatedAt:   internal.FormatTimestamp(ex.UpdatedAt),
+		}
+	}
+
+	response := PaginatedExamplesResponse{
+		Data:       examples,
+		Pagination: internal.NewPaginationMeta(result.Total, result.Page, result.PageSize),
+	}
+
+	internal.SetPaginationHeaders(c, response.Pagination)
 	c.JSON(http.StatusOK, response)
 }
 
 // UpdateExample updates an existing example
 //
 //	@Summary		Update example
-//	@Description	Update an existing example for the authenticated user
+//	@Description	Update an existing example for the authenticated user (admins can update any example)
 //	@Tags			examples
 //	@Accept			json
 //	@Produce		json
@@ -212,20 +462,27 @@ func (h *Handler) UpdateExample(c *gin.Context) {
 		return
 	}
 
-	example, err := h.service.UpdateExample(c.Request.Context(), int32(id), userID, req.Title, req.Description)
+	ownerID, err := middleware.OwnerOrAdmin(c, userID, h.exampleOwner(int32(id)))
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	example, err := h.service.UpdateExample(c.Request.Context(), int32(id), ownerID, req.Title, req.Description, req.Public)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to update example", "error", err, "example_id", id, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to update example", err, "example_id", id, "user_id", userID)
 		errs.RespondWithError(c, err)
 		return
 	}
 
 	response := ExampleResponse{
-		ID:          example.ID,
-		UserID:      example.UserID,
+		ID:          internal.JSONID(example.ID),
+		UserID:      internal.JSONID(example.UserID),
 		Title:       example.Title,
 		Description: example.Description.String,
-		CreatedAt:   example.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-		UpdatedAt:   example.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+		Public:      example.Public,
+		CreatedAt:   internal.FormatTimestamp(example.CreatedAt),
+		UpdatedAt:   internal.FormatTimestamp(example.UpdatedAt),
 	}
 
 	c.JSON(http.StatusOK, ExampleDataResponse{Data: &response})
@@ -234,7 +491,7 @@ func (h *Handler) UpdateExample(c *gin.Context) {
 // DeleteExample deletes an example
 //
 //	@Summary		Delete example
-//	@Description	Delete an example for the authenticated user
+//	@Description	Delete an example for the authenticated user (admins can delete any example)
 //	@Tags			examples
 //	@Accept			json
 //	@Produce		json
@@ -260,9 +517,15 @@ func (h *Handler) DeleteExample(c *gin.Context) {
 		return
 	}
 
-	err = h.service.DeleteExample(c.Request.Context(), int32(id), userID)
+	ownerID, err := middleware.OwnerOrAdmin(c, userID, h.exampleOwner(int32(id)))
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	err = h.service.DeleteExample(c.Request.Context(), int32(id), ownerID)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to delete example", "error", err, "example_id", id, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to delete example", err, "example_id", id, "user_id", userID)
 		errs.RespondWithError(c, err)
 		return
 	}
@@ -271,3 +534,155 @@ func (h *Handler) DeleteExample(c *gin.Context) {
 	response.Data.Message = "Example deleted successfully"
 	c.JSON(http.StatusOK, response)
 }
+
+// parseTimeQuery reads an optional RFC 3339 query parameter, returning nil when it is absent
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	value := c.Query(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errs.WrapBadRequest(errs.ErrKeyBadRequest, fmt.Sprintf("invalid %s parameter (must be an RFC 3339 timestamp)", name), err).
+			WithDetails(map[string]interface{}{"field": name})
+	}
+	return &parsed, nil
+}
+
+// exampleOwner looks up who owns an example, for admin access through OwnerOrAdmin
+func (h *Handler) exampleOwner(exampleID int32) middleware.OwnerLookup {
+	return func(ctx context.Context) (int32, error) {
+		return h.service.GetExampleOwnerID(ctx, exampleID)
+	}
+}
+
+// ShareExample creates a signed link that grants read access to an example for 24 hours
+//
+//	@Summary		Share example
+//	@Description	Create a time-limited public link to an example owned by the authenticated user
+//	@Tags			examples
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int	true	"Example ID"
+//	@Success		200	{object}	ShareLinkDataResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/api/v1/examples/{id}/share [post]
+func (h *Handler) ShareExample(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid example ID")
+		return
+	}
+
+	// Only the owner may share an example
+	example, err := h.service.GetExample(c.Request.Context(), int32(id), userID)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to share example", err, "example_id", id, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	expiresAt := time.Now().Add(shareLinkTTL)
+	url, err := middleware.SignURL(h.shareSecret, fmt.Sprintf("%s/%d", h.sharePath, example.ID), expiresAt)
+	if err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to sign share link", "error", err, "example_id", id)
+		errs.RespondWithInternalError(c, "Failed to create share link")
+		return
+	}
+
+	c.JSON(http.StatusOK, ShareLinkDataResponse{Data: ShareLinkResponse{
+		URL:       url,
+		ExpiresAt: internal.FormatTime(expiresAt.UTC()),
+	}})
+}
+
+// GetSharedExample returns an example through a signed share link, no authentication required
+//
+//	@Summary		Get shared example
+//	@Description	Get an example via a link created by the share endpoint
+//	@Tags			examples
+//	@Produce		json
+//	@Param			id			path		int		true	"Example ID"
+//	@Param			expires		query		int		true	"Link expiry (unix seconds)"
+//	@Param			signature	query		string	true	"Link signature"
+//	@Success		200			{object}	ExampleDataResponse
+//	@Failure		403			{object}	ErrorResponse
+//	@Failure		404			{object}	ErrorResponse
+//	@Router			/api/v1/shared/examples/{id} [get]
+func (h *Handler) GetSharedExample(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid example ID")
+		return
+	}
+
+	example, err := h.service.GetSharedExample(c.Request.Context(), int32(id))
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := ExampleResponse{
+		ID:          internal.JSONID(example.ID),
+		UserID:      internal.JSONID(example.UserID),
+		Title:       example.Title,
+		Description: example.Description.String,
+		Public:      example.Public,
+		CreatedAt:   internal.FormatTimestamp(example.CreatedAt),
+		UpdatedAt:   internal.FormatTimestamp(example.UpdatedAt),
+	}
+
+	c.JSON(http.StatusOK, ExampleDataResponse{Data: &response})
+}
+
+// GetPublicExample returns a public example, or a private one to its owner
+//
+//	@Summary		Get public example
+//	@Description	Get an example that is public or owned by the caller, authentication is optional
+//	@Tags			examples
+//	@Produce		json
+//	@Param			id	path		int	true	"Example ID"
+//	@Success		200	{object}	ExampleDataResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/api/v1/examples/{id}/public [get]
+func (h *Handler) GetPublicExample(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid example ID")
+		return
+	}
+
+	// Anonymous callers only see public examples
+	var userID *int32
+	if authenticatedID, err := middleware.GetUserIDFromContext(c); err == nil {
+		userID = &authenticatedID
+	}
+
+	example, err := h.service.GetPublicExample(c.Request.Context(), int32(id), userID)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := ExampleResponse{
+		ID:          internal.JSONID(example.ID),
+		UserID:      internal.JSONID(example.UserID),
+		Title:       example.Title,
+		Description: example.Description.String,
+		Public:      example.Public,
+		CreatedAt:   internal.FormatTimestamp(example.CreatedAt),
+		UpdatedAt:   internal.FormatTimestamp(example.UpdatedAt),
+	}
+
+	c.JSON(http.StatusOK, ExampleDataResponse{Data: &response})
+}
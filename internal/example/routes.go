@@ -5,21 +5,47 @@ import (
 	"app/internal/middleware"
 )
 
-func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier) {
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier, apiKeys middleware.APIKeyVerifier) {
 	// Create service with only the dependencies it needs
 	service := NewExampleService(app.Queries)
 
+	// Public routes gated by a signed link instead of a user token
+	shareSecret := []byte(app.Config.SignedURLSecret)
+	shared := app.Api.Group("/shared/examples")
+	shared.Use(middleware.SignedURL(shareSecret))
+
 	// Create handler with only the service it needs
-	handler := NewHandler(service, app.Logger)
+	handler := NewHandler(service, app.Logger, shareSecret, shared.BasePath())
+
+	shared.GET("/:id", handler.GetSharedExample)
+
+	// Public examples, a token is optional and lets owners read their private ones
+	public := app.Api.Group("/examples")
+	public.Use(middleware.OptionalUserAuthMiddleware(authService))
+	{
+		public.GET("/:id/public", handler.GetPublicExample)
+	}
 
-	// Protected routes (require user authentication)
+	// Protected routes (require a user token or API key)
 	examples := app.Api.Group("/examples")
-	examples.Use(middleware.UserAuthMiddleware(authService))
+	examples.Use(middleware.UserOrAPIKeyAuthMiddleware(authService, apiKeys))
+	examples.Use(middleware.RequireJSON())
 	{
-		examples.POST("", handler.CreateExample)
+		examples.POST("", middleware.Idempotency(app.Cache), handler.CreateExample)
+		examples.POST("/batch", handler.CreateExamplesBatch)
 		examples.GET("", handler.ListExamples)
+		examples.GET("/search", handler.SearchExamples)
 		examples.GET("/:id", handler.GetExample)
 		examples.PUT("/:id", handler.UpdateExample)
 		examples.DELETE("/:id", handler.DeleteExample)
+		examples.POST("/:id/share", handler.ShareExample)
+	}
+
+	// Admin routes (require authentication and admin role)
+	admin := app.Api.Group("/admin/examples")
+	admin.Use(middleware.UserAuthMiddleware(authService))
+	admin.Use(middleware.RequireRole(middleware.RoleAdmin))
+	{
+		admin.GET("/:id", handler.GetExampleAdmin)
 	}
 }
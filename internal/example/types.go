@@ -10,38 +10,54 @@ type CreateExampleRequest struct {
 	Description string `json:"description"`
 }
 
+// CreateExamplesBatchRequest represents the request to create several examples at once
+type CreateExamplesBatchRequest struct {
+	Items []CreateExampleRequest `json:"items" binding:"required,min=1,dive"`
+}
+
 // UpdateExampleRequest represents the request to update an example
 type UpdateExampleRequest struct {
 	Title       string `json:"title" binding:"required"`
 	Description string `json:"description"`
+	// Public makes the example readable by anyone, omitted keeps the current visibility
+	Public *bool `json:"public"`
 }
 
 // ExampleResponse represents example information
 type ExampleResponse struct {
-	ID          int32  `json:"id"`
-	UserID      int32  `json:"user_id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID          internal.JSONID    `json:"id"`
+	UserID      internal.JSONID    `json:"user_id"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+	Public      bool               `json:"public"`
+	CreatedAt   internal.Timestamp `json:"created_at"`
+	UpdatedAt   internal.Timestamp `json:"updated_at"`
 }
 
 // ExampleDataResponse wraps example data in response
-type ExampleDataResponse struct {
-	Data *ExampleResponse `json:"data"`
-}
+type ExampleDataResponse = internal.DataResponse[*ExampleResponse]
 
 // PaginatedExamplesResponse wraps paginated examples in response
-type PaginatedExamplesResponse struct {
-	Data       []ExampleResponse      `json:"data"`
-	Pagination internal.PaginationMeta `json:"pagination"`
+type PaginatedExamplesResponse = internal.PaginatedResponse[ExampleResponse]
+
+// CursorExamplesResponse wraps a page of examples listed with a cursor
+type CursorExamplesResponse struct {
+	Data       []ExampleResponse   `json:"data"`
+	Pagination internal.CursorMeta `json:"pagination"`
 }
 
 // ExamplesListResponse wraps examples list in response
-type ExamplesListResponse struct {
-	Data []ExampleResponse `json:"data"`
+type ExamplesListResponse = internal.DataResponse[[]ExampleResponse]
+
+// ShareLinkResponse represents a time-limited public link to an example
+type ShareLinkResponse struct {
+	URL       string             `json:"url"`
+	ExpiresAt internal.Timestamp `json:"expires_at"`
 }
 
+// ShareLinkDataResponse wraps share link data in response
+type ShareLinkDataResponse = internal.DataResponse[ShareLinkResponse]
+
 // MessageResponse wraps a simple message in response
 type MessageResponse struct {
 	Data struct {
@@ -0,0 +1,148 @@
+package folders
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"app/internal/db"
+	"app/internal/errs"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrFolderNotFound      = errs.NewNotFoundError(errs.ErrKeyFolderNotFound, "Folder not found")
+	ErrFolderNotEmpty      = errs.NewDomainError(errs.ErrKeyFolderNotEmpty, "Folder still contains uploads", http.StatusConflict)
+	ErrFolderRootProtected = errs.NewBadRequestError(errs.ErrKeyFolderRootProtected, "The root folder can't be deleted")
+	ErrFolderNameRequired  = errs.NewBadRequestError(errs.ErrKeyValidationError, "Folder name is required")
+)
+
+// FolderService manages the folders users organize their uploads in.
+// Every user has a root folder, created the first time it is needed, which can't be deleted.
+type FolderService struct {
+	queries *db.Queries
+}
+
+// NewFolderService creates a new folder service
+func NewFolderService(queries *db.Queries) *FolderService {
+	return &FolderService{
+		queries: queries,
+	}
+}
+
+// RootFolder returns the user's root folder, creating it if it doesn't exist yet.
+// Safe to call concurrently, there is only ever one root folder per user.
+func (s *FolderService) RootFolder(ctx context.Context, userID int32) (*db.Folder, error) {
+	folder, err := s.queries.EnsureRootFolder(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to get root folder", err)
+	}
+	return &folder, nil
+}
+
+// CreateFolder creates a folder for the user. Names don't have to be unique.
+func (s *FolderService) CreateFolder(ctx context.Context, userID int32, name string) (*db.Folder, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrFolderNameRequired
+	}
+
+	folder, err := s.queries.CreateFolder(ctx, db.CreateFolderParams{
+		UserID: userID,
+		Name:   name,
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to create folder", err)
+	}
+	return &folder, nil
+}
+
+// ListFolders lists the user's folders, root folder first, then by name
+func (s *FolderService) ListFolders(ctx context.Context, userID int32) ([]db.Folder, error) {
+	if _, err := s.RootFolder(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	folders, err := s.queries.ListFoldersByUserID(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list folders", err)
+	}
+	return folders, nil
+}
+
+// GetFolder returns one of the user's folders
+// Returns ErrFolderNotFound if the folder doesn't exist or belongs to another user
+func (s *FolderService) GetFolder(ctx context.Context, folderID, userID int32) (*db.Folder, error) {
+	folder, err := s.queries.GetFolderByIDAndUserID(ctx, db.GetFolderByIDAndUserIDParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to get folder", err)
+	}
+	return &folder, nil
+}
+
+// RenameFolder renames one of the user's folders
+// Returns ErrFolderNotFound if the folder doesn't exist or belongs to another user
+func (s *FolderService) RenameFolder(ctx context.Context, folderID, userID int32, name string) (*db.Folder, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrFolderNameRequired
+	}
+
+	folder, err := s.queries.UpdateFolderName(ctx, db.UpdateFolderNameParams{
+		ID:     folderID,
+		UserID: userID,
+		Name:   name,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFolderNotFound
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to rename folder", err)
+	}
+	return &folder, nil
+}
+
+// DeleteFolder deletes one of the user's folders. Only empty folders can be deleted,
+// ErrFolderNotEmpty is returned while uploads still reference it, and the root folder
+// is never deleted (ErrFolderRootProtected).
+func (s *FolderService) DeleteFolder(ctx context.Context, folderID, userID int32) error {
+	folder, err := s.GetFolder(ctx, folderID, userID)
+	if err != nil {
+		return err
+	}
+	if folder.IsRoot {
+		return ErrFolderRootProtected
+	}
+
+	// The uploads foreign key decides whether the folder is empty, a count first would race with new uploads
+	deleted, err := s.queries.DeleteFolder(ctx, db.DeleteFolderParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if err != nil {
+		if isForeignKeyViolation(err) {
+			return ErrFolderNotEmpty
+		}
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete folder", err)
+	}
+	if deleted == 0 {
+		return ErrFolderNotFound
+	}
+	return nil
+}
+
+// isForeignKeyViolation reports whether err is a Postgres foreign key violation
+func isForeignKeyViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgerrcode.ForeignKeyViolation
+}
@@ -0,0 +1,228 @@
+package folders
+
+import (
+	"net/http"
+	"strconv"
+
+	"app/internal"
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/logger"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service *FolderService
+	logger  *logger.Logger
+}
+
+func NewHandler(service *FolderService, logger *logger.Logger) *Handler {
+	return &Handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListFolders lists the authenticated user's folders
+//
+//	@Summary		List folders
+//	@Description	List folders of the authenticated user, root folder first; the root folder is created on first use
+//	@Tags			folders
+//	@Produce		json
+//	@Security		Bearer
+//	@Success		200	{object}	FoldersListResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/api/v1/folders [get]
+func (h *Handler) ListFolders(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	folders, err := h.service.ListFolders(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to list folders", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	response := make([]FolderResponse, len(folders))
+	for i := range folders {
+		response[i] = toFolderResponse(&folders[i])
+	}
+
+	c.JSON(http.StatusOK, FoldersListResponse{Data: response})
+}
+
+// CreateFolder creates a folder for the authenticated user
+//
+//	@Summary		Create folder
+//	@Description	Create a folder to upload files into
+//	@Tags			folders
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		FolderRequest	true	"Folder details"
+//	@Success		201		{object}	FolderDataResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/folders [post]
+func (h *Handler) CreateFolder(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	folder, err := h.service.CreateFolder(c.Request.Context(), userID, req.Name)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to create folder", err, "user_id", userID)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, FolderDataResponse{Data: toFolderResponse(folder)})
+}
+
+// GetFolder returns one of the authenticated user's folders
+//
+//	@Summary		Get folder
+//	@Description	Get a folder owned by the authenticated user
+//	@Tags			folders
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int	true	"Folder ID"
+//	@Success		200	{object}	FolderDataResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Router			/api/v1/folders/{id} [get]
+func (h *Handler) GetFolder(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	folderID, ok := parseFolderID(c)
+	if !ok {
+		return
+	}
+
+	folder, err := h.service.GetFolder(c.Request.Context(), folderID, userID)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, FolderDataResponse{Data: toFolderResponse(folder)})
+}
+
+// RenameFolder renames one of the authenticated user's folders
+//
+//	@Summary		Rename folder
+//	@Description	Rename a folder owned by the authenticated user
+//	@Tags			folders
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id		path		int				true	"Folder ID"
+//	@Param			request	body		FolderRequest	true	"New name"
+//	@Success		200		{object}	FolderDataResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Router			/api/v1/folders/{id} [patch]
+func (h *Handler) RenameFolder(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	folderID, ok := parseFolderID(c)
+	if !ok {
+		return
+	}
+
+	var req FolderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	folder, err := h.service.RenameFolder(c.Request.Context(), folderID, userID, req.Name)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, FolderDataResponse{Data: toFolderResponse(folder)})
+}
+
+// DeleteFolder deletes one of the authenticated user's folders
+//
+//	@Summary		Delete folder
+//	@Description	Delete an empty folder owned by the authenticated user; the root folder can't be deleted
+//	@Tags			folders
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id	path		int	true	"Folder ID"
+//	@Success		200	{object}	MessageResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Router			/api/v1/folders/{id} [delete]
+func (h *Handler) DeleteFolder(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	folderID, ok := parseFolderID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteFolder(c.Request.Context(), folderID, userID); err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	var response MessageResponse
+	response.Data.Message = "Folder deleted successfully"
+	c.JSON(http.StatusOK, response)
+}
+
+// parseFolderID reads the :id path parameter, responding with 400 when it isn't a valid ID
+func parseFolderID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyBadRequest, "Invalid folder ID")
+		return 0, false
+	}
+	return int32(id), true
+}
+
+func toFolderResponse(folder *db.Folder) FolderResponse {
+	return FolderResponse{
+		ID:        folder.ID,
+		Name:      folder.Name,
+		IsRoot:    folder.IsRoot,
+		CreatedAt: internal.FormatTimestamp(folder.CreatedAt),
+		UpdatedAt: internal.FormatTimestamp(folder.UpdatedAt),
+	}
+}
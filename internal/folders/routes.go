@@ -0,0 +1,23 @@
+package folders
+
+import (
+	"app/internal"
+	"app/internal/middleware"
+)
+
+// RegisterRoutes registers folder routes
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier) {
+	service := NewFolderService(app.Queries)
+	handler := NewHandler(service, app.Logger)
+
+	folders := app.Api.Group("/folders")
+	folders.Use(middleware.UserAuthMiddleware(authService))
+	folders.Use(middleware.RequireJSON())
+	{
+		folders.GET("", handler.ListFolders)
+		folders.POST("", handler.CreateFolder)
+		folders.GET("/:id", handler.GetFolder)
+		folders.PATCH("/:id", handler.RenameFolder)
+		folders.DELETE("/:id", handler.DeleteFolder)
+	}
+}
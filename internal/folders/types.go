@@ -0,0 +1,39 @@
+package folders
+
+import "app/internal"
+
+// FolderRequest represents the request to create or rename a folder
+type FolderRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// FolderResponse represents folder information
+type FolderResponse struct {
+	ID        int32              `json:"id"`
+	Name      string             `json:"name"`
+	IsRoot    bool               `json:"is_root"`
+	CreatedAt internal.Timestamp `json:"created_at"`
+	UpdatedAt internal.Timestamp `json:"updated_at"`
+}
+
+// FolderDataResponse wraps a folder in response
+type FolderDataResponse struct {
+	Data FolderResponse `json:"data"`
+}
+
+// FoldersListResponse wraps the folder list in response
+type FoldersListResponse struct {
+	Data []FolderResponse `json:"data"`
+}
+
+// MessageResponse wraps a simple message in response
+type MessageResponse struct {
+	Data struct {
+		Message string `json:"message"`
+	} `json:"data"`
+}
+
+// ErrorResponse represents error response structure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
@@ -0,0 +1,132 @@
+package health
+
+import (
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"app/config"
+	"app/internal"
+	"app/internal/buildinfo"
+	"app/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	config       *config.Config
+	service      *HealthService
+	logger       *logger.Logger
+	startedAt    time.Time
+	shuttingDown atomic.Bool
+}
+
+// NewHandler creates the health handler. startedAt is when the process started, for the
+// uptime reported by /health.
+func NewHandler(cfg *config.Config, service *HealthService, logger *logger.Logger, startedAt time.Time) *Handler {
+	return &Handler{
+		config:    cfg,
+		service:   service,
+		logger:    logger,
+		startedAt: startedAt,
+	}
+}
+
+// MarkShuttingDown makes the health check fail so load balancers stop routing new traffic
+func (h *Handler) MarkShuttingDown() {
+	h.shuttingDown.Store(true)
+}
+
+// Health reports whether the process is alive. It never touches the database or Redis, so
+// an outage there doesn't get the process restarted; the only self-check is the goroutine
+// count, which keeps climbing when requests pile up behind something stuck.
+//
+//	@Summary		Health check
+//	@Description	Liveness check with uptime, returns 503 once graceful shutdown has started or when more goroutines than HEALTH_MAX_GOROUTINES are running
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	HealthResponse
+//	@Failure		503	{object}	HealthResponse
+//	@Router			/health [get]
+func (h *Handler) Health(c *gin.Context) {
+	response := HealthResponse{
+		Status:        "healthy",
+		App:           h.config.AppName,
+		Version:       h.config.AppVersion,
+		Env:           h.config.Environment,
+		StartedAt:     internal.FormatTime(h.startedAt.UTC()),
+		UptimeSeconds: max(int64(time.Since(h.startedAt).Seconds()), 0),
+		Goroutines:    runtime.NumGoroutine(),
+	}
+
+	if h.shuttingDown.Load() {
+		response.Status = "shutting_down"
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	if limit := h.config.HealthMaxGoroutines; limit > 0 && response.Goroutines > limit {
+		h.logger.WarnContext(c.Request.Context(), "Liveness check failed", "goroutines", response.Goroutines, "limit", limit)
+		response.Status = "unhealthy"
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Ready reports whether the database and Redis are reachable
+//
+//	@Summary		Readiness check
+//	@Description	Pings the database and Redis, returns 503 with per-dependency status when either is down
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	ReadinessResponse
+//	@Failure		503	{object}	ReadinessResponse
+//	@Router			/health/ready [get]
+func (h *Handler) Ready(c *gin.Context) {
+	ctx := c.Request.Context()
+	response := ReadinessResponse{
+		Database: StatusOK,
+		Redis:    StatusOK,
+	}
+	ready := true
+
+	if err := h.service.PingDatabase(ctx); err != nil {
+		h.logger.WarnContext(ctx, "Readiness check failed", "dependency", "database", "error", err)
+		response.Database = StatusDown
+		ready = false
+	}
+
+	if err := h.service.PingCache(ctx); err != nil {
+		h.logger.WarnContext(ctx, "Readiness check failed", "dependency", "redis", "error", err)
+		response.Redis = StatusDown
+		ready = false
+	}
+
+	if !ready || h.shuttingDown.Load() {
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Version reports the build of the running binary
+//
+//	@Summary		Build info
+//	@Description	Version, git commit and build time injected at build time ("dev"/"unknown" for local builds)
+//	@Tags			health
+//	@Produce		json
+//	@Success		200	{object}	VersionResponse
+//	@Router			/version [get]
+func (h *Handler) Version(c *gin.Context) {
+	info := buildinfo.Get()
+	c.JSON(http.StatusOK, VersionResponse{
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		GoVersion: info.GoVersion,
+	})
+}
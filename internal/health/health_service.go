@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"app/internal/cache"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	StatusOK   = "ok"
+	StatusDown = "down"
+
+	// readinessTimeout bounds each dependency ping so a hung backend can't stall the probe
+	readinessTimeout = 2 * time.Second
+)
+
+var errNotConfigured = errors.New("dependency not configured")
+
+type HealthService struct {
+	pool  *pgxpool.Pool
+	cache cache.Cache
+}
+
+func NewHealthService(pool *pgxpool.Pool, cacheService cache.Cache) *HealthService {
+	return &HealthService{
+		pool:  pool,
+		cache: cacheService,
+	}
+}
+
+// PingDatabase checks that a connection can be acquired from the pool
+func (s *HealthService) PingDatabase(ctx context.Context) error {
+	if s.pool == nil {
+		return errNotConfigured
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	return s.pool.Ping(ctx)
+}
+
+// PingCache checks that the cache backend is reachable
+func (s *HealthService) PingCache(ctx context.Context) error {
+	if s.cache == nil {
+		return errNotConfigured
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	return s.cache.Ping(ctx)
+}
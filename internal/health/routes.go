@@ -0,0 +1,12 @@
+package health
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes registers liveness, readiness and version routes on the root router
+func RegisterRoutes(r gin.IRoutes, handler *Handler) {
+	r.GET("/health", handler.Health)
+	r.GET("/health/ready", handler.Ready)
+	r.GET("/version", handler.Version)
+}
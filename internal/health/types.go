@@ -0,0 +1,29 @@
+package health
+
+import "app/internal"
+
+// HealthResponse represents the liveness check response
+type HealthResponse struct {
+	Status        string             `json:"status"`
+	App           string             `json:"app"`
+	Version       string             `json:"version"`
+	Env           string             `json:"env"`
+	StartedAt     internal.Timestamp `json:"started_at"`
+	UptimeSeconds int64              `json:"uptime_seconds"`
+	Goroutines    int                `json:"goroutines"`
+}
+
+// ReadinessResponse reports the status of each dependency ("ok" or "down").
+// Redis is the status of the cache, which runs on Redis outside of tests.
+type ReadinessResponse struct {
+	Database string `json:"database"`
+	Redis    string `json:"redis"`
+}
+
+// VersionResponse describes the build running behind the API
+type VersionResponse struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
@@ -0,0 +1,52 @@
+package internal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// stringIDs switches every JSONID to a quoted string, see SetStringIDs
+var stringIDs atomic.Bool
+
+// SetStringIDs makes every JSONID marshal as a string ("42") instead of a number (42).
+// Set once at startup from JSON_STRING_IDS, it applies to all responses.
+func SetStringIDs(enabled bool) {
+	stringIDs.Store(enabled)
+}
+
+// StringIDs reports whether JSONIDs are marshaled as strings
+func StringIDs() bool {
+	return stringIDs.Load()
+}
+
+// JSONID is a record ID in a response. IDs are int32 today and fit a JSON number, but
+// JavaScript clients lose precision above 2^53 once they grow into bigint, so clients can
+// get them as strings ahead of that with JSON_STRING_IDS. Either form is accepted when
+// decoding, so the same types work in clients and tests whichever way the flag is set.
+type JSONID int64
+
+func (id JSONID) MarshalJSON() ([]byte, error) {
+	if stringIDs.Load() {
+		return strconv.AppendQuote(nil, strconv.FormatInt(int64(id), 10)), nil
+	}
+	return strconv.AppendInt(nil, int64(id), 10), nil
+}
+
+func (id *JSONID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+	parsed, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid ID %s: %w", data, err)
+	}
+	*id = JSONID(parsed)
+	return nil
+}
@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
@@ -82,9 +84,15 @@ func New(cfg Config) (*Logger, error) {
 		handler = slog.NewTextHandler(writer, opts)
 	}
 
+	return NewWithHandler(handler), nil
+}
+
+// NewWithHandler creates a logger writing through handler, with the request ID
+// from the context added to every record and sensitive values (see IsSensitiveKey) redacted
+func NewWithHandler(handler slog.Handler) *Logger {
 	return &Logger{
-		Logger: slog.New(handler),
-	}, nil
+		Logger: slog.New(contextHandler{Handler: redactHandler{Handler: handler}}),
+	}
 }
 
 // createLogFile creates log file with proper permissions
@@ -105,3 +113,17 @@ func createLogFile(filename string) (*os.File, error) {
 func (l *Logger) WithError(err error) *slog.Logger {
 	return l.With("error", err)
 }
+
+// FailureContext logs a failed operation with err under "error". It logs at error level,
+// except when err comes from the request's context: a client that went away is logged at
+// info and an expired deadline at warn, so abandoned requests don't show up as server errors.
+func (l *Logger) FailureContext(ctx context.Context, msg string, err error, args ...any) {
+	level := slog.LevelError
+	switch {
+	case errors.Is(err, context.Canceled):
+		level = slog.LevelInfo
+	case errors.Is(err, context.DeadlineExceeded):
+		level = slog.LevelWarn
+	}
+	l.Log(ctx, level, msg, append([]any{"error", err}, args...)...)
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces the value of every sensitive attribute or JSON key
+const Redacted = "[REDACTED]"
+
+// sensitiveKeyParts mark a key as sensitive when its lowercased name contains one of them,
+// so "password", "new_password", "refresh_token" and "X-Api-Key" are all covered
+var sensitiveKeyParts = []string{"password", "token", "secret", "authorization", "api_key", "apikey", "api-key", "cookie"}
+
+// IsSensitiveKey reports whether values logged under key must be redacted
+func IsSensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, part := range sensitiveKeyParts {
+		if strings.Contains(key, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact returns a copy of a decoded JSON value with the values of sensitive keys replaced
+// by Redacted, at any depth. Values that aren't maps or slices are returned as they are.
+func Redact(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, item := range v {
+			if IsSensitiveKey(key) {
+				redacted[key] = Redacted
+				continue
+			}
+			redacted[key] = Redact(item)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, item := range v {
+			redacted[i] = Redact(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// redactHandler hides the values of sensitive attributes, including keys inside groups
+// and decoded JSON logged with slog.Any, before they reach the output
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		redacted.AddAttrs(redactAttr(attr))
+		return true
+	})
+	return h.Handler.Handle(ctx, redacted)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, attr := range attrs {
+		redacted[i] = redactAttr(attr)
+	}
+	return redactHandler{Handler: h.Handler.WithAttrs(redacted)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+func redactAttr(attr slog.Attr) slog.Attr {
+	if IsSensitiveKey(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		redacted := make([]any, len(group))
+		for i, member := range group {
+			redacted[i] = redactAttr(member)
+		}
+		return slog.Group(attr.Key, redacted...)
+	case slog.KindAny:
+		switch attr.Value.Any().(type) {
+		case map[string]any, []any:
+			return slog.Any(attr.Key, Redact(attr.Value.Any()))
+		}
+	}
+	return attr
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// Sampler caps how many records per second are logged for each key, e.g. a route template.
+// Each key has a token bucket holding up to perSecond tokens that refills at perSecond a
+// second, so bursts up to the limit get through and the steady rate is capped at it.
+// Buckets are never evicted: keep keys low-cardinality (route templates, not raw paths).
+type Sampler struct {
+	perSecond float64
+	now       func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*sampleBucket
+}
+
+type sampleBucket struct {
+	tokens  float64
+	last    time.Time
+	dropped int64
+}
+
+// NewSampler returns a sampler keeping perSecond records per key each second, nil when
+// perSecond is 0 or less. A nil Sampler keeps everything.
+func NewSampler(perSecond int) *Sampler {
+	if perSecond <= 0 {
+		return nil
+	}
+	return &Sampler{
+		perSecond: float64(perSecond),
+		now:       time.Now,
+		buckets:   make(map[string]*sampleBucket),
+	}
+}
+
+// Allow reports whether a record for key should be logged. When it should, dropped is how
+// many records for key were left out since the last one kept, worth logging alongside it.
+func (s *Sampler) Allow(key string) (ok bool, dropped int64) {
+	if s == nil {
+		return true, 0
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	bucket, found := s.buckets[key]
+	if !found {
+		bucket = &sampleBucket{tokens: s.perSecond, last: now}
+		s.buckets[key] = bucket
+	}
+
+	bucket.tokens = min(bucket.tokens+now.Sub(bucket.last).Seconds()*s.perSecond, s.perSecond)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		bucket.dropped++
+		return false, 0
+	}
+
+	bucket.tokens--
+	dropped, bucket.dropped = bucket.dropped, 0
+	return true, dropped
+}
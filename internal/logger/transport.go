@@ -0,0 +1,37 @@
+package logger
+
+import "net/http"
+
+// RequestIDHeader carries the request ID between services
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDTransport is an http.RoundTripper that copies the request ID stored in the
+// outbound request's context into its X-Request-ID header, so calls made while handling
+// a request can be correlated with it downstream. Requests that already set the header
+// or carry no request ID are sent unchanged.
+type RequestIDTransport struct {
+	// Base sends the requests, http.DefaultTransport when nil
+	Base http.RoundTripper
+}
+
+// NewRequestIDTransport wraps base, which may be nil to use http.DefaultTransport
+func NewRequestIDTransport(base http.RoundTripper) *RequestIDTransport {
+	return &RequestIDTransport{Base: base}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if requestID := RequestIDFromContext(req.Context()); requestID != "" && req.Header.Get(RequestIDHeader) == "" {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(req.Context())
+		req.Header.Set(RequestIDHeader, requestID)
+	}
+	return t.base().RoundTrip(req)
+}
+
+func (t *RequestIDTransport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
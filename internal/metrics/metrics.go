@@ -0,0 +1,152 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	appredis "app/internal/redis"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
+)
+
+// unmatchedRoute labels requests that matched no route, keeping raw paths out of label values
+const unmatchedRoute = "unmatched"
+
+// Metrics owns a Prometheus registry with the HTTP, cache and connection pool metrics
+type Metrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	cacheLookups *prometheus.CounterVec
+}
+
+// New creates the registry with HTTP and cache metrics plus the Go runtime and process collectors.
+// Connection pools are added with RegisterDBPool and RegisterRedisPool.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests handled, by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency, by method, route template and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "cache_lookups_total",
+			Help: "Cache lookups, by result (hit or miss).",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.cacheLookups,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// Registry returns the registry the metrics are exported from
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Middleware records a count and latency for every request, labelled by the route
+// template (c.FullPath()) so path parameters don't create a series per ID
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+
+		m.requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		m.duration.WithLabelValues(c.Request.Method, route, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Handler serves the registry in the Prometheus text format
+func (m *Metrics) Handler() gin.HandlerFunc {
+	return gin.WrapH(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// CacheHit counts a cache lookup that found its key; implements cache.Observer
+func (m *Metrics) CacheHit() {
+	m.cacheLookups.WithLabelValues("hit").Inc()
+}
+
+// CacheMiss counts a cache lookup that did not find its key; implements cache.Observer
+func (m *Metrics) CacheMiss() {
+	m.cacheLookups.WithLabelValues("miss").Inc()
+}
+
+// RegisterDBPool exports pgx pool connection gauges, read from pool.Stat() at scrape time
+func (m *Metrics) RegisterDBPool(pool *pgxpool.Pool) {
+	gauge := func(name, help string, value func(*pgxpool.Stat) float64) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return value(pool.Stat())
+		})
+	}
+
+	m.registry.MustRegister(
+		gauge("db_pool_acquired_connections", "Database connections currently in use.", func(s *pgxpool.Stat) float64 {
+			return float64(s.AcquiredConns())
+		}),
+		gauge("db_pool_idle_connections", "Idle database connections in the pool.", func(s *pgxpool.Stat) float64 {
+			return float64(s.IdleConns())
+		}),
+		gauge("db_pool_total_connections", "Open database connections.", func(s *pgxpool.Stat) float64 {
+			return float64(s.TotalConns())
+		}),
+		gauge("db_pool_max_connections", "Configured maximum database connections.", func(s *pgxpool.Stat) float64 {
+			return float64(s.MaxConns())
+		}),
+	)
+}
+
+// RegisterRedisPool exports the Redis pool stats from redis.GetPoolStats
+func (m *Metrics) RegisterRedisPool(client *redis.Client) {
+	counter := func(name, help string, value func(appredis.PoolStats) uint32) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(appredis.GetPoolStats(client)))
+		})
+	}
+	gauge := func(name, help string, value func(appredis.PoolStats) uint32) prometheus.Collector {
+		return prometheus.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(appredis.GetPoolStats(client)))
+		})
+	}
+
+	m.registry.MustRegister(
+		counter("redis_pool_hits_total", "Times a free Redis connection was found in the pool.", func(s appredis.PoolStats) uint32 {
+			return s.Hits
+		}),
+		counter("redis_pool_misses_total", "Times a new Redis connection had to be dialed.", func(s appredis.PoolStats) uint32 {
+			return s.Misses
+		}),
+		counter("redis_pool_timeouts_total", "Times waiting for a Redis connection timed out.", func(s appredis.PoolStats) uint32 {
+			return s.Timeouts
+		}),
+		gauge("redis_pool_total_connections", "Open Redis connections.", func(s appredis.PoolStats) uint32 {
+			return s.TotalConns
+		}),
+		gauge("redis_pool_idle_connections", "Idle Redis connections in the pool.", func(s appredis.PoolStats) uint32 {
+			return s.IdleConns
+		}),
+	)
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader is the request header carrying an API key
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyVerifier resolves a plaintext API key to the user that owns it
+type APIKeyVerifier interface {
+	VerifyAPIKey(ctx context.Context, key string) (int32, error)
+}
+
+// UserOrAPIKeyAuthMiddleware authenticates with an X-API-Key header when present,
+// otherwise it falls back to the JWT checks of UserAuthMiddleware.
+// API keys authenticate the owning user without any roles, so they never pass RequireRole.
+func UserOrAPIKeyAuthMiddleware(verifier UserJWTVerifier, keyVerifier APIKeyVerifier) gin.HandlerFunc {
+	userAuth := UserAuthMiddleware(verifier)
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			userAuth(c)
+			return
+		}
+
+		userID, err := keyVerifier.VerifyAPIKey(c.Request.Context(), key)
+		if err != nil {
+			errs.RespondWithError(c, err)
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", userID)
+		c.Set("user_roles", []string{})
+
+		c.Next()
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"mime"
+	"strings"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIVersionHeader selects the API version of a request and reports the one that served it
+	APIVersionHeader = "X-API-Version"
+	// APIVersionKey is the gin context key holding the resolved API version
+	APIVersionKey = "api_version"
+)
+
+// SupportedAPIVersions lists the versions clients may ask for, oldest first.
+// The last one is used when a request doesn't ask.
+var SupportedAPIVersions = []string{"1"}
+
+// APIVersion resolves the version a client asked for with the X-API-Version header, or a
+// version parameter on Accept ("application/json; version=1") when the header is absent.
+// Requests that don't ask get the latest of supported; unknown versions get a 400
+// unsupported_api_version listing the supported ones. Handlers read it with GetAPIVersion.
+func APIVersion(supported ...string) gin.HandlerFunc {
+	latest := supported[len(supported)-1]
+
+	return func(c *gin.Context) {
+		version := requestedAPIVersion(c)
+		if version == "" {
+			version = latest
+		} else if !isSupportedAPIVersion(version, supported) {
+			errs.RespondWithError(c, errs.NewBadRequestError(
+				errs.ErrKeyUnsupportedAPIVersion,
+				"Unsupported API version",
+			).WithDetails(map[string]interface{}{
+				"version":            version,
+				"supported_versions": supported,
+			}))
+			c.Abort()
+			return
+		}
+
+		c.Set(APIVersionKey, version)
+		c.Header(APIVersionHeader, version)
+		c.Next()
+	}
+}
+
+// GetAPIVersion returns the version resolved by the APIVersion middleware, "" when it isn't installed
+func GetAPIVersion(c *gin.Context) string {
+	return c.GetString(APIVersionKey)
+}
+
+func requestedAPIVersion(c *gin.Context) string {
+	if version := strings.TrimSpace(c.GetHeader(APIVersionHeader)); version != "" {
+		return version
+	}
+
+	for _, mediaRange := range strings.Split(c.GetHeader("Accept"), ",") {
+		_, params, err := mime.ParseMediaType(strings.TrimSpace(mediaRange))
+		if err != nil {
+			continue
+		}
+		if version := strings.TrimSpace(params["version"]); version != "" {
+			return version
+		}
+	}
+	return ""
+}
+
+func isSupportedAPIVersion(version string, supported []string) bool {
+	for _, candidate := range supported {
+		if version == candidate {
+			return true
+		}
+	}
+	return false
+}
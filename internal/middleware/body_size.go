@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// originalBodyKey keeps the unlimited request body so a later MaxBodySize can replace the limit
+const originalBodyKey = "max_body_size_original"
+
+// MaxBodySize caps how many bytes handlers can read from the request body. Reading past
+// the limit fails with *http.MaxBytesError, which errs.RespondWithValidationError and
+// errs.AsRequestTooLarge turn into a 413 request_too_large.
+// Installed again on a route, the route's limit replaces the global one, so it can be raised as well as lowered.
+func MaxBodySize(limit int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		body := c.Request.Body
+		if original, ok := c.Get(originalBodyKey); ok {
+			body = original.(io.ReadCloser)
+		} else {
+			c.Set(originalBodyKey, body)
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, body, limit)
+		c.Next()
+	}
+}
@@ -0,0 +1,260 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	EncodingGzip    = "gzip"
+	EncodingDeflate = "deflate"
+	EncodingBrotli  = "br"
+)
+
+// DefaultSkipContentTypes are formats that are already compressed, so re-encoding them
+// costs CPU without making the response smaller
+var DefaultSkipContentTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"video/",
+	"audio/",
+	"application/zip",
+	"application/gzip",
+	"application/x-gzip",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/pdf",
+}
+
+// CompressionConfig controls the response compression middleware
+type CompressionConfig struct {
+	// MinSize is the smallest body (in bytes) worth compressing; smaller bodies are sent as-is
+	MinSize int
+	// EnableBrotli offers br to clients that advertise it, gzip and deflate are always available
+	EnableBrotli bool
+	// SkipContentTypes lists Content-Type prefixes that are sent as-is; nil uses DefaultSkipContentTypes
+	SkipContentTypes []string
+}
+
+// Compression compresses response bodies with the best encoding the client accepts.
+// The body is buffered until MinSize bytes are written so tiny responses skip compression.
+func Compression(cfg CompressionConfig) gin.HandlerFunc {
+	skipContentTypes := cfg.SkipContentTypes
+	if skipContentTypes == nil {
+		skipContentTypes = DefaultSkipContentTypes
+	}
+
+	return func(c *gin.Context) {
+		c.Header("Vary", "Accept-Encoding")
+
+		if c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		encoding := NegotiateEncoding(c.GetHeader("Accept-Encoding"), cfg.EnableBrotli)
+		if encoding == "" {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter:   c.Writer,
+			encoding:         encoding,
+			minSize:          cfg.MinSize,
+			skipContentTypes: skipContentTypes,
+		}
+		c.Writer = writer
+		defer func() {
+			writer.finish()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// NegotiateEncoding picks the preferred supported encoding from an Accept-Encoding header.
+// Ties go to brotli, then gzip, then deflate; an empty string means the response should not be compressed.
+func NegotiateEncoding(acceptEncoding string, enableBrotli bool) string {
+	if acceptEncoding == "" {
+		return ""
+	}
+
+	weights := map[string]float64{}
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		weights[name] = q
+	}
+
+	weight := func(name string) float64 {
+		if q, ok := weights[name]; ok {
+			return q
+		}
+		if q, ok := weights["*"]; ok {
+			return q
+		}
+		return 0
+	}
+
+	candidates := []string{EncodingGzip, EncodingDeflate}
+	if enableBrotli {
+		candidates = append([]string{EncodingBrotli}, candidates...)
+	}
+
+	best, bestWeight := "", 0.0
+	for _, name := range candidates {
+		if q := weight(name); q > bestWeight {
+			best, bestWeight = name, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the start of the body and only switches to compression
+// once the configured threshold has been reached
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding         string
+	minSize          int
+	skipContentTypes []string
+	buffer           bytes.Buffer
+	encoder          io.WriteCloser
+	decided          bool
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.encoder != nil {
+			return w.encoder.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.start(w.shouldCompress()); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// WriteHeader ignores status changes once body bytes are buffered, as gin does once they are sent
+func (w *compressWriter) WriteHeader(code int) {
+	if w.buffer.Len() > 0 {
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Written reports a response as started once its first bytes are buffered, even though
+// nothing has reached the client yet, so later middleware doesn't write a second one
+func (w *compressWriter) Written() bool {
+	return w.buffer.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush sends buffered data immediately; a stream flushed before reaching the threshold stays uncompressed
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		if err := w.start(false); err != nil {
+			return
+		}
+	}
+	if flusher, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = flusher.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend write deadlines
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) shouldCompress() bool {
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" || header.Get("Content-Range") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, skip := range w.skipContentTypes {
+		if strings.HasPrefix(contentType, skip) {
+			return false
+		}
+	}
+	status := w.Status()
+	return status != http.StatusNoContent && status != http.StatusNotModified && status != http.StatusPartialContent
+}
+
+// start commits to an encoding and writes out whatever has been buffered so far
+func (w *compressWriter) start(compress bool) error {
+	w.decided = true
+
+	if compress {
+		header := w.Header()
+		header.Set("Content-Encoding", w.encoding)
+		header.Del("Content-Length")
+
+		switch w.encoding {
+		case EncodingBrotli:
+			w.encoder = brotli.NewWriter(w.ResponseWriter)
+		case EncodingDeflate:
+			// The deflate content-coding is zlib-wrapped (RFC 9110 8.4.1.2), not raw DEFLATE.
+			// Only fails for an invalid level
+			w.encoder, _ = zlib.NewWriterLevel(w.ResponseWriter, zlib.DefaultCompression)
+		default:
+			w.encoder = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+
+	var err error
+	if w.encoder != nil {
+		_, err = w.encoder.Write(w.buffer.Bytes())
+	} else {
+		_, err = w.ResponseWriter.Write(w.buffer.Bytes())
+	}
+	w.buffer.Reset()
+	return err
+}
+
+// finish flushes sub-threshold bodies uncompressed and closes the encoder
+func (w *compressWriter) finish() {
+	if !w.decided {
+		_ = w.start(false)
+	}
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+	}
+}
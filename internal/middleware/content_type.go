@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSON rejects POST, PUT and PATCH requests whose body isn't application/json with a
+// 415 unsupported_media_type, instead of letting ShouldBindJSON fail on form data with a
+// confusing binding error. Requests without a body pass, so actions that take no input
+// don't need a Content-Type. Don't install it on multipart upload routes.
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if c.Request.ContentLength == 0 {
+			c.Next()
+			return
+		}
+
+		contentType := c.GetHeader("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || mediaType != "application/json" {
+			errs.RespondWithError(c, errs.NewDomainError(
+				errs.ErrKeyUnsupportedMediaType,
+				"Content-Type must be application/json",
+				http.StatusUnsupportedMediaType,
+			).WithDetails(map[string]interface{}{
+				"content_type": contentType,
+			}))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"app/internal/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// debugBodyMaxBytes is the largest body CaptureBodies decodes; bigger ones are only counted
+	debugBodyMaxBytes = 64 << 10
+	// debugBodyLogBytes caps how much of a redacted body ends up in the log line
+	debugBodyLogBytes = 4 << 10
+)
+
+// CaptureBodies logs the JSON request and response bodies of every request at debug level,
+// for chasing client issues with DEBUG_CAPTURE_BODIES (never in production). Bodies are
+// decoded and the values of sensitive keys (see logger.IsSensitiveKey) redacted before
+// anything is logged, then cut to debugBodyLogBytes. Only application/json bodies are
+// captured: multipart uploads and streamed downloads pass through untouched, and bodies
+// over debugBodyMaxBytes are only counted, so capture never holds a large body in memory.
+// Register it after Compression so it sees the response before it is encoded.
+func CaptureBodies(log *logger.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestBody := captureRequestBody(c.Request)
+
+		writer := &captureWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() { c.Writer = writer.ResponseWriter }()
+
+		c.Next()
+
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+		}
+		if requestBody != nil {
+			attrs = append(attrs, "request_body", debugBody(requestBody.body, requestBody.truncated))
+		}
+		if writer.capturing() {
+			attrs = append(attrs, "response_body", debugBody(writer.body.Bytes(), writer.truncated))
+		}
+		log.DebugContext(c.Request.Context(), "HTTP bodies", attrs...)
+	}
+}
+
+// capturedBody is the start of a request body, truncated when the body was longer than debugBodyMaxBytes
+type capturedBody struct {
+	body      []byte
+	truncated bool
+}
+
+// captureRequestBody reads up to debugBodyMaxBytes of a JSON request body and puts
+// it back in front of the rest, so handlers still read the whole body
+func captureRequestBody(req *http.Request) *capturedBody {
+	if req.Body == nil || req.Body == http.NoBody || !isJSONContentType(req.Header.Get("Content-Type")) {
+		return nil
+	}
+
+	head, err := io.ReadAll(io.LimitReader(req.Body, debugBodyMaxBytes+1))
+	req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(head), req.Body), Closer: req.Body}
+	if err != nil {
+		return nil
+	}
+
+	if len(head) > debugBodyMaxBytes {
+		return &capturedBody{body: head[:debugBodyMaxBytes], truncated: true}
+	}
+	return &capturedBody{body: head}
+}
+
+// debugBody renders a captured body for the log. Anything that can't be decoded, and so
+// can't be redacted, is replaced by its size.
+func debugBody(body []byte, truncated bool) string {
+	if truncated {
+		return fmt.Sprintf("[not captured: over %d bytes]", debugBodyMaxBytes)
+	}
+	if len(body) == 0 {
+		return ""
+	}
+
+	var decoded any
+	if err := json.Unmarshal(body, &decoded); err != nil {
+		return fmt.Sprintf("[not captured: %d bytes of invalid JSON]", len(body))
+	}
+	redacted, err := json.Marshal(logger.Redact(decoded))
+	if err != nil {
+		return fmt.Sprintf("[not captured: %d bytes]", len(body))
+	}
+
+	if len(redacted) > debugBodyLogBytes {
+		return string(redacted[:debugBodyLogBytes]) + "...(truncated)"
+	}
+	return string(redacted)
+}
+
+func isJSONContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// readCloser reads from a replacement reader and closes the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// captureWriter passes the response through while keeping a copy of up to
+// debugBodyMaxBytes of a JSON body
+type captureWriter struct {
+	gin.ResponseWriter
+	body      bytes.Buffer
+	truncated bool
+}
+
+func (w *captureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend write deadlines
+func (w *captureWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *captureWriter) capturing() bool {
+	return isJSONContentType(w.Header().Get("Content-Type"))
+}
+
+func (w *captureWriter) capture(data []byte) {
+	if w.truncated || !w.capturing() {
+		return
+	}
+	if w.body.Len()+len(data) > debugBodyMaxBytes {
+		w.truncated = true
+		w.body.Reset()
+		return
+	}
+	w.body.Write(data)
+}
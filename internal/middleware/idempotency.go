@@ -0,0 +1,166 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"app/internal/cache"
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader carries the client-chosen key that identifies a retried request
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is set to "true" on responses served from a stored result
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	// IdempotencyTTL is how long a stored response is replayed for
+	IdempotencyTTL = 24 * time.Hour
+	// idempotencyPendingTTL bounds how long a request that never finished blocks its key
+	idempotencyPendingTTL = time.Minute
+
+	maxIdempotencyKeyLength = 255
+)
+
+var (
+	ErrIdempotencyKeyInvalid = errs.NewBadRequestError(errs.ErrKeyIdempotencyKeyInvalid, "Idempotency-Key must be at most 255 characters")
+	ErrIdempotencyInProgress = errs.NewDomainError(errs.ErrKeyIdempotencyInProgress, "A request with this Idempotency-Key is still being processed", http.StatusConflict)
+	ErrIdempotencyKeyReused  = errs.NewDomainError(errs.ErrKeyIdempotencyKeyReused, "Idempotency-Key was already used for a different request", http.StatusUnprocessableEntity)
+)
+
+// idempotentResponse is what gets cached per key. Pending marks a request that is still running.
+type idempotentResponse struct {
+	Pending     bool   `json:"pending"`
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Idempotency replays the stored response when a request repeats an Idempotency-Key,
+// so a client retrying after a network error doesn't perform the action twice.
+// Keys are scoped to the user (or client IP when unauthenticated) and the route, and
+// reusing a key with a different body is rejected. Requests without the header, or
+// when the cache is unavailable, are handled normally. 5xx responses are not stored,
+// so the client can retry them with the same key.
+func Idempotency(store cache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || store == nil {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			errs.RespondWithError(c, ErrIdempotencyKeyInvalid)
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			if tooLarge, ok := errs.AsRequestTooLarge(err); ok {
+				errs.RespondWithError(c, tooLarge)
+			} else {
+				errs.RespondWithError(c, errs.WrapBadRequest(errs.ErrKeyValidationBodyInvalid, "failed to read request body", err))
+			}
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		ctx := c.Request.Context()
+		cacheKey := idempotencyCacheKey(c, key)
+		fingerprint := requestFingerprint(c.Request.Method, c.Request.URL.RequestURI(), body)
+
+		// Claim the key atomically so of two concurrent requests only one runs the handler
+		claimed, err := store.SetNX(ctx, cacheKey, idempotentResponse{Pending: true, Fingerprint: fingerprint}, idempotencyPendingTTL)
+		if err != nil {
+			c.Next()
+			return
+		}
+		if !claimed {
+			var stored idempotentResponse
+			err := store.Get(ctx, cacheKey, &stored)
+			switch {
+			case errors.Is(err, cache.ErrKeyNotFound):
+				// The claim expired or was released in between, the client can simply retry
+				errs.RespondWithError(c, ErrIdempotencyInProgress)
+			case err != nil:
+				c.Next()
+				return
+			case stored.Fingerprint != fingerprint:
+				errs.RespondWithError(c, ErrIdempotencyKeyReused)
+			case stored.Pending:
+				errs.RespondWithError(c, ErrIdempotencyInProgress)
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(stored.Status, stored.ContentType, stored.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+
+		// Store the result even if the client went away, that is exactly when it will retry
+		storeCtx := context.WithoutCancel(ctx)
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			_ = store.Delete(storeCtx, cacheKey)
+			return
+		}
+		_ = store.Set(storeCtx, cacheKey, idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}, IdempotencyTTL)
+	}
+}
+
+func idempotencyCacheKey(c *gin.Context, key string) string {
+	owner := "ip:" + c.ClientIP()
+	if userID, err := GetUserIDFromContext(c); err == nil {
+		owner = fmt.Sprintf("user:%d", userID)
+	}
+	return fmt.Sprintf("idempotency:%s:%s %s:%s", owner, c.Request.Method, c.FullPath(), key)
+}
+
+func requestFingerprint(method, uri string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + uri + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// recordingWriter passes the response through while keeping a copy of the body
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
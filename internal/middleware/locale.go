@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleKey is the gin context key holding the resolved locale
+const LocaleKey = "locale"
+
+// Locale resolves the request locale from Accept-Language, falling back to defaultLocale,
+// and stores it on both the gin context and the request context
+func Locale(defaultLocale string) gin.HandlerFunc {
+	if !errs.IsSupportedLocale(defaultLocale) {
+		defaultLocale = errs.DefaultLocale
+	}
+
+	return func(c *gin.Context) {
+		locale := errs.ParseAcceptLanguage(c.GetHeader("Accept-Language"), defaultLocale)
+
+		c.Set(LocaleKey, locale)
+		c.Request = c.Request.WithContext(errs.WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+
+		c.Next()
+	}
+}
+
+// GetLocaleFromContext returns the locale resolved by the Locale middleware
+func GetLocaleFromContext(c *gin.Context) string {
+	return errs.LocaleFromContext(c.Request.Context())
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"app/internal/errs"
@@ -11,9 +12,33 @@ import (
 	"github.com/google/uuid"
 )
 
-// RequestLogging creates a structured request logging middleware
+// requestLoggingSkipPrefixes are paths polled by load balancers and probes, too noisy to log
+var requestLoggingSkipPrefixes = []string{"/health"}
+
+// unmatchedRoute stands in for the route template of requests that matched no route
+const unmatchedRoute = "unmatched"
+
+// RequestLogging creates a structured request logging middleware.
+// It logs the route template (c.FullPath()) rather than the raw path so IDs in URLs
+// don't explode log cardinality, the bytes written to the client and the
+// authenticated user when there is one. Health checks are not logged.
 func RequestLogging(log *logger.Logger) gin.HandlerFunc {
+	return SampledRequestLogging(log, nil)
+}
+
+// SampledRequestLogging is RequestLogging with successful requests sampled per route
+// template by sampler (LOG_SAMPLE_PER_SEC), so busy routes don't flood the logs. Failed
+// requests (handler errors or 5xx) are always logged, and the next line kept for a route
+// carries sampled_out, the number of lines dropped before it. A nil sampler logs everything.
+func SampledRequestLogging(log *logger.Logger, sampler *logger.Sampler) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		for _, prefix := range requestLoggingSkipPrefixes {
+			if strings.HasPrefix(c.Request.URL.Path, prefix) {
+				c.Next()
+				return
+			}
+		}
+
 		start := time.Now()
 
 		// Process request
@@ -22,55 +47,82 @@ func RequestLogging(log *logger.Logger) gin.HandlerFunc {
 		// Log after request completes
 		latency := time.Since(start)
 
-		status := c.Writer.Status()
-		method := c.Request.Method
-		path := c.Request.URL.Path
-		ip := c.ClientIP()
-		userAgent := c.Request.UserAgent()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		// gin's writer counts the bytes actually sent, i.e. after compression;
+		// Size is -1 when nothing was written
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		attrs := []any{
+			"status", c.Writer.Status(),
+			"method", c.Request.Method,
+			"route", route,
+			"latency_ms", latency.Milliseconds(),
+			"response_bytes", size,
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if userID, err := GetUserIDFromContext(c); err == nil {
+			attrs = append(attrs, "user_id", userID)
+		}
 
 		// Get any errors from context
 		errors := c.Errors.ByType(gin.ErrorTypeAny)
 
 		if len(errors) > 0 {
-			log.ErrorContext(c.Request.Context(), "HTTP request failed",
-				"status", status,
-				"method", method,
-				"path", path,
-				"latency_ms", latency.Milliseconds(),
-				"client_ip", ip,
-				"user_agent", userAgent,
-				"errors", errors.String(),
-			)
-		} else {
-			log.InfoContext(c.Request.Context(), "HTTP request completed",
-				"status", status,
-				"method", method,
-				"path", path,
-				"latency_ms", latency.Milliseconds(),
-				"client_ip", ip,
-				"user_agent", userAgent,
-			)
+			attrs = append(attrs, "errors", errors.String())
+			// The client went away, nothing failed on our side
+			if c.Writer.Status() == errs.StatusClientClosedRequest {
+				log.InfoContext(c.Request.Context(), "HTTP request cancelled", attrs...)
+				return
+			}
+			log.ErrorContext(c.Request.Context(), "HTTP request failed", attrs...)
+			return
 		}
+
+		if c.Writer.Status() < 500 {
+			keep, dropped := sampler.Allow(route)
+			if !keep {
+				return
+			}
+			if dropped > 0 {
+				attrs = append(attrs, "sampled_out", dropped)
+			}
+		}
+		log.InfoContext(c.Request.Context(), "HTTP request completed", attrs...)
 	}
 }
 
-// RequestID middleware adds request ID to context and response headers
+// RequestID middleware adds request ID to the request context and response headers
 func RequestID(log *logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Generate or extract request ID
-		requestID := c.Request.Header.Get("X-Request-ID")
+		requestID := c.Request.Header.Get(logger.RequestIDHeader)
 		if requestID == "" {
 			requestID = uuid.New().String()
 		}
 
 		// Add to response header
-		c.Header("X-Request-ID", requestID)
+		c.Header(logger.RequestIDHeader, requestID)
+
+		// Every log line written with this request's context carries the ID
+		c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), requestID))
 
 		c.Next()
 	}
 }
 
-// ErrorHandler creates a middleware that handles errors and sends appropriate responses
+// ErrorHandler creates a middleware that handles errors and sends appropriate responses.
+// Handlers may report an error with c.Error(err) and return (or abort) instead of writing
+// the response themselves: when nothing was written, the last error is sent as the usual
+// JSON error body, keeping the status, key and message of a DomainError anywhere in its
+// chain (errs.NewNotFoundError becomes a 404). Errors that aren't DomainErrors become 500s.
 func ErrorHandler(log *logger.Logger) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Next()
@@ -79,22 +131,35 @@ func ErrorHandler(log *logger.Logger) gin.HandlerFunc {
 		if len(c.Errors) > 0 {
 			err := c.Errors.Last()
 			ctx := c.Request.Context()
+			domainErr := errs.ExtractDomainError(err.Err)
 
-			// Log only 5xx errors (server errors)
-			if c.Writer.Status() >= 500 {
+			status := c.Writer.Status()
+			if !c.Writer.Written() {
+				status = domainErr.Status
+			}
+
+			// Log only 5xx errors (server errors) - a client disconnecting
+			// mid-request maps to 499 and must not count as one
+			if status >= 500 {
 				log.ErrorContext(ctx, "HTTP server error",
 					"error", err.Err,
-					"status_code", c.Writer.Status(),
+					"error_key", domainErr.Key,
+					"status_code", status,
 					"error_message", err.Error(),
 					"method", c.Request.Method,
 					"uri", c.Request.URL.Path,
 				)
+			} else if status == errs.StatusClientClosedRequest {
+				log.InfoContext(ctx, "HTTP request cancelled by client",
+					"error", err.Err,
+					"method", c.Request.Method,
+					"uri", c.Request.URL.Path,
+				)
 			}
 
 			// Send JSON error response if not already sent
 			if !c.Writer.Written() {
-				// Use the new error response system
-				errs.RespondWithError(c, err.Err)
+				errs.RespondWithError(c, domainErr)
 			}
 		}
 	}
@@ -1,18 +1,35 @@
 package middleware
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 
+	"app/internal"
+	"app/internal/errs"
+
 	"github.com/gin-gonic/gin"
 )
 
 var (
-	ErrInvalidPageParameter = errors.New("invalid page parameter")
-	ErrInvalidPageSize      = errors.New("invalid page_size parameter")
+	ErrInvalidPageParameter = errs.NewBadRequestError(errs.ErrKeyBadRequest, "invalid page parameter")
+	ErrInvalidPageSize      = errs.NewBadRequestError(errs.ErrKeyBadRequest, "invalid page_size parameter")
+	ErrInvalidLimit         = errs.NewBadRequestError(errs.ErrKeyBadRequest, "invalid limit parameter")
 )
 
+// DefaultMaxPaginationOffset is the deepest offset accepted when PaginationLimits isn't installed
+const DefaultMaxPaginationOffset int64 = 10000
+
+const paginationMaxOffsetKey = "pagination_max_offset"
+
+// PaginationLimits sets the deepest offset GetPaginationParamsFromContext accepts.
+// Huge OFFSETs make Postgres scan and discard every skipped row, so deep pages are refused.
+func PaginationLimits(maxOffset int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(paginationMaxOffsetKey, maxOffset)
+		c.Next()
+	}
+}
+
 // PaginationParams holds parsed pagination parameters
 type PaginationParams struct {
 	Page     int32
@@ -20,8 +37,9 @@ type PaginationParams struct {
 }
 
 // GetPaginationParamsFromContext parses pagination parameters from the gin context query parameters
-// It validates page (must be >= 1) and pageSize (must be between minPageSize and maxPageSize)
-// Returns parsed params on success, or an error if validation fails
+// It validates page (must be >= 1) and pageSize (must be between minPageSize and maxPageSize),
+// and rejects pages beyond the max offset with internal.ErrPageTooDeep
+// Returns parsed params on success, or a domain error if validation fails
 func GetPaginationParamsFromContext(c *gin.Context, defaultPageSize, minPageSize, maxPageSize int32) (PaginationParams, error) {
 	var params PaginationParams
 
@@ -38,12 +56,66 @@ func GetPaginationParamsFromContext(c *gin.Context, defaultPageSize, minPageSize
 	if pageSizeStr := c.Query("page_size"); pageSizeStr != "" {
 		pageSizeInt, err := strconv.ParseInt(pageSizeStr, 10, 32)
 		if err != nil || pageSizeInt < int64(minPageSize) || pageSizeInt > int64(maxPageSize) {
-			return params, fmt.Errorf("%w (must be between %d and %d)", ErrInvalidPageSize, minPageSize, maxPageSize)
+			message := fmt.Sprintf("%s (must be between %d and %d)", ErrInvalidPageSize.Message, minPageSize, maxPageSize)
+			return params, errs.WrapBadRequest(errs.ErrKeyBadRequest, message, ErrInvalidPageSize)
 		}
 		pageSize = int32(pageSizeInt)
 	}
 
+	maxOffset := DefaultMaxPaginationOffset
+	if value, exists := c.Get(paginationMaxOffsetKey); exists {
+		if limit, ok := value.(int64); ok {
+			maxOffset = limit
+		}
+	}
+	if int64(page-1)*int64(pageSize) > maxOffset {
+		tooDeep := internal.ErrPageTooDeep
+		return params, errs.WrapBadRequest(tooDeep.Key, tooDeep.Message, tooDeep).WithDetails(map[string]interface{}{
+			"max_offset": maxOffset,
+		})
+	}
+
 	params.Page = page
 	params.PageSize = pageSize
 	return params, nil
 }
+
+// CursorParams holds parsed cursor pagination parameters. AfterID is nil for the first page.
+type CursorParams struct {
+	AfterID *int32
+	Limit   int32
+}
+
+// WantsCursorPagination reports whether the request asked for cursor pagination
+// by passing a cursor query parameter, empty for the first page
+func WantsCursorPagination(c *gin.Context) bool {
+	_, ok := c.GetQuery("cursor")
+	return ok
+}
+
+// GetCursorParamsFromContext parses the cursor and limit query parameters.
+// The limit must be between minLimit and maxLimit; a malformed cursor returns internal.ErrInvalidCursor.
+func GetCursorParamsFromContext(c *gin.Context, defaultLimit, minLimit, maxLimit int32) (CursorParams, error) {
+	var params CursorParams
+
+	limit := defaultLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limitInt, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || limitInt < int64(minLimit) || limitInt > int64(maxLimit) {
+			message := fmt.Sprintf("%s (must be between %d and %d)", ErrInvalidLimit.Message, minLimit, maxLimit)
+			return params, errs.WrapBadRequest(errs.ErrKeyBadRequest, message, ErrInvalidLimit)
+		}
+		limit = int32(limitInt)
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" {
+		afterID, err := internal.DecodeCursor(cursor)
+		if err != nil {
+			return params, err
+		}
+		params.AfterID = &afterID
+	}
+
+	params.Limit = limit
+	return params, nil
+}
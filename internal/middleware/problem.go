@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemJSON makes errs.RespondWithError emit RFC 7807 problem+json for every request when enabled.
+// When disabled, clients can still opt in per request with "Accept: application/problem+json".
+func ProblemJSON(enabled bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if enabled {
+			c.Set(errs.ProblemJSONKey, true)
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"app/internal/errs"
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleAdmin is the role granted to administrators
+const RoleAdmin = "admin"
+
+// GetUserRolesFromContext retrieves the authenticated user's roles from the Gin context
+// Returns an empty slice if the user is not authenticated or has no roles
+func GetUserRolesFromContext(c *gin.Context) []string {
+	roles, exists := c.Get("user_roles")
+	if !exists {
+		return []string{}
+	}
+
+	rolesSlice, ok := roles.([]string)
+	if !ok {
+		return []string{}
+	}
+
+	return rolesSlice
+}
+
+// HasRole reports whether the authenticated user has the given role
+func HasRole(c *gin.Context, role string) bool {
+	for _, r := range GetUserRolesFromContext(c) {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// RequireRole aborts with 403 unless the authenticated user has the given role
+// Must be used after UserAuthMiddleware
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasRole(c, role) {
+			errs.RespondWithForbidden(c, "Insufficient permissions")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// OwnerLookup returns the id of the user who owns the resource a request targets
+type OwnerLookup func(ctx context.Context) (int32, error)
+
+// OwnerOrAdmin resolves the user id an owner-scoped service call should run as.
+// Regular users are always scoped to themselves, so someone else's resource stays a 404
+// from the service. Admins are scoped to the resource's real owner via lookup, which lets
+// them manage any user's content through the same owner-scoped services.
+// Must be used after UserAuthMiddleware
+func OwnerOrAdmin(c *gin.Context, userID int32, lookup OwnerLookup) (int32, error) {
+	if !HasRole(c, RoleAdmin) {
+		return userID, nil
+	}
+	return lookup(c.Request.Context())
+}
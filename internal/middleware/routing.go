@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrRouteNotFound    = errs.NewNotFoundError(errs.ErrKeyNotFound, "Route not found")
+	ErrMethodNotAllowed = errs.NewDomainError(errs.ErrKeyMethodNotAllowed, "Method not allowed", http.StatusMethodNotAllowed)
+)
+
+// NoRoute answers requests for unknown paths with the standard JSON error body
+// instead of Gin's plain-text 404. Register it with engine.NoRoute.
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		errs.RespondWithError(c, ErrRouteNotFound)
+	}
+}
+
+// NoMethod answers requests whose path exists under a different method with a JSON 405.
+// Gin only calls it when engine.HandleMethodNotAllowed is set, and fills the Allow header itself.
+func NoMethod() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		errs.RespondWithError(c, ErrMethodNotAllowed)
+	}
+}
+
+// TrimTrailingSlash wraps the engine so GET and HEAD requests for "/api/v1/cities/" are
+// routed as "/api/v1/cities". The path is rewritten before Gin sees it, so there is no
+// redirect and every middleware runs once, for the trimmed path. Other methods keep their
+// path and still 404 with a trailing slash: a 301 would make clients resend a POST as GET.
+// engine.RedirectTrailingSlash stays off, it redirects instead.
+//
+// Catch-all routes see the trimmed path too, so "/swagger/" becomes "/swagger"; link to
+// "/swagger/index.html".
+func TrimTrailingSlash(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if (r.Method == http.MethodGet || r.Method == http.MethodHead) && len(r.URL.Path) > 1 && strings.HasSuffix(r.URL.Path, "/") {
+			r = r.Clone(r.Context())
+			r.URL.Path = trimTrailingSlashes(r.URL.Path)
+			if r.URL.RawPath != "" {
+				r.URL.RawPath = trimTrailingSlashes(r.URL.RawPath)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// trimTrailingSlashes strips every trailing slash but keeps the root "/"
+func trimTrailingSlashes(path string) string {
+	trimmed := strings.TrimRight(path, "/")
+	if trimmed == "" {
+		return "/"
+	}
+	return trimmed
+}
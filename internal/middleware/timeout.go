@@ -0,0 +1,152 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrRequestTimeout is the response for requests that ran past the Timeout middleware's limit
+var ErrRequestTimeout = errs.ExtractDomainError(context.DeadlineExceeded)
+
+// timeoutStateKey keeps the first Timeout's state so a later Timeout can replace the limit
+const timeoutStateKey = "timeout_state"
+
+// timeoutState is the request context from before the first Timeout's deadline and the
+// writer guarding the response against it
+type timeoutState struct {
+	parent context.Context
+	writer *timeoutWriter
+}
+
+// Timeout bounds how long a handler may take. The request context gets a deadline so
+// pgx queries and other context-aware calls abort once it passes, and any response the
+// handler starts after the deadline is replaced with a 503 request_timeout.
+// Handlers still run on the request goroutine, so one that ignores its context keeps
+// running until it returns; only its late response is dropped. A zero duration disables the limit.
+// Installed again on a route, the route's limit replaces the global one, e.g. for uploads
+// whose body takes longer to arrive than any ordinary request should run.
+func Timeout(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if value, ok := c.Get(timeoutStateKey); ok {
+			replaceTimeout(c, value.(*timeoutState), timeout)
+			return
+		}
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		parent := c.Request.Context()
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &timeoutWriter{ResponseWriter: c.Writer, c: c, ctx: ctx}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+		c.Set(timeoutStateKey, &timeoutState{parent: parent, writer: writer})
+
+		c.Next()
+
+		// The handler gave up without writing anything, e.g. after its query was aborted
+		writer.checkTimeout()
+	}
+}
+
+// replaceTimeout swaps the deadline of the first Timeout for one of timeout counted from
+// now. The context keeps the values added since, and is still cancelled with the parent
+// when the client goes away.
+func replaceTimeout(c *gin.Context, state *timeoutState, timeout time.Duration) {
+	ctx, cancel := context.WithCancel(context.WithoutCancel(c.Request.Context()))
+	defer cancel()
+	stop := context.AfterFunc(state.parent, cancel)
+	defer stop()
+	if timeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, timeout)
+		defer cancelTimeout()
+	}
+
+	// The first Timeout checks the writer again once this returns, against this context
+	state.writer.ctx = ctx
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+
+	state.writer.checkTimeout()
+}
+
+// timeoutWriter substitutes the timeout response for the first write after the deadline
+// and drops everything the handler writes afterwards, so the client never sees two responses
+type timeoutWriter struct {
+	gin.ResponseWriter
+	c        *gin.Context
+	ctx      context.Context
+	timedOut bool
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if w.checkTimeout() {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.checkTimeout() {
+		return 0, http.ErrHandlerTimeout
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.checkTimeout() {
+		return
+	}
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timeoutWriter) Flush() {
+	if w.checkTimeout() {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap lets http.ResponseController reach the connection, e.g. to extend write deadlines
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// checkTimeout reports whether the response belongs to the timeout, sending it if the
+// deadline has passed and the handler has not started its own response yet
+func (w *timeoutWriter) checkTimeout() bool {
+	if w.timedOut {
+		return true
+	}
+	if w.ResponseWriter.Written() || !errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		return false
+	}
+
+	w.timedOut = true
+
+	// Drop headers describing the handler's abandoned body
+	header := w.ResponseWriter.Header()
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	header.Del("Content-Disposition")
+
+	w.c.Writer = w.ResponseWriter
+	errs.RespondWithError(w.c, ErrRequestTimeout)
+	w.c.Writer = w
+
+	return true
+}
@@ -15,8 +15,9 @@ var (
 
 // Claims represents JWT claims for user authentication
 type Claims struct {
-	UserID int32  `json:"user_id"`
-	Email  string `json:"email"`
+	UserID int32    `json:"user_id"`
+	Email  string   `json:"email"`
+	Roles  []string `json:"roles,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -42,12 +43,12 @@ func ExtractBearerToken(authHeader string) (string, bool) {
 	return authHeader, true
 }
 
-// ExtractUserIDFromJWT extracts user ID from JWT token in Authorization header or query parameter
+// ExtractClaimsFromJWT extracts the JWT claims from the Authorization header or query parameter
 // Checks query parameter "token" first (for WebSocket connections), then Authorization header
 // Returns (nil, nil) if no token is provided
 // Returns (nil, error) if token is invalid or verification fails
-// Returns (userID, nil) if token is valid
-func ExtractUserIDFromJWT(c *gin.Context, verifier UserJWTVerifier) (*int32, error) {
+// Returns (claims, nil) if token is valid
+func ExtractClaimsFromJWT(c *gin.Context, verifier UserJWTVerifier) (*Claims, error) {
 	var tokenString string
 
 	// Check query parameter first (common for WebSocket connections)
@@ -74,26 +75,40 @@ func ExtractUserIDFromJWT(c *gin.Context, verifier UserJWTVerifier) (*int32, err
 		return nil, errs.NewUnauthorizedError(errs.ErrKeyAuthInvalidToken, "Invalid token claims")
 	}
 
+	return claims, nil
+}
+
+// ExtractUserIDFromJWT extracts user ID from JWT token in Authorization header or query parameter
+// Returns (nil, nil) if no token is provided
+// Returns (nil, error) if token is invalid or verification fails
+// Returns (userID, nil) if token is valid
+func ExtractUserIDFromJWT(c *gin.Context, verifier UserJWTVerifier) (*int32, error) {
+	claims, err := ExtractClaimsFromJWT(c, verifier)
+	if err != nil || claims == nil {
+		return nil, err
+	}
+
 	return &claims.UserID, nil
 }
 
 // UserAuthMiddleware validates JWT token and sets user context
 func UserAuthMiddleware(verifier UserJWTVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID, err := ExtractUserIDFromJWT(c, verifier)
+		claims, err := ExtractClaimsFromJWT(c, verifier)
 		if err != nil {
 			errs.RespondWithError(c, err)
 			c.Abort()
 			return
 		}
-		if userID == nil {
+		if claims == nil {
 			errs.RespondWithError(c, ErrUserNotAuthenticated)
 			c.Abort()
 			return
 		}
 
 		// Set user context
-		c.Set("user_id", *userID)
+		c.Set("user_id", claims.UserID)
+		c.Set("user_roles", claims.Roles)
 
 		c.Next()
 	}
@@ -104,11 +119,12 @@ func UserAuthMiddleware(verifier UserJWTVerifier) gin.HandlerFunc {
 // This allows routes to be accessible to both authenticated and unauthenticated users
 func OptionalUserAuthMiddleware(verifier UserJWTVerifier) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID, err := ExtractUserIDFromJWT(c, verifier)
+		claims, err := ExtractClaimsFromJWT(c, verifier)
 
 		// If token is present and valid, set user context
-		if err == nil && userID != nil {
-			c.Set("user_id", *userID)
+		if err == nil && claims != nil {
+			c.Set("user_id", claims.UserID)
+			c.Set("user_roles", claims.Roles)
 		}
 
 		// Continue regardless of authentication status
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaginationHeaders are the response headers set by SetPaginationHeaders,
+// listed so CORS can expose them to browser clients
+var PaginationHeaders = []string{"X-Total-Count", "X-Page", "X-Per-Page", "Link"}
+
+// ErrPageTooDeep is returned when a page lies beyond the deepest offset offset pagination allows
+var ErrPageTooDeep = errs.NewBadRequestError(errs.ErrKeyValidationPageTooDeep, "Page is too deep, use cursor pagination or narrow the query instead")
+
+// ErrInvalidCursor is returned for a cursor that DecodeCursor can't read
+var ErrInvalidCursor = errs.NewBadRequestError(errs.ErrKeyValidationCursor, "Invalid pagination cursor")
+
+// EncodeCursor turns the ID of the last row on a page into an opaque cursor for the next one
+func EncodeCursor(id int32) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(int64(id), 10)))
+}
+
+// DecodeCursor returns the row ID encoded by EncodeCursor, or ErrInvalidCursor
+func DecodeCursor(cursor string) (int32, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 32)
+	if err != nil || id < 1 {
+		return 0, ErrInvalidCursor
+	}
+	return int32(id), nil
+}
+
+// PaginationOffset computes the row offset for page/pageSize using int64 math,
+// rejecting pages whose offset does not fit the int32 OFFSET query parameter
+func PaginationOffset(page, pageSize int32) (int32, error) {
+	offset := int64(page-1) * int64(pageSize)
+	if offset > math.MaxInt32 {
+		return 0, ErrPageTooDeep
+	}
+	return int32(offset), nil
+}
+
+// SetPaginationHeaders mirrors meta in X-Total-Count, X-Page and X-Per-Page and adds an
+// RFC 5988 Link header with next, prev and last page URLs. The URLs keep the request's
+// path and query, only page is replaced, so filters carry over to the other pages.
+func SetPaginationHeaders(c *gin.Context, meta PaginationMeta) {
+	c.Header("X-Total-Count", strconv.FormatInt(meta.Total, 10))
+	c.Header("X-Page", strconv.Itoa(int(meta.CurrentPage)))
+	c.Header("X-Per-Page", strconv.Itoa(int(meta.PerPage)))
+
+	pageURL := func(page int32) string {
+		query := c.Request.URL.Query()
+		query.Set("page", strconv.Itoa(int(page)))
+		return c.Request.URL.Path + "?" + query.Encode()
+	}
+
+	var links []string
+	if meta.HasNext {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(meta.CurrentPage+1)))
+	}
+	if meta.HasPrev {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(meta.CurrentPage-1)))
+	}
+	links = append(links, fmt.Sprintf(`<%s>; rel="last"`, pageURL(meta.LastPage)))
+
+	c.Header("Link", strings.Join(links, ", "))
+}
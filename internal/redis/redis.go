@@ -6,12 +6,15 @@ import (
 	"time"
 
 	"app/config"
+	"app/internal/logger"
+	"app/internal/retry"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// NewConnection creates a new Redis client with production-ready settings
-func NewConnection(cfg *config.Config) (*redis.Client, error) {
+// NewConnection creates a new Redis client with production-ready settings.
+// The first ping is retried like the database one, see db.NewConnection.
+func NewConnection(cfg *config.Config, log *logger.Logger) (*redis.Client, error) {
 	if cfg.RedisURL == "" {
 		return nil, fmt.Errorf("REDIS_URL is required")
 	}
@@ -23,37 +26,70 @@ func NewConnection(cfg *config.Config) (*redis.Client, error) {
 	}
 
 	// Configure production settings
-	configureRedisOptions(opt)
+	configureRedisOptions(opt, cfg)
 
 	client := redis.NewClient(opt)
 
-	// Test connection
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	if err := client.Ping(ctx).Err(); err != nil {
+	// Test connection, each attempt bounded on its own
+	err = retry.Do(context.Background(), cfg.ConnectMaxAttempts, retry.ConnectBackoff(cfg.ConnectMaxDelay),
+		func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+			return client.Ping(ctx).Err()
+		},
+		func(attempt int, delay time.Duration, err error) {
+			log.Warn("Redis not reachable yet, retrying", "attempt", attempt, "retry_in", delay, "error", err)
+		},
+	)
+	if err != nil {
+		client.Close()
 		return nil, fmt.Errorf("failed to ping Redis: %w", err)
 	}
 
 	return client, nil
 }
 
-// configureRedisOptions sets production-ready Redis options
-func configureRedisOptions(opt *redis.Options) {
+// configureRedisOptions applies the pool and timeout settings from config
+func configureRedisOptions(opt *redis.Options, cfg *config.Config) {
 	// Connection pool settings
-	opt.PoolSize = 20     // Max number of connections
-	opt.MinIdleConns = 5  // Min idle connections to keep
-	opt.MaxIdleConns = 10 // Max idle connections
-	opt.ConnMaxLifetime = 5 * time.Minute
-	opt.ConnMaxIdleTime = 5 * time.Minute
+	opt.PoolSize = cfg.RedisPoolSize         // Max number of connections
+	opt.MinIdleConns = cfg.RedisMinIdleConns // Min idle connections to keep
+	opt.MaxIdleConns = cfg.RedisMaxIdleConns // Max idle connections
+	opt.ConnMaxLifetime = cfg.RedisConnMaxLifetime
+	opt.ConnMaxIdleTime = cfg.RedisConnMaxIdleTime
 
 	// Timeout settings
-	opt.DialTimeout = 5 * time.Second
-	opt.ReadTimeout = 3 * time.Second
-	opt.WriteTimeout = 3 * time.Second
+	opt.DialTimeout = cfg.RedisDialTimeout
+	opt.ReadTimeout = cfg.RedisReadTimeout
+	opt.WriteTimeout = cfg.RedisWriteTimeout
 
 	// Retry settings
 	opt.MaxRetries = 3
 	opt.MinRetryBackoff = 8 * time.Millisecond
 	opt.MaxRetryBackoff = 512 * time.Millisecond
 }
+
+// PoolStats is a snapshot of the Redis connection pool, shaped for the metrics endpoint
+type PoolStats struct {
+	Hits       uint32 `json:"hits"`        // Times a free connection was found in the pool
+	Misses     uint32 `json:"misses"`      // Times a new connection had to be dialed
+	Timeouts   uint32 `json:"timeouts"`    // Times waiting for a connection timed out
+	TotalConns uint32 `json:"total_conns"` // Open connections
+	IdleConns  uint32 `json:"idle_conns"`  // Open connections not in use
+	StaleConns uint32 `json:"stale_conns"` // Connections closed by idle reaping or max lifetime
+}
+
+// GetPoolStats returns the current pool counters for client.
+// A steadily growing Misses or Timeouts count means REDIS_POOL_SIZE is too small,
+// while many idle connections mean REDIS_MIN_IDLE can come down
+func GetPoolStats(client *redis.Client) PoolStats {
+	stats := client.PoolStats()
+	return PoolStats{
+		Hits:       stats.Hits,
+		Misses:     stats.Misses,
+		Timeouts:   stats.Timeouts,
+		TotalConns: stats.TotalConns,
+		IdleConns:  stats.IdleConns,
+		StaleConns: stats.StaleConns,
+	}
+}
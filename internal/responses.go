@@ -7,25 +7,65 @@ type ErrorResponse struct {
 	Error string `json:"error"`
 }
 
-// PaginationMeta contains pagination metadata
+// DataResponse is the {"data": ...} envelope every successful response is wrapped in.
+// Modules alias instantiations of it, e.g. type ExampleDataResponse = internal.DataResponse[*ExampleResponse].
+type DataResponse[T any] struct {
+	Data T `json:"data"`
+}
+
+// PaginatedResponse wraps one page of a list together with its pagination metadata
+type PaginatedResponse[T any] struct {
+	Data       []T            `json:"data"`
+	Pagination PaginationMeta `json:"pagination"`
+}
+
+// PaginationMeta contains pagination metadata. From and To are the 1-based positions of
+// the first and last record on the current page, both 0 when there are no results.
 type PaginationMeta struct {
 	Total       int64 `json:"total"`
 	CurrentPage int32 `json:"current_page"`
 	LastPage    int32 `json:"last_page"`
 	PerPage     int32 `json:"per_page"`
+	TotalPages  int32 `json:"total_pages"`
+	From        int64 `json:"from"`
+	To          int64 `json:"to"`
+	HasNext     bool  `json:"has_next"`
+	HasPrev     bool  `json:"has_prev"`
 }
 
-// NewPaginationMeta creates pagination metadata from pagination parameters
+// CursorMeta contains cursor pagination metadata. NextCursor is null on the last page.
+type CursorMeta struct {
+	NextCursor *string `json:"next_cursor"`
+	Limit      int32   `json:"limit"`
+}
+
+// NewPaginationMeta creates pagination metadata from pagination parameters.
+// TotalPages is 0 without results while LastPage is never below 1, and CurrentPage is
+// clamped to [1, TotalPages] when there are results, so a page past the end reports the last one.
 func NewPaginationMeta(total int64, page, pageSize int32) PaginationMeta {
-	lastPage := int32(math.Ceil(float64(total) / float64(pageSize)))
-	if lastPage == 0 {
-		lastPage = 1
+	var totalPages int32
+	if total > 0 && pageSize > 0 {
+		totalPages = int32(min((total-1)/int64(pageSize)+1, math.MaxInt32))
+	}
+	lastPage := max(totalPages, 1)
+
+	page = max(page, 1)
+	if totalPages > 0 {
+		page = min(page, totalPages)
 	}
 
-	return PaginationMeta{
+	meta := PaginationMeta{
 		Total:       total,
 		CurrentPage: page,
 		LastPage:    lastPage,
 		PerPage:     pageSize,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrev:     page > 1 && totalPages > 0,
+	}
+	if totalPages > 0 {
+		meta.From = int64(page-1)*int64(pageSize) + 1
+		meta.To = min(int64(page)*int64(pageSize), total)
 	}
+	return meta
 }
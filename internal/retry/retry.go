@@ -0,0 +1,65 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// connectInitialDelay is the first wait of ConnectBackoff
+const connectInitialDelay = 500 * time.Millisecond
+
+// Backoff doubles the wait after each failed attempt, starting at Initial and capped at Max
+type Backoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+// Delay returns the wait after the given failed attempt, counting from 1
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Initial
+	for i := 1; i < attempt && delay < b.Max; i++ {
+		delay *= 2
+	}
+	if b.Max > 0 && delay > b.Max {
+		return b.Max
+	}
+	return delay
+}
+
+// ConnectBackoff is the backoff for reaching a dependency at startup, waits grow from 500ms up to max
+func ConnectBackoff(max time.Duration) Backoff {
+	return Backoff{Initial: connectInitialDelay, Max: max}
+}
+
+// Do calls fn until it succeeds, at most attempts times, waiting backoff.Delay between
+// failures. onRetry, when not nil, is called before each wait, e.g. to log the failure.
+// The last error is returned once attempts run out, ctx.Err() if ctx is done while waiting.
+func Do(ctx context.Context, attempts int, backoff Backoff, fn func(ctx context.Context) error, onRetry func(attempt int, delay time.Duration, err error)) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+		}
+
+		delay := backoff.Delay(attempt)
+		if onRetry != nil {
+			onRetry(attempt, delay, err)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package scheduler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"app/internal"
+	"app/internal/errs"
+	"app/internal/logger"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultJobRunsLimit = 20
+	maxJobRunsLimit     = 100
+)
+
+type Handler struct {
+	scheduler *Scheduler
+	logger    *logger.Logger
+}
+
+func NewHandler(scheduler *Scheduler, logger *logger.Logger) *Handler {
+	return &Handler{
+		scheduler: scheduler,
+		logger:    logger,
+	}
+}
+
+// RunJob triggers a registered job immediately
+//
+//	@Summary		Run job now
+//	@Description	Execute a registered scheduler job synchronously (admin only)
+//	@Tags			scheduler
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string	true	"Job name"
+//	@Success		200		{object}	JobRunDataResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/scheduler/jobs/{name}/run [post]
+func (h *Handler) RunJob(c *gin.Context) {
+	name := c.Param("name")
+
+	start := time.Now()
+	if err := h.scheduler.RunJob(c.Request.Context(), name); err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to run job", err, "job", name)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, JobRunDataResponse{
+		Data: JobRunResponse{
+			Job:        name,
+			Status:     "completed",
+			DurationMs: time.Since(start).Milliseconds(),
+		},
+	})
+}
+
+// ListJobRuns returns the recent run history of a registered job
+//
+//	@Summary		List job runs
+//	@Description	Recent executions of a scheduler job, newest first, both scheduled and manual (admin only)
+//	@Tags			scheduler
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			name	path		string	true	"Job name"
+//	@Param			limit	query		int		false	"Number of runs (default 20, max 100)"
+//	@Success		200		{object}	JobRunHistoryResponse
+//	@Failure		400		{object}	ErrorResponse
+//	@Failure		401		{object}	ErrorResponse
+//	@Failure		403		{object}	ErrorResponse
+//	@Failure		404		{object}	ErrorResponse
+//	@Failure		500		{object}	ErrorResponse
+//	@Router			/api/v1/scheduler/jobs/{name}/runs [get]
+func (h *Handler) ListJobRuns(c *gin.Context) {
+	name := c.Param("name")
+
+	limit := int32(defaultJobRunsLimit)
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limitInt, err := strconv.ParseInt(limitStr, 10, 32)
+		if err != nil || limitInt < 1 || limitInt > maxJobRunsLimit {
+			message := fmt.Sprintf("%s (must be between 1 and %d)", middleware.ErrInvalidLimit.Message, maxJobRunsLimit)
+			errs.RespondWithError(c, errs.WrapBadRequest(errs.ErrKeyBadRequest, message, middleware.ErrInvalidLimit))
+			return
+		}
+		limit = int32(limitInt)
+	}
+
+	runs, err := h.scheduler.ListJobRuns(c.Request.Context(), name, limit)
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to list job runs", err, "job", name)
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	data := make([]JobRunHistoryItem, len(runs))
+	for i, run := range runs {
+		data[i] = JobRunHistoryItem{
+			ID:         run.ID,
+			Job:        run.JobName,
+			Status:     run.Status,
+			StartedAt:  internal.FormatTimestamp(run.StartedAt),
+			FinishedAt: internal.FormatTimestampPtr(run.FinishedAt),
+		}
+		if run.DurationMs.Valid {
+			data[i].DurationMs = &run.DurationMs.Int64
+		}
+		if run.Error.Valid {
+			data[i].Error = &run.Error.String
+		}
+	}
+
+	c.JSON(http.StatusOK, JobRunHistoryResponse{Data: data})
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"app/internal/db"
+	"app/internal/logger"
+)
+
+// TokenCleanupJob deletes refresh tokens past their expiry, which can never be used again
+type TokenCleanupJob struct {
+	queries *db.Queries
+	logger  *logger.Logger
+}
+
+// NewTokenCleanupJob creates a new token cleanup job
+func NewTokenCleanupJob(queries *db.Queries, logger *logger.Logger) *TokenCleanupJob {
+	return &TokenCleanupJob{
+		queries: queries,
+		logger:  logger,
+	}
+}
+
+// Execute deletes expired refresh tokens
+func (j *TokenCleanupJob) Execute(ctx context.Context) error {
+	_, err := j.Prune(ctx)
+	return err
+}
+
+// Prune deletes expired refresh tokens and returns how many were removed.
+// Revoked tokens are kept until they expire as well.
+func (j *TokenCleanupJob) Prune(ctx context.Context) (int64, error) {
+	deleted, err := j.queries.DeleteExpiredRefreshTokens(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired refresh tokens: %w", err)
+	}
+
+	j.logger.InfoContext(ctx, "Deleted expired refresh tokens", "deleted", deleted)
+	return deleted, nil
+}
+
+// Name returns the job name
+func (j *TokenCleanupJob) Name() string {
+	return "token-cleanup"
+}
+
+// Description returns the job description
+func (j *TokenCleanupJob) Description() string {
+	return "Deletes expired refresh tokens"
+}
@@ -0,0 +1,20 @@
+package scheduler
+
+import (
+	"app/internal"
+	"app/internal/middleware"
+)
+
+// RegisterRoutes registers scheduler routes
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier, scheduler *Scheduler) {
+	handler := NewHandler(scheduler, app.Logger)
+
+	// Admin routes (require authentication and admin role)
+	jobs := app.Api.Group("/scheduler/jobs")
+	jobs.Use(middleware.UserAuthMiddleware(authService))
+	jobs.Use(middleware.RequireRole(middleware.RoleAdmin))
+	{
+		jobs.POST("/:name/run", handler.RunJob)
+		jobs.GET("/:name/runs", handler.ListJobRuns)
+	}
+}
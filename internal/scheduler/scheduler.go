@@ -5,15 +5,30 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"sync"
+	"time"
 
 	"app/config"
 	"app/internal/db"
+	"app/internal/errs"
 	"app/internal/logger"
 	"app/internal/scheduler/jobs"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/robfig/cron/v3"
 )
 
+// Job run statuses stored in job_runs.status
+const (
+	JobRunStatusRunning   = "running"
+	JobRunStatusSucceeded = "succeeded"
+	JobRunStatusFailed    = "failed"
+)
+
+var (
+	ErrJobNotFound = errs.NewNotFoundError(errs.ErrKeySchedulerJobNotFound, "Job not found")
+)
+
 // Dependencies contains all services that might be needed by the scheduler
 type Dependencies struct {
 	Config  *config.Config
@@ -33,6 +48,7 @@ type Job interface {
 type Scheduler struct {
 	cron *cron.Cron
 	deps *Dependencies
+	jobs map[string]Job
 	mu   sync.RWMutex
 }
 
@@ -45,6 +61,7 @@ func NewScheduler(deps *Dependencies) *Scheduler {
 	return &Scheduler{
 		cron: c,
 		deps: deps,
+		jobs: make(map[string]Job),
 	}
 }
 
@@ -58,9 +75,70 @@ func (s *Scheduler) RegisterJobs() error {
 		return fmt.Errorf("failed to register example job: %w", err)
 	}
 
+	// Register token cleanup job
+	if err := s.registerTokenCleanupJob(); err != nil {
+		return fmt.Errorf("failed to register token cleanup job: %w", err)
+	}
+
 	return nil
 }
 
+// AddJob schedules a job with the given cron spec and adds it to the job registry
+func (s *Scheduler) AddJob(spec string, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.addJob(spec, job)
+}
+
+// RunJob looks up a registered job by name and executes it synchronously
+// Returns ErrJobNotFound if no job is registered under that name
+func (s *Scheduler) RunJob(ctx context.Context, name string) error {
+	s.mu.RLock()
+	job, exists := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !exists {
+		return ErrJobNotFound
+	}
+
+	s.deps.Logger.InfoContext(ctx, "Running job manually", "job", name)
+	return s.execute(ctx, job)
+}
+
+// ListJobRuns returns the most recent runs of a registered job, newest first
+// Returns ErrJobNotFound if no job is registered under that name
+func (s *Scheduler) ListJobRuns(ctx context.Context, name string, limit int32) ([]db.JobRun, error) {
+	s.mu.RLock()
+	_, exists := s.jobs[name]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, ErrJobNotFound
+	}
+
+	runs, err := s.deps.Queries.ListJobRuns(ctx, db.ListJobRunsParams{JobName: name, MaxResults: limit})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to list job runs", err)
+	}
+
+	return runs, nil
+}
+
+// JobNames returns the names of all registered jobs in alphabetical order
+func (s *Scheduler) JobNames() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0, len(s.jobs))
+	for name := range s.jobs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
 // Start begins executing all registered cron jobs
 func (s *Scheduler) Start() {
 	s.mu.Lock()
@@ -87,6 +165,63 @@ func (s *Scheduler) GetEntries() []cron.Entry {
 	return s.cron.Entries()
 }
 
+// addJob registers a job with cron and the name registry; callers must hold the lock
+func (s *Scheduler) addJob(spec string, job Job) error {
+	name := job.Name()
+	if _, exists := s.jobs[name]; exists {
+		return fmt.Errorf("job %s is already registered", name)
+	}
+
+	_, err := s.cron.AddFunc(spec, func() {
+		if err := s.execute(context.Background(), job); err != nil {
+			s.deps.Logger.Error("Scheduled job failed", "job", name, "error", err)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add job %s: %w", name, err)
+	}
+
+	s.jobs[name] = job
+	return nil
+}
+
+// execute runs the job and records the run in job_runs. Failing to record a run is
+// logged but never fails the job itself. Runs aren't recorded without Queries.
+func (s *Scheduler) execute(ctx context.Context, job Job) error {
+	if s.deps.Queries == nil {
+		return job.Execute(ctx)
+	}
+
+	name := job.Name()
+	run, recordErr := s.deps.Queries.CreateJobRun(ctx, name)
+	if recordErr != nil {
+		s.deps.Logger.ErrorContext(ctx, "Failed to record job start", "job", name, "error", recordErr)
+	}
+
+	start := time.Now()
+	err := job.Execute(ctx)
+	duration := time.Since(start)
+
+	if recordErr != nil {
+		return err
+	}
+
+	params := db.FinishJobRunParams{
+		ID:         run.ID,
+		Status:     JobRunStatusSucceeded,
+		DurationMs: pgtype.Int8{Int64: duration.Milliseconds(), Valid: true},
+	}
+	if err != nil {
+		params.Status = JobRunStatusFailed
+		params.Error = pgtype.Text{String: err.Error(), Valid: true}
+	}
+	if _, finishErr := s.deps.Queries.FinishJobRun(ctx, params); finishErr != nil {
+		s.deps.Logger.ErrorContext(ctx, "Failed to record job finish", "job", name, "error", finishErr)
+	}
+
+	return err
+}
+
 // Private job registration methods
 
 func (s *Scheduler) registerExampleJob() error {
@@ -94,16 +229,22 @@ func (s *Scheduler) registerExampleJob() error {
 	job := jobs.NewExampleJob(s.deps.Config, s.deps.Queries, s.deps.Logger)
 
 	// Run example job every 2 hours
-	_, err := s.cron.AddFunc("@every 2h", func() {
-		if err := job.Execute(context.Background()); err != nil {
-			s.deps.Logger.Error("Example job failed", err)
-		}
-	})
-
-	if err != nil {
-		return fmt.Errorf("failed to add example job: %w", err)
+	if err := s.addJob("@every 2h", job); err != nil {
+		return err
 	}
 
 	s.deps.Logger.Info("Registered example job (every 2 hours)")
 	return nil
 }
+
+func (s *Scheduler) registerTokenCleanupJob() error {
+	job := jobs.NewTokenCleanupJob(s.deps.Queries, s.deps.Logger)
+
+	// Expired tokens are useless but harmless, once a day keeps the table lean
+	if err := s.addJob("@daily", job); err != nil {
+		return err
+	}
+
+	s.deps.Logger.Info("Registered token cleanup job (daily)")
+	return nil
+}
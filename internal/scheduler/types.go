@@ -0,0 +1,37 @@
+package scheduler
+
+import "app/internal"
+
+// JobRunResponse represents the result of a manually triggered job
+type JobRunResponse struct {
+	Job        string `json:"job"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// JobRunDataResponse wraps job run result in response
+type JobRunDataResponse struct {
+	Data JobRunResponse `json:"data"`
+}
+
+// JobRunHistoryItem represents one recorded execution of a job. finished_at and
+// duration_ms are null while the job is still running.
+type JobRunHistoryItem struct {
+	ID         int32               `json:"id"`
+	Job        string              `json:"job"`
+	Status     string              `json:"status"`
+	StartedAt  internal.Timestamp  `json:"started_at"`
+	FinishedAt *internal.Timestamp `json:"finished_at"`
+	DurationMs *int64              `json:"duration_ms"`
+	Error      *string             `json:"error"`
+}
+
+// JobRunHistoryResponse wraps the run history of a job in response
+type JobRunHistoryResponse struct {
+	Data []JobRunHistoryItem `json:"data"`
+}
+
+// ErrorResponse represents error response structure
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
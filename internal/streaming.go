@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// streamChunkSize is how much of a stream is written between write deadline extensions
+const streamChunkSize = 32 * 1024
+
+// ExtendWriteDeadline moves the connection's write deadline to timeout from now, overriding
+// the server's WriteTimeout, e.g. while a large request body is still arriving. A zero
+// timeout clears the deadline. Writers that cannot set deadlines are left as they are.
+func ExtendWriteDeadline(w http.ResponseWriter, timeout time.Duration) error {
+	var deadline time.Time
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if err := http.NewResponseController(w).SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+// StreamWithDeadline copies src to w, giving each chunk chunkTimeout to reach the client.
+// Every chunk pushes the connection's write deadline forward (overriding the server's
+// WriteTimeout), so a client that keeps reading can take as long as the download needs
+// while one that stops reading fails within chunkTimeout. A zero chunkTimeout copies
+// without touching deadlines. Writers that cannot set deadlines are streamed as-is.
+func StreamWithDeadline(w http.ResponseWriter, src io.Reader, chunkTimeout time.Duration) (int64, error) {
+	controller := http.NewResponseController(w)
+	useDeadlines := chunkTimeout > 0
+	if useDeadlines {
+		// Clear the per-chunk deadline so it does not leak into the next request on a kept-alive connection
+		defer controller.SetWriteDeadline(time.Time{})
+	}
+
+	buffer := make([]byte, streamChunkSize)
+	var written int64
+	for {
+		n, readErr := src.Read(buffer)
+		if n > 0 {
+			if useDeadlines {
+				if err := controller.SetWriteDeadline(time.Now().Add(chunkTimeout)); err != nil {
+					if !errors.Is(err, http.ErrNotSupported) {
+						return written, err
+					}
+					useDeadlines = false
+				}
+			}
+
+			m, err := w.Write(buffer[:n])
+			written += int64(m)
+			if err != nil {
+				return written, err
+			}
+			if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+				return written, err
+			}
+		}
+
+		if readErr == io.EOF {
+			return written, nil
+		}
+		if readErr != nil {
+			return written, readErr
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package swagger
+
+import (
+	"strings"
+
+	"app/config"
+	"app/docs"
+
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// RegisterRoutes serves the API docs on /swagger unless ENABLE_SWAGGER is off, pointing
+// "Try it out" at cfg.AppURL with the scheme the app is served over
+func RegisterRoutes(r gin.IRoutes, cfg *config.Config) {
+	if !cfg.Features.EnableSwagger {
+		return
+	}
+
+	docs.SwaggerInfo.Host = cfg.AppURL
+	docs.SwaggerInfo.Schemes = Schemes(cfg)
+	if cfg.APIBasePath != "" {
+		SetBasePath(cfg.APIBasePath)
+	}
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+}
+
+// Schemes returns the schemes the API is reachable over: https in production, where
+// TLS is expected in front of the app, http everywhere else
+func Schemes(cfg *config.Config) []string {
+	if cfg.IsProduction() {
+		return []string{"https"}
+	}
+	return []string{"http"}
+}
+
+// SetBasePath serves the documented API under basePath. The generated paths carry the
+// default /api/v1 prefix from the @Router annotations, so it is moved into the base path:
+// "/api/v1/examples" becomes "/examples" under basePath.
+func SetBasePath(basePath string) {
+	docs.SwaggerInfo.BasePath = basePath
+	docs.SwaggerInfo.SwaggerTemplate = strings.ReplaceAll(docs.SwaggerInfo.SwaggerTemplate, `"`+config.DefaultAPIBasePath+`/`, `"/`)
+}
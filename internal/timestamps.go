@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TimestampLayout is the RFC 3339 layout used for timestamps in API responses
+const TimestampLayout = "2006-01-02T15:04:05Z07:00"
+
+// Supported TIMESTAMP_FORMAT values
+const (
+	TimestampRFC3339 = "rfc3339"
+	TimestampUnixMS  = "unix_ms"
+)
+
+// unixMillis switches FormatTimestamp to epoch milliseconds, see SetTimestampFormat
+var unixMillis atomic.Bool
+
+// SetTimestampFormat picks how FormatTimestamp renders times: TimestampUnixMS for JSON numbers
+// of milliseconds since the epoch, anything else for RFC 3339 strings. Set once at startup
+// from TIMESTAMP_FORMAT, it applies to all responses.
+func SetTimestampFormat(format string) {
+	unixMillis.Store(format == TimestampUnixMS)
+}
+
+// Timestamp is a time in a response as FormatTimestamp rendered it: an RFC 3339 string, or
+// the digits of epoch milliseconds, which marshal as a JSON number. Empty means no time and
+// marshals as "" in RFC 3339 mode and null in unix_ms mode.
+type Timestamp string
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if unixMillis.Load() {
+		if t == "" {
+			return []byte("null"), nil
+		}
+		if _, err := strconv.ParseInt(string(t), 10, 64); err == nil {
+			return []byte(t), nil
+		}
+	}
+	return json.Marshal(string(t))
+}
+
+// UnmarshalJSON accepts either format, so the same types decode responses whichever is configured
+func (t *Timestamp) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*t = ""
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*t = Timestamp(s)
+		return nil
+	}
+	var millis int64
+	if err := json.Unmarshal(data, &millis); err != nil {
+		return err
+	}
+	*t = Timestamp(strconv.FormatInt(millis, 10))
+	return nil
+}
+
+// FormatTime formats t for API responses in the configured format
+func FormatTime(t time.Time) Timestamp {
+	if unixMillis.Load() {
+		return Timestamp(strconv.FormatInt(t.UnixMilli(), 10))
+	}
+	return Timestamp(t.Format(TimestampLayout))
+}
+
+// FormatTimestamp formats ts for API responses, returning an empty Timestamp for NULL or zero timestamps
+func FormatTimestamp(ts pgtype.Timestamp) Timestamp {
+	if !ts.Valid || ts.Time.IsZero() {
+		return ""
+	}
+	return FormatTime(ts.Time)
+}
+
+// FormatTimestampPtr formats nullable timestamps, returning nil so they serialize as JSON null
+func FormatTimestampPtr(ts pgtype.Timestamp) *Timestamp {
+	formatted := FormatTimestamp(ts)
+	if formatted == "" {
+		return nil
+	}
+	return &formatted
+}
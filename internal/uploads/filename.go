@@ -0,0 +1,94 @@
+package uploads
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxFilenameLength matches the original_filename column
+const maxFilenameLength = 255
+
+// fallbackFilename replaces names that are empty once sanitized
+const fallbackFilename = "file"
+
+// SanitizeFilename makes a client-supplied filename safe to store and echo back:
+//   - unicode is normalized to NFC, so visually identical names compare equal
+//   - any directory part is dropped ("../../etc/passwd" -> "passwd", "C:\tmp\a.txt" -> "a.txt")
+//   - control and invisible formatting characters (newlines, right-to-left overrides) are removed
+//   - surrounding whitespace is trimmed and the name is cut to 255 characters, keeping the extension
+//
+// Names left empty, "." or ".." become "file". Everything else, including non-ASCII
+// letters, is kept, so the displayed name stays what the user uploaded.
+func SanitizeFilename(name string) string {
+	name = norm.NFC.String(strings.ToValidUTF8(name, ""))
+
+	if i := strings.LastIndexAny(name, `/\`); i >= 0 {
+		name = name[i+1:]
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		ext := []rune(filepath.Ext(name))
+		if len(ext) >= maxFilenameLength {
+			ext = nil
+		}
+		name = string(runes[:maxFilenameLength-len(ext)]) + string(ext)
+	}
+
+	if name == "" || name == "." || name == ".." {
+		return fallbackFilename
+	}
+	return name
+}
+
+// ContentDisposition builds an attachment header for filename following RFC 6266: an ASCII
+// filename for old clients, with anything else replaced by "_", and the exact UTF-8 name
+// percent-encoded in filename*. The name is sanitized first, so stored names from before
+// sanitization can't inject header content either.
+func ContentDisposition(filename string) string {
+	filename = SanitizeFilename(filename)
+
+	var ascii strings.Builder
+	for _, r := range filename {
+		if r < ' ' || r > '~' || r == '"' || r == '\\' || r == '%' {
+			ascii.WriteByte('_')
+		} else {
+			ascii.WriteRune(r)
+		}
+	}
+
+	return fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`, ascii.String(), encodeRFC5987(filename))
+}
+
+// encodeRFC5987 percent-encodes every byte of s that isn't an RFC 5987 attr-char
+func encodeRFC5987(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
@@ -1,9 +1,13 @@
 package uploads
 
 import (
+	"errors"
+	"mime"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"app/internal"
 	"app/internal/errs"
 	"app/internal/logger"
 	"app/internal/middleware"
@@ -31,11 +35,16 @@ func NewHandler(service *UploadService, logger *logger.Logger) *Handler {
 //	@Accept			multipart/form-data
 //	@Produce		json
 //	@Security		Bearer
-//	@Param			file	formData	file				true	"File to upload"
-//	@Success		200		{object}	UploadDataResponse
-//	@Failure		400		{object}	map[string]interface{}
-//	@Failure		401		{object}	map[string]interface{}
-//	@Failure		500		{object}	map[string]interface{}
+//	@Param			file		formData	file				true	"File to upload"
+//	@Param			caption		formData	string				false	"Caption stored with the upload, at most 1000 characters"
+//	@Param			folder_id	formData	int					false	"Folder to upload into, must be owned by the user (defaults to the user's folder)"
+//	@Success		200			{object}	UploadDataResponse
+//	@Failure		400			{object}	map[string]interface{}
+//	@Failure		401			{object}	map[string]interface{}
+//	@Failure		404			{object}	map[string]interface{}
+//	@Failure		413			{object}	map[string]interface{}
+//	@Failure		500			{object}	map[string]interface{}
+//	@Failure		507			{object}	map[string]interface{}
 //	@Router			/api/v1/uploads [post]
 func (h *Handler) UploadFile(c *gin.Context) {
 	userID, err := middleware.GetUserIDFromContext(c)
@@ -44,16 +53,33 @@ func (h *Handler) UploadFile(c *gin.Context) {
 		return
 	}
 
+	h.extendWriteDeadline(c)
+
 	file, err := c.FormFile("file")
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to get uploaded file", "error", err)
+		h.logger.FailureContext(c.Request.Context(), "Failed to get uploaded file", err)
+		if tooLarge, ok := errs.AsRequestTooLarge(err); ok {
+			errs.RespondWithError(c, tooLarge)
+			return
+		}
 		errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "No file uploaded")
 		return
 	}
 
-	upload, err := h.service.UploadFile(c.Request.Context(), file, userID)
+	opts := UploadOptions{Caption: c.PostForm("caption")}
+	if folder := c.PostForm("folder_id"); folder != "" {
+		parsed, err := strconv.ParseInt(folder, 10, 32)
+		if err != nil {
+			errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "Invalid folder ID")
+			return
+		}
+		folderID := int32(parsed)
+		opts.FolderID = &folderID
+	}
+
+	upload, err := h.service.UploadFileWithOptions(c.Request.Context(), file, userID, opts)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to upload file", "error", err, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to upload file", err, "user_id", userID)
 		errs.RespondWithError(c, err)
 		return
 	}
@@ -62,25 +88,109 @@ func (h *Handler) UploadFile(c *gin.Context) {
 
 	c.JSON(http.StatusOK, UploadDataResponse{
 		Data: &UploadResponse{
-			ID:               upload.ID,
-			UserID:           upload.UserID,
-			FolderID:         upload.FolderID,
+			ID:               internal.JSONID(upload.ID),
+			UserID:           internal.JSONID(upload.UserID),
+			FolderID:         internal.JSONID(upload.FolderID),
 			Type:             upload.Type,
 			RelativePath:     upload.RelativePath,
 			FullURL:          h.service.GetFullURL(upload.RelativePath),
 			OriginalFilename: upload.OriginalFilename,
 			FileSize:         upload.FileSize,
 			MimeType:         upload.MimeType.String,
-			CreatedAt:        upload.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:        upload.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+			Caption:          upload.Caption.String,
+			CreatedAt:        internal.FormatTimestamp(upload.CreatedAt),
+			UpdatedAt:        internal.FormatTimestamp(upload.UpdatedAt),
 		},
 	})
 }
 
+// UploadFiles uploads several files in one request
+//
+//	@Summary		Upload files
+//	@Description	Upload up to 10 files at once from repeated "files" fields. Files are stored independently: the response lists the stored uploads and an error for each file that failed
+//	@Tags			uploads
+//	@Accept			multipart/form-data
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			files	formData	[]file				true	"Files to upload"	collectionFormat(multi)
+//	@Success		200		{object}	BulkUploadDataResponse
+//	@Failure		400		{object}	map[string]interface{}
+//	@Failure		401		{object}	map[string]interface{}
+//	@Failure		413		{object}	map[string]interface{}
+//	@Router			/api/v1/uploads/bulk [post]
+func (h *Handler) UploadFiles(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	h.extendWriteDeadline(c)
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		h.logger.FailureContext(c.Request.Context(), "Failed to parse multipart form", err)
+		if tooLarge, ok := errs.AsRequestTooLarge(err); ok {
+			errs.RespondWithError(c, tooLarge)
+			return
+		}
+		errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "No files uploaded")
+		return
+	}
+
+	files := form.File["files"]
+	if len(files) == 0 {
+		errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "No files uploaded")
+		return
+	}
+
+	items, err := h.service.UploadFiles(c.Request.Context(), files, userID)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	result := BulkUploadResult{
+		Uploads: []UploadResponse{},
+		Errors:  []BulkUploadError{},
+	}
+	for i, item := range items {
+		if item.Err != nil {
+			h.logger.WarnContext(c.Request.Context(), "Failed to upload file in bulk upload", "error", item.Err, "filename", item.Filename, "user_id", userID)
+			result.Errors = append(result.Errors, BulkUploadError{
+				Index:    i,
+				Filename: item.Filename,
+				Error:    errs.NewErrorResponse(c, item.Err),
+			})
+			continue
+		}
+
+		upload := item.Upload
+		result.Uploads = append(result.Uploads, UploadResponse{
+			ID:               internal.JSONID(upload.ID),
+			UserID:           internal.JSONID(upload.UserID),
+			FolderID:         internal.JSONID(upload.FolderID),
+			Type:             upload.Type,
+			RelativePath:     upload.RelativePath,
+			FullURL:          h.service.GetFullURL(upload.RelativePath),
+			OriginalFilename: upload.OriginalFilename,
+			FileSize:         upload.FileSize,
+			MimeType:         upload.MimeType.String,
+			Caption:          upload.Caption.String,
+			CreatedAt:        internal.FormatTimestamp(upload.CreatedAt),
+			UpdatedAt:        internal.FormatTimestamp(upload.UpdatedAt),
+		})
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Bulk upload finished", "uploaded", len(result.Uploads), "failed", len(result.Errors), "user_id", userID)
+
+	c.JSON(http.StatusOK, BulkUploadDataResponse{Data: result})
+}
+
 // GetUpload retrieves an upload by ID
 //
 //	@Summary		Get upload
-//	@Description	Get an upload by ID
+//	@Description	Get an upload by ID, with a signed_url to its file that expires after UPLOAD_SIGNED_URL_TTL
 //	@Tags			uploads
 //	@Produce		json
 //	@Security		Bearer
@@ -109,23 +219,82 @@ func (h *Handler) GetUpload(c *gin.Context) {
 		return
 	}
 
+	signedURL, err := h.service.GetSignedURL(upload.RelativePath)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, UploadDataResponse{
 		Data: &UploadResponse{
-			ID:               upload.ID,
-			UserID:           upload.UserID,
-			FolderID:         upload.FolderID,
+			ID:               internal.JSONID(upload.ID),
+			UserID:           internal.JSONID(upload.UserID),
+			FolderID:         internal.JSONID(upload.FolderID),
 			Type:             upload.Type,
 			RelativePath:     upload.RelativePath,
 			FullURL:          h.service.GetFullURL(upload.RelativePath),
 			OriginalFilename: upload.OriginalFilename,
 			FileSize:         upload.FileSize,
 			MimeType:         upload.MimeType.String,
-			CreatedAt:        upload.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:        upload.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+			Caption:          upload.Caption.String,
+			SignedURL:        signedURL,
+			CreatedAt:        internal.FormatTimestamp(upload.CreatedAt),
+			UpdatedAt:        internal.FormatTimestamp(upload.UpdatedAt),
 		},
 	})
 }
 
+// HeadUpload answers with an upload's size, type and modification time without a body,
+// all taken from its record, so clients can check a file without downloading it
+//
+//	@Summary		Get upload headers
+//	@Description	Check an upload exists and read its size, type and last modification from the headers, without a body
+//	@Tags			uploads
+//	@Security		Bearer
+//	@Param			id	path	int	true	"Upload ID"
+//	@Success		200	"Upload exists"
+//	@Header			200	{integer}	Content-Length	"Size of the file in bytes"
+//	@Header			200	{string}	Content-Type	"MIME type of the file"
+//	@Header			200	{string}	Last-Modified	"When the upload was last changed"
+//	@Failure		401	"Not authenticated"
+//	@Failure		404	"Upload not found"
+//	@Router			/api/v1/uploads/{id} [head]
+func (h *Handler) HeadUpload(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	uploadID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "Invalid upload ID")
+		return
+	}
+
+	upload, err := h.service.GetUpload(c.Request.Context(), int32(uploadID), userID)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	contentType := upload.MimeType.String
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	lastModified := upload.UpdatedAt
+	if !lastModified.Valid {
+		lastModified = upload.CreatedAt
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Length", strconv.FormatInt(upload.FileSize, 10))
+	if lastModified.Valid {
+		c.Header("Last-Modified", lastModified.Time.UTC().Format(http.TimeFormat))
+	}
+	c.Status(http.StatusOK)
+}
+
 // ListUploads lists all uploads for the authenticated user
 //
 //	@Summary		List uploads
@@ -145,7 +314,7 @@ func (h *Handler) ListUploads(c *gin.Context) {
 
 	uploads, err := h.service.ListUploads(c.Request.Context(), userID)
 	if err != nil {
-		h.logger.ErrorContext(c.Request.Context(), "Failed to list uploads", "error", err, "user_id", userID)
+		h.logger.FailureContext(c.Request.Context(), "Failed to list uploads", err, "user_id", userID)
 		errs.RespondWithError(c, err)
 		return
 	}
@@ -153,17 +322,18 @@ func (h *Handler) ListUploads(c *gin.Context) {
 	response := make([]UploadResponse, len(uploads))
 	for i, upload := range uploads {
 		response[i] = UploadResponse{
-			ID:               upload.ID,
-			UserID:           upload.UserID,
-			FolderID:         upload.FolderID,
+			ID:               internal.JSONID(upload.ID),
+			UserID:           internal.JSONID(upload.UserID),
+			FolderID:         internal.JSONID(upload.FolderID),
 			Type:             upload.Type,
 			RelativePath:     upload.RelativePath,
 			FullURL:          h.service.GetFullURL(upload.RelativePath),
 			OriginalFilename: upload.OriginalFilename,
 			FileSize:         upload.FileSize,
 			MimeType:         upload.MimeType.String,
-			CreatedAt:        upload.CreatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
-			UpdatedAt:        upload.UpdatedAt.Time.Format("2006-01-02T15:04:05Z07:00"),
+			Caption:          upload.Caption.String,
+			CreatedAt:        internal.FormatTimestamp(upload.CreatedAt),
+			UpdatedAt:        internal.FormatTimestamp(upload.UpdatedAt),
 		}
 	}
 
@@ -172,6 +342,65 @@ func (h *Handler) ListUploads(c *gin.Context) {
 	})
 }
 
+// RenameUpload changes an upload's original filename
+//
+//	@Summary		Rename upload
+//	@Description	Change the original filename of an upload owned by the authenticated user, the stored file is not touched
+//	@Tags			uploads
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			id		path		int					true	"Upload ID"
+//	@Param			request	body		RenameUploadRequest	true	"New filename"
+//	@Success		200		{object}	UploadDataResponse
+//	@Failure		400		{object}	map[string]interface{}
+//	@Failure		401		{object}	map[string]interface{}
+//	@Failure		404		{object}	map[string]interface{}
+//	@Router			/api/v1/uploads/{id} [patch]
+func (h *Handler) RenameUpload(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	uploadIDStr := c.Param("id")
+	uploadID, err := strconv.ParseInt(uploadIDStr, 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "Invalid upload ID")
+		return
+	}
+
+	var req RenameUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	upload, err := h.service.RenameUpload(c.Request.Context(), int32(uploadID), userID, req.OriginalFilename)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, UploadDataResponse{
+		Data: &UploadResponse{
+			ID:               internal.JSONID(upload.ID),
+			UserID:           internal.JSONID(upload.UserID),
+			FolderID:         internal.JSONID(upload.FolderID),
+			Type:             upload.Type,
+			RelativePath:     upload.RelativePath,
+			FullURL:          h.service.GetFullURL(upload.RelativePath),
+			OriginalFilename: upload.OriginalFilename,
+			FileSize:         upload.FileSize,
+			MimeType:         upload.MimeType.String,
+			Caption:          upload.Caption.String,
+			CreatedAt:        internal.FormatTimestamp(upload.CreatedAt),
+			UpdatedAt:        internal.FormatTimestamp(upload.UpdatedAt),
+		},
+	})
+}
+
 // DeleteUpload deletes an upload
 //
 //	@Summary		Delete upload
@@ -212,3 +441,174 @@ func (h *Handler) DeleteUpload(c *gin.Context) {
 		},
 	})
 }
+
+// DeleteUploads deletes several uploads in one request
+//
+//	@Summary		Delete uploads
+//	@Description	Delete up to 100 uploads owned by the authenticated user. Each ID is reported separately: IDs that don't exist or belong to someone else fail without affecting the rest
+//	@Tags			uploads
+//	@Accept			json
+//	@Produce		json
+//	@Security		Bearer
+//	@Param			request	body		BulkDeleteRequest	true	"Upload IDs"
+//	@Success		200		{object}	BulkDeleteDataResponse
+//	@Failure		400		{object}	map[string]interface{}
+//	@Failure		401		{object}	map[string]interface{}
+//	@Router			/api/v1/uploads [delete]
+func (h *Handler) DeleteUploads(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	var req BulkDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		errs.RespondWithValidationError(c, err)
+		return
+	}
+
+	uploadIDs := make([]int32, len(req.IDs))
+	for i, id := range req.IDs {
+		uploadIDs[i] = int32(id)
+	}
+
+	items, err := h.service.DeleteUploads(c.Request.Context(), uploadIDs, userID)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	result := BulkDeleteResult{Results: make([]BulkDeleteItemResult, len(items))}
+	for i, item := range items {
+		result.Results[i] = BulkDeleteItemResult{ID: internal.JSONID(item.ID), Deleted: item.Err == nil}
+		if item.Err != nil {
+			if !errors.Is(item.Err, ErrUploadNotFound) {
+				h.logger.WarnContext(c.Request.Context(), "Failed to delete upload in bulk delete", "error", item.Err, "upload_id", item.ID, "user_id", userID)
+			}
+			response := errs.NewErrorResponse(c, item.Err)
+			result.Results[i].Error = &response
+			result.Failed++
+			continue
+		}
+		result.Deleted++
+	}
+
+	h.logger.InfoContext(c.Request.Context(), "Bulk delete finished", "deleted", result.Deleted, "failed", result.Failed, "user_id", userID)
+
+	c.JSON(http.StatusOK, BulkDeleteDataResponse{Data: result})
+}
+
+// DownloadUpload streams an upload's file to the client
+//
+//	@Summary		Download upload
+//	@Description	Download the file of an upload owned by the authenticated user. Uploads whose file was removed from disk return 410 uploads.file_gone and are flagged as missing
+//	@Tags			uploads
+//	@Produce		octet-stream
+//	@Security		Bearer
+//	@Param			id	path		int	true	"Upload ID"
+//	@Success		200	{file}		file
+//	@Failure		401	{object}	map[string]interface{}
+//	@Failure		404	{object}	map[string]interface{}
+//	@Failure		410	{object}	map[string]interface{}
+//	@Router			/api/v1/uploads/{id}/download [get]
+func (h *Handler) DownloadUpload(c *gin.Context) {
+	userID, err := middleware.GetUserIDFromContext(c)
+	if err != nil {
+		errs.RespondWithUnauthorized(c, "Unauthorized")
+		return
+	}
+
+	uploadID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		errs.RespondWithBadRequest(c, errs.ErrKeyValidationError, "Invalid upload ID")
+		return
+	}
+
+	upload, err := h.service.GetUploadWithOptions(c.Request.Context(), int32(uploadID), userID, GetUploadOptions{VerifyExists: true})
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+
+	file, err := h.service.OpenUpload(upload)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		errs.RespondWithError(c, errs.WrapInternal(errs.ErrKeyInternalError, "failed to stat upload", err))
+		return
+	}
+
+	contentType := upload.MimeType.String
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Length", strconv.FormatInt(info.Size(), 10))
+	c.Header("Content-Disposition", ContentDisposition(upload.OriginalFilename))
+	c.Status(http.StatusOK)
+
+	// Headers are out once streaming starts, so failures can only be logged
+	if _, err := internal.StreamWithDeadline(c.Writer, file, h.service.config.DownloadChunkTimeout); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "Upload download aborted", "error", err, "upload_id", upload.ID, "user_id", userID)
+		c.Abort()
+	}
+}
+
+// ServeSignedFile streams a stored file to anyone holding a link signed by LocalStorage.
+// Must be mounted behind middleware.SignedURL, which rejects bad and expired signatures.
+//
+//	@Summary		Get file by signed link
+//	@Description	Download a file through a temporary link from the signed_url of an upload, no auth needed
+//	@Tags			uploads
+//	@Produce		octet-stream
+//	@Param			path		path		string	true	"Relative path of the file"
+//	@Param			expires		query		int		true	"Expiry as a Unix timestamp"
+//	@Param			signature	query		string	true	"Link signature"
+//	@Success		200			{file}		file
+//	@Failure		403			{object}	map[string]interface{}
+//	@Failure		404			{object}	map[string]interface{}
+//	@Router			/api/v1/files/{path} [get]
+func (h *Handler) ServeSignedFile(c *gin.Context) {
+	relativePath := strings.TrimPrefix(c.Param("path"), "/")
+
+	file, err := h.service.OpenFile(relativePath)
+	if err != nil {
+		errs.RespondWithError(c, err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		errs.RespondWithError(c, errs.WrapInternal(errs.ErrKeyInternalError, "failed to stat file", err))
+		return
+	}
+
+	contentType := mime.TypeByExtension(FileExtension(relativePath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Length", strconv.FormatInt(info.Size(), 10))
+	c.Status(http.StatusOK)
+
+	if _, err := internal.StreamWithDeadline(c.Writer, file, h.service.config.DownloadChunkTimeout); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "Signed file download aborted", "error", err, "path", relativePath)
+		c.Abort()
+	}
+}
+
+// extendWriteDeadline gives an upload UploadTimeout to answer before its body is read.
+// The server's WriteTimeout runs from the request headers, so a body that takes longer to
+// arrive would otherwise lose its response.
+func (h *Handler) extendWriteDeadline(c *gin.Context) {
+	if err := internal.ExtendWriteDeadline(c.Writer, h.service.config.UploadTimeout); err != nil {
+		h.logger.WarnContext(c.Request.Context(), "Failed to extend write deadline for upload", "error", err)
+	}
+}
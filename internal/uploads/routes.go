@@ -1,19 +1,80 @@
 package uploads
 
 import (
+	"context"
+
 	"app/internal"
+	"app/internal/db"
 	"app/internal/middleware"
 )
 
-// RegisterRoutes registers upload routes
-func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier) {
+// multipartOverhead is headroom on top of MaxFileSize for multipart boundaries and part headers
+const multipartOverhead = 1 << 20
+
+// EventUploadCreated is the webhook event sent after a file is uploaded
+const EventUploadCreated = "upload.created"
+
+// filesPath is the group serving signed links to files, verified by the link instead of a user token
+const filesPath = "/files"
+
+// NewService creates the upload service configured from app.Config, shared by the upload
+// routes and the services that delete uploads of their own, e.g. account deletion
+func NewService(app *internal.App) *UploadService {
 	config := DefaultUploadConfig(app.Config.UploadFolder, app.Config.FilesBaseURL)
+	config.DownloadChunkTimeout = app.Config.StreamChunkTimeout
+	config.UploadTimeout = app.Config.UploadTimeout
+	config.MaxTotalBytesPerUser = app.Config.UploadMaxBytesPerUser
+	config.MaxFilesPerUser = app.Config.UploadMaxFilesPerUser
+	config.Deduplicate = app.Config.UploadDeduplicate
+	config.SignedURLTTL = app.Config.UploadSignedURLTTL
+	config.Storage = NewLocalStorage(app.Api.Group(filesPath).BasePath(), []byte(app.Config.SignedURLSecret))
+
 	service := NewUploadService(app.Queries, config)
+
+	if app.Webhooks.Enabled() {
+		config.OnUploadCreated = func(ctx context.Context, upload *db.Upload) {
+			app.Webhooks.Dispatch(ctx, EventUploadCreated, UploadResponse{
+				ID:               internal.JSONID(upload.ID),
+				UserID:           internal.JSONID(upload.UserID),
+				FolderID:         internal.JSONID(upload.FolderID),
+				Type:             upload.Type,
+				RelativePath:     upload.RelativePath,
+				FullURL:          service.GetFullURL(upload.RelativePath),
+				OriginalFilename: upload.OriginalFilename,
+				FileSize:         upload.FileSize,
+				MimeType:         upload.MimeType.String,
+				Caption:          upload.Caption.String,
+				CreatedAt:        internal.FormatTimestamp(upload.CreatedAt),
+				UpdatedAt:        internal.FormatTimestamp(upload.UpdatedAt),
+			})
+		}
+	}
+
+	return service
+}
+
+// RegisterRoutes registers upload routes, authenticated by a user token or API key
+func RegisterRoutes(app *internal.App, authService middleware.UserJWTVerifier, apiKeys middleware.APIKeyVerifier, service *UploadService) {
+	config := service.config
 	handler := NewHandler(service, app.Logger)
 
+	// Signed links to files, verified here instead of by a user token
+	files := app.Api.Group(filesPath)
+	files.Use(middleware.SignedURL([]byte(app.Config.SignedURLSecret)))
+
+	files.GET("/*path", handler.ServeSignedFile)
+
 	uploads := app.Api.Group("/uploads")
-	uploads.Use(middleware.UserAuthMiddleware(authService))
+	uploads.Use(middleware.UserOrAPIKeyAuthMiddleware(authService, apiKeys))
 	{
-		uploads.POST("", handler.UploadFile)
+		// The multipart body is read inside the handler, so uploads get their own time limit
+		uploadTimeout := middleware.Timeout(config.UploadTimeout)
+		uploads.POST("", uploadTimeout, middleware.MaxBodySize(config.MaxFileSize+multipartOverhead), handler.UploadFile)
+		uploads.POST("/bulk", uploadTimeout, middleware.MaxBodySize(config.MaxFileSize*int64(config.MaxFilesPerRequest)+multipartOverhead), handler.UploadFiles)
+		uploads.DELETE("", middleware.RequireJSON(), handler.DeleteUploads)
+		uploads.GET("/:id", handler.GetUpload)
+		uploads.HEAD("/:id", handler.HeadUpload)
+		uploads.PATCH("/:id", middleware.RequireJSON(), handler.RenameUpload)
+		uploads.GET("/:id/download", handler.DownloadUpload)
 	}
 }
@@ -0,0 +1,37 @@
+package uploads
+
+import (
+	"path"
+	"time"
+
+	"app/internal/middleware"
+)
+
+// Storage hands out links to stored files. Files on private storage aren't reachable
+// through their FullURL, so clients get a signed link that stops working after a while.
+// LocalStorage is the only implementation in this tree; an object storage backend would
+// return presigned URLs from GetSignedURL instead.
+type Storage interface {
+	// GetSignedURL returns a link to the file at relativePath that expires after ttl
+	GetSignedURL(relativePath string, ttl time.Duration) (string, error)
+}
+
+// LocalStorage signs links to files on the local disk with middleware.SignURL.
+// They are served by Handler.ServeSignedFile, mounted at BasePath behind middleware.SignedURL.
+type LocalStorage struct {
+	// BasePath is the route the files are served under, e.g. "/api/v1/files"
+	BasePath string
+	Secret   []byte
+}
+
+// NewLocalStorage creates a local storage signing links under basePath with secret
+func NewLocalStorage(basePath string, secret []byte) *LocalStorage {
+	return &LocalStorage{
+		BasePath: basePath,
+		Secret:   secret,
+	}
+}
+
+func (s *LocalStorage) GetSignedURL(relativePath string, ttl time.Duration) (string, error) {
+	return middleware.SignURL(s.Secret, path.Join(s.BasePath, relativePath), time.Now().Add(ttl))
+}
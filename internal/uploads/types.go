@@ -2,39 +2,78 @@ package uploads
 
 import (
 	"app/internal"
+	"app/internal/errs"
 )
 
+// RenameUploadRequest represents the request body for renaming an upload
+type RenameUploadRequest struct {
+	OriginalFilename string `json:"original_filename" binding:"required,max=255"`
+}
+
 // UploadResponse represents upload information
 type UploadResponse struct {
-	ID               int32  `json:"id"`
-	UserID           int32  `json:"user_id"`
-	FolderID         int32  `json:"folder_id"`
-	Type             string `json:"type"`
-	RelativePath     string `json:"relative_path"`
-	FullURL          string `json:"full_url"`
-	OriginalFilename string `json:"original_filename"`
-	FileSize         int64  `json:"file_size"`
-	MimeType         string `json:"mime_type"`
-	CreatedAt        string `json:"created_at"`
-	UpdatedAt        string `json:"updated_at"`
+	ID               internal.JSONID    `json:"id"`
+	UserID           internal.JSONID    `json:"user_id"`
+	FolderID         internal.JSONID    `json:"folder_id"`
+	Type             string             `json:"type"`
+	RelativePath     string             `json:"relative_path"`
+	FullURL          string             `json:"full_url"`
+	OriginalFilename string             `json:"original_filename"`
+	FileSize         int64              `json:"file_size"`
+	MimeType         string             `json:"mime_type"`
+	Caption          string             `json:"caption,omitempty"`
+	SignedURL        string             `json:"signed_url,omitempty"` // Only returned by GET /uploads/:id
+	CreatedAt        internal.Timestamp `json:"created_at"`
+	UpdatedAt        internal.Timestamp `json:"updated_at"`
 }
 
 // UploadDataResponse wraps upload data in response
-type UploadDataResponse struct {
-	Data *UploadResponse `json:"data"`
+type UploadDataResponse = internal.DataResponse[*UploadResponse]
+
+// BulkUploadError reports a file of a bulk upload that was not stored
+type BulkUploadError struct {
+	Index    int                `json:"index"`
+	Filename string             `json:"filename"`
+	Error    errs.ErrorResponse `json:"error"`
 }
 
-// PaginatedUploadsResponse wraps paginated uploads in response
-type PaginatedUploadsResponse struct {
-	Data       []UploadResponse        `json:"data"`
-	Pagination internal.PaginationMeta `json:"pagination"`
+// BulkUploadResult lists the stored uploads and the files that failed
+type BulkUploadResult struct {
+	Uploads []UploadResponse  `json:"uploads"`
+	Errors  []BulkUploadError `json:"errors"`
 }
 
-// UploadsListResponse wraps uploads list in response
-type UploadsListResponse struct {
-	Data []UploadResponse `json:"data"`
+// BulkUploadDataResponse wraps bulk upload results in response
+type BulkUploadDataResponse = internal.DataResponse[BulkUploadResult]
+
+// BulkDeleteRequest represents the request body for deleting several uploads
+type BulkDeleteRequest struct {
+	IDs []internal.JSONID `json:"ids" binding:"required,min=1,dive,min=1,max=2147483647"`
+}
+
+// BulkDeleteItemResult reports whether one ID of a bulk delete was deleted
+type BulkDeleteItemResult struct {
+	ID      internal.JSONID     `json:"id"`
+	Deleted bool                `json:"deleted"`
+	Error   *errs.ErrorResponse `json:"error,omitempty"`
 }
 
+// BulkDeleteResult lists the outcome of each requested ID
+type BulkDeleteResult struct {
+	Results []BulkDeleteItemResult `json:"results"`
+	Deleted int                    `json:"deleted"`
+	Failed  int                    `json:"failed"`
+}
+
+// BulkDeleteDataResponse wraps bulk delete results in response
+type BulkDeleteDataResponse = internal.DataResponse[BulkDeleteResult]
+
+// PaginatedUploadsResponse wraps paginated uploads in response
+type PaginatedUploadsResponse = internal.PaginatedResponse[UploadResponse]
+
+// UploadsListResponse wraps uploads list in response
+type UploadsListResponse = internal.DataResponse[[]UploadResponse]
+
 // MessageResponse wraps a simple message in response
 type MessageResponse struct {
 	Data struct {
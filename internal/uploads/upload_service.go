@@ -3,19 +3,25 @@ package uploads
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"app/internal/db"
 	"app/internal/errs"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -24,25 +30,59 @@ type UploadConfig struct {
 	UploadFolder string
 	BaseURL      string
 	MaxFileSize  int64
-	AllowedTypes []string
-	GetFolderID  func(ctx context.Context, userID int32) (int32, error)
+	AllowedTypes []string // Extensions with leading dot, matched case-insensitively
+	// GetAllowedTypes resolves the extensions a user may upload, e.g. from their roles or plan.
+	// AllowedTypes is used when nil.
+	GetAllowedTypes func(ctx context.Context, userID int32) ([]string, error)
+	// AllowNoExtension accepts files without an extension (e.g. "README", ".env")
+	AllowNoExtension bool
+	// GetFolderID returns the folder uploads go into when a request names none.
+	// When nil, it is the user's root folder, created on first use.
+	GetFolderID func(ctx context.Context, userID int32) (int32, error)
+	// UserOwnsFolder reports whether userID may upload into folderID when a request names
+	// a folder. When nil, any folder of the user's in the folders table.
+	UserOwnsFolder func(ctx context.Context, userID, folderID int32) (bool, error)
+	// CreateFile opens the destination for an upload, os.Create is used when nil
+	CreateFile func(path string) (io.WriteCloser, error)
+	// DownloadChunkTimeout is how long each chunk of a download may take to reach the client
+	DownloadChunkTimeout time.Duration
+	// UploadTimeout is how long an upload, body included, may take to answer,
+	// replacing the server's WriteTimeout; 0 means unbounded
+	UploadTimeout time.Duration
+	// MaxTotalBytesPerUser caps the combined size of a user's uploads, 0 means unlimited
+	MaxTotalBytesPerUser int64
+	// MaxFilesPerUser caps how many uploads a user may keep, 0 means unlimited
+	MaxFilesPerUser int64
+	// MaxFilesPerRequest caps how many files one bulk upload may carry
+	MaxFilesPerRequest int
+	// MaxDeletesPerRequest caps how many IDs one bulk delete may carry
+	MaxDeletesPerRequest int
+	// Deduplicate stores identical bytes uploaded again by the same user only once:
+	// the new upload row points at the file already on disk, which is removed with its last row
+	Deduplicate bool
+	// Storage signs temporary links to uploads, nil leaves signed_url out of responses
+	Storage Storage
+	// SignedURLTTL is how long a signed link stays valid
+	SignedURLTTL time.Duration
+	// OnUploadCreated is called after an upload is stored, nil does nothing.
+	// It runs before the response is sent, so it must not block.
+	OnUploadCreated func(ctx context.Context, upload *db.Upload)
 }
 
 // DefaultUploadConfig returns a default configuration
 func DefaultUploadConfig(uploadFolder, baseURL string) *UploadConfig {
 	return &UploadConfig{
-		UploadFolder: uploadFolder,
-		BaseURL:      baseURL,
-		MaxFileSize:  50 * 1024 * 1024, // 50MB
+		UploadFolder:         uploadFolder,
+		BaseURL:              baseURL,
+		MaxFileSize:          50 * 1024 * 1024, // 50MB
+		MaxFilesPerRequest:   10,
+		MaxDeletesPerRequest: 100,
 		AllowedTypes: []string{
-			".jpg", ".jpeg", ".png", ".gif", ".webp",
+			".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic", ".heif", ".avif",
 			".pdf", ".doc", ".docx", ".txt",
-			".mp4", ".avi", ".mov",
+			".mp4", ".avi", ".mov", ".mkv", ".webm",
 			".mp3", ".wav", ".ogg",
 		},
-		GetFolderID: func(ctx context.Context, userID int32) (int32, error) {
-			return userID, nil
-		},
 	}
 }
 
@@ -60,14 +100,40 @@ func NewUploadService(queries *db.Queries, config *UploadConfig) *UploadService
 	}
 }
 
+// WithTx returns a copy of the service running on q, so its writes join the transaction
+// q belongs to (see db.Transactor)
+func (s *UploadService) WithTx(q *db.Queries) *UploadService {
+	return &UploadService{queries: q, config: s.config}
+}
+
+// FileExtension returns the lowercased extension of a filename, including the leading dot.
+// Only the last extension counts ("archive.tar.gz" -> ".gz", "photo.jpg.exe" -> ".exe").
+// Returns an empty string when there is no usable extension:
+//   - no dot at all ("README")
+//   - a trailing dot ("image.")
+//   - a dotfile without a further extension (".env", ".htaccess")
+func FileExtension(filename string) string {
+	base := filepath.Base(filename)
+
+	// A leading dot marks a hidden file, not an extension
+	base = strings.TrimLeft(base, ".")
+
+	ext := filepath.Ext(base)
+	if ext == "." {
+		return ""
+	}
+
+	return strings.ToLower(ext)
+}
+
 // GetFileType determines the file type based on extension
 func (s *UploadService) GetFileType(filename string) string {
-	ext := strings.ToLower(filepath.Ext(filename))
+	ext := FileExtension(filename)
 
 	switch ext {
-	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".heic", ".heif", ".avif":
 		return "image"
-	case ".mp4", ".avi", ".mov", ".wmv", ".flv":
+	case ".mp4", ".avi", ".mov", ".wmv", ".flv", ".mkv", ".webm":
 		return "video"
 	case ".mp3", ".wav", ".ogg", ".aac", ".flac":
 		return "audio"
@@ -78,11 +144,34 @@ func (s *UploadService) GetFileType(filename string) string {
 	}
 }
 
-// IsValidFileType checks if the file type is allowed
+// IsValidFileType checks if the file type is in the static AllowedTypes list
+// Files without an extension are rejected unless AllowNoExtension is set
 func (s *UploadService) IsValidFileType(filename string) bool {
-	ext := strings.ToLower(filepath.Ext(filename))
-	for _, allowedExt := range s.config.AllowedTypes {
-		if ext == allowedExt {
+	return s.isAllowedType(filename, s.config.AllowedTypes)
+}
+
+// allowedTypes returns the extensions userID may upload
+func (s *UploadService) allowedTypes(ctx context.Context, userID int32) ([]string, error) {
+	if s.config.GetAllowedTypes == nil {
+		return s.config.AllowedTypes, nil
+	}
+
+	allowed, err := s.config.GetAllowedTypes(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to get allowed file types", err)
+	}
+	return allowed, nil
+}
+
+func (s *UploadService) isAllowedType(filename string, allowed []string) bool {
+	ext := FileExtension(filename)
+	if ext == "" {
+		return s.config.AllowNoExtension
+	}
+
+	for _, allowedExt := range allowed {
+		// Accept config entries regardless of case or leading dot ("JPG", ".jpg")
+		if ext == "."+strings.TrimPrefix(strings.ToLower(allowedExt), ".") {
 			return true
 		}
 	}
@@ -91,7 +180,7 @@ func (s *UploadService) IsValidFileType(filename string) bool {
 
 // GenerateRandomName generates a random filename
 func (s *UploadService) GenerateRandomName(originalName string) string {
-	ext := filepath.Ext(originalName)
+	ext := FileExtension(originalName)
 	randomBytes := make([]byte, 8)
 	rand.Read(randomBytes)
 	randomString := hex.EncodeToString(randomBytes)
@@ -100,13 +189,42 @@ func (s *UploadService) GenerateRandomName(originalName string) string {
 	return filename
 }
 
-// UploadFile uploads a file and stores it in the database
+// MaxCaptionLength is the longest caption, in characters, an upload may carry
+const MaxCaptionLength = 1000
+
+// UploadOptions are the optional form fields sent along with a file
+type UploadOptions struct {
+	// Caption is stored with the upload, blank stores none
+	Caption string
+	// FolderID picks the folder, it must be one the user owns. Nil uses the default folder.
+	FolderID *int32
+}
+
+// UploadFile uploads a file into the user's default folder and stores it in the database
 func (s *UploadService) UploadFile(ctx context.Context, file *multipart.FileHeader, userID int32) (*db.Upload, error) {
-	if !s.IsValidFileType(file.Filename) {
+	return s.UploadFileWithOptions(ctx, file, userID, UploadOptions{})
+}
+
+// UploadFileWithOptions uploads a file with a caption and into a chosen folder. A folder the
+// user doesn't own returns ErrFolderNotFound, a caption over MaxCaptionLength a 400.
+func (s *UploadService) UploadFileWithOptions(ctx context.Context, file *multipart.FileHeader, userID int32, opts UploadOptions) (*db.Upload, error) {
+	caption := strings.TrimSpace(opts.Caption)
+	if utf8.RuneCountInString(caption) > MaxCaptionLength {
+		return nil, errs.NewBadRequestError(
+			errs.ErrKeyValidationError,
+			fmt.Sprintf("Caption must be at most %d characters", MaxCaptionLength),
+		)
+	}
+
+	allowed, err := s.allowedTypes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.isAllowedType(file.Filename, allowed) {
 		return nil, errs.WrapBadRequest(
 			errs.ErrKeyValidationError,
 			"File type not allowed",
-			fmt.Errorf("file type not allowed: %s", filepath.Ext(file.Filename)),
+			fmt.Errorf("file type not allowed: %q", FileExtension(file.Filename)),
 		)
 	}
 
@@ -125,13 +243,41 @@ func (s *UploadService) UploadFile(ctx context.Context, file *multipart.FileHead
 		)
 	}
 
-	folderID, err := s.config.GetFolderID(ctx, userID)
+	if err := s.checkQuota(ctx, userID, file.Size); err != nil {
+		return nil, err
+	}
+
+	folderID, err := s.resolveFolderID(ctx, userID, opts.FolderID)
 	if err != nil {
-		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to get folder ID", err)
+		return nil, err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to open uploaded file", err)
+	}
+	defer src.Close()
+
+	// In dedup mode the checksum is needed before writing, otherwise it is taken while writing
+	hash := sha256.New()
+	checksum := ""
+	if s.config.Deduplicate {
+		if _, err := io.Copy(hash, src); err != nil {
+			return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to read uploaded file", err)
+		}
+		checksum = hex.EncodeToString(hash.Sum(nil))
+
+		upload, err := s.createDuplicate(ctx, file, userID, folderID, checksum, caption)
+		if err != nil || upload != nil {
+			return upload, err
+		}
+
+		if _, err := src.Seek(0, io.SeekStart); err != nil {
+			return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to rewind uploaded file", err)
+		}
 	}
 
 	filename := s.GenerateRandomName(file.Filename)
-	fileType := s.GetFileType(file.Filename)
 
 	folderDir := strconv.Itoa(int(folderID))
 	userPath := filepath.Join(s.config.UploadFolder, folderDir)
@@ -143,44 +289,292 @@ func (s *UploadService) UploadFile(ctx context.Context, file *multipart.FileHead
 	filePath := filepath.Join(userPath, filename)
 	relativePath := filepath.Join(folderDir, filename)
 
-	src, err := file.Open()
+	var reader io.Reader = src
+	if checksum == "" {
+		reader = io.TeeReader(src, hash)
+	}
+	if err := s.writeFile(filePath, reader); err != nil {
+		return nil, err
+	}
+	if checksum == "" {
+		checksum = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	upload, err := s.createUpload(ctx, file, userID, folderID, relativePath, checksum, caption)
 	if err != nil {
-		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to open uploaded file", err)
+		os.Remove(filePath)
+		return nil, err
 	}
-	defer src.Close()
 
-	dst, err := os.Create(filePath)
+	return upload, nil
+}
+
+// resolveFolderID returns the folder an upload goes into: the requested one when the user
+// owns it, the default folder otherwise
+func (s *UploadService) resolveFolderID(ctx context.Context, userID int32, requested *int32) (int32, error) {
+	if requested == nil {
+		folderID, err := s.defaultFolderID(ctx, userID)
+		if err != nil {
+			return 0, errs.WrapInternal(errs.ErrKeyInternalError, "failed to get folder ID", err)
+		}
+		return folderID, nil
+	}
+
+	owned, err := s.userOwnsFolder(ctx, userID, *requested)
+	if err != nil {
+		return 0, errs.WrapInternal(errs.ErrKeyInternalError, "failed to check folder ownership", err)
+	}
+	if !owned {
+		return 0, ErrFolderNotFound
+	}
+	return *requested, nil
+}
+
+func (s *UploadService) defaultFolderID(ctx context.Context, userID int32) (int32, error) {
+	if s.config.GetFolderID != nil {
+		return s.config.GetFolderID(ctx, userID)
+	}
+	folder, err := s.queries.EnsureRootFolder(ctx, userID)
 	if err != nil {
-		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to create destination file", err)
+		return 0, err
 	}
-	defer dst.Close()
+	return folder.ID, nil
+}
 
-	if _, err := io.Copy(dst, src); err != nil {
-		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to copy file", err)
+func (s *UploadService) userOwnsFolder(ctx context.Context, userID, folderID int32) (bool, error) {
+	if s.config.UserOwnsFolder != nil {
+		return s.config.UserOwnsFolder(ctx, userID, folderID)
 	}
+	_, err := s.queries.GetFolderByIDAndUserID(ctx, db.GetFolderByIDAndUserIDParams{
+		ID:     folderID,
+		UserID: userID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// createUpload records an upload of file stored at relativePath
+func (s *UploadService) createUpload(ctx context.Context, file *multipart.FileHeader, userID, folderID int32, relativePath, checksum, caption string) (*db.Upload, error) {
+	return s.insertUpload(ctx, file, userID, folderID, checksum, caption, func(*db.Queries) (string, error) {
+		return relativePath, nil
+	})
+}
 
+// createDuplicate records an upload sharing the file of the user's upload with the same
+// checksum, or returns nil when there is none. The lookup and the insert hold
+// LockUserUploads, which deletes take too, so deleting the last upload sharing the file
+// can't remove it in between.
+func (s *UploadService) createDuplicate(ctx context.Context, file *multipart.FileHeader, userID, folderID int32, checksum, caption string) (*db.Upload, error) {
+	return s.insertUpload(ctx, file, userID, folderID, checksum, caption, func(q *db.Queries) (string, error) {
+		duplicate, err := s.WithTx(q).findDuplicate(ctx, userID, checksum)
+		if err != nil || duplicate == nil {
+			return "", err
+		}
+		return duplicate.RelativePath, nil
+	})
+}
+
+// insertUpload inserts the upload row for file at the path locate returns, inserting
+// nothing and returning nil when locate returns "". With quotas or deduplication on,
+// locate, the quota check and the insert run in one transaction under LockUserUploads.
+func (s *UploadService) insertUpload(ctx context.Context, file *multipart.FileHeader, userID, folderID int32, checksum, caption string, locate func(q *db.Queries) (string, error)) (*db.Upload, error) {
 	mimeType := file.Header.Get("Content-Type")
 	if mimeType == "" {
 		mimeType = "application/octet-stream"
 	}
 
-	upload, err := s.queries.CreateUpload(ctx, db.CreateUploadParams{
+	params := db.CreateUploadParams{
 		UserID:           userID,
 		FolderID:         folderID,
-		Type:             fileType,
-		RelativePath:     relativePath,
-		OriginalFilename: file.Filename,
+		Type:             s.GetFileType(file.Filename),
+		OriginalFilename: SanitizeFilename(file.Filename),
 		FileSize:         file.Size,
 		MimeType:         pgtype.Text{String: mimeType, Valid: true},
-	})
+		Checksum:         pgtype.Text{String: checksum, Valid: checksum != ""},
+		Caption:          pgtype.Text{String: caption, Valid: caption != ""},
+	}
+
+	insert := func(q *db.Queries) (*db.Upload, error) {
+		var err error
+		if params.RelativePath, err = locate(q); err != nil || params.RelativePath == "" {
+			return nil, err
+		}
+		if err := s.WithTx(q).checkQuota(ctx, userID, file.Size); err != nil {
+			return nil, err
+		}
+		upload, err := q.CreateUpload(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		return &upload, nil
+	}
+
+	var upload *db.Upload
+	var err error
+	if s.hasQuota() || s.config.Deduplicate {
+		// The quota check before the file was written let concurrent uploads all pass it, and a
+		// duplicate's file may be removed by a concurrent delete, so both are settled under a
+		// per-user lock held until this row is committed
+		err = s.queries.InTx(ctx, func(q *db.Queries) error {
+			if err := q.LockUserUploads(ctx, userID); err != nil {
+				return errs.WrapInternal(errs.ErrKeyInternalError, "failed to lock user uploads", err)
+			}
+			upload, err = insert(q)
+			return err
+		})
+	} else {
+		upload, err = insert(s.queries)
+	}
 	if err != nil {
-		os.Remove(filePath)
+		if errs.IsDomainError(err) {
+			return nil, err
+		}
+		if domainErr := errs.FromPgError(err); domainErr != nil {
+			return nil, domainErr
+		}
 		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to save upload to database", err)
 	}
+	if upload == nil {
+		return nil, nil
+	}
+
+	if s.config.OnUploadCreated != nil {
+		s.config.OnUploadCreated(ctx, upload)
+	}
+
+	return upload, nil
+}
 
+// findDuplicate returns an upload of the user with the same content whose file is
+// still on disk, or nil when there is none
+func (s *UploadService) findDuplicate(ctx context.Context, userID int32, checksum string) (*db.Upload, error) {
+	upload, err := s.queries.GetUploadByChecksum(ctx, db.GetUploadByChecksumParams{
+		UserID:   userID,
+		Checksum: pgtype.Text{String: checksum, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to look up duplicate upload", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(s.config.UploadFolder, upload.RelativePath)); err != nil {
+		return nil, nil
+	}
 	return &upload, nil
 }
 
+// BulkUploadItem is the outcome of one file in UploadFiles, exactly one of Upload and Err is set
+type BulkUploadItem struct {
+	Filename string
+	Upload   *db.Upload
+	Err      error
+}
+
+// UploadFiles uploads each file in turn with UploadFile and reports every outcome, so
+// one bad file doesn't fail the others. Quotas are checked per file against what is
+// already stored, so once the batch reaches a quota the remaining files fail with
+// uploads.quota_exceeded. More than MaxFilesPerRequest files are rejected up front.
+func (s *UploadService) UploadFiles(ctx context.Context, files []*multipart.FileHeader, userID int32) ([]BulkUploadItem, error) {
+	if s.config.MaxFilesPerRequest > 0 && len(files) > s.config.MaxFilesPerRequest {
+		return nil, errs.NewBadRequestError(
+			errs.ErrKeyUploadTooManyFiles,
+			"Too many files in one request",
+		).WithDetails(map[string]interface{}{"max_files": s.config.MaxFilesPerRequest})
+	}
+
+	items := make([]BulkUploadItem, len(files))
+	for i, file := range files {
+		upload, err := s.UploadFile(ctx, file, userID)
+		items[i] = BulkUploadItem{Filename: file.Filename, Upload: upload, Err: err}
+	}
+	return items, nil
+}
+
+// hasQuota reports whether uploads are limited per user
+func (s *UploadService) hasQuota() bool {
+	return s.config.MaxTotalBytesPerUser > 0 || s.config.MaxFilesPerUser > 0
+}
+
+// checkQuota rejects an upload of size bytes that would take the user past
+// MaxFilesPerUser or MaxTotalBytesPerUser with uploads.quota_exceeded (413).
+// It runs before the file is written to turn away uploads early, and again with the
+// insert under LockUserUploads so concurrent uploads can't overshoot the quota together.
+func (s *UploadService) checkQuota(ctx context.Context, userID int32, size int64) error {
+	if !s.hasQuota() {
+		return nil
+	}
+
+	usage, err := s.queries.SumUploadSizesByUserID(ctx, userID)
+	if err != nil {
+		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to check upload quota", err)
+	}
+
+	if s.config.MaxFilesPerUser > 0 && usage.FileCount+1 > s.config.MaxFilesPerUser {
+		return quotaExceededError("files", usage.FileCount, s.config.MaxFilesPerUser)
+	}
+	if s.config.MaxTotalBytesPerUser > 0 && usage.TotalBytes+size > s.config.MaxTotalBytesPerUser {
+		return quotaExceededError("bytes", usage.TotalBytes, s.config.MaxTotalBytesPerUser)
+	}
+
+	return nil
+}
+
+func quotaExceededError(quota string, used, limit int64) error {
+	return errs.NewDomainError(
+		errs.ErrKeyUploadQuotaExceeded,
+		"Upload quota exceeded",
+		http.StatusRequestEntityTooLarge,
+	).WithDetails(map[string]interface{}{
+		"quota": quota,
+		"used":  used,
+		"limit": limit,
+	})
+}
+
+// writeFile copies src to path, removing the partial file if anything fails.
+// Close errors are checked too, since a full disk may only surface when buffered data is flushed.
+func (s *UploadService) writeFile(path string, src io.Reader) error {
+	createFile := s.config.CreateFile
+	if createFile == nil {
+		createFile = func(path string) (io.WriteCloser, error) {
+			return os.Create(path)
+		}
+	}
+
+	dst, err := createFile(path)
+	if err != nil {
+		return uploadWriteError("failed to create destination file", err)
+	}
+
+	_, err = io.Copy(dst, src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+		return uploadWriteError("failed to write file", err)
+	}
+
+	return nil
+}
+
+// uploadWriteError reports a full disk as uploads.storage_full and anything else as an internal error
+func uploadWriteError(message string, err error) error {
+	if errors.Is(err, syscall.ENOSPC) {
+		return errs.WrapDomainError(
+			errs.ErrKeyUploadStorageFull,
+			"Not enough storage space to save the file",
+			http.StatusInsufficientStorage,
+			err,
+		)
+	}
+	return errs.WrapInternal(errs.ErrKeyInternalError, message, err)
+}
+
 // GetUpload retrieves an upload by ID and user ID.
 // Returns ErrUploadNotFound if the upload doesn't exist or doesn't belong to the user.
 // This method can be used internally by other services to retrieve upload information.
@@ -195,6 +589,51 @@ func (s *UploadService) GetUpload(ctx context.Context, uploadID, userID int32) (
 	return &upload, nil
 }
 
+// GetUploadOptions change how GetUploadWithOptions looks an upload up
+type GetUploadOptions struct {
+	// VerifyExists checks the file is still on disk, not just its row
+	VerifyExists bool
+}
+
+// GetUploadWithOptions retrieves an upload like GetUpload. With VerifyExists, an upload whose
+// file was removed from disk behind the service's back (e.g. by an external cleanup) is
+// flagged with MarkUploadMissing and ErrUploadFileGone is returned instead.
+func (s *UploadService) GetUploadWithOptions(ctx context.Context, uploadID, userID int32, opts GetUploadOptions) (*db.Upload, error) {
+	upload, err := s.GetUpload(ctx, uploadID, userID)
+	if err != nil || !opts.VerifyExists {
+		return upload, err
+	}
+
+	_, err = os.Stat(filepath.Join(s.config.UploadFolder, upload.RelativePath))
+	if err == nil {
+		return upload, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to check upload file", err)
+	}
+	if _, err := s.MarkUploadMissing(ctx, uploadID, userID); err != nil {
+		return nil, err
+	}
+	return nil, ErrUploadFileGone
+}
+
+// MarkUploadMissing records that an upload's file is gone from disk by setting missing_at.
+// An upload already flagged keeps the time it was first found missing.
+// Returns ErrUploadNotFound if the upload doesn't exist or doesn't belong to the user.
+func (s *UploadService) MarkUploadMissing(ctx context.Context, uploadID, userID int32) (*db.Upload, error) {
+	upload, err := s.queries.MarkUploadMissing(ctx, db.MarkUploadMissingParams{
+		ID:     uploadID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to mark upload missing", err)
+	}
+	return &upload, nil
+}
+
 // ListUploads lists all uploads for a user.
 // Returns an empty slice if the user has no uploads.
 // This method can be used internally by other services to retrieve all uploads for a user.
@@ -206,11 +645,36 @@ func (s *UploadService) ListUploads(ctx context.Context, userID int32) ([]db.Upl
 	return uploads, nil
 }
 
+// RenameUpload changes the original filename shown for an upload; the stored file keeps its name.
+// Returns ErrInvalidFilename for blank names or names containing path separators,
+// and ErrUploadNotFound if the upload doesn't exist or doesn't belong to the user.
+// Control characters are stripped as in SanitizeFilename.
+func (s *UploadService) RenameUpload(ctx context.Context, uploadID, userID int32, filename string) (*db.Upload, error) {
+	filename = strings.TrimSpace(filename)
+	if filename == "" || filename == "." || filename == ".." || strings.ContainsAny(filename, `/\`) {
+		return nil, ErrInvalidFilename
+	}
+	filename = SanitizeFilename(filename)
+
+	upload, err := s.queries.UpdateUploadFilename(ctx, db.UpdateUploadFilenameParams{
+		ID:               uploadID,
+		UserID:           userID,
+		OriginalFilename: filename,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to rename upload", err)
+	}
+	return &upload, nil
+}
+
 // DeleteUpload deletes an upload by ID and user ID.
 // This method:
 //   - Verifies the upload exists and belongs to the user
 //   - Deletes the record from the database
-//   - Removes the file from disk
+//   - Removes the file from disk, unless other uploads still share it (see Deduplicate)
 //
 // Returns ErrUploadNotFound if the upload doesn't exist or doesn't belong to the user.
 // This method can be used internally by other services to delete uploads.
@@ -220,13 +684,29 @@ func (s *UploadService) DeleteUpload(ctx context.Context, uploadID, userID int32
 		return err
 	}
 
-	err = s.queries.DeleteUpload(ctx, db.DeleteUploadParams{
-		ID:     uploadID,
-		UserID: userID,
+	// Delete and count in one transaction so the count runs on the primary and sees the delete
+	var references int64
+	err = db.WithTx(ctx, s.queries.TxBeginner(), func(q *db.Queries) error {
+		// Deduplicated uploads take the same lock, so none can start sharing the file before it goes
+		if err := q.LockUserUploads(ctx, userID); err != nil {
+			return err
+		}
+		err := q.DeleteUpload(ctx, db.DeleteUploadParams{
+			ID:     uploadID,
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		references, err = q.CountUploadsByPath(ctx, upload.RelativePath)
+		return err
 	})
 	if err != nil {
 		return errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete upload", err)
 	}
+	if references > 0 {
+		return nil
+	}
 
 	filePath := filepath.Join(s.config.UploadFolder, upload.RelativePath)
 	if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
@@ -236,6 +716,137 @@ func (s *UploadService) DeleteUpload(ctx context.Context, uploadID, userID int32
 	return nil
 }
 
+// DeleteUserUploads deletes every upload row of a user and returns the relative paths of
+// their files. The files are left on disk: run it inside a transaction (see WithTx) and
+// remove them once it commits, so a rollback never leaves rows pointing at missing files.
+// It holds LockUserUploads until then, so no deduplicated upload starts sharing those files.
+func (s *UploadService) DeleteUserUploads(ctx context.Context, userID int32) ([]string, error) {
+	if err := s.queries.LockUserUploads(ctx, userID); err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to lock user uploads", err)
+	}
+	paths, err := s.queries.DeleteUploadsByUserID(ctx, userID)
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete uploads", err)
+	}
+	return paths, nil
+}
+
+// RemoveFiles removes the files at paths, as returned by DeleteUserUploads, once the rows
+// are committed. Each file is removed once even when deduplicated uploads shared it, and
+// files already gone count as removed. The failures are returned joined, each naming its path.
+// A service without an UploadFolder removes nothing.
+func (s *UploadService) RemoveFiles(paths []string) error {
+	if s.config.UploadFolder == "" {
+		return nil
+	}
+
+	var failures []error
+	removed := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		if removed[path] {
+			continue
+		}
+		removed[path] = true
+
+		if err := os.Remove(filepath.Join(s.config.UploadFolder, path)); err != nil && !os.IsNotExist(err) {
+			failures = append(failures, fmt.Errorf("remove %s: %w", path, err))
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// BulkDeleteItem is the outcome of deleting one ID of a bulk delete, Err is nil when it was deleted
+type BulkDeleteItem struct {
+	ID  int32
+	Err error
+}
+
+// DeleteUploads deletes the user's uploads with the given IDs and reports the outcome of each
+// ID, in request order with duplicates dropped. Rows are deleted in one transaction, then the
+// files no other upload shares are removed from disk. IDs the user doesn't own fail with
+// ErrUploadNotFound without affecting the rest, and files already missing from disk count
+// as deleted. More than MaxDeletesPerRequest IDs are rejected up front.
+func (s *UploadService) DeleteUploads(ctx context.Context, uploadIDs []int32, userID int32) ([]BulkDeleteItem, error) {
+	if s.config.MaxDeletesPerRequest > 0 && len(uploadIDs) > s.config.MaxDeletesPerRequest {
+		return nil, errs.NewBadRequestError(
+			errs.ErrKeyUploadTooManyFiles,
+			"Too many uploads in one request",
+		).WithDetails(map[string]interface{}{"max_files": s.config.MaxDeletesPerRequest})
+	}
+
+	ids := make([]int32, 0, len(uploadIDs))
+	seen := make(map[int32]bool, len(uploadIDs))
+	for _, id := range uploadIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	// Delete and count in one transaction so the counts run on the primary and see the deletes
+	deleted := make(map[int32]string, len(ids))
+	references := make(map[string]int64)
+	err := db.WithTx(ctx, s.queries.TxBeginner(), func(q *db.Queries) error {
+		// Deduplicated uploads take the same lock, so none can start sharing a file before it goes
+		if err := q.LockUserUploads(ctx, userID); err != nil {
+			return err
+		}
+		rows, err := q.DeleteUploadsByIDs(ctx, db.DeleteUploadsByIDsParams{UserID: userID, Ids: ids})
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			deleted[row.ID] = row.RelativePath
+			if _, counted := references[row.RelativePath]; counted {
+				continue
+			}
+			if references[row.RelativePath], err = q.CountUploadsByPath(ctx, row.RelativePath); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete uploads", err)
+	}
+
+	// Remove each unshared file once, remembering failures for every upload that pointed at it
+	removeErrs := make(map[string]error)
+	for path, count := range references {
+		if count > 0 {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.config.UploadFolder, path)); err != nil && !os.IsNotExist(err) {
+			removeErrs[path] = errs.WrapInternal(errs.ErrKeyInternalError, "failed to delete file from disk", err)
+		}
+	}
+
+	items := make([]BulkDeleteItem, len(ids))
+	for i, id := range ids {
+		items[i] = BulkDeleteItem{ID: id}
+		path, ok := deleted[id]
+		if !ok {
+			items[i].Err = ErrUploadNotFound
+			continue
+		}
+		items[i].Err = removeErrs[path]
+	}
+	return items, nil
+}
+
+// OpenUpload opens the stored file of an upload for reading
+// Returns ErrUploadFileGone if the file is no longer on disk
+func (s *UploadService) OpenUpload(upload *db.Upload) (*os.File, error) {
+	file, err := os.Open(filepath.Join(s.config.UploadFolder, upload.RelativePath))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrUploadFileGone
+		}
+		return nil, errs.WrapInternal(errs.ErrKeyInternalError, "failed to open upload", err)
+	}
+	return file, nil
+}
+
 // GetFullURL returns the full URL for an upload
 func (s *UploadService) GetFullURL(relativePath string) string {
 	if relativePath == "" {
@@ -244,9 +855,50 @@ func (s *UploadService) GetFullURL(relativePath string) string {
 	return fmt.Sprintf("%s/%s", s.config.BaseURL, relativePath)
 }
 
+// GetSignedURL returns a temporary link to an upload's file, valid for SignedURLTTL,
+// or "" when no Storage is configured
+func (s *UploadService) GetSignedURL(relativePath string) (string, error) {
+	if s.config.Storage == nil || relativePath == "" {
+		return "", nil
+	}
+	signedURL, err := s.config.Storage.GetSignedURL(relativePath, s.config.SignedURLTTL)
+	if err != nil {
+		return "", errs.WrapInternal(errs.ErrKeyInternalError, "failed to sign upload URL", err)
+	}
+	return signedURL, nil
+}
+
+// OpenFile opens a stored file by its relative path, for links signed by LocalStorage.
+// Returns ErrUploadNotFound if the file isn't on disk or the path leaves the upload folder.
+func (s *UploadService) OpenFile(relativePath string) (*os.File, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(relativePath))
+	if cleaned == "." || filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return nil, ErrUploadNotFound
+	}
+	file, err := s.OpenUpload(&db.Upload{RelativePath: cleaned})
+	if errors.Is(err, ErrUploadFileGone) {
+		// A signed path names no upload row, so there is nothing to be gone from
+		return nil, ErrUploadNotFound
+	}
+	return file, err
+}
+
 var (
 	ErrUploadNotFound = errs.NewNotFoundError(
 		errs.ErrKeyUploadNotFound,
 		"Upload not found",
 	)
+	ErrFolderNotFound = errs.NewNotFoundError(
+		errs.ErrKeyUploadFolderNotFound,
+		"Folder not found",
+	)
+	ErrUploadFileGone = errs.NewDomainError(
+		errs.ErrKeyUploadFileGone,
+		"The file of this upload is no longer available",
+		http.StatusGone,
+	)
+	ErrInvalidFilename = errs.NewBadRequestError(
+		errs.ErrKeyUploadInvalidFilename,
+		"File name must not be blank or contain path separators",
+	)
 )
@@ -0,0 +1,201 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"app/internal/logger"
+)
+
+const (
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the body keyed with the shared secret
+	SignatureHeader = "X-Webhook-Signature"
+	// EventHeader carries the event name, so receivers can route without parsing the body
+	EventHeader = "X-Webhook-Event"
+
+	defaultMaxAttempts    = 5
+	defaultInitialBackoff = time.Second
+	defaultTimeout        = 10 * time.Second
+)
+
+// Config configures where events are delivered and how hard to try
+type Config struct {
+	// URL receives every event as a POST, empty disables webhooks
+	URL string
+	// Secret signs each body, receivers verify it with Verify
+	Secret string
+	// MaxAttempts bounds deliveries per event, defaults to 5
+	MaxAttempts int
+	// InitialBackoff is the wait after the first failure, doubled after each one after that. Defaults to 1s
+	InitialBackoff time.Duration
+	// Timeout bounds each attempt, defaults to 10s
+	Timeout time.Duration
+}
+
+// Event is the JSON body POSTed to the webhook URL
+type Event struct {
+	Event      string    `json:"event"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// Dispatcher delivers events to the configured URL. A Dispatcher without a URL does nothing.
+type Dispatcher struct {
+	config Config
+	client *http.Client
+	logger *logger.Logger
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher, filling in defaults for unset limits
+func NewDispatcher(config Config, log *logger.Logger) *Dispatcher {
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Dispatcher{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout, Transport: logger.NewRequestIDTransport(nil)},
+		logger: log,
+	}
+}
+
+// Enabled reports whether a webhook URL is configured
+func (d *Dispatcher) Enabled() bool {
+	return d != nil && d.config.URL != ""
+}
+
+// Dispatch delivers the event in the background and returns immediately. The delivery
+// outlives ctx (a finished request) but keeps its values, so failures are logged with
+// the request_id of the request that triggered them.
+func (d *Dispatcher) Dispatch(ctx context.Context, event string, data any) {
+	if !d.Enabled() {
+		return
+	}
+
+	payload := Event{Event: event, OccurredAt: time.Now().UTC(), Data: data}
+	ctx = context.WithoutCancel(ctx)
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		if err := d.Send(ctx, payload); err != nil {
+			d.logger.ErrorContext(ctx, "Failed to deliver webhook", "error", err, "event", event)
+		}
+	}()
+}
+
+// Wait blocks until every delivery started by Dispatch has finished
+func (d *Dispatcher) Wait() {
+	if d != nil {
+		d.wg.Wait()
+	}
+}
+
+// WaitContext is Wait bounded by ctx, e.g. the time left for a graceful shutdown.
+// It returns ctx's error if deliveries were still running when ctx was done.
+func (d *Dispatcher) WaitContext(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		d.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Send delivers the event, retrying network errors, 429 and 5xx responses with
+// exponential backoff. Other 4xx responses are not retried.
+func (d *Dispatcher) Send(ctx context.Context, event Event) error {
+	if !d.Enabled() {
+		return nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook event: %w", err)
+	}
+
+	backoff := d.config.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := d.post(ctx, event.Event, body)
+		if err == nil {
+			return nil
+		}
+		if !retry || attempt >= d.config.MaxAttempts {
+			return fmt.Errorf("webhook %s failed after %d attempts: %w", event.Event, attempt, err)
+		}
+
+		d.logger.WarnContext(ctx, "Webhook delivery failed, retrying", "error", err, "event", event.Event, "attempt", attempt, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one delivery attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) post(ctx context.Context, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(SignatureHeader, Sign(d.config.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// Sign returns the SignatureHeader value for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrInvalidSignature is returned by Verify when the signature doesn't match the body
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// Verify checks a SignatureHeader value against body in constant time
+func Verify(secret string, body []byte, signature string) error {
+	if !hmac.Equal([]byte(Sign(secret, body)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
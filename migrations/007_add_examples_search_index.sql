@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_examples_search ON examples
+    USING GIN (to_tsvector('english', title || ' ' || coalesce(description, '')));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_examples_search;
+-- +goose StatementEnd
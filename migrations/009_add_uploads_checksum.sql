@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE uploads ADD COLUMN checksum VARCHAR(64);
+
+CREATE INDEX idx_uploads_user_id_checksum ON uploads(user_id, checksum) WHERE checksum IS NOT NULL;
+CREATE INDEX idx_uploads_relative_path ON uploads(relative_path);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_uploads_relative_path;
+DROP INDEX IF EXISTS idx_uploads_user_id_checksum;
+ALTER TABLE uploads DROP COLUMN IF EXISTS checksum;
+-- +goose StatementEnd
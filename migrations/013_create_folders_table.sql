@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE folders (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    is_root BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_folders_user_id ON folders(user_id);
+CREATE UNIQUE INDEX idx_folders_user_id_root ON folders(user_id) WHERE is_root;
+
+-- Uploads used to carry the user ID as folder_id: move them into a root folder per user
+INSERT INTO folders (user_id, name, is_root)
+SELECT DISTINCT user_id, 'root', TRUE FROM uploads;
+
+UPDATE uploads SET folder_id = folders.id
+FROM folders
+WHERE folders.user_id = uploads.user_id AND folders.is_root;
+
+ALTER TABLE uploads
+    ADD CONSTRAINT fk_uploads_folder_id FOREIGN KEY (folder_id) REFERENCES folders(id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE uploads DROP CONSTRAINT IF EXISTS fk_uploads_folder_id;
+
+UPDATE uploads SET folder_id = user_id;
+
+DROP INDEX IF EXISTS idx_folders_user_id_root;
+DROP INDEX IF EXISTS idx_folders_user_id;
+DROP TABLE IF EXISTS folders;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+package helpers
+
+import (
+	"testing"
+	"time"
+
+	"app/internal/middleware"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// TestJWTSecret is the JWT secret CreateTestServer configures the auth service with
+const TestJWTSecret = "test-secret-key"
+
+// SignTestToken signs an access token for userID with the given secret and roles
+func SignTestToken(t *testing.T, secret string, userID int32, roles ...string) string {
+	return SignTestTokenWithKID(t, "", secret, userID, roles...)
+}
+
+// SignTestTokenWithKID signs like SignTestToken and names the key in the kid header, omitted when kid is empty
+func SignTestTokenWithKID(t *testing.T, kid, secret string, userID int32, roles ...string) string {
+	claims := &middleware.Claims{
+		UserID: userID,
+		Email:  "test@example.com",
+		Roles:  roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString([]byte(secret))
+	require.NoError(t, err, "Failed to sign test token")
+	return signed
+}
@@ -1,8 +1,10 @@
 package helpers
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -137,13 +139,35 @@ func GetUserByEmail(t *testing.T, ctx context.Context, tx pgx.Tx, email string)
 	return &user
 }
 
-// CreateTestUpload creates a test upload and returns it
+// GetTestRootFolder returns the user's root folder, creating it if needed
+func GetTestRootFolder(t *testing.T, ctx context.Context, tx pgx.Tx, userID int32) *db.Folder {
+	var folder db.Folder
+	row := tx.QueryRow(ctx,
+		"INSERT INTO folders (user_id, name, is_root) VALUES ($1, 'root', TRUE) ON CONFLICT (user_id) WHERE is_root DO UPDATE SET user_id = EXCLUDED.user_id RETURNING id, user_id, name, is_root, created_at, updated_at",
+		userID)
+	err := row.Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.IsRoot, &folder.CreatedAt, &folder.UpdatedAt)
+	require.NoError(t, err, "Failed to get root folder")
+	return &folder
+}
+
+// CreateTestFolder creates a folder with the given name for the user
+func CreateTestFolder(t *testing.T, ctx context.Context, tx pgx.Tx, userID int32, name string) *db.Folder {
+	var folder db.Folder
+	row := tx.QueryRow(ctx,
+		"INSERT INTO folders (user_id, name) VALUES ($1, $2) RETURNING id, user_id, name, is_root, created_at, updated_at",
+		userID, name)
+	err := row.Scan(&folder.ID, &folder.UserID, &folder.Name, &folder.IsRoot, &folder.CreatedAt, &folder.UpdatedAt)
+	require.NoError(t, err, "Failed to create test folder")
+	return &folder
+}
+
+// CreateTestUpload creates a test upload in the user's root folder and returns it
 func CreateTestUpload(t *testing.T, ctx context.Context, tx pgx.Tx, userID int32) *db.Upload {
 	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
 
 	upload := &db.Upload{
 		UserID:           userID,
-		FolderID:         userID,
+		FolderID:         GetTestRootFolder(t, ctx, tx, userID).ID,
 		Type:             "image",
 		RelativePath:     fmt.Sprintf("%d/test_%d.jpg", userID, time.Now().UnixNano()),
 		OriginalFilename: "test.jpg",
@@ -164,6 +188,20 @@ func CreateTestUpload(t *testing.T, ctx context.Context, tx pgx.Tx, userID int32
 	return upload
 }
 
+// CreateTestCity creates a test city with the given name
+func CreateTestCity(t *testing.T, ctx context.Context, tx pgx.Tx, name string) *db.City {
+	city := &db.City{}
+
+	row := tx.QueryRow(ctx,
+		"INSERT INTO cities (name) VALUES ($1) RETURNING id, name, created_at, updated_at",
+		name)
+
+	err := row.Scan(&city.ID, &city.Name, &city.CreatedAt, &city.UpdatedAt)
+	require.NoError(t, err, "Failed to create test city")
+
+	return city
+}
+
 // GetTestLogger creates a test logger
 func GetTestLogger(t *testing.T) *logger.Logger {
 	testLogger, err := logger.New(logger.Config{
@@ -174,3 +212,11 @@ func GetTestLogger(t *testing.T) *logger.Logger {
 	require.NoError(t, err, "Failed to create test logger")
 	return testLogger
 }
+
+// NewBufferLogger creates a debug-level text logger writing into the returned buffer,
+// so tests can assert on what was (or wasn't) logged
+func NewBufferLogger() (*logger.Logger, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	handler := slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	return logger.NewWithHandler(handler), buf
+}
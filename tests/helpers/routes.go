@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// inspectRouteHeader marks a request as a route introspection probe
+const inspectRouteHeader = "X-Inspect-Route"
+
+var funcSuffix = regexp.MustCompile(`\.func\d+$`)
+
+// RouteInfo describes a registered route and its full handler chain
+type RouteInfo struct {
+	Method     string
+	Path       string
+	Handler    string
+	Middleware []string
+}
+
+// Uses reports whether the route's middleware chain contains the given middleware
+// Name can be a full or partial function name, e.g. "middleware.UserAuthMiddleware"
+func (r RouteInfo) Uses(name string) bool {
+	for _, m := range r.Middleware {
+		if strings.HasSuffix(m, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectRouteMiddleware answers introspection probes with the matched route's handler chain
+// It must be the first middleware on the router so it runs before auth and handlers
+func inspectRouteMiddleware(c *gin.Context) {
+	if c.GetHeader(inspectRouteHeader) == "" {
+		c.Next()
+		return
+	}
+
+	c.AbortWithStatusJSON(http.StatusOK, c.HandlerNames())
+}
+
+// Routes returns all routes registered on the test server with their middleware chain names
+func (ts *TestServer) Routes() []RouteInfo {
+	var routes []RouteInfo
+
+	for _, route := range ts.router.Routes() {
+		req := httptest.NewRequest(route.Method, probePath(route.Path), nil)
+		req.Header.Set(inspectRouteHeader, "1")
+		w := httptest.NewRecorder()
+		ts.router.ServeHTTP(w, req)
+
+		var names []string
+		_ = json.Unmarshal(w.Body.Bytes(), &names)
+
+		// Drop the probe middleware itself and the final handler
+		var middleware []string
+		for i, name := range names {
+			if i == 0 || i == len(names)-1 {
+				continue
+			}
+			middleware = append(middleware, funcSuffix.ReplaceAllString(name, ""))
+		}
+
+		routes = append(routes, RouteInfo{
+			Method:     route.Method,
+			Path:       route.Path,
+			Handler:    route.Handler,
+			Middleware: middleware,
+		})
+	}
+
+	return routes
+}
+
+// FindRoute returns the route registered for method and path
+func (ts *TestServer) FindRoute(method, path string) (RouteInfo, bool) {
+	for _, route := range ts.Routes() {
+		if route.Method == method && route.Path == path {
+			return route, true
+		}
+	}
+	return RouteInfo{}, false
+}
+
+// probePath fills path parameters with placeholder values so the route can be matched
+func probePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "0"
+		} else if strings.HasPrefix(segment, "*") {
+			segments[i] = "probe"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -9,14 +9,23 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"app/config"
 	"app/internal"
+	"app/internal/apikeys"
 	"app/internal/auth"
+	"app/internal/cache"
+	"app/internal/cities"
 	"app/internal/db"
+	"app/internal/debug"
 	"app/internal/example"
+	"app/internal/folders"
 	"app/internal/logger"
+	"app/internal/middleware"
+	"app/internal/scheduler"
 	"app/internal/uploads"
+	"app/internal/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5"
@@ -24,8 +33,9 @@ import (
 
 // TestServer wraps httptest.Server with helper methods
 type TestServer struct {
-	server *httptest.Server
-	router *gin.Engine
+	server       *httptest.Server
+	router       *gin.Engine
+	uploadFolder string
 }
 
 // TestResponse represents an HTTP response for testing
@@ -37,11 +47,20 @@ type TestResponse struct {
 
 // CreateTestServer creates a test server with transaction-scoped database queries
 func CreateTestServer(t *testing.T, ctx context.Context, tx pgx.Tx, queries *db.Queries) *TestServer {
+	return CreateTestServerWithConfig(t, ctx, tx, queries, nil)
+}
+
+// CreateTestServerWithConfig creates a test server like CreateTestServer, with configure
+// applied to the test config before any route is registered
+func CreateTestServerWithConfig(t *testing.T, ctx context.Context, tx pgx.Tx, queries *db.Queries, configure func(cfg *config.Config)) *TestServer {
 	// Set Gin to test mode
 	gin.SetMode(gin.TestMode)
 
 	// Create router
 	router := gin.New()
+	router.RedirectTrailingSlash = false
+	router.HandleMethodNotAllowed = true
+	router.Use(inspectRouteMiddleware)
 
 	// Logger
 	testLogger, err := logger.New(logger.Config{
@@ -55,39 +74,106 @@ func CreateTestServer(t *testing.T, ctx context.Context, tx pgx.Tx, queries *db.
 
 	// Create test config
 	testConfig := &config.Config{
-		UploadFolder: t.TempDir(),
-		FilesBaseURL: "http://localhost:8181/api/files",
+		JWTSecret:       TestJWTSecret,
+		SignedURLSecret: TestJWTSecret,
+		UploadFolder:    t.TempDir(),
+		FilesBaseURL:    "http://localhost:8181/api/files",
+		MaxBodySize:     1 << 20,
+		APIBasePath:     config.DefaultAPIBasePath,
+
+		UploadSignedURLTTL: 15 * time.Minute,
+	}
+	if configure != nil {
+		configure(testConfig)
+	}
+	if err := router.SetTrustedProxies(testConfig.TrustedProxies); err != nil {
+		t.Fatalf("Failed to set trusted proxies: %v", err)
 	}
 
+	router.Use(middleware.SecurityHeaders(middleware.SecurityHeadersConfig{
+		HSTS: testConfig.IsProduction(),
+	}))
+	router.Use(middleware.MaxBodySize(testConfig.MaxBodySize))
+	router.Use(middleware.Timeout(testConfig.RequestTimeout))
+
+	// Webhook dispatcher, as in cmd/api
+	webhooks := webhook.NewDispatcher(webhook.Config{
+		URL:    testConfig.WebhookURL,
+		Secret: testConfig.WebhookSecret,
+	}, testLogger)
+
 	// Create minimal app structure for testing
+	cacheStats := cache.NewStatsCache(cache.NewMemoryCache())
 	app := &internal.App{
-		Config:  testConfig,
-		Queries: queries,
-		Logger:  testLogger,
-		Api:     router.Group("/api/v1"),
+		Config:   testConfig,
+		Queries:  queries,
+		Cache:    cacheStats,
+		Logger:   testLogger,
+		Api:      router.Group(testConfig.APIBasePath),
+		Webhooks: webhooks,
 	}
+	app.Api.Use(middleware.APIVersion(middleware.SupportedAPIVersions...))
+
+	// Upload service, shared by the upload routes and account deletion
+	uploadService := uploads.NewService(app)
 
 	// Register auth routes
-	jwtSecret := []byte("test-secret-key")
-	authService := auth.NewAuthService(queries, jwtSecret, testLogger)
+	authService, err := auth.NewAuthServiceFromConfig(testConfig, queries, app.Cache, uploadService, testLogger)
+	if err != nil {
+		t.Fatalf("Failed to create auth service: %v", err)
+	}
 	authHandler := auth.NewAuthHandler(authService, testLogger)
 	auth.RegisterRoutes(app.Api, authHandler, authService)
 
+	// Register API key routes, keys also authenticate examples and uploads
+	apiKeyService := apikeys.NewAPIKeyService(app.Queries)
+	apikeys.RegisterRoutes(app, authService, apiKeyService)
+
 	// Register example routes
-	example.RegisterRoutes(app, authService)
+	example.RegisterRoutes(app, authService, apiKeyService)
+
+	// Register folder routes
+	folders.RegisterRoutes(app, authService)
 
 	// Register uploads routes
-	uploads.RegisterRoutes(app, authService)
+	uploads.RegisterRoutes(app, authService, apiKeyService, uploadService)
+
+	// Register scheduler routes
+	cronScheduler := scheduler.NewScheduler(&scheduler.Dependencies{
+		Config:  testConfig,
+		Queries: queries,
+		Logger:  testLogger,
+	})
+	if err := cronScheduler.RegisterJobs(); err != nil {
+		t.Fatalf("Failed to register scheduler jobs: %v", err)
+	}
+	scheduler.RegisterRoutes(app, authService, cronScheduler)
+
+	// Register cities routes
+	cities.RegisterRoutes(app, authService)
+
+	// Register debug routes
+	debug.RegisterRoutes(app, authService, cacheStats)
+
+	// JSON 404/405 handlers, as in cmd/api
+	router.NoRoute(middleware.NoRoute())
+	router.NoMethod(middleware.NoMethod())
 
 	// Create test server
-	server := httptest.NewServer(router)
+	server := httptest.NewServer(middleware.TrimTrailingSlash(router))
 
 	return &TestServer{
-		server: server,
-		router: router,
+		server:       server,
+		router:       router,
+		uploadFolder: testConfig.UploadFolder,
 	}
 }
 
+// UploadFolder returns the temporary directory uploads are stored in
+func (ts *TestServer) UploadFolder() string {
+	return ts.uploadFolder
+}
+
 // Close closes the test server
 func (ts *TestServer) Close() {
 	ts.server.Close()
@@ -189,7 +275,8 @@ func (ts *TestServer) NewRequest(method, path string, body io.Reader) *http.Requ
 
 // Do executes an HTTP request and returns the response
 func (ts *TestServer) Do(req *http.Request) *TestResponse {
-	if req.Body != nil {
+	// Default to JSON, but keep an explicit type such as multipart/form-data
+	if req.Body != nil && req.Header.Get("Content-Type") == "" {
 		req.Header.Set("Content-Type", "application/json")
 	}
 
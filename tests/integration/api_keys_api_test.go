@@ -0,0 +1,167 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"app/internal/apikeys"
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createAPIKey(t *testing.T, server *helpers.TestServer, token, name string) apikeys.CreatedAPIKeyResponse {
+	req := server.NewRequest("POST", "/api/v1/me/api-keys", helpers.StringToReadCloser(`{"name":"`+name+`"}`))
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp := server.Do(req)
+	require.Equal(t, http.StatusCreated, resp.StatusCode, resp.String())
+
+	var response apikeys.CreatedAPIKeyDataResponse
+	require.NoError(t, resp.JSON(&response))
+	return response.Data
+}
+
+func listAPIKeysWithKey(server *helpers.TestServer, key string) *helpers.TestResponse {
+	req := server.NewRequest("GET", "/api/v1/me/api-keys", nil)
+	req.Header.Set(middleware.APIKeyHeader, key)
+	return server.Do(req)
+}
+
+func TestAPIKeysAPI_CreateAndAuthenticate(t *testing.T) {
+	t.Run("should authenticate with a newly created key", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			created := createAPIKey(t, server, helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID), "ci")
+
+			assert.True(t, strings.HasPrefix(created.Key, apikeys.KeyPrefix))
+			assert.True(t, strings.HasPrefix(created.Key, created.Prefix))
+			assert.Equal(t, "ci", created.Name)
+
+			// The key acts as its owner on resources, not just on key management
+			example := helpers.CreateTestExample(t, ctx, tx, user.ID)
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			for _, path := range []string{
+				"/api/v1/examples/" + strconv.Itoa(int(example.ID)),
+				"/api/v1/uploads/" + strconv.Itoa(int(upload.ID)),
+			} {
+				req := server.NewRequest("GET", path, nil)
+				req.Header.Set(middleware.APIKeyHeader, created.Key)
+				resp := server.Do(req)
+				assert.Equal(t, http.StatusOK, resp.StatusCode, "%s: %s", path, resp.String())
+			}
+		})
+	})
+
+	t.Run("should not create or revoke keys with a key", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			created := createAPIKey(t, server, getAuthToken(t, server), "leaked")
+
+			req := server.NewRequest("POST", "/api/v1/me/api-keys", helpers.StringToReadCloser(`{"name":"backdoor"}`))
+			req.Header.Set(middleware.APIKeyHeader, created.Key)
+			assert.Equal(t, http.StatusUnauthorized, server.Do(req).StatusCode)
+
+			req = server.NewRequest("DELETE", "/api/v1/me/api-keys/"+strconv.Itoa(int(created.ID)), nil)
+			req.Header.Set(middleware.APIKeyHeader, created.Key)
+			assert.Equal(t, http.StatusUnauthorized, server.Do(req).StatusCode)
+		})
+	})
+
+	t.Run("should reject an unknown key", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			resp := listAPIKeysWithKey(server, apikeys.KeyPrefix+strings.Repeat("0", 64))
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			var response errs.ErrorResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.Equal(t, errs.ErrKeyAPIKeyInvalid, response.ErrorKey)
+		})
+	})
+}
+
+func TestAPIKeysAPI_ListAPIKeys(t *testing.T) {
+	t.Run("should only show key prefixes", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			created := createAPIKey(t, server, token, "deploy")
+
+			req := server.NewRequest("GET", "/api/v1/me/api-keys", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			assert.NotContains(t, resp.String(), created.Key)
+			assert.NotContains(t, resp.String(), apikeys.HashAPIKey(created.Key))
+
+			var raw struct {
+				Data []map[string]json.RawMessage `json:"data"`
+			}
+			require.NoError(t, resp.JSON(&raw))
+			require.Len(t, raw.Data, 1)
+			assert.NotContains(t, raw.Data[0], "key")
+			assert.NotContains(t, raw.Data[0], "key_hash")
+
+			var response apikeys.APIKeysListResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.Equal(t, created.Prefix, response.Data[0].Prefix)
+		})
+	})
+}
+
+func TestAPIKeysAPI_RevokeAPIKey(t *testing.T) {
+	t.Run("should block a revoked key", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			created := createAPIKey(t, server, token, "old")
+			require.Equal(t, http.StatusOK, listAPIKeysWithKey(server, created.Key).StatusCode)
+
+			req := server.NewRequest("DELETE", "/api/v1/me/api-keys/"+strconv.Itoa(int(created.ID)), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			assert.Equal(t, http.StatusUnauthorized, listAPIKeysWithKey(server, created.Key).StatusCode)
+		})
+	})
+
+	t.Run("should return 404 for another user's key", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			other := helpers.CreateTestUser(t, ctx, tx)
+			otherKey := createAPIKey(t, server, helpers.SignTestToken(t, helpers.TestJWTSecret, other.ID), "other")
+
+			token := getAuthToken(t, server)
+			req := server.NewRequest("DELETE", "/api/v1/me/api-keys/"+strconv.Itoa(int(otherKey.ID)), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.Equal(t, http.StatusOK, listAPIKeysWithKey(server, otherKey.Key).StatusCode)
+		})
+	})
+}
@@ -3,9 +3,17 @@ package integration
 import (
 	"app/internal/auth"
 	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/uploads"
 	"app/tests/helpers"
+	"bytes"
 	"context"
+	"fmt"
+	"mime/multipart"
 	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -66,6 +74,24 @@ func TestAuthAPI_Register(t *testing.T) {
 		})
 	})
 
+	t.Run("should return 400 when the password is weak", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			reqBody := `{
+				"email": "weak@example.com",
+				"name": "Weak Password",
+				"password": "password"
+			}`
+
+			resp := server.POST("/api/v1/auth/register", reqBody)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Contains(t, resp.String(), "validation.password.strongpassword")
+		})
+	})
+
 	t.Run("should return 400 when user already exists", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
 			// Setup: Create test server
@@ -326,3 +352,460 @@ func TestAuthAPI_GetMe(t *testing.T) {
 		})
 	})
 }
+
+func TestAuthAPI_UpdateMe(t *testing.T) {
+	updateMe := func(server *helpers.TestServer, token, body string) *helpers.TestResponse {
+		req := server.NewRequest("PUT", "/api/v1/auth/me", helpers.StringToReadCloser(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		return server.Do(req)
+	}
+
+	t.Run("should update name and email", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUserWithEmail(t, ctx, tx, "before@example.com")
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID)
+
+			// Load the user once so a cached copy exists before the update
+			req := server.NewRequest("GET", "/api/v1/auth/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			require.Equal(t, http.StatusOK, server.Do(req).StatusCode)
+
+			resp := updateMe(server, token, `{"name": "After", "email": "after@example.com"}`)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response auth.UserDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, user.ID, response.Data.ID)
+			assert.Equal(t, "after@example.com", response.Data.Email)
+			assert.Equal(t, "After", response.Data.Name)
+
+			// Assert: GET /me no longer serves the stale cached user
+			req = server.NewRequest("GET", "/api/v1/auth/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			meResp := server.Do(req)
+			require.Equal(t, http.StatusOK, meResp.StatusCode)
+
+			var me auth.UserDataResponse
+			require.NoError(t, meResp.JSON(&me))
+			assert.Equal(t, "after@example.com", me.Data.Email)
+			assert.Equal(t, "After", me.Data.Name)
+		})
+	})
+
+	t.Run("should return 400 when the email belongs to another user", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			helpers.CreateTestUserWithEmail(t, ctx, tx, "taken@example.com")
+			user := helpers.CreateTestUserWithEmail(t, ctx, tx, "mine@example.com")
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID)
+
+			resp := updateMe(server, token, `{"name": "Test User", "email": "taken@example.com"}`)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			var body errs.ErrorResponse
+			require.NoError(t, resp.JSON(&body))
+			assert.Equal(t, errs.ErrKeyAuthUserExists, body.ErrorKey)
+
+			// Assert: the user keeps their email
+			stored := helpers.GetUserByEmail(t, ctx, tx, "mine@example.com")
+			assert.Equal(t, user.ID, stored.ID)
+		})
+	})
+
+	t.Run("should return 400 for an invalid body", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 1)
+
+		for _, body := range []string{
+			`{"name": "", "email": "user@example.com"}`,
+			`{"name": "Test User", "email": "not-an-email"}`,
+		} {
+			resp := updateMe(server, token, body)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "body %s", body)
+		}
+	})
+}
+
+func TestAuthAPI_ChangePassword(t *testing.T) {
+	changePassword := func(server *helpers.TestServer, token, body string) *helpers.TestResponse {
+		req := server.NewRequest("POST", "/api/v1/auth/change-password", helpers.StringToReadCloser(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		return server.Do(req)
+	}
+
+	register := func(t *testing.T, server *helpers.TestServer) auth.RegisterDataResponse {
+		regResp := server.POST("/api/v1/auth/register", `{
+			"email": "user@example.com",
+			"name": "Test User",
+			"password": "password123"
+		}`)
+		require.Equal(t, http.StatusOK, regResp.StatusCode)
+
+		var registerResponse auth.RegisterDataResponse
+		require.NoError(t, regResp.JSON(&registerResponse))
+		return registerResponse
+	}
+
+	t.Run("should change the password and revoke refresh tokens", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			registered := register(t, server)
+
+			resp := changePassword(server, registered.Data.AccessToken, `{"current_password": "password123", "new_password": "newpassword456"}`)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			// Assert: refresh tokens issued before the change no longer work
+			refreshResp := server.POST("/api/v1/auth/refresh", `{"refresh_token": "`+registered.Data.RefreshToken+`"}`)
+			assert.Equal(t, http.StatusUnauthorized, refreshResp.StatusCode)
+
+			// Assert: only the new password logs in
+			oldLogin := server.POST("/api/v1/auth/login", `{"email": "user@example.com", "password": "password123"}`)
+			assert.Equal(t, http.StatusUnauthorized, oldLogin.StatusCode)
+
+			newLogin := server.POST("/api/v1/auth/login", `{"email": "user@example.com", "password": "newpassword456"}`)
+			assert.Equal(t, http.StatusOK, newLogin.StatusCode)
+		})
+	})
+
+	t.Run("should return 401 when the current password is wrong", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			registered := register(t, server)
+
+			resp := changePassword(server, registered.Data.AccessToken, `{"current_password": "wrong-password", "new_password": "newpassword456"}`)
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+			var body errs.ErrorResponse
+			require.NoError(t, resp.JSON(&body))
+			assert.Equal(t, errs.ErrKeyAuthInvalidCredentials, body.ErrorKey)
+
+			// Assert: nothing was changed or revoked
+			refreshResp := server.POST("/api/v1/auth/refresh", `{"refresh_token": "`+registered.Data.RefreshToken+`"}`)
+			assert.Equal(t, http.StatusOK, refreshResp.StatusCode)
+
+			login := server.POST("/api/v1/auth/login", `{"email": "user@example.com", "password": "password123"}`)
+			assert.Equal(t, http.StatusOK, login.StatusCode)
+		})
+	})
+
+	t.Run("should return 400 when the new password is too short", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 1)
+		resp := changePassword(server, token, `{"current_password": "password123", "new_password": "short"}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should return 400 when the new password is weak", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 1)
+		resp := changePassword(server, token, `{"current_password": "password123", "new_password": "password"}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.String(), "validation.new_password.strongpassword")
+	})
+}
+
+func TestAuthAPI_EmailAvailable(t *testing.T) {
+	emailAvailablePath := func(email string) string {
+		return "/api/v1/auth/email-available?email=" + url.QueryEscape(email)
+	}
+
+	t.Run("should return false for a registered email", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			helpers.CreateTestUserWithEmail(t, ctx, tx, "taken@example.com")
+
+			resp := server.GET(emailAvailablePath("taken@example.com"))
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response auth.EmailAvailableDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.False(t, response.Data.Available)
+		})
+	})
+
+	t.Run("should return true for an unused email", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			resp := server.GET(emailAvailablePath("free@example.com"))
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response auth.EmailAvailableDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.True(t, response.Data.Available)
+		})
+	})
+
+	// Malformed input is rejected before the database is queried
+	t.Run("should return 400 with a stable key for a malformed email", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		for _, email := range []string{"", "not-an-email", "user@"} {
+			resp := server.GET(emailAvailablePath(email))
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "email %q", email)
+
+			var body errs.ErrorResponse
+			require.NoError(t, resp.JSON(&body))
+			assert.Equal(t, errs.ErrKeyAuthInvalidEmail, body.ErrorKey)
+		}
+	})
+
+	t.Run("should return 429 once the client exceeds the limit", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		var resp *helpers.TestResponse
+		for i := 0; i < 11; i++ {
+			resp = server.GET(emailAvailablePath("not-an-email"))
+		}
+
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.NotEmpty(t, resp.Header.Get("Retry-After"))
+
+		var body errs.ErrorResponse
+		require.NoError(t, resp.JSON(&body))
+		assert.Equal(t, errs.ErrKeyRateLimited, body.ErrorKey)
+	})
+}
+
+func TestAuthAPI_ConfiguredJWTSecret(t *testing.T) {
+	// The scheduler run route only needs a verified admin token to reach its handler,
+	// so an unknown job name tells "token accepted" (404) apart from "token rejected" (401)
+	const path = "/api/v1/scheduler/jobs/does-not-exist/run"
+
+	t.Run("should accept a token signed with the configured secret", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("POST", path, nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin"))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, resp.String(), "scheduler.job_not_found")
+	})
+
+	t.Run("should reject a token signed with a different secret", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("POST", path, nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, "some-other-secret", 1, "admin"))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Contains(t, resp.String(), "auth.invalid_token")
+	})
+}
+
+func TestAuthAPI_DeleteMe(t *testing.T) {
+	t.Run("should delete the account with its uploads, examples and tokens", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+			helpers.CreateTestExample(t, ctx, tx, userID)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("file", "photo.jpg")
+			require.NoError(t, err)
+			_, err = part.Write([]byte("image bytes"))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			req := server.NewRequest("POST", "/api/v1/uploads", body)
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			uploadResp := server.Do(req)
+			require.Equal(t, http.StatusOK, uploadResp.StatusCode)
+			var upload uploads.UploadDataResponse
+			require.NoError(t, uploadResp.JSON(&upload))
+			filePath := filepath.Join(server.UploadFolder(), upload.Data.RelativePath)
+			require.FileExists(t, filePath)
+
+			req = server.NewRequest("DELETE", "/api/v1/auth/me", strings.NewReader(`{"password": "password123"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp := server.Do(req)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			for _, table := range []string{"users WHERE id = $1", "uploads WHERE user_id = $1", "examples WHERE user_id = $1", "refresh_tokens WHERE user_id = $1"} {
+				var count int
+				require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM "+table, userID).Scan(&count))
+				assert.Zero(t, count, table)
+			}
+			assert.NoFileExists(t, filePath)
+		})
+	})
+
+	t.Run("should keep the account when the password is wrong", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			req := server.NewRequest("DELETE", "/api/v1/auth/me", strings.NewReader(`{"password": "wrongpassword"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			var count int
+			require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE id = $1", userID).Scan(&count))
+			assert.Equal(t, 1, count)
+		})
+	})
+
+	t.Run("should require the password", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("DELETE", "/api/v1/auth/me", strings.NewReader(`{}`))
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", "application/json")
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.String(), "validation.password.required")
+	})
+}
+
+func TestAuthAPI_Sessions(t *testing.T) {
+	t.Run("should list sessions and stop a revoked one from refreshing", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			regResp := server.POST("/api/v1/auth/register", `{"email": "sessions@example.com", "name": "Test User", "password": "password123"}`)
+			require.Equal(t, http.StatusOK, regResp.StatusCode, regResp.String())
+			var first auth.RegisterDataResponse
+			require.NoError(t, regResp.JSON(&first))
+
+			loginResp := server.POST("/api/v1/auth/login", `{"email": "sessions@example.com", "password": "password123"}`)
+			require.Equal(t, http.StatusOK, loginResp.StatusCode, loginResp.String())
+			var second auth.LoginDataResponse
+			require.NoError(t, loginResp.JSON(&second))
+
+			req := server.NewRequest("GET", "/api/v1/auth/sessions", nil)
+			req.Header.Set("Authorization", "Bearer "+second.Data.AccessToken)
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			assert.NotContains(t, resp.String(), first.Data.RefreshToken)
+			var sessions auth.SessionsListResponse
+			require.NoError(t, resp.JSON(&sessions))
+			require.Len(t, sessions.Data, 2)
+			assert.NotEmpty(t, sessions.Data[0].CreatedAt)
+			assert.NotEmpty(t, sessions.Data[0].ExpiresAt)
+
+			// Newest first, so the second entry is the session created on registration
+			req = server.NewRequest("DELETE", fmt.Sprintf("/api/v1/auth/sessions/%d", sessions.Data[1].ID), nil)
+			req.Header.Set("Authorization", "Bearer "+second.Data.AccessToken)
+			resp = server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+
+			resp = server.POST("/api/v1/auth/refresh", `{"refresh_token": "`+first.Data.RefreshToken+`"}`)
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+			resp = server.POST("/api/v1/auth/refresh", `{"refresh_token": "`+second.Data.RefreshToken+`"}`)
+			assert.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+		})
+	})
+
+	t.Run("should record the user agent of the login", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			getAuthToken(t, server)
+			req := server.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(`{"email": "test@example.com", "password": "password123"}`))
+			req.Header.Set("User-Agent", "SessionsTest/2.0 (X11; Linux x86_64)")
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			var login auth.LoginDataResponse
+			require.NoError(t, resp.JSON(&login))
+
+			var userAgent, ipAddress string
+			require.NoError(t, tx.QueryRow(ctx, "SELECT user_agent, ip_address FROM refresh_tokens WHERE token = $1", login.Data.RefreshToken).Scan(&userAgent, &ipAddress))
+			assert.Equal(t, "SessionsTest/2.0 (X11; Linux x86_64)", userAgent)
+			assert.NotEmpty(t, ipAddress)
+
+			req = server.NewRequest("GET", "/api/v1/auth/sessions", nil)
+			req.Header.Set("Authorization", "Bearer "+login.Data.AccessToken)
+			resp = server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			var sessions auth.SessionsListResponse
+			require.NoError(t, resp.JSON(&sessions))
+			require.NotEmpty(t, sessions.Data)
+			assert.Equal(t, "SessionsTest/2.0 (X11; Linux x86_64)", sessions.Data[0].UserAgent)
+		})
+	})
+
+	t.Run("should return 404 for another user's session", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			getAuthToken(t, server)
+			ownerID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+			var sessionID int32
+			require.NoError(t, tx.QueryRow(ctx, "SELECT id FROM refresh_tokens WHERE user_id = $1", ownerID).Scan(&sessionID))
+
+			req := server.NewRequest("DELETE", fmt.Sprintf("/api/v1/auth/sessions/%d", sessionID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, ownerID+1))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.Contains(t, resp.String(), errs.ErrKeyAuthSessionNotFound)
+			var revoked bool
+			require.NoError(t, tx.QueryRow(ctx, "SELECT is_revoked FROM refresh_tokens WHERE id = $1", sessionID).Scan(&revoked))
+			assert.False(t, revoked)
+		})
+	})
+
+	t.Run("should reject a non-numeric session ID", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("DELETE", "/api/v1/auth/sessions/abc", nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
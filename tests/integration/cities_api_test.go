@@ -0,0 +1,282 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"app/internal/cities"
+	"app/internal/db"
+	"app/internal/errs"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedSearchCities(t *testing.T, ctx context.Context, tx pgx.Tx) {
+	for _, name := range []string{"New York", "New Orleans", "Munich"} {
+		helpers.CreateTestCity(t, ctx, tx, name)
+	}
+}
+
+func cityNames(response cities.CitiesListResponse) []string {
+	names := make([]string, len(response.Data))
+	for i, city := range response.Data {
+		names[i] = city.Name
+	}
+	return names
+}
+
+func TestCitiesAPI_ListCities(t *testing.T) {
+	t.Run("should return all cities ordered by name with all=true", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			resp := server.GET("/api/v1/cities?all=true")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.CitiesListResponse
+			require.NoError(t, resp.JSON(&response))
+
+			names := cityNames(response)
+			assert.Subset(t, names, []string{"Munich", "New Orleans", "New York"})
+			assert.IsNonDecreasing(t, names)
+		})
+	})
+
+	t.Run("should return only cities matching q", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			resp := server.GET("/api/v1/cities?q=New")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.CitiesListResponse
+			require.NoError(t, resp.JSON(&response))
+
+			names := cityNames(response)
+			assert.Contains(t, names, "New York")
+			assert.Contains(t, names, "New Orleans")
+			assert.NotContains(t, names, "Munich")
+		})
+	})
+
+	t.Run("should match case-insensitively", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			resp := server.GET("/api/v1/cities?q=mun")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.CitiesListResponse
+			require.NoError(t, resp.JSON(&response))
+
+			assert.Contains(t, cityNames(response), "Munich")
+		})
+	})
+
+	t.Run("should respect limit", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			resp := server.GET("/api/v1/cities?q=New&limit=1")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.CitiesListResponse
+			require.NoError(t, resp.JSON(&response))
+
+			assert.Len(t, response.Data, 1)
+		})
+	})
+
+	t.Run("should treat LIKE wildcards literally", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			resp := server.GET("/api/v1/cities?q=%25")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.CitiesListResponse
+			require.NoError(t, resp.JSON(&response))
+
+			assert.NotContains(t, cityNames(response), "Munich")
+		})
+	})
+
+	t.Run("should return 400 for an out of range limit", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		resp := server.GET("/api/v1/cities?q=New&limit=500")
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestCitiesAPI_ListCitiesTrailingSlash(t *testing.T) {
+	t.Run("should list cities with and without a trailing slash", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			for _, path := range []string{"/api/v1/cities?all=true", "/api/v1/cities/?all=true"} {
+				resp := server.GET(path)
+
+				require.Equal(t, http.StatusOK, resp.StatusCode, path)
+				var response cities.CitiesListResponse
+				require.NoError(t, resp.JSON(&response))
+				assert.Subset(t, cityNames(response), []string{"New York", "New Orleans", "Munich"}, path)
+			}
+		})
+	})
+}
+
+func TestCitiesAPI_CreateCity(t *testing.T) {
+	t.Run("should create a city once and reject the duplicate", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			adminToken := helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin")
+
+			req := server.NewRequest("POST", "/api/v1/cities", strings.NewReader(`{"name": "Reykjavik"}`))
+			req.Header.Set("Authorization", "Bearer "+adminToken)
+			resp := server.Do(req)
+
+			require.Equal(t, http.StatusCreated, resp.StatusCode, resp.String())
+			var created cities.CityDataResponse
+			require.NoError(t, resp.JSON(&created))
+			assert.Equal(t, "Reykjavik", created.Data.Name)
+			assert.True(t, created.Data.ID > 0)
+
+			req = server.NewRequest("POST", "/api/v1/cities", strings.NewReader(`{"name": "Reykjavik"}`))
+			req.Header.Set("Authorization", "Bearer "+adminToken)
+			resp = server.Do(req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			var errorResponse map[string]interface{}
+			require.NoError(t, resp.JSON(&errorResponse))
+			assert.Equal(t, errs.ErrKeyCityAlreadyExists, errorResponse["error_key"])
+		})
+	})
+
+	t.Run("should require the admin role", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("POST", "/api/v1/cities", strings.NewReader(`{"name": "Reykjavik"}`))
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestCitiesAPI_ListCitiesETag(t *testing.T) {
+	t.Run("should answer 304 for an unchanged list", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			get := func(path, ifNoneMatch string) *helpers.TestResponse {
+				req := server.NewRequest("GET", path, nil)
+				if ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", ifNoneMatch)
+				}
+				return server.Do(req)
+			}
+
+			for _, path := range []string{"/api/v1/cities", "/api/v1/cities?all=true", "/api/v1/cities?q=New"} {
+				first := get(path, "")
+				require.Equal(t, http.StatusOK, first.StatusCode, path)
+				etag := first.Header.Get("ETag")
+				require.NotEmpty(t, etag, path)
+
+				cached := get(path, etag)
+				assert.Equal(t, http.StatusNotModified, cached.StatusCode, path)
+				assert.Empty(t, cached.Body, path)
+			}
+
+			// A new city changes the list
+			etag := get("/api/v1/cities?q=New", "").Header.Get("ETag")
+			helpers.CreateTestCity(t, ctx, tx, "Newcastle")
+			assert.Equal(t, http.StatusOK, get("/api/v1/cities?q=New", etag).StatusCode)
+		})
+	})
+}
+
+func TestCitiesAPI_ListCitiesPaginated(t *testing.T) {
+	t.Run("should return page 2 of size 10 in name order", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			// Start from a known table so the expected slice is deterministic
+			_, err := tx.Exec(ctx, "DELETE FROM cities")
+			require.NoError(t, err)
+
+			// Insert in reverse so ordering can't come from insertion order
+			for i := 25; i >= 1; i-- {
+				helpers.CreateTestCity(t, ctx, tx, fmt.Sprintf("City %02d", i))
+			}
+
+			resp := server.GET("/api/v1/cities?page=2&page_size=10")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.PaginatedCitiesResponse
+			require.NoError(t, resp.JSON(&response))
+
+			require.Len(t, response.Data, 10)
+			for i, city := range response.Data {
+				assert.Equal(t, fmt.Sprintf("City %02d", i+11), city.Name)
+			}
+
+			assert.Equal(t, int64(25), response.Pagination.Total)
+			assert.Equal(t, int32(2), response.Pagination.CurrentPage)
+			assert.Equal(t, int32(3), response.Pagination.LastPage)
+			assert.Equal(t, int32(10), response.Pagination.PerPage)
+		})
+	})
+
+	t.Run("should paginate by default", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+			seedSearchCities(t, ctx, tx)
+
+			resp := server.GET("/api/v1/cities")
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response cities.PaginatedCitiesResponse
+			require.NoError(t, resp.JSON(&response))
+
+			assert.Equal(t, int32(1), response.Pagination.CurrentPage)
+			assert.Equal(t, int32(20), response.Pagination.PerPage)
+			assert.GreaterOrEqual(t, response.Pagination.Total, int64(3))
+		})
+	})
+
+	t.Run("should return 400 for an invalid page size", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		resp := server.GET("/api/v1/cities?page_size=1000")
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
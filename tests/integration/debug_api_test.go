@@ -0,0 +1,43 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"app/internal/debug"
+	"app/tests/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugAPI_CacheStats(t *testing.T) {
+	const path = "/api/v1/debug/cache-stats"
+
+	t.Run("should return the cache counters to admins", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin"))
+		resp := server.Do(req)
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var response debug.CacheStatsDataResponse
+		require.NoError(t, resp.JSON(&response))
+		assert.NotNil(t, response.Data.Operations)
+		require.NotNil(t, response.Data.Keys)
+	})
+
+	t.Run("should forbid other users", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", path, nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
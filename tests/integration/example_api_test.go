@@ -4,10 +4,12 @@ import (
 	"app/internal/auth"
 	"app/internal/example"
 	"app/internal/db"
+	"app/internal/errs"
 	"app/tests/helpers"
 	"context"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/jackc/pgx/v5"
@@ -120,6 +122,126 @@ func TestExampleAPI_CreateExample(t *testing.T) {
 	})
 }
 
+func TestExampleAPI_CreateExampleIdempotency(t *testing.T) {
+	t.Run("should create one example for a repeated Idempotency-Key", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			create := func() *helpers.TestResponse {
+				req := server.NewRequest("POST", "/api/v1/examples", helpers.StringToReadCloser(`{"title": "Retried Example"}`))
+				req.Header.Set("Authorization", "Bearer "+token)
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Idempotency-Key", "create-retried-example")
+				return server.Do(req)
+			}
+
+			first := create()
+			second := create()
+
+			assert.Equal(t, http.StatusOK, first.StatusCode)
+			assert.Equal(t, http.StatusOK, second.StatusCode)
+			assert.Equal(t, first.String(), second.String())
+			assert.Equal(t, "true", second.Header.Get("Idempotent-Replayed"))
+
+			var count int
+			require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM examples WHERE user_id = $1", userID).Scan(&count))
+			assert.Equal(t, 1, count)
+		})
+	})
+}
+
+func TestExampleAPI_CreateExamplesBatch(t *testing.T) {
+	t.Run("should create every item and return them with ids", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+
+			req := server.NewRequest("POST", "/api/v1/examples/batch", helpers.StringToReadCloser(`{
+				"items": [
+					{"title": "First", "description": "One"},
+					{"title": "Second"}
+				]
+			}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp := server.Do(req)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.ExamplesListResponse
+			require.NoError(t, resp.JSON(&response))
+			require.Len(t, response.Data, 2)
+			assert.Equal(t, "First", response.Data[0].Title)
+			assert.Equal(t, "Second", response.Data[1].Title)
+			assert.True(t, response.Data[0].ID > 0)
+			assert.NotEqual(t, response.Data[0].ID, response.Data[1].ID)
+		})
+	})
+
+	t.Run("should roll back the whole batch when one item is invalid", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			req := server.NewRequest("POST", "/api/v1/examples/batch", helpers.StringToReadCloser(`{
+				"items": [
+					{"title": "Valid"},
+					{"description": "Missing title"}
+				]
+			}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			count, err := queries.CountExamplesForUser(ctx, userID)
+			require.NoError(t, err)
+			assert.Zero(t, count, "no example from a rejected batch should be stored")
+		})
+	})
+
+	t.Run("should return 400 batch_too_large over the item limit", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		items := make([]string, example.MaxExamplesBatchSize+1)
+		for i := range items {
+			items[i] = `{"title": "Item"}`
+		}
+		body := `{"items": [` + strings.Join(items, ",") + `]}`
+
+		req := server.NewRequest("POST", "/api/v1/examples/batch", helpers.StringToReadCloser(body))
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", "application/json")
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.String(), "examples.batch_too_large")
+	})
+
+	t.Run("should return 400 for an empty batch", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("POST", "/api/v1/examples/batch", helpers.StringToReadCloser(`{"items": []}`))
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", "application/json")
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
 func TestExampleAPI_GetExample(t *testing.T) {
 	t.Run("should return 200 when example is found", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
@@ -145,7 +267,7 @@ func TestExampleAPI_GetExample(t *testing.T) {
 			require.NoError(t, err)
 
 			assert.NotNil(t, response.Data)
-			assert.Equal(t, testExample.ID, response.Data.ID)
+			assert.EqualValues(t, testExample.ID, response.Data.ID)
 			assert.Equal(t, testExample.Title, response.Data.Title)
 		})
 	})
@@ -169,6 +291,78 @@ func TestExampleAPI_GetExample(t *testing.T) {
 	})
 }
 
+func TestExampleAPI_GetExampleETag(t *testing.T) {
+	t.Run("should answer 304 until the example changes", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+			ex := helpers.CreateTestExample(t, ctx, tx, userID)
+			path := "/api/v1/examples/" + strconv.Itoa(int(ex.ID))
+
+			get := func(ifNoneMatch string) *helpers.TestResponse {
+				req := server.NewRequest("GET", path, nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				if ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", ifNoneMatch)
+				}
+				return server.Do(req)
+			}
+
+			first := get("")
+			require.Equal(t, http.StatusOK, first.StatusCode)
+			etag := first.Header.Get("ETag")
+			require.NotEmpty(t, etag)
+
+			cached := get(etag)
+			assert.Equal(t, http.StatusNotModified, cached.StatusCode)
+			assert.Empty(t, cached.Body)
+			assert.Equal(t, etag, cached.Header.Get("ETag"))
+
+			req := server.NewRequest("PUT", path, strings.NewReader(`{"title": "Changed"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			require.Equal(t, http.StatusOK, server.Do(req).StatusCode)
+
+			changed := get(etag)
+			assert.Equal(t, http.StatusOK, changed.StatusCode)
+			assert.NotEqual(t, etag, changed.Header.Get("ETag"))
+			assert.Contains(t, changed.String(), "Changed")
+		})
+	})
+}
+
+func TestExampleAPI_RequireJSON(t *testing.T) {
+	t.Run("should return 415 for a form-encoded body", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("POST", "/api/v1/examples", strings.NewReader("title=Hello"))
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+		assert.Contains(t, resp.String(), "unsupported_media_type")
+	})
+
+	t.Run("should pass a JSON body through to the handler", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			req := server.NewRequest("POST", "/api/v1/examples", strings.NewReader(`{"title": "Hello"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json; charset=utf-8")
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	})
+}
+
 func TestExampleAPI_ListExamples(t *testing.T) {
 	t.Run("should return 200 with paginated examples", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
@@ -231,6 +425,105 @@ func TestExampleAPI_ListExamples(t *testing.T) {
 	})
 }
 
+func TestExampleAPI_ListExamplesPaginationHeaders(t *testing.T) {
+	t.Run("should link to the neighbouring pages from a middle page", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			for i := 0; i < 7; i++ {
+				helpers.CreateTestExample(t, ctx, tx, userID)
+			}
+
+			req := server.NewRequest("GET", "/api/v1/examples?page=2&page_size=3", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "7", resp.Header.Get("X-Total-Count"))
+			assert.Equal(t, "2", resp.Header.Get("X-Page"))
+			assert.Equal(t, "3", resp.Header.Get("X-Per-Page"))
+
+			link := resp.Header.Get("Link")
+			assert.Contains(t, link, `</api/v1/examples?page=3&page_size=3>; rel="next"`)
+			assert.Contains(t, link, `</api/v1/examples?page=1&page_size=3>; rel="prev"`)
+			assert.Contains(t, link, `</api/v1/examples?page=3&page_size=3>; rel="last"`)
+
+			// The body keeps its pagination block
+			var response example.PaginatedExamplesResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.Equal(t, int64(7), response.Pagination.Total)
+			assert.Len(t, response.Data, 3)
+		})
+	})
+}
+
+func TestExampleAPI_ListExamplesCursor(t *testing.T) {
+	t.Run("should page stably when an example is created between fetches", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			var created []int32
+			for i := 0; i < 5; i++ {
+				created = append(created, helpers.CreateTestExample(t, ctx, tx, userID).ID)
+			}
+
+			fetch := func(target string) example.CursorExamplesResponse {
+				req := server.NewRequest("GET", target, nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				resp := server.Do(req)
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var response example.CursorExamplesResponse
+				require.NoError(t, resp.JSON(&response))
+				return response
+			}
+			ids := func(response example.CursorExamplesResponse) []int32 {
+				var ids []int32
+				for _, ex := range response.Data {
+					ids = append(ids, int32(ex.ID))
+				}
+				return ids
+			}
+
+			first := fetch("/api/v1/examples?cursor=&limit=2")
+			assert.Equal(t, []int32{created[4], created[3]}, ids(first))
+			assert.Equal(t, int32(2), first.Pagination.Limit)
+			require.NotNil(t, first.Pagination.NextCursor)
+
+			// An offset page 2 would now repeat created[3]
+			helpers.CreateTestExample(t, ctx, tx, userID)
+
+			second := fetch("/api/v1/examples?limit=2&cursor=" + *first.Pagination.NextCursor)
+			assert.Equal(t, []int32{created[2], created[1]}, ids(second))
+			require.NotNil(t, second.Pagination.NextCursor)
+
+			last := fetch("/api/v1/examples?limit=2&cursor=" + *second.Pagination.NextCursor)
+			assert.Equal(t, []int32{created[0]}, ids(last))
+			assert.Nil(t, last.Pagination.NextCursor)
+		})
+	})
+
+	t.Run("should return 400 for an invalid cursor", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", "/api/v1/examples?cursor=garbage", nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.String(), errs.ErrKeyValidationCursor)
+	})
+}
+
 func TestExampleAPI_UpdateExample(t *testing.T) {
 	t.Run("should return 200 when example is updated successfully", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
@@ -338,3 +631,462 @@ func TestExampleAPI_DeleteExample(t *testing.T) {
 		})
 	})
 }
+
+func TestExampleAPI_ShareExample(t *testing.T) {
+	t.Run("should return a signed link that serves the example without auth", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+			testExample := helpers.CreateTestExample(t, ctx, tx, userID)
+
+			req := server.NewRequest("POST", "/api/v1/examples/"+strconv.Itoa(int(testExample.ID))+"/share", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var shareResponse example.ShareLinkDataResponse
+			require.NoError(t, resp.JSON(&shareResponse))
+			assert.Contains(t, shareResponse.Data.URL, "/api/v1/shared/examples/"+strconv.Itoa(int(testExample.ID)))
+			assert.NotEmpty(t, shareResponse.Data.ExpiresAt)
+
+			// Test: Follow the link without a token
+			sharedResp := server.GET(shareResponse.Data.URL)
+
+			assert.Equal(t, http.StatusOK, sharedResp.StatusCode)
+			var response example.ExampleDataResponse
+			require.NoError(t, sharedResp.JSON(&response))
+			assert.Equal(t, testExample.Title, response.Data.Title)
+		})
+	})
+
+	t.Run("should return 404 when sharing someone else's example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			otherUser := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			otherExample := helpers.CreateTestExample(t, ctx, tx, otherUser.ID)
+
+			req := server.NewRequest("POST", "/api/v1/examples/"+strconv.Itoa(int(otherExample.ID))+"/share", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+
+	t.Run("should return 403 for a shared link without signature", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		resp := server.GET("/api/v1/shared/examples/1")
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestExampleAPI_GetPublicExample(t *testing.T) {
+	publicPath := func(id int32) string {
+		return "/api/v1/examples/" + strconv.Itoa(int(id)) + "/public"
+	}
+
+	t.Run("should let an anonymous user read a public example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+
+			// Setup: the owner publishes the example
+			req := server.NewRequest("PUT", "/api/v1/examples/"+strconv.Itoa(int(testExample.ID)),
+				helpers.StringToReadCloser(`{"title": "Published", "public": true}`))
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, owner.ID))
+			req.Header.Set("Content-Type", "application/json")
+			updateResp := server.Do(req)
+			require.Equal(t, http.StatusOK, updateResp.StatusCode)
+
+			var updated example.ExampleDataResponse
+			require.NoError(t, updateResp.JSON(&updated))
+			require.True(t, updated.Data.Public)
+
+			resp := server.GET(publicPath(testExample.ID))
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.ExampleDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, testExample.ID, response.Data.ID)
+			assert.Equal(t, "Published", response.Data.Title)
+			assert.True(t, response.Data.Public)
+		})
+	})
+
+	t.Run("should return 404 to an anonymous user for a private example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+
+			resp := server.GET(publicPath(testExample.ID))
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+
+	t.Run("should let the owner read their private example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+
+			req := server.NewRequest("GET", publicPath(testExample.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, owner.ID))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.ExampleDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, testExample.ID, response.Data.ID)
+			assert.False(t, response.Data.Public)
+		})
+	})
+
+	t.Run("should return 404 to another user for a private example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+
+			req := server.NewRequest("GET", publicPath(testExample.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, other.ID))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
+
+func TestExampleAPI_OwnerOrAdmin(t *testing.T) {
+	t.Run("should let the owner read their example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+
+			req := server.NewRequest("GET", "/api/v1/examples/"+strconv.Itoa(int(testExample.ID)), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, owner.ID))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	})
+
+	t.Run("should let an admin read, update and delete another user's example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			admin := helpers.CreateTestUserWithEmail(t, ctx, tx, "admin@example.com")
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+			path := "/api/v1/examples/" + strconv.Itoa(int(testExample.ID))
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, admin.ID, "admin")
+
+			req := server.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.ExampleDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, owner.ID, response.Data.UserID)
+
+			req = server.NewRequest("PUT", path, helpers.StringToReadCloser(`{"title": "Moderated"}`))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			resp = server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			require.NoError(t, resp.JSON(&response))
+			assert.Equal(t, "Moderated", response.Data.Title)
+			assert.EqualValues(t, owner.ID, response.Data.UserID, "update should not transfer ownership to the admin")
+
+			req = server.NewRequest("DELETE", path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp = server.Do(req)
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		})
+	})
+
+	t.Run("should return 404 for a non-owner without the admin role", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+			path := "/api/v1/examples/" + strconv.Itoa(int(testExample.ID))
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, other.ID)
+
+			for _, method := range []string{"GET", "DELETE"} {
+				req := server.NewRequest(method, path, nil)
+				req.Header.Set("Authorization", "Bearer "+token)
+				resp := server.Do(req)
+
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode, method)
+			}
+		})
+	})
+
+	t.Run("should return 404 to an admin for a missing example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			req := server.NewRequest("GET", "/api/v1/examples/999999", nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin"))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.Contains(t, resp.String(), "examples.not_found")
+		})
+	})
+}
+
+func TestExampleAPI_GetExampleAdmin(t *testing.T) {
+	t.Run("should return another user's example to an admin", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			admin := helpers.CreateTestUserWithEmail(t, ctx, tx, "admin@example.com")
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+
+			req := server.NewRequest("GET", "/api/v1/admin/examples/"+strconv.Itoa(int(testExample.ID)), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, admin.ID, "admin"))
+			resp := server.Do(req)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response example.ExampleDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, testExample.ID, response.Data.ID)
+			assert.EqualValues(t, owner.ID, response.Data.UserID)
+		})
+	})
+
+	t.Run("should keep 404 on the user route and return 403 on the admin route for a non-admin", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			testExample := helpers.CreateTestExample(t, ctx, tx, owner.ID)
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, other.ID)
+
+			req := server.NewRequest("GET", "/api/v1/examples/"+strconv.Itoa(int(testExample.ID)), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			req = server.NewRequest("GET", "/api/v1/admin/examples/"+strconv.Itoa(int(testExample.ID)), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp = server.Do(req)
+			assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		})
+	})
+
+	t.Run("should return 404 to an admin for a missing example", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			req := server.NewRequest("GET", "/api/v1/admin/examples/999999", nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin"))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.Contains(t, resp.String(), "examples.not_found")
+		})
+	})
+}
+
+func TestExampleAPI_ListExamplesPageTooDeep(t *testing.T) {
+	t.Run("should return 400 page_too_deep for a huge page", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", "/api/v1/examples?page=1000000", nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		assert.Contains(t, resp.String(), "validation.page_too_deep")
+	})
+}
+
+func TestExampleAPI_ListExamplesCreatedRange(t *testing.T) {
+	t.Run("should only return and count examples inside the range", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			// One example per day, 2024-01-01 through 2024-01-05
+			for day := 1; day <= 5; day++ {
+				created := helpers.CreateTestExampleWithTitle(t, ctx, tx, userID, "Day "+strconv.Itoa(day))
+				_, err := tx.Exec(ctx, "UPDATE examples SET created_at = make_timestamp(2024, 1, $1, 12, 0, 0) WHERE id = $2", day, created.ID)
+				require.NoError(t, err)
+			}
+
+			req := server.NewRequest("GET", "/api/v1/examples?created_after=2024-01-02T00:00:00Z&created_before=2024-01-04T00:00:00Z", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.PaginatedExamplesResponse
+			require.NoError(t, resp.JSON(&response))
+
+			require.Len(t, response.Data, 2)
+			assert.Equal(t, "Day 3", response.Data[0].Title)
+			assert.Equal(t, "Day 2", response.Data[1].Title)
+			assert.Equal(t, int64(2), response.Pagination.Total)
+		})
+	})
+
+	t.Run("should accept a single open-ended bound", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			for day := 1; day <= 5; day++ {
+				created := helpers.CreateTestExample(t, ctx, tx, userID)
+				_, err := tx.Exec(ctx, "UPDATE examples SET created_at = make_timestamp(2024, 1, $1, 12, 0, 0) WHERE id = $2", day, created.ID)
+				require.NoError(t, err)
+			}
+
+			req := server.NewRequest("GET", "/api/v1/examples?page_size=2&created_after=2024-01-03T12:00:00%2B00:00", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.PaginatedExamplesResponse
+			require.NoError(t, resp.JSON(&response))
+
+			assert.Len(t, response.Data, 2)
+			assert.Equal(t, int64(3), response.Pagination.Total)
+		})
+	})
+
+	t.Run("should return 400 bad_request for a malformed timestamp", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", "/api/v1/examples?created_after=yesterday", nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var body errs.ErrorResponse
+		require.NoError(t, resp.JSON(&body))
+		assert.Equal(t, errs.ErrKeyBadRequest, body.ErrorKey)
+		assert.Equal(t, "created_after", body.Details["field"])
+	})
+
+	t.Run("should return 400 bad_request when the range is empty", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", "/api/v1/examples?created_after=2024-01-04T00:00:00Z&created_before=2024-01-02T00:00:00Z", nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var body errs.ErrorResponse
+		require.NoError(t, resp.JSON(&body))
+		assert.Equal(t, errs.ErrKeyBadRequest, body.ErrorKey)
+	})
+}
+
+func TestExampleAPI_SearchExamples(t *testing.T) {
+	t.Run("should return only the matching examples, paginated", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			token := getAuthToken(t, server)
+			userID := getUserIDFromToken(t, ctx, tx, "test@example.com")
+
+			helpers.CreateTestExampleWithTitle(t, ctx, tx, userID, "Sourdough bread recipe")
+			helpers.CreateTestExampleWithTitle(t, ctx, tx, userID, "Rye bread basics")
+			helpers.CreateTestExampleWithTitle(t, ctx, tx, userID, "Bicycle maintenance")
+
+			req := server.NewRequest("GET", "/api/v1/examples/search?q=bread&page_size=1", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response example.PaginatedExamplesResponse
+			require.NoError(t, resp.JSON(&response))
+
+			require.Len(t, response.Data, 1)
+			assert.Contains(t, response.Data[0].Title, "bread")
+			assert.Equal(t, int64(2), response.Pagination.Total)
+		})
+	})
+
+	t.Run("should return 400 without a query", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		req := server.NewRequest("GET", "/api/v1/examples/search", nil)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestExampleAPI_CreateExampleBodyTooLarge(t *testing.T) {
+	t.Run("should return 413 for a body over the global limit", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		body := `{"title": "` + strings.Repeat("a", 1<<20) + `"}`
+		req := server.NewRequest("POST", "/api/v1/examples", helpers.StringToReadCloser(body))
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", "application/json")
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+		assert.Contains(t, resp.String(), errs.ErrKeyRequestTooLarge)
+	})
+}
@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/folders"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func folderRequest(t *testing.T, server *helpers.TestServer, userID int32, method, path, body string) *helpers.TestResponse {
+	req := server.NewRequest(method, path, helpers.StringToReadCloser(body))
+	req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, userID))
+	return server.Do(req)
+}
+
+func TestFoldersAPI_CreateFolder(t *testing.T) {
+	t.Run("should create a folder and list it after the root folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			resp := folderRequest(t, server, user.ID, "POST", "/api/v1/folders", `{"name":"  Photos  "}`)
+			require.Equal(t, http.StatusCreated, resp.StatusCode, resp.String())
+
+			var created folders.FolderDataResponse
+			require.NoError(t, resp.JSON(&created))
+			assert.Equal(t, "Photos", created.Data.Name)
+			assert.False(t, created.Data.IsRoot)
+
+			resp = folderRequest(t, server, user.ID, "GET", "/api/v1/folders", "")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var list folders.FoldersListResponse
+			require.NoError(t, resp.JSON(&list))
+			require.Len(t, list.Data, 2)
+			assert.True(t, list.Data[0].IsRoot)
+			assert.Equal(t, created.Data.ID, list.Data[1].ID)
+		})
+	})
+
+	t.Run("should reject a blank name", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			resp := folderRequest(t, server, user.ID, "POST", "/api/v1/folders", `{"name":"   "}`)
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+}
+
+func TestFoldersAPI_GetAndRenameFolder(t *testing.T) {
+	t.Run("should rename the user's folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			folder := helpers.CreateTestFolder(t, ctx, tx, user.ID, "Drafts")
+			path := fmt.Sprintf("/api/v1/folders/%d", folder.ID)
+
+			resp := folderRequest(t, server, user.ID, "PATCH", path, `{"name":"Final"}`)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+
+			resp = folderRequest(t, server, user.ID, "GET", path, "")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			var response folders.FolderDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.Equal(t, "Final", response.Data.Name)
+		})
+	})
+
+	t.Run("should return 404 for another user's folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			folder := helpers.CreateTestFolder(t, ctx, tx, other.ID, "Theirs")
+			path := fmt.Sprintf("/api/v1/folders/%d", folder.ID)
+
+			for _, resp := range []*helpers.TestResponse{
+				folderRequest(t, server, user.ID, "GET", path, ""),
+				folderRequest(t, server, user.ID, "PATCH", path, `{"name":"Mine"}`),
+				folderRequest(t, server, user.ID, "DELETE", path, ""),
+			} {
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Contains(t, resp.String(), errs.ErrKeyFolderNotFound)
+			}
+		})
+	})
+}
+
+func TestFoldersAPI_DeleteFolder(t *testing.T) {
+	t.Run("should delete an empty folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			folder := helpers.CreateTestFolder(t, ctx, tx, user.ID, "Empty")
+			path := fmt.Sprintf("/api/v1/folders/%d", folder.ID)
+
+			resp := folderRequest(t, server, user.ID, "DELETE", path, "")
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+
+			assert.Equal(t, http.StatusNotFound, folderRequest(t, server, user.ID, "GET", path, "").StatusCode)
+		})
+	})
+
+	t.Run("should keep a folder that still has uploads", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			folder := helpers.CreateTestFolder(t, ctx, tx, user.ID, "Full")
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			_, err := tx.Exec(ctx, "UPDATE uploads SET folder_id = $1 WHERE id = $2", folder.ID, upload.ID)
+			require.NoError(t, err)
+
+			resp := folderRequest(t, server, user.ID, "DELETE", fmt.Sprintf("/api/v1/folders/%d", folder.ID), "")
+
+			assert.Equal(t, http.StatusConflict, resp.StatusCode)
+			assert.Contains(t, resp.String(), errs.ErrKeyFolderNotEmpty)
+		})
+	})
+
+	t.Run("should never delete the root folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			root := helpers.GetTestRootFolder(t, ctx, tx, user.ID)
+
+			resp := folderRequest(t, server, user.ID, "DELETE", fmt.Sprintf("/api/v1/folders/%d", root.ID), "")
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			assert.Contains(t, resp.String(), errs.ErrKeyFolderRootProtected)
+		})
+	})
+}
@@ -0,0 +1,202 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"app/config"
+	"app/internal/buildinfo"
+	"app/internal/cache"
+	"app/internal/health"
+	"app/tests"
+	"app/tests/helpers"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newHealthRouter(t *testing.T, service *health.HealthService) (*gin.Engine, *health.Handler) {
+	return newHealthRouterWithConfig(t, service, &config.Config{AppName: "TestApp", AppVersion: "1.0.0", Environment: "test"}, time.Now())
+}
+
+func newHealthRouterWithConfig(t *testing.T, service *health.HealthService, cfg *config.Config, startedAt time.Time) (*gin.Engine, *health.Handler) {
+	gin.SetMode(gin.TestMode)
+	handler := health.NewHandler(cfg, service, helpers.GetTestLogger(t), startedAt)
+
+	router := gin.New()
+	health.RegisterRoutes(router, handler)
+	return router, handler
+}
+
+// newClosedPool returns a pool that has been closed, so every ping fails
+func newClosedPool(t *testing.T) *pgxpool.Pool {
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/unreachable?sslmode=disable")
+	require.NoError(t, err)
+	pool.Close()
+	return pool
+}
+
+func newTestRedis(t *testing.T) *redis.Client {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestHealthAPI_Health(t *testing.T) {
+	t.Run("should return 200 when healthy", func(t *testing.T) {
+		router, _ := newHealthRouter(t, health.NewHealthService(nil, nil))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response health.HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "healthy", response.Status)
+		assert.Equal(t, "TestApp", response.App)
+	})
+
+	t.Run("should report uptime since the process started", func(t *testing.T) {
+		startedAt := time.Now().Add(-90 * time.Second)
+		router, _ := newHealthRouterWithConfig(t, health.NewHealthService(nil, nil), &config.Config{AppName: "TestApp"}, startedAt)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.Contains(t, body, "uptime_seconds")
+		uptime, ok := body["uptime_seconds"].(float64)
+		require.True(t, ok, "uptime_seconds should be a number")
+		assert.GreaterOrEqual(t, uptime, float64(90))
+		assert.Equal(t, startedAt.UTC().Format(time.RFC3339), body["started_at"])
+		assert.Greater(t, body["goroutines"], float64(0))
+	})
+
+	t.Run("should never report negative uptime", func(t *testing.T) {
+		router, _ := newHealthRouterWithConfig(t, health.NewHealthService(nil, nil), &config.Config{}, time.Now().Add(time.Hour))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+		var response health.HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.GreaterOrEqual(t, response.UptimeSeconds, int64(0))
+	})
+
+	t.Run("should return 503 when too many goroutines are running", func(t *testing.T) {
+		router, _ := newHealthRouterWithConfig(t, health.NewHealthService(nil, nil), &config.Config{HealthMaxGoroutines: 1}, time.Now())
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response health.HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "unhealthy", response.Status)
+	})
+
+	t.Run("should return 503 once shutdown begins", func(t *testing.T) {
+		router, handler := newHealthRouter(t, health.NewHealthService(nil, nil))
+
+		handler.MarkShuttingDown()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response health.HealthResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "shutting_down", response.Status)
+	})
+}
+
+func TestHealthAPI_Version(t *testing.T) {
+	t.Run("should return the injected build info", func(t *testing.T) {
+		original := []string{buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime}
+		buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = "v1.2.3", "abc1234", "2024-01-01T00:00:00Z"
+		t.Cleanup(func() {
+			buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = original[0], original[1], original[2]
+		})
+
+		router, _ := newHealthRouter(t, health.NewHealthService(nil, nil))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/version", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response health.VersionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "v1.2.3", response.Version)
+		assert.Equal(t, "abc1234", response.Commit)
+		assert.Equal(t, "2024-01-01T00:00:00Z", response.BuildTime)
+		assert.Equal(t, runtime.Version(), response.GoVersion)
+	})
+
+	t.Run("should report dev defaults without ldflags", func(t *testing.T) {
+		router, _ := newHealthRouter(t, health.NewHealthService(nil, nil))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/version", nil))
+
+		var response health.VersionResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "dev", response.Version)
+		assert.Equal(t, "unknown", response.Commit)
+		assert.Equal(t, "unknown", response.BuildTime)
+	})
+}
+
+func TestHealthAPI_Ready(t *testing.T) {
+	t.Run("should return 503 when the database pool is closed", func(t *testing.T) {
+		router, _ := newHealthRouter(t, health.NewHealthService(newClosedPool(t), cache.NewRedisCache(newTestRedis(t), "")))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response health.ReadinessResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, health.StatusDown, response.Database)
+		assert.Equal(t, health.StatusOK, response.Redis)
+	})
+
+	t.Run("should report both dependencies down", func(t *testing.T) {
+		redisClient := newTestRedis(t)
+		require.NoError(t, redisClient.Close())
+		router, _ := newHealthRouter(t, health.NewHealthService(newClosedPool(t), cache.NewRedisCache(redisClient, "")))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.JSONEq(t, `{"database":"down","redis":"down"}`, w.Body.String())
+	})
+
+	t.Run("should return 200 when database and redis are up", func(t *testing.T) {
+		router, _ := newHealthRouter(t, health.NewHealthService(tests.GetTestDBPool(), cache.NewRedisCache(newTestRedis(t), "")))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health/ready", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"database":"ok","redis":"ok"}`, w.Body.String())
+	})
+}
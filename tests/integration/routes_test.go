@@ -0,0 +1,240 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"app/config"
+	"app/internal/errs"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Route registration is checked without a database: only the router is inspected
+func TestRoutes_CriticalRoutesAreProtected(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	protected := []struct {
+		method string
+		path   string
+	}{
+		{"GET", "/api/v1/folders"},
+		{"POST", "/api/v1/folders"},
+		{"GET", "/api/v1/folders/:id"},
+		{"PATCH", "/api/v1/folders/:id"},
+		{"DELETE", "/api/v1/folders/:id"},
+		{"GET", "/api/v1/auth/me"},
+		{"PUT", "/api/v1/auth/me"},
+		{"DELETE", "/api/v1/auth/me"},
+		{"POST", "/api/v1/auth/change-password"},
+		{"POST", "/api/v1/auth/logout"},
+		{"GET", "/api/v1/auth/sessions"},
+		{"DELETE", "/api/v1/auth/sessions/:id"},
+		{"POST", "/api/v1/scheduler/jobs/:name/run"},
+		{"GET", "/api/v1/scheduler/jobs/:name/runs"},
+	}
+
+	for _, tc := range protected {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			route, found := server.FindRoute(tc.method, tc.path)
+
+			assert.True(t, found, "route should be registered")
+			assert.True(t, route.Uses("middleware.UserAuthMiddleware"), "route should require authentication, chain: %v", route.Middleware)
+		})
+	}
+}
+
+func TestRoutes_APIKeyRoutesAreProtected(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	// Resources an API key can act on, a user token works as well
+	for _, tc := range []struct{ method, path string }{
+		{"GET", "/api/v1/me/api-keys"},
+		{"POST", "/api/v1/examples"},
+		{"POST", "/api/v1/examples/batch"},
+		{"GET", "/api/v1/examples"},
+		{"GET", "/api/v1/examples/search"},
+		{"GET", "/api/v1/examples/:id"},
+		{"PUT", "/api/v1/examples/:id"},
+		{"DELETE", "/api/v1/examples/:id"},
+		{"POST", "/api/v1/examples/:id/share"},
+		{"POST", "/api/v1/uploads"},
+		{"POST", "/api/v1/uploads/bulk"},
+		{"DELETE", "/api/v1/uploads"},
+		{"GET", "/api/v1/uploads/:id"},
+		{"HEAD", "/api/v1/uploads/:id"},
+		{"PATCH", "/api/v1/uploads/:id"},
+		{"GET", "/api/v1/uploads/:id/download"},
+	} {
+		route, found := server.FindRoute(tc.method, tc.path)
+
+		assert.True(t, found, tc.path)
+		assert.True(t, route.Uses("middleware.UserOrAPIKeyAuthMiddleware"), "route should require a token or API key, chain: %v", route.Middleware)
+	}
+
+	// A key must not mint or revoke keys, only a user token can
+	for _, tc := range []struct{ method, path string }{
+		{"POST", "/api/v1/me/api-keys"},
+		{"DELETE", "/api/v1/me/api-keys/:id"},
+	} {
+		route, found := server.FindRoute(tc.method, tc.path)
+
+		assert.True(t, found, tc.path)
+		assert.True(t, route.Uses("middleware.UserAuthMiddleware"), "route should require a user token, chain: %v", route.Middleware)
+		assert.False(t, route.Uses("middleware.UserOrAPIKeyAuthMiddleware"), "route should not accept an API key, chain: %v", route.Middleware)
+	}
+}
+
+func TestRoutes_AdminRoutesRequireRole(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	for _, tc := range []struct{ method, path string }{
+		{"POST", "/api/v1/scheduler/jobs/:name/run"},
+		{"GET", "/api/v1/scheduler/jobs/:name/runs"},
+		{"GET", "/api/v1/debug/cache-stats"},
+		{"POST", "/api/v1/cities"},
+		{"GET", "/api/v1/admin/examples/:id"},
+	} {
+		route, found := server.FindRoute(tc.method, tc.path)
+
+		assert.True(t, found, tc.path)
+		assert.True(t, route.Uses("middleware.RequireRole"), "admin route should require a role, chain: %v", route.Middleware)
+	}
+}
+
+func TestRoutes_PublicAuthRoutesAreNotProtected(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	for _, path := range []string{"/api/v1/auth/register", "/api/v1/auth/login", "/api/v1/auth/refresh"} {
+		route, found := server.FindRoute("POST", path)
+
+		assert.True(t, found, "route %s should be registered", path)
+		assert.False(t, route.Uses("middleware.UserAuthMiddleware"), "route %s should be public", path)
+	}
+}
+
+func TestRoutes_SharedRoutesRequireSignature(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	for _, path := range []string{"/api/v1/shared/examples/:id", "/api/v1/files/*path"} {
+		route, found := server.FindRoute("GET", path)
+
+		assert.True(t, found, path)
+		assert.True(t, route.Uses("middleware.SignedURL"), "shared route should require a signed link, chain: %v", route.Middleware)
+		assert.False(t, route.Uses("middleware.UserAuthMiddleware"), "shared route should not require a user token")
+	}
+}
+
+func TestRoutes_PublicExampleRouteHasOptionalAuth(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	route, found := server.FindRoute("GET", "/api/v1/examples/:id/public")
+
+	assert.True(t, found)
+	assert.True(t, route.Uses("middleware.OptionalUserAuthMiddleware"), "chain: %v", route.Middleware)
+	assert.False(t, route.Uses("middleware.UserAuthMiddleware"), "public example route should not require a token")
+}
+
+func TestRoutes_APIBasePath(t *testing.T) {
+	server := helpers.CreateTestServerWithConfig(t, context.Background(), nil, nil, func(cfg *config.Config) {
+		cfg.APIBasePath = "/gateway/v1"
+	})
+	defer server.Close()
+
+	t.Run("should serve routes under the configured prefix", func(t *testing.T) {
+		_, found := server.FindRoute("GET", "/gateway/v1/auth/me")
+		assert.True(t, found)
+
+		resp := server.GET("/gateway/v1/auth/me")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("should not serve routes under the default prefix", func(t *testing.T) {
+		_, found := server.FindRoute("GET", "/api/v1/auth/me")
+		assert.False(t, found)
+
+		resp := server.GET("/api/v1/auth/me")
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestRoutes_UnknownRoutesReturnJSON(t *testing.T) {
+	t.Run("should return a JSON 404 for an unknown path", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		resp := server.GET("/api/v1/does-not-exist")
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+
+		var body errs.ErrorResponse
+		require.NoError(t, resp.JSON(&body))
+		assert.Equal(t, errs.ErrKeyNotFound, body.ErrorKey)
+		assert.Equal(t, http.StatusNotFound, body.Status)
+	})
+
+	t.Run("should return a JSON 405 with Allow for a wrong method", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		resp := server.DELETE("/api/v1/auth/login")
+
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get("Content-Type"), "application/json")
+		assert.Equal(t, "POST", resp.Header.Get("Allow"))
+
+		var body errs.ErrorResponse
+		require.NoError(t, resp.JSON(&body))
+		assert.Equal(t, errs.ErrKeyMethodNotAllowed, body.ErrorKey)
+		assert.Equal(t, http.StatusMethodNotAllowed, body.Status)
+	})
+}
+
+func TestRoutes_TrailingSlash(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+	adminToken := helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin")
+
+	t.Run("should route GET requests with and without a trailing slash alike", func(t *testing.T) {
+		for _, path := range []string{"/api/v1/debug/cache-stats", "/api/v1/debug/cache-stats/"} {
+			req := server.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+adminToken)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode, path)
+		}
+	})
+
+	t.Run("should not redirect or rewrite other methods", func(t *testing.T) {
+		resp := server.POST("/api/v1/auth/login/", `{"email": "user@example.com", "password": "password123"}`)
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get("Location"))
+	})
+}
+
+func TestRoutes_SecurityHeaders(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	req := server.NewRequest("GET", "/api/v1/debug/cache-stats", nil)
+	req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1, "admin"))
+	resp := server.Do(req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	for name, value := range middleware.DefaultSecurityHeaders {
+		assert.Equal(t, value, resp.Header.Get(name), name)
+	}
+	assert.Empty(t, resp.Header.Get(middleware.HSTSHeader), "HSTS must be off outside production")
+}
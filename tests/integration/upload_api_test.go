@@ -1,14 +1,23 @@
 package integration
 
 import (
+	"app/config"
 	"app/internal/db"
+	"app/internal/errs"
 	"app/internal/uploads"
 	"app/tests/helpers"
 	"bytes"
 	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"mime/multipart"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/stretchr/testify/assert"
@@ -52,8 +61,8 @@ func TestUploadAPI_UploadFile(t *testing.T) {
 
 			assert.NotNil(t, response.Data)
 			assert.True(t, response.Data.ID > 0)
-			assert.Equal(t, userID, response.Data.UserID)
-			assert.Equal(t, userID, response.Data.FolderID)
+			assert.EqualValues(t, userID, response.Data.UserID)
+			assert.EqualValues(t, helpers.GetTestRootFolder(t, ctx, tx, userID).ID, response.Data.FolderID)
 			assert.Equal(t, "image", response.Data.Type)
 			assert.Equal(t, "test.jpg", response.Data.OriginalFilename)
 			assert.Equal(t, int64(len(fileContent)), response.Data.FileSize)
@@ -106,7 +115,625 @@ func TestUploadAPI_UploadFile(t *testing.T) {
 			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
 		})
 	})
+
+	t.Run("should accept a body that takes longer than REQUEST_TIMEOUT to arrive", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServerWithConfig(t, ctx, tx, queries, func(cfg *config.Config) {
+				cfg.RequestTimeout = 50 * time.Millisecond
+				cfg.UploadTimeout = time.Minute
+			})
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("file", "slow.txt")
+			require.NoError(t, err)
+			_, err = part.Write(bytes.Repeat([]byte("slow "), 100))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			// About 200ms to send, four times REQUEST_TIMEOUT
+			req := server.NewRequest("POST", "/api/v1/uploads", &slowReader{data: body.Bytes(), chunk: body.Len()/8 + 1, delay: 25 * time.Millisecond})
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+		})
+	})
+}
+
+// slowReader hands out data a chunk at a time with a pause before each, like a slow client
+type slowReader struct {
+	data  []byte
+	chunk int
+	delay time.Duration
+}
+
+func (r *slowReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	time.Sleep(r.delay)
+	n := copy(p[:min(len(p), r.chunk)], r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestUploadAPI_UploadFileWithFields(t *testing.T) {
+	// newBody builds a multipart body with a "file" part and the given form fields
+	newBody := func(t *testing.T, fields map[string]string) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "photo.jpg")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("test image content"))
+		require.NoError(t, err)
+		for name, value := range fields {
+			require.NoError(t, writer.WriteField(name, value))
+		}
+		require.NoError(t, writer.Close())
+		return body, writer.FormDataContentType()
+	}
+
+	upload := func(server *helpers.TestServer, userID int32, body *bytes.Buffer, contentType string) *helpers.TestResponse {
+		req := server.NewRequest("POST", "/api/v1/uploads", body)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, userID))
+		req.Header.Set("Content-Type", contentType)
+		return server.Do(req)
+	}
+
+	t.Run("should store the caption and echo it back", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			body, contentType := newBody(t, map[string]string{"caption": "  Sunset over the bay  "})
+			resp := upload(server, user.ID, body, contentType)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			var response uploads.UploadDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.Equal(t, "Sunset over the bay", response.Data.Caption)
+			assert.EqualValues(t, helpers.GetTestRootFolder(t, ctx, tx, user.ID).ID, response.Data.FolderID)
+
+			stored, err := queries.GetUploadByID(ctx, int32(response.Data.ID))
+			require.NoError(t, err)
+			assert.Equal(t, "Sunset over the bay", stored.Caption.String)
+		})
+	})
+
+	t.Run("should store no caption when the field is absent", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			body, contentType := newBody(t, nil)
+			resp := upload(server, user.ID, body, contentType)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			assert.NotContains(t, resp.String(), `"caption"`)
+
+			var response uploads.UploadDataResponse
+			require.NoError(t, resp.JSON(&response))
+			stored, err := queries.GetUploadByID(ctx, int32(response.Data.ID))
+			require.NoError(t, err)
+			assert.False(t, stored.Caption.Valid)
+		})
+	})
+
+	t.Run("should upload into a folder the user created", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			folder := helpers.CreateTestFolder(t, ctx, tx, user.ID, "Photos")
+			body, contentType := newBody(t, map[string]string{"folder_id": fmt.Sprint(folder.ID)})
+			resp := upload(server, user.ID, body, contentType)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			var response uploads.UploadDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, folder.ID, response.Data.FolderID)
+
+			stored, err := queries.GetUploadByID(ctx, int32(response.Data.ID))
+			require.NoError(t, err)
+			assert.Equal(t, folder.ID, stored.FolderID)
+		})
+	})
+
+	t.Run("should return 404 for a folder the user doesn't own", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			for _, folderID := range []int32{
+				helpers.CreateTestFolder(t, ctx, tx, other.ID, "Theirs").ID,
+				helpers.GetTestRootFolder(t, ctx, tx, other.ID).ID,
+			} {
+				body, contentType := newBody(t, map[string]string{"folder_id": fmt.Sprint(folderID)})
+				resp := upload(server, user.ID, body, contentType)
+
+				assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+				assert.Contains(t, resp.String(), errs.ErrKeyUploadFolderNotFound)
+			}
+		})
+	})
+
+	t.Run("should reject a non-numeric folder and an overlong caption", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			body, contentType := newBody(t, map[string]string{"folder_id": "photos"})
+			resp := upload(server, user.ID, body, contentType)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+			body, contentType = newBody(t, map[string]string{"caption": strings.Repeat("a", uploads.MaxCaptionLength+1)})
+			resp = upload(server, user.ID, body, contentType)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	})
+}
+
+func TestUploadAPI_UploadFiles(t *testing.T) {
+	// newBulkBody builds a multipart body with one "files" part per name
+	newBulkBody := func(t *testing.T, names ...string) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		for _, name := range names {
+			part, err := writer.CreateFormFile("files", name)
+			require.NoError(t, err)
+			_, err = part.Write([]byte("content of " + name))
+			require.NoError(t, err)
+		}
+		require.NoError(t, writer.Close())
+		return body, writer.FormDataContentType()
+	}
+
+	t.Run("should store valid files and report the invalid one", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			body, contentType := newBulkBody(t, "photo.jpg", "virus.exe", "notes.txt")
+
+			req := server.NewRequest("POST", "/api/v1/uploads/bulk", body)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			req.Header.Set("Content-Type", contentType)
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response uploads.BulkUploadDataResponse
+			require.NoError(t, resp.JSON(&response))
+
+			require.Len(t, response.Data.Uploads, 2)
+			assert.Equal(t, "photo.jpg", response.Data.Uploads[0].OriginalFilename)
+			assert.Equal(t, "image", response.Data.Uploads[0].Type)
+			assert.Equal(t, "notes.txt", response.Data.Uploads[1].OriginalFilename)
+			assert.Equal(t, "document", response.Data.Uploads[1].Type)
+
+			require.Len(t, response.Data.Errors, 1)
+			assert.Equal(t, 1, response.Data.Errors[0].Index)
+			assert.Equal(t, "virus.exe", response.Data.Errors[0].Filename)
+			assert.Equal(t, errs.ErrKeyValidationError, response.Data.Errors[0].Error.ErrorKey)
+			assert.Equal(t, http.StatusBadRequest, response.Data.Errors[0].Error.Status)
+
+			// Assert: only the valid files were recorded
+			var count int
+			require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM uploads WHERE user_id = $1", user.ID).Scan(&count))
+			assert.Equal(t, 2, count)
+		})
+	})
+
+	// The file count is checked before anything is stored
+	t.Run("should return 400 when there are too many files", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		names := make([]string, 11)
+		for i := range names {
+			names[i] = fmt.Sprintf("photo-%d.jpg", i)
+		}
+		body, contentType := newBulkBody(t, names...)
+
+		req := server.NewRequest("POST", "/api/v1/uploads/bulk", body)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", contentType)
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var errBody errs.ErrorResponse
+		require.NoError(t, resp.JSON(&errBody))
+		assert.Equal(t, errs.ErrKeyUploadTooManyFiles, errBody.ErrorKey)
+		assert.EqualValues(t, 10, errBody.Details["max_files"])
+	})
+
+	t.Run("should return 400 without files", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		body, contentType := newBulkBody(t)
+
+		req := server.NewRequest("POST", "/api/v1/uploads/bulk", body)
+		req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, 1))
+		req.Header.Set("Content-Type", contentType)
+		resp := server.Do(req)
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
 }
 
-// Note: GetUpload, ListUploads, and DeleteUpload are service methods only
+// Note: ListUploads and DeleteUpload are service methods only
 // They are not exposed as HTTP endpoints but can be used internally by other services
+
+func TestUploadAPI_GetUpload(t *testing.T) {
+	t.Run("should return a signed URL that downloads the file", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			filePath := filepath.Join(server.UploadFolder(), upload.RelativePath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+			require.NoError(t, os.WriteFile(filePath, []byte("signed content"), 0o644))
+
+			req := server.NewRequest("GET", fmt.Sprintf("/api/v1/uploads/%d", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+
+			var response uploads.UploadDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.Contains(t, response.Data.SignedURL, "expires=")
+			assert.Contains(t, response.Data.SignedURL, "signature=")
+
+			// No token: the signature is the only credential
+			resp = server.Do(server.NewRequest("GET", response.Data.SignedURL, nil))
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "signed content", resp.String())
+		})
+	})
+
+	t.Run("should return 404 for another user's upload", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			upload := helpers.CreateTestUpload(t, ctx, tx, other.ID)
+
+			req := server.NewRequest("GET", fmt.Sprintf("/api/v1/uploads/%d", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
+
+func TestUploadAPI_HeadUpload(t *testing.T) {
+	t.Run("should return the upload's headers without a body", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+
+			// No file on disk: the headers come from the record alone
+			req := server.NewRequest("HEAD", fmt.Sprintf("/api/v1/uploads/%d", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			resp := server.Do(req)
+
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			assert.Equal(t, "1024", resp.Header.Get("Content-Length"))
+			assert.Equal(t, "image/jpeg", resp.Header.Get("Content-Type"))
+			assert.Equal(t, upload.UpdatedAt.Time.UTC().Format(http.TimeFormat), resp.Header.Get("Last-Modified"))
+			assert.Empty(t, resp.Body)
+		})
+	})
+
+	t.Run("should return 404 for another user's upload", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			upload := helpers.CreateTestUpload(t, ctx, tx, other.ID)
+
+			req := server.NewRequest("HEAD", fmt.Sprintf("/api/v1/uploads/%d", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+			assert.Empty(t, resp.Body)
+		})
+	})
+}
+
+func TestUploadAPI_DownloadUpload(t *testing.T) {
+	t.Run("should return 410 once the file is deleted from disk", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			filePath := filepath.Join(server.UploadFolder(), upload.RelativePath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+			require.NoError(t, os.WriteFile(filePath, []byte("file content"), 0o644))
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID)
+
+			req := server.NewRequest("GET", fmt.Sprintf("/api/v1/uploads/%d/download", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp := server.Do(req)
+			require.Equal(t, http.StatusOK, resp.StatusCode, resp.String())
+			assert.Equal(t, "file content", resp.String())
+
+			// Deleted out-of-band, the row is still there
+			require.NoError(t, os.Remove(filePath))
+
+			req = server.NewRequest("GET", fmt.Sprintf("/api/v1/uploads/%d/download", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			resp = server.Do(req)
+
+			assert.Equal(t, http.StatusGone, resp.StatusCode)
+			var errBody errs.ErrorResponse
+			require.NoError(t, resp.JSON(&errBody))
+			assert.Equal(t, errs.ErrKeyUploadFileGone, errBody.ErrorKey)
+
+			// Assert: the upload was flagged as missing
+			var missing bool
+			require.NoError(t, tx.QueryRow(ctx, "SELECT missing_at IS NOT NULL FROM uploads WHERE id = $1", upload.ID).Scan(&missing))
+			assert.True(t, missing)
+		})
+	})
+
+	t.Run("should return 404 for another user's upload", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			upload := helpers.CreateTestUpload(t, ctx, tx, other.ID)
+
+			req := server.NewRequest("GET", fmt.Sprintf("/api/v1/uploads/%d/download", upload.ID), nil)
+			req.Header.Set("Authorization", "Bearer "+helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID))
+			resp := server.Do(req)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		})
+	})
+}
+
+// Signed file links are checked without a database: only the signature and the disk are involved
+func TestUploadAPI_ServeSignedFile(t *testing.T) {
+	server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+	defer server.Close()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(server.UploadFolder(), "7"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(server.UploadFolder(), "7", "report.txt"), []byte("report"), 0o644))
+
+	storage := uploads.NewLocalStorage("/api/v1/files", []byte(helpers.TestJWTSecret))
+
+	t.Run("should serve the file with a valid link", func(t *testing.T) {
+		link, err := storage.GetSignedURL("7/report.txt", time.Minute)
+		require.NoError(t, err)
+
+		resp := server.Do(server.NewRequest("GET", link, nil))
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "report", resp.String())
+	})
+
+	t.Run("should reject an expired link", func(t *testing.T) {
+		link, err := storage.GetSignedURL("7/report.txt", -time.Minute)
+		require.NoError(t, err)
+
+		resp := server.Do(server.NewRequest("GET", link, nil))
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, resp.String(), errs.ErrKeySignedURLExpired)
+	})
+
+	t.Run("should reject a link signed for another file", func(t *testing.T) {
+		link, err := storage.GetSignedURL("7/other.txt", time.Minute)
+		require.NoError(t, err)
+
+		resp := server.Do(server.NewRequest("GET", strings.Replace(link, "other.txt", "report.txt", 1), nil))
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Contains(t, resp.String(), errs.ErrKeySignedURLInvalid)
+	})
+
+	t.Run("should return 404 for a missing file", func(t *testing.T) {
+		link, err := storage.GetSignedURL("7/missing.txt", time.Minute)
+		require.NoError(t, err)
+
+		resp := server.Do(server.NewRequest("GET", link, nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
+
+func TestUploadAPI_RenameUpload(t *testing.T) {
+	renameUpload := func(server *helpers.TestServer, token string, uploadID int32, body string) *helpers.TestResponse {
+		req := server.NewRequest("PATCH", fmt.Sprintf("/api/v1/uploads/%d", uploadID), helpers.StringToReadCloser(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		return server.Do(req)
+	}
+
+	t.Run("should return 200 with the new filename", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID)
+
+			resp := renameUpload(server, token, upload.ID, `{"original_filename": "holiday photo.jpg"}`)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response uploads.UploadDataResponse
+			require.NoError(t, resp.JSON(&response))
+			assert.EqualValues(t, upload.ID, response.Data.ID)
+			assert.Equal(t, "holiday photo.jpg", response.Data.OriginalFilename)
+			// Assert: the stored file is not moved
+			assert.Equal(t, upload.RelativePath, response.Data.RelativePath)
+		})
+	})
+
+	t.Run("should return 404 for another user's upload", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			owner := helpers.CreateTestUser(t, ctx, tx)
+			upload := helpers.CreateTestUpload(t, ctx, tx, owner.ID)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			token := helpers.SignTestToken(t, helpers.TestJWTSecret, other.ID)
+
+			resp := renameUpload(server, token, upload.ID, `{"original_filename": "mine-now.jpg"}`)
+
+			assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+			var body errs.ErrorResponse
+			require.NoError(t, resp.JSON(&body))
+			assert.Equal(t, errs.ErrKeyUploadNotFound, body.ErrorKey)
+		})
+	})
+
+	// Invalid names are rejected before the database is queried
+	t.Run("should return 400 for an invalid filename", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 1)
+
+		for _, name := range []string{"   ", "../secret.txt", `dir\file.txt`, ".."} {
+			body, err := json.Marshal(map[string]string{"original_filename": name})
+			require.NoError(t, err)
+
+			resp := renameUpload(server, token, 1, string(body))
+
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "name %q", name)
+
+			var errBody errs.ErrorResponse
+			require.NoError(t, resp.JSON(&errBody))
+			assert.Equal(t, errs.ErrKeyUploadInvalidFilename, errBody.ErrorKey, "name %q", name)
+		}
+
+		resp := renameUpload(server, token, 1, `{"original_filename": ""}`)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestUploadAPI_DeleteUploads(t *testing.T) {
+	deleteUploads := func(server *helpers.TestServer, token, body string) *helpers.TestResponse {
+		req := server.NewRequest("DELETE", "/api/v1/uploads", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		return server.Do(req)
+	}
+
+	t.Run("should delete owned uploads and report the others", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			server := helpers.CreateTestServer(t, ctx, tx, queries)
+			defer server.Close()
+
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			onDisk := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			missing := helpers.CreateTestUpload(t, ctx, tx, user.ID) // file never written
+			theirs := helpers.CreateTestUpload(t, ctx, tx, other.ID)
+
+			filePath := filepath.Join(server.UploadFolder(), onDisk.RelativePath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+			require.NoError(t, os.WriteFile(filePath, []byte("photo"), 0o644))
+
+			body := fmt.Sprintf(`{"ids": [%d, %d, %d]}`, onDisk.ID, theirs.ID, missing.ID)
+			resp := deleteUploads(server, helpers.SignTestToken(t, helpers.TestJWTSecret, user.ID), body)
+
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var response uploads.BulkDeleteDataResponse
+			require.NoError(t, resp.JSON(&response))
+
+			assert.Equal(t, 2, response.Data.Deleted)
+			assert.Equal(t, 1, response.Data.Failed)
+			require.Len(t, response.Data.Results, 3)
+
+			assert.EqualValues(t, onDisk.ID, response.Data.Results[0].ID)
+			assert.True(t, response.Data.Results[0].Deleted)
+			assert.Nil(t, response.Data.Results[0].Error)
+
+			assert.EqualValues(t, theirs.ID, response.Data.Results[1].ID)
+			assert.False(t, response.Data.Results[1].Deleted)
+			require.NotNil(t, response.Data.Results[1].Error)
+			assert.Equal(t, errs.ErrKeyUploadNotFound, response.Data.Results[1].Error.ErrorKey)
+
+			assert.EqualValues(t, missing.ID, response.Data.Results[2].ID)
+			assert.True(t, response.Data.Results[2].Deleted, "a file already gone from disk should not fail the delete")
+
+			// Assert: the owned rows and file are gone, the other user's upload is untouched
+			var count int
+			require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM uploads WHERE user_id = $1", user.ID).Scan(&count))
+			assert.Equal(t, 0, count)
+			_, err := queries.GetUploadByID(ctx, theirs.ID)
+			assert.NoError(t, err)
+			assert.NoFileExists(t, filePath)
+		})
+	})
+
+	// The ID count is checked before the database is queried
+	t.Run("should return 400 when there are too many IDs", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		ids := make([]int, 101)
+		for i := range ids {
+			ids[i] = i + 1
+		}
+		body, err := json.Marshal(map[string][]int{"ids": ids})
+		require.NoError(t, err)
+
+		resp := deleteUploads(server, helpers.SignTestToken(t, helpers.TestJWTSecret, 1), string(body))
+
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+		var errBody errs.ErrorResponse
+		require.NoError(t, resp.JSON(&errBody))
+		assert.Equal(t, errs.ErrKeyUploadTooManyFiles, errBody.ErrorKey)
+		assert.EqualValues(t, 100, errBody.Details["max_files"])
+	})
+
+	t.Run("should return 400 without IDs", func(t *testing.T) {
+		server := helpers.CreateTestServer(t, context.Background(), nil, nil)
+		defer server.Close()
+
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 1)
+		for _, body := range []string{`{}`, `{"ids": []}`, `{"ids": [0]}`} {
+			resp := deleteUploads(server, token, body)
+			assert.Equal(t, http.StatusBadRequest, resp.StatusCode, "body %s", body)
+		}
+	})
+}
@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/apikeys"
+	"app/internal/auth"
+	"app/internal/errs"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeAPIKeyVerifier map[string]int32
+
+func (f fakeAPIKeyVerifier) VerifyAPIKey(ctx context.Context, key string) (int32, error) {
+	if userID, ok := f[key]; ok {
+		return userID, nil
+	}
+	return 0, apikeys.ErrAPIKeyInvalid
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, err := apikeys.GenerateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, apikeys.KeyPrefix))
+	assert.True(t, strings.HasPrefix(key, prefix))
+	assert.Less(t, len(prefix), len(key))
+
+	other, _, err := apikeys.GenerateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestHashAPIKey(t *testing.T) {
+	assert.Equal(t, apikeys.HashAPIKey("gk_abc"), apikeys.HashAPIKey("gk_abc"))
+	assert.NotEqual(t, apikeys.HashAPIKey("gk_abc"), apikeys.HashAPIKey("gk_abd"))
+	assert.Len(t, apikeys.HashAPIKey("gk_abc"), 64)
+}
+
+func TestAPIKeyService_VerifyAPIKeyRejectsForeignFormat(t *testing.T) {
+	// Keys without the prefix are rejected before touching the database
+	service := apikeys.NewAPIKeyService(nil)
+
+	_, err := service.VerifyAPIKey(context.Background(), "not-an-api-key")
+
+	assert.ErrorIs(t, err, apikeys.ErrAPIKeyInvalid)
+}
+
+func TestUserOrAPIKeyAuthMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	authService := auth.NewAuthService(nil, nil, []byte(helpers.TestJWTSecret), helpers.GetTestLogger(t))
+	router := gin.New()
+	router.Use(middleware.UserOrAPIKeyAuthMiddleware(authService, fakeAPIKeyVerifier{"gk_valid": 42}))
+	router.GET("/me", func(c *gin.Context) {
+		userID, _ := middleware.GetUserIDFromContext(c)
+		c.JSON(http.StatusOK, gin.H{"user_id": userID, "admin": middleware.HasRole(c, middleware.RoleAdmin)})
+	})
+
+	request := func(setup func(r *http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		setup(req)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should authenticate with a valid API key", func(t *testing.T) {
+		w := request(func(r *http.Request) { r.Header.Set(middleware.APIKeyHeader, "gk_valid") })
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id":42,"admin":false}`, w.Body.String())
+	})
+
+	t.Run("should reject an invalid API key without falling back to JWT", func(t *testing.T) {
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 7)
+		w := request(func(r *http.Request) {
+			r.Header.Set(middleware.APIKeyHeader, "gk_revoked")
+			r.Header.Set("Authorization", "Bearer "+token)
+		})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyAPIKeyInvalid)
+	})
+
+	t.Run("should fall back to JWT without an API key", func(t *testing.T) {
+		token := helpers.SignTestToken(t, helpers.TestJWTSecret, 7, middleware.RoleAdmin)
+		w := request(func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) })
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"user_id":7,"admin":true}`, w.Body.String())
+	})
+
+	t.Run("should require credentials", func(t *testing.T) {
+		w := request(func(r *http.Request) {})
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.APIVersion("1", "2"))
+	router.GET("/items", func(c *gin.Context) {
+		c.String(http.StatusOK, "version "+middleware.GetAPIVersion(c))
+	})
+
+	request := func(headers map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/items", nil)
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should use a supported version from the header", func(t *testing.T) {
+		w := request(map[string]string{middleware.APIVersionHeader: "1"})
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "version 1", w.Body.String())
+		assert.Equal(t, "1", w.Header().Get(middleware.APIVersionHeader))
+	})
+
+	t.Run("should read the version parameter of Accept", func(t *testing.T) {
+		w := request(map[string]string{"Accept": "text/html, application/json; version=1"})
+
+		assert.Equal(t, "version 1", w.Body.String())
+	})
+
+	t.Run("should prefer the header over Accept", func(t *testing.T) {
+		w := request(map[string]string{middleware.APIVersionHeader: "2", "Accept": "application/json; version=1"})
+
+		assert.Equal(t, "version 2", w.Body.String())
+	})
+
+	t.Run("should default to the latest version", func(t *testing.T) {
+		w := request(nil)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "version 2", w.Body.String())
+		assert.Equal(t, "2", w.Header().Get(middleware.APIVersionHeader))
+	})
+
+	t.Run("should reject an unknown version", func(t *testing.T) {
+		for _, headers := range []map[string]string{
+			{middleware.APIVersionHeader: "3"},
+			{"Accept": "application/json; version=0"},
+		} {
+			w := request(headers)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+
+			var body errs.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, errs.ErrKeyUnsupportedAPIVersion, body.ErrorKey)
+			assert.Equal(t, []interface{}{"1", "2"}, body.Details["supported_versions"])
+		}
+	})
+}
@@ -2,10 +2,13 @@ package unit
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
+	"app/config"
 	"app/internal/auth"
+	"app/internal/cache"
 	"app/internal/db"
 	"app/tests/helpers"
 
@@ -20,7 +23,7 @@ func TestAuthService_Register(t *testing.T) {
 			// Setup: Create auth service
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Register user
 			req := auth.RegisterRequest{
@@ -50,7 +53,7 @@ func TestAuthService_Register(t *testing.T) {
 
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Try to register with same email
 			req := auth.RegisterRequest{
@@ -70,13 +73,108 @@ func TestAuthService_Register(t *testing.T) {
 	})
 }
 
+func TestAuthService_RegisterRollsBack(t *testing.T) {
+	t.Run("should not keep the user when storing the refresh token fails", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			// Setup: Queries whose refresh token insert fails after the user row was written
+			failing := db.New(failingTx{Tx: tx, failOn: "INSERT INTO refresh_tokens"})
+			service := auth.NewAuthService(failing, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+			// Test: Register user
+			tokenPair, user, err := service.Register(ctx, auth.RegisterRequest{
+				Email:    "partial@example.com",
+				Name:     "Partial User",
+				Password: "password123",
+			})
+
+			// Assert: The error surfaces and the user insert was rolled back
+			assert.ErrorIs(t, err, errInjected)
+			assert.Nil(t, tokenPair)
+			assert.Nil(t, user)
+			assert.Equal(t, 0, countUsersWithEmail(t, ctx, tx, "partial@example.com"))
+		})
+	})
+}
+
+func TestAuthService_DeleteAccountRollsBack(t *testing.T) {
+	t.Run("should keep the uploads when deleting the examples fails", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			upload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			helpers.CreateTestExample(t, ctx, tx, user.ID)
+
+			// Setup: Queries whose example delete fails after the upload service deleted the uploads
+			failing := db.New(failingTx{Tx: tx, failOn: "DELETE FROM examples"})
+			service := auth.NewAuthService(failing, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+			err := service.DeleteAccount(ctx, user.ID, "password123")
+
+			// Assert: The error surfaces and neither the uploads nor the user were deleted
+			assert.ErrorIs(t, err, errInjected)
+			_, err = queries.GetUploadByID(ctx, upload.ID)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, countUsersWithEmail(t, ctx, tx, user.Email))
+		})
+	})
+}
+
+func TestAuthService_CreateUser(t *testing.T) {
+	t.Run("should create a user with extra roles who can log in", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := auth.NewAuthService(queries, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+			user, err := service.CreateUser(ctx, auth.RegisterRequest{
+				Email:    "admin@example.com",
+				Name:     "Admin",
+				Password: "password123",
+			}, []string{auth.DefaultRole, "admin"})
+
+			require.NoError(t, err)
+			assert.True(t, user.ID > 0)
+			assert.Empty(t, user.Password)
+			stored, err := queries.GetUserByID(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"user", "admin"}, stored.Roles)
+			assert.NotEqual(t, "password123", stored.Password)
+
+			_, loggedIn, err := service.Login(ctx, auth.LoginRequest{Email: "admin@example.com", Password: "password123"})
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, loggedIn.ID)
+		})
+	})
+
+	t.Run("should keep the default role without extra roles", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := auth.NewAuthService(queries, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+			user, err := service.CreateUser(ctx, auth.RegisterRequest{Email: "user@example.com", Name: "User", Password: "password123"}, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{auth.DefaultRole}, user.Roles)
+		})
+	})
+
+	t.Run("should return ErrUserAlreadyExists for a taken email", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := auth.NewAuthService(queries, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+			req := auth.RegisterRequest{Email: "user@example.com", Name: "User", Password: "password123"}
+			_, err := service.CreateUser(ctx, req, nil)
+			require.NoError(t, err)
+
+			_, err = service.CreateUser(ctx, req, []string{auth.DefaultRole, "admin"})
+
+			assert.ErrorIs(t, err, auth.ErrUserAlreadyExists)
+		})
+	})
+}
+
 func TestAuthService_Login(t *testing.T) {
 	t.Run("should login successfully with valid credentials", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
 			// Setup: Register a user first
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			registerReq := auth.RegisterRequest{
 				Email:    "user@example.com",
@@ -106,12 +204,54 @@ func TestAuthService_Login(t *testing.T) {
 		})
 	})
 
+	t.Run("should rehash a bcrypt password with argon2id on login", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			// Setup: A user stored with a bcrypt hash, and PASSWORD_HASHER switched to argon2id
+			existing := helpers.CreateTestUser(t, ctx, tx)
+			require.True(t, strings.HasPrefix(existing.Password, "$2a$"))
+
+			cfg := &config.Config{JWTSecret: "test-secret-key", PasswordHasher: auth.HasherArgon2id}
+			service, err := auth.NewAuthServiceFromConfig(cfg, queries, nil, nil, helpers.GetTestLogger(t))
+			require.NoError(t, err)
+
+			// Test: The old hash still logs in, and is replaced on the way
+			_, _, err = service.Login(ctx, auth.LoginRequest{Email: existing.Email, Password: "password123"})
+			require.NoError(t, err)
+
+			stored, err := queries.GetUserByEmail(ctx, existing.Email)
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(stored.Password, "$argon2id$"), stored.Password)
+
+			// Assert: The new hash logs in too and isn't rehashed again
+			_, _, err = service.Login(ctx, auth.LoginRequest{Email: existing.Email, Password: "password123"})
+			require.NoError(t, err)
+
+			again, err := queries.GetUserByEmail(ctx, existing.Email)
+			require.NoError(t, err)
+			assert.Equal(t, stored.Password, again.Password)
+		})
+	})
+
+	t.Run("should keep the bcrypt hash when bcrypt is configured", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			existing := helpers.CreateTestUser(t, ctx, tx)
+			service := auth.NewAuthService(queries, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+			_, _, err := service.Login(ctx, auth.LoginRequest{Email: existing.Email, Password: "password123"})
+			require.NoError(t, err)
+
+			stored, err := queries.GetUserByEmail(ctx, existing.Email)
+			require.NoError(t, err)
+			assert.Equal(t, existing.Password, stored.Password)
+		})
+	})
+
 	t.Run("should return error with invalid email", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
 			// Setup: Create auth service
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Login with non-existent email
 			loginReq := auth.LoginRequest{
@@ -134,7 +274,7 @@ func TestAuthService_Login(t *testing.T) {
 			// Setup: Register a user first
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			registerReq := auth.RegisterRequest{
 				Email:    "user@example.com",
@@ -160,6 +300,27 @@ func TestAuthService_Login(t *testing.T) {
 			assert.Nil(t, user)
 		})
 	})
+
+	t.Run("should store the client of the session when the context carries one", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := auth.NewAuthService(queries, nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+			_, user, err := service.Register(ctx, auth.RegisterRequest{Email: "user@example.com", Name: "Test User", Password: "password123"})
+			require.NoError(t, err)
+
+			clientCtx := auth.WithClientInfo(ctx, auth.ClientInfo{UserAgent: "TestAgent/1.0", IPAddress: "203.0.113.7"})
+			_, _, err = service.Login(clientCtx, auth.LoginRequest{Email: "user@example.com", Password: "password123"})
+			require.NoError(t, err)
+
+			sessions, err := queries.ListRefreshTokensByUser(ctx, user.ID)
+			require.NoError(t, err)
+			require.Len(t, sessions, 2)
+			// Newest first: the login carried client info, the registration without it left both NULL
+			assert.Equal(t, "TestAgent/1.0", sessions[0].UserAgent.String)
+			assert.Equal(t, "203.0.113.7", sessions[0].IpAddress.String)
+			assert.False(t, sessions[1].UserAgent.Valid)
+			assert.False(t, sessions[1].IpAddress.Valid)
+		})
+	})
 }
 
 func TestAuthService_RefreshToken(t *testing.T) {
@@ -168,7 +329,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 			// Setup: Register a user and get tokens
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			registerReq := auth.RegisterRequest{
 				Email:    "user@example.com",
@@ -198,7 +359,7 @@ func TestAuthService_RefreshToken(t *testing.T) {
 			// Setup: Create auth service
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Refresh with invalid token
 			tokenPair, err := service.RefreshToken(ctx, "invalid-token")
@@ -217,7 +378,7 @@ func TestAuthService_VerifyJWT(t *testing.T) {
 			// Setup: Register a user and get token
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			registerReq := auth.RegisterRequest{
 				Email:    "user@example.com",
@@ -243,7 +404,7 @@ func TestAuthService_VerifyJWT(t *testing.T) {
 			// Setup: Create auth service
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Verify invalid token
 			token, err := service.VerifyJWT("invalid-token")
@@ -264,7 +425,7 @@ func TestAuthService_GetUserFromContext(t *testing.T) {
 
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Get user by ID
 			resultUser, err := service.GetUserFromContext(ctx, user.ID)
@@ -282,7 +443,7 @@ func TestAuthService_GetUserFromContext(t *testing.T) {
 			// Setup: Create auth service
 			jwtSecret := []byte("test-secret-key")
 			testLogger := helpers.GetTestLogger(t)
-			service := auth.NewAuthService(queries, jwtSecret, testLogger)
+			service := auth.NewAuthService(queries, nil, jwtSecret, testLogger)
 
 			// Test: Get non-existent user
 			user, err := service.GetUserFromContext(ctx, 99999)
@@ -294,3 +455,113 @@ func TestAuthService_GetUserFromContext(t *testing.T) {
 		})
 	})
 }
+
+// userLookupDB answers single-row user queries with a fixed user and counts them, standing in for Postgres
+type userLookupDB struct {
+	db.DBTX
+	user    db.User
+	lookups int
+}
+
+func (d *userLookupDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	d.lookups++
+	return userRow{user: d.user}
+}
+
+type userRow struct{ user db.User }
+
+func (r userRow) Scan(dest ...interface{}) error {
+	*dest[0].(*int32) = r.user.ID
+	*dest[1].(*string) = r.user.Email
+	*dest[2].(*string) = r.user.Name
+	*dest[3].(*string) = r.user.Password
+	*dest[4].(*[]string) = r.user.Roles
+	return nil
+}
+
+func TestAuthService_GetUserFromContextCache(t *testing.T) {
+	stored := db.User{ID: 7, Email: "cached@example.com", Name: "Cached User", Password: "hashed", Roles: []string{"admin"}}
+
+	t.Run("should serve the second lookup from the cache", func(t *testing.T) {
+		database := &userLookupDB{user: stored}
+		userCache := cache.NewMemoryCache()
+		service := auth.NewAuthService(db.New(database), userCache, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+		first, err := service.GetUserFromContext(context.Background(), stored.ID)
+		require.NoError(t, err)
+		second, err := service.GetUserFromContext(context.Background(), stored.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, database.lookups)
+		assert.Equal(t, first, second)
+		assert.Equal(t, "cached@example.com", second.Email)
+		assert.Equal(t, []string{"admin"}, second.Roles)
+
+		has, err := userCache.Has(context.Background(), auth.UserCacheKey(stored.ID))
+		require.NoError(t, err)
+		assert.True(t, has)
+	})
+
+	t.Run("should never return or cache the password hash", func(t *testing.T) {
+		userCache := cache.NewMemoryCache()
+		service := auth.NewAuthService(db.New(&userLookupDB{user: stored}), userCache, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+		user, err := service.GetUserFromContext(context.Background(), stored.ID)
+		require.NoError(t, err)
+		assert.Empty(t, user.Password)
+
+		var cached db.User
+		require.NoError(t, userCache.Get(context.Background(), auth.UserCacheKey(stored.ID), &cached))
+		assert.Empty(t, cached.Password)
+	})
+
+	t.Run("should drop the cached user after a profile update", func(t *testing.T) {
+		database := &userLookupDB{user: stored}
+		service := auth.NewAuthService(db.New(database), cache.NewMemoryCache(), []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+		_, err := service.GetUserFromContext(context.Background(), stored.ID)
+		require.NoError(t, err)
+
+		updated, err := service.UpdateProfile(context.Background(), stored.ID, stored.Name, stored.Email)
+		require.NoError(t, err)
+		assert.Empty(t, updated.Password)
+
+		_, err = service.GetUserFromContext(context.Background(), stored.ID)
+		require.NoError(t, err)
+
+		// Lookup, update, then a fresh lookup because the cached copy was dropped
+		assert.Equal(t, 3, database.lookups)
+	})
+
+	t.Run("should query every time without a cache", func(t *testing.T) {
+		database := &userLookupDB{user: stored}
+		service := auth.NewAuthService(db.New(database), nil, []byte("test-secret-key"), helpers.GetTestLogger(t))
+
+		for i := 0; i < 2; i++ {
+			_, err := service.GetUserFromContext(context.Background(), stored.ID)
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, 2, database.lookups)
+	})
+}
+
+func TestAuthService_NewAuthServiceFromConfig(t *testing.T) {
+	t.Run("should fail fast when the secret is empty", func(t *testing.T) {
+		service, err := auth.NewAuthServiceFromConfig(&config.Config{Environment: "production"}, nil, nil, nil, helpers.GetTestLogger(t))
+
+		assert.Error(t, err)
+		assert.Nil(t, service)
+	})
+
+	t.Run("should verify tokens signed with the configured secret only", func(t *testing.T) {
+		service, err := auth.NewAuthServiceFromConfig(&config.Config{JWTSecret: "configured-secret"}, nil, nil, nil, helpers.GetTestLogger(t))
+		require.NoError(t, err)
+
+		_, err = service.VerifyJWT(helpers.SignTestToken(t, "configured-secret", 1))
+		assert.NoError(t, err)
+
+		_, err = service.VerifyJWT(helpers.SignTestToken(t, "other-secret", 1))
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+}
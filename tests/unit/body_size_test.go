@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	const limit = 64
+
+	bindTitle := func(c *gin.Context) {
+		var req struct {
+			Title string `json:"title" binding:"required"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errs.RespondWithValidationError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"title": req.Title})
+	}
+
+	newRouter := func() *gin.Engine {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.MaxBodySize(limit))
+		router.POST("/items", bindTitle)
+		router.POST("/large", middleware.MaxBodySize(4*limit), bindTitle)
+		router.POST("/small", middleware.MaxBodySize(limit/2), bindTitle)
+		return router
+	}
+
+	// bodyOfSize builds a JSON body of exactly size bytes
+	bodyOfSize := func(size int) string {
+		wrapper := `{"title":""}`
+		return `{"title":"` + strings.Repeat("a", size-len(wrapper)) + `"}`
+	}
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		newRouter().ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should accept a body exactly at the limit", func(t *testing.T) {
+		resp := post("/items", bodyOfSize(limit))
+
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("should return 413 request_too_large one byte over the limit", func(t *testing.T) {
+		resp := post("/items", bodyOfSize(limit+1))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+
+		var body errs.ErrorResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+		assert.Equal(t, errs.ErrKeyRequestTooLarge, body.ErrorKey)
+		assert.EqualValues(t, limit, body.Details["max_bytes"])
+	})
+
+	t.Run("should let a route raise the global limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("/large", bodyOfSize(2*limit)).Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, post("/large", bodyOfSize(4*limit+1)).Code)
+	})
+
+	t.Run("should let a route lower the global limit", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, post("/small", bodyOfSize(limit/2)).Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, post("/small", bodyOfSize(limit/2+1)).Code)
+	})
+}
@@ -0,0 +1,297 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/cache"
+	"app/internal/metrics"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should round-trip values", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		require.NoError(t, c.Set(ctx, "user:1", map[string]string{"name": "Ada"}, time.Minute))
+
+		var dest map[string]string
+		require.NoError(t, c.Get(ctx, "user:1", &dest))
+		assert.Equal(t, "Ada", dest["name"])
+	})
+
+	t.Run("should return ErrKeyNotFound for missing and expired keys", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		require.NoError(t, c.Set(ctx, "short", "value", time.Millisecond))
+		time.Sleep(5 * time.Millisecond)
+
+		var dest string
+		assert.ErrorIs(t, c.Get(ctx, "missing", &dest), cache.ErrKeyNotFound)
+		assert.ErrorIs(t, c.Get(ctx, "short", &dest), cache.ErrKeyNotFound)
+	})
+
+	t.Run("should only call Remember callback on a miss", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		calls := 0
+		callback := func() (interface{}, error) {
+			calls++
+			return 42, nil
+		}
+
+		var first, second int
+		require.NoError(t, c.Remember(ctx, "answer", time.Minute, callback, &first))
+		require.NoError(t, c.Remember(ctx, "answer", time.Minute, callback, &second))
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, 42, second)
+	})
+
+	t.Run("should flush only keys with the given prefix", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		require.NoError(t, c.Set(ctx, "cities:list:page=1", 1, 0))
+		require.NoError(t, c.Set(ctx, "cities:list:page=2", 2, 0))
+		require.NoError(t, c.Set(ctx, "user:1", 3, 0))
+
+		require.NoError(t, c.FlushPrefix(ctx, "cities:list:"))
+
+		hasPage, _ := c.Has(ctx, "cities:list:page=1")
+		hasUser, _ := c.Has(ctx, "user:1")
+		assert.False(t, hasPage)
+		assert.True(t, hasUser)
+	})
+}
+
+func TestCachedList(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should build keys from the namespace and sorted params", func(t *testing.T) {
+		assert.Equal(t, "cities:list:page=2:size=20", cache.ListKey("cities:list", cache.Params{"size": 20, "page": 2}))
+		assert.Equal(t, "cities:list:all", cache.ListKey("cities:list", nil))
+	})
+
+	t.Run("should serve the second request for the same params from cache", func(t *testing.T) {
+		list := cache.NewCachedList[[]string](cache.NewMemoryCache(), "names", time.Minute)
+		calls := 0
+		load := func() ([]string, error) {
+			calls++
+			return []string{"Ada", "Grace"}, nil
+		}
+
+		first, err := list.Get(ctx, cache.Params{"page": 1}, load)
+		require.NoError(t, err)
+		second, err := list.Get(ctx, cache.Params{"page": 1}, load)
+		require.NoError(t, err)
+		_, err = list.Get(ctx, cache.Params{"page": 2}, load)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"Ada", "Grace"}, first)
+		assert.Equal(t, first, second)
+		assert.Equal(t, 2, calls, "page 1 should be loaded once, page 2 once")
+	})
+
+	t.Run("should not cache load errors", func(t *testing.T) {
+		list := cache.NewCachedList[[]string](cache.NewMemoryCache(), "names", time.Minute)
+		calls := 0
+
+		_, err := list.Get(ctx, nil, func() ([]string, error) {
+			calls++
+			return nil, errInjected
+		})
+		assert.ErrorIs(t, err, errInjected)
+
+		names, err := list.Get(ctx, nil, func() ([]string, error) {
+			calls++
+			return []string{"Ada"}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Ada"}, names)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("should invalidate every list in the namespace only", func(t *testing.T) {
+		memoryCache := cache.NewMemoryCache()
+		names := cache.NewCachedList[[]string](memoryCache, "names", time.Minute)
+		counts := cache.NewCachedList[int](memoryCache, "names", time.Minute)
+		others := cache.NewCachedList[int](memoryCache, "others", time.Minute)
+
+		_, err := names.Get(ctx, nil, func() ([]string, error) { return []string{"Ada"}, nil })
+		require.NoError(t, err)
+		_, err = counts.Get(ctx, cache.Params{"page": 1}, func() (int, error) { return 1, nil })
+		require.NoError(t, err)
+		_, err = others.Get(ctx, nil, func() (int, error) { return 1, nil })
+		require.NoError(t, err)
+
+		require.NoError(t, names.Invalidate(ctx))
+
+		for key, want := range map[string]bool{
+			names.Key(nil):                      false,
+			counts.Key(cache.Params{"page": 1}): false,
+			others.Key(nil):                     true,
+		} {
+			has, err := memoryCache.Has(ctx, key)
+			require.NoError(t, err)
+			assert.Equal(t, want, has, key)
+		}
+	})
+}
+
+func TestRedisCache_FlushPrefix(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	c := cache.NewRedisCache(client, "app:")
+	require.NoError(t, c.Set(ctx, "cities:list:page=1", 1, time.Minute))
+	require.NoError(t, c.Set(ctx, "cities:list:page=2", 2, time.Minute))
+	require.NoError(t, c.Set(ctx, "user:1", 3, time.Minute))
+
+	require.NoError(t, c.FlushPrefix(ctx, "cities:list:"))
+
+	hasPage, err := c.Has(ctx, "cities:list:page=2")
+	require.NoError(t, err)
+	hasUser, err := c.Has(ctx, "user:1")
+	require.NoError(t, err)
+	assert.False(t, hasPage)
+	assert.True(t, hasUser)
+}
+
+func TestCache_SetNX(t *testing.T) {
+	ctx := context.Background()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	caches := map[string]cache.Cache{
+		"memory": cache.NewMemoryCache(),
+		"redis":  cache.NewRedisCache(client, "app:"),
+	}
+	for name, c := range caches {
+		t.Run(name+" should only store the first value", func(t *testing.T) {
+			stored, err := c.SetNX(ctx, "claim", "first", time.Minute)
+			require.NoError(t, err)
+			assert.True(t, stored)
+
+			stored, err = c.SetNX(ctx, "claim", "second", time.Minute)
+			require.NoError(t, err)
+			assert.False(t, stored)
+
+			var dest string
+			require.NoError(t, c.Get(ctx, "claim", &dest))
+			assert.Equal(t, "first", dest)
+		})
+	}
+
+	t.Run("memory should store again once the key expired", func(t *testing.T) {
+		c := cache.NewMemoryCache()
+		_, err := c.SetNX(ctx, "claim", "first", time.Millisecond)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		stored, err := c.SetNX(ctx, "claim", "second", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, stored)
+	})
+}
+
+func TestCache_Ping(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should succeed against a healthy Redis", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		defer client.Close()
+
+		assert.NoError(t, cache.NewRedisCache(client, "app:").Ping(ctx))
+	})
+
+	t.Run("should fail once the Redis client is closed", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		require.NoError(t, client.Close())
+
+		assert.Error(t, cache.NewRedisCache(client, "app:").Ping(ctx))
+	})
+
+	t.Run("should pass through wrappers", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		defer client.Close()
+		c := cache.NewStatsCache(cache.NewRedisCache(client, "app:"))
+
+		require.NoError(t, c.Ping(ctx))
+		server.Close()
+		assert.Error(t, c.Ping(ctx))
+	})
+
+	t.Run("should always succeed in memory", func(t *testing.T) {
+		assert.NoError(t, cache.NewMemoryCache().Ping(ctx))
+	})
+}
+
+func TestStatsCache(t *testing.T) {
+	ctx := context.Background()
+
+	remember := func(t *testing.T, c cache.Cache) string {
+		var value string
+		require.NoError(t, c.Remember(ctx, "key", time.Minute, func() (interface{}, error) {
+			return "computed", nil
+		}, &value))
+		return value
+	}
+
+	t.Run("should count a Remember miss then a hit", func(t *testing.T) {
+		c := cache.NewStatsCache(cache.NewMemoryCache())
+
+		assert.Equal(t, "computed", remember(t, c))
+		assert.Equal(t, "computed", remember(t, c))
+
+		stats := c.Stats()
+		assert.Equal(t, cache.OperationStats{Hits: 1, Misses: 1}, stats.Operations[cache.OperationRemember])
+		assert.NotContains(t, stats.Operations, cache.OperationGet)
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(1), stats.Misses)
+		require.NotNil(t, stats.Keys)
+		assert.Equal(t, int64(1), *stats.Keys)
+	})
+
+	t.Run("should count Get and Has per operation", func(t *testing.T) {
+		c := cache.NewStatsCache(cache.NewMemoryCache())
+		require.NoError(t, c.Set(ctx, "present", 1, time.Minute))
+
+		var value int
+		require.NoError(t, c.Get(ctx, "present", &value))
+		assert.ErrorIs(t, c.Get(ctx, "missing", &value), cache.ErrKeyNotFound)
+		_, err := c.Has(ctx, "missing")
+		require.NoError(t, err)
+
+		stats := c.Stats()
+		assert.Equal(t, cache.OperationStats{Hits: 1, Misses: 1}, stats.Operations[cache.OperationGet])
+		assert.Equal(t, cache.OperationStats{Misses: 1}, stats.Operations[cache.OperationHas])
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(2), stats.Misses)
+	})
+
+	t.Run("should count keys of a Redis cache through other decorators", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+		defer client.Close()
+		require.NoError(t, client.Set(ctx, "other-app:key", 1, 0).Err())
+
+		m := metrics.New()
+		c := cache.NewStatsCache(cache.NewInstrumentedCache(cache.NewRedisCache(client, "app:"), m))
+		remember(t, c)
+		remember(t, c)
+
+		stats := c.Stats()
+		assert.Equal(t, cache.OperationStats{Hits: 1, Misses: 1}, stats.Operations[cache.OperationRemember])
+		require.NotNil(t, stats.Keys)
+		assert.Equal(t, int64(1), *stats.Keys)
+	})
+}
@@ -0,0 +1,128 @@
+package unit
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"app/internal/logger"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCaptureBodiesRouter(t *testing.T) (*gin.Engine, func() string) {
+	gin.SetMode(gin.TestMode)
+	log, buf := helpers.NewBufferLogger()
+
+	router := gin.New()
+	router.Use(middleware.CaptureBodies(log))
+	router.POST("/auth/login", func(c *gin.Context) {
+		var req struct {
+			Email    string `json:"email"`
+			Password string `json:"password"`
+		}
+		require.NoError(t, c.ShouldBindJSON(&req))
+		assert.Equal(t, "s3cret-pass1", req.Password, "the handler should still read the whole body")
+		c.JSON(http.StatusOK, gin.H{"data": gin.H{
+			"user":          gin.H{"email": req.Email},
+			"access_token":  "eyJ.access",
+			"refresh_token": "abc123",
+		}})
+	})
+	router.POST("/echo", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.String(http.StatusOK, "%d", len(body))
+	})
+
+	return router, buf.String
+}
+
+func TestCaptureBodies(t *testing.T) {
+	t.Run("should log the login body with the password redacted", func(t *testing.T) {
+		router, logs := newCaptureBodiesRouter(t)
+
+		req := httptest.NewRequest("POST", "/auth/login", strings.NewReader(`{"email": "user@example.com", "password": "s3cret-pass1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "eyJ.access", "the client still gets the real response")
+
+		output := logs()
+		assert.Contains(t, output, `msg="HTTP bodies"`)
+		assert.Contains(t, output, "level=DEBUG")
+		assert.Contains(t, output, `user@example.com`)
+		assert.Contains(t, output, `\"password\":\"[REDACTED]\"`)
+		assert.Contains(t, output, `\"access_token\":\"[REDACTED]\"`)
+		assert.NotContains(t, output, "s3cret-pass1")
+		assert.NotContains(t, output, "eyJ.access")
+		assert.NotContains(t, output, "abc123")
+	})
+
+	t.Run("should leave multipart bodies alone", func(t *testing.T) {
+		router, logs := newCaptureBodiesRouter(t)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, err := writer.CreateFormFile("file", "photo.jpg")
+		require.NoError(t, err)
+		_, err = part.Write([]byte("binary image content"))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		size := body.Len()
+
+		req := httptest.NewRequest("POST", "/echo", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, strconv.Itoa(size), w.Body.String())
+		assert.NotContains(t, logs(), "request_body")
+		assert.NotContains(t, logs(), "binary image content")
+	})
+
+	t.Run("should only count bodies over the capture limit", func(t *testing.T) {
+		router, logs := newCaptureBodiesRouter(t)
+
+		large := `{"data": "` + strings.Repeat("a", 100<<10) + `"}`
+		req := httptest.NewRequest("POST", "/echo", strings.NewReader(large))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, strconv.Itoa(len(large)), w.Body.String(), "the handler should still read the whole body")
+		assert.Contains(t, logs(), "not captured: over")
+		assert.NotContains(t, logs(), "aaaa")
+	})
+}
+
+func TestLogger_Redaction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := logger.NewWithHandler(slog.NewJSONHandler(buf, nil))
+
+	log.Info("signed in",
+		"password", "hunter2",
+		"user", map[string]any{"email": "user@example.com", "api_key": "key-1"},
+		slog.Group("request", "authorization", "Bearer token-1", "path", "/login"),
+	)
+
+	output := buf.String()
+	assert.NotContains(t, output, "hunter2")
+	assert.NotContains(t, output, "key-1")
+	assert.NotContains(t, output, "token-1")
+	assert.Contains(t, output, "user@example.com")
+	assert.Contains(t, output, `"path":"/login"`)
+	assert.Equal(t, 3, strings.Count(output, logger.Redacted))
+}
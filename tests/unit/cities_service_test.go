@@ -0,0 +1,259 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"app/internal/cache"
+	"app/internal/cities"
+	"app/internal/db"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flushFailingCache fails every prefix flush, standing in for a cache that went away after a write
+type flushFailingCache struct {
+	cache.Cache
+}
+
+func (flushFailingCache) FlushPrefix(ctx context.Context, prefix string) error {
+	return errors.New("cache unavailable")
+}
+
+func TestCitiesService_CreateCity(t *testing.T) {
+	t.Run("should create a city", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := cities.NewCitiesService(queries, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+			city, err := service.CreateCity(ctx, "  Valencia ")
+
+			require.NoError(t, err)
+			assert.Equal(t, "Valencia", city.Name)
+			assert.True(t, city.ID > 0)
+		})
+	})
+
+	t.Run("should return ErrCityAlreadyExists for a duplicate name", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := cities.NewCitiesService(queries, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+			_, err := service.CreateCity(ctx, "Valencia")
+			require.NoError(t, err)
+
+			_, err = service.CreateCity(ctx, "Valencia")
+
+			assert.ErrorIs(t, err, cities.ErrCityAlreadyExists)
+		})
+	})
+
+	t.Run("should require a name", func(t *testing.T) {
+		service := cities.NewCitiesService(nil, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+		_, err := service.CreateCity(context.Background(), "   ")
+
+		assert.ErrorIs(t, err, cities.ErrCityNameRequired)
+	})
+}
+
+func TestCitiesService_EnsureCity(t *testing.T) {
+	t.Run("should create city on first call", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := cities.NewCitiesService(queries, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+			city, created, err := service.EnsureCity(ctx, "Lisbon")
+
+			require.NoError(t, err)
+			assert.True(t, created)
+			assert.Equal(t, "Lisbon", city.Name)
+			assert.True(t, city.ID > 0)
+		})
+	})
+
+	t.Run("should return existing city on second call", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := cities.NewCitiesService(queries, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+			first, created, err := service.EnsureCity(ctx, "Lisbon")
+			require.NoError(t, err)
+			require.True(t, created)
+
+			second, created, err := service.EnsureCity(ctx, "Lisbon")
+
+			require.NoError(t, err)
+			assert.False(t, created)
+			assert.Equal(t, first.ID, second.ID)
+		})
+	})
+
+	t.Run("should trim whitespace before matching", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := cities.NewCitiesService(queries, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+			first, _, err := service.EnsureCity(ctx, "Porto")
+			require.NoError(t, err)
+
+			second, created, err := service.EnsureCity(ctx, "  Porto ")
+
+			require.NoError(t, err)
+			assert.False(t, created)
+			assert.Equal(t, first.ID, second.ID)
+		})
+	})
+
+	t.Run("should report the city as created when the cache cannot be invalidated", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := cities.NewCitiesService(queries, flushFailingCache{cache.NewMemoryCache()}, helpers.GetTestLogger(t))
+
+			city, created, err := service.EnsureCity(ctx, "Lisbon")
+
+			require.NoError(t, err)
+			assert.True(t, created)
+			assert.Equal(t, "Lisbon", city.Name)
+		})
+	})
+
+	t.Run("should reject empty name", func(t *testing.T) {
+		service := cities.NewCitiesService(nil, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+		city, created, err := service.EnsureCity(context.Background(), "   ")
+
+		assert.ErrorIs(t, err, cities.ErrCityNameRequired)
+		assert.Nil(t, city)
+		assert.False(t, created)
+	})
+}
+
+func TestCitiesService_ListCities(t *testing.T) {
+	t.Run("should serve the second request from cache until a city is created", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			memoryCache := cache.NewMemoryCache()
+			service := cities.NewCitiesService(queries, memoryCache, helpers.GetTestLogger(t))
+			_, err := service.CreateCity(ctx, "Amsterdam")
+			require.NoError(t, err)
+
+			first, err := service.ListCities(ctx)
+			require.NoError(t, err)
+
+			hasKey, err := memoryCache.Has(ctx, cities.AllCacheKey())
+			require.NoError(t, err)
+			assert.True(t, hasKey)
+
+			// Insert behind the service's back - the cached list must not see it
+			_, err = tx.Exec(ctx, "INSERT INTO cities (name) VALUES ('Berlin')")
+			require.NoError(t, err)
+
+			second, err := service.ListCities(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, first, second)
+
+			_, err = service.CreateCity(ctx, "Copenhagen")
+			require.NoError(t, err)
+
+			third, err := service.ListCities(ctx)
+			require.NoError(t, err)
+			assert.Len(t, third, len(first)+2, "Berlin and Copenhagen should show up after the invalidation")
+		})
+	})
+}
+
+func TestCitiesService_ListCitiesPaginated(t *testing.T) {
+	t.Run("should serve the second identical page request from cache", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			memoryCache := cache.NewMemoryCache()
+			service := cities.NewCitiesService(queries, memoryCache, helpers.GetTestLogger(t))
+			_, _, err := service.EnsureCity(ctx, "Amsterdam")
+			require.NoError(t, err)
+
+			first, err := service.ListCitiesPaginated(ctx, 1, 10)
+			require.NoError(t, err)
+
+			hasKey, err := memoryCache.Has(ctx, cities.ListCacheKey(1, 10))
+			require.NoError(t, err)
+			assert.True(t, hasKey)
+
+			// Insert behind the service's back - a cached page must not see it
+			_, err = tx.Exec(ctx, "INSERT INTO cities (name) VALUES ('Berlin')")
+			require.NoError(t, err)
+
+			second, err := service.ListCitiesPaginated(ctx, 1, 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, first.Total, second.Total)
+			assert.Equal(t, len(first.Data), len(second.Data))
+		})
+	})
+
+	t.Run("should cache each page size under its own key", func(t *testing.T) {
+		assert.NotEqual(t, cities.ListCacheKey(1, 10), cities.ListCacheKey(1, 20))
+		assert.NotEqual(t, cities.ListCacheKey(1, 10), cities.ListCacheKey(2, 10))
+	})
+
+	t.Run("should flush all cached pages when a city is created", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			memoryCache := cache.NewMemoryCache()
+			service := cities.NewCitiesService(queries, memoryCache, helpers.GetTestLogger(t))
+
+			_, err := service.ListCitiesPaginated(ctx, 1, 10)
+			require.NoError(t, err)
+			_, err = service.ListCitiesPaginated(ctx, 2, 5)
+			require.NoError(t, err)
+
+			_, created, err := service.EnsureCity(ctx, "Copenhagen")
+			require.NoError(t, err)
+			require.True(t, created)
+
+			for _, key := range []string{cities.ListCacheKey(1, 10), cities.ListCacheKey(2, 5)} {
+				hasKey, err := memoryCache.Has(ctx, key)
+				require.NoError(t, err)
+				assert.False(t, hasKey, "expected %s to be flushed", key)
+			}
+
+			result, err := service.ListCitiesPaginated(ctx, 1, 10)
+			require.NoError(t, err)
+			names := make([]string, 0, len(result.Data))
+			for _, city := range result.Data {
+				names = append(names, city.Name)
+			}
+			assert.Contains(t, names, "Copenhagen")
+		})
+	})
+
+	t.Run("should reject invalid page parameters", func(t *testing.T) {
+		service := cities.NewCitiesService(nil, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+		_, err := service.ListCitiesPaginated(context.Background(), 0, 10)
+		assert.ErrorIs(t, err, cities.ErrInvalidPage)
+
+		_, err = service.ListCitiesPaginated(context.Background(), 1, 101)
+		assert.ErrorIs(t, err, cities.ErrInvalidPageSize)
+	})
+}
+
+func TestCitiesService_SearchCities(t *testing.T) {
+	t.Run("should find cities by case-insensitive substring", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			helpers.CreateTestCity(t, ctx, tx, "New York")
+			helpers.CreateTestCity(t, ctx, tx, "Munich")
+			service := cities.NewCitiesService(queries, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+			result, err := service.SearchCities(ctx, "york", 10)
+
+			require.NoError(t, err)
+			require.Len(t, result, 1)
+			assert.Equal(t, "New York", result[0].Name)
+		})
+	})
+
+	t.Run("should reject out of range limits", func(t *testing.T) {
+		service := cities.NewCitiesService(nil, cache.NewMemoryCache(), helpers.GetTestLogger(t))
+
+		_, err := service.SearchCities(context.Background(), "New", 0)
+		assert.ErrorIs(t, err, cities.ErrInvalidLimit)
+
+		_, err = service.SearchCities(context.Background(), "New", cities.MaxSearchLimit+1)
+		assert.ErrorIs(t, err, cities.ErrInvalidLimit)
+	})
+}
@@ -0,0 +1,199 @@
+package unit
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/middleware"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCompressionRouter(cfg middleware.CompressionConfig, body string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Compression(cfg))
+	router.GET("/payload", func(c *gin.Context) {
+		c.String(http.StatusOK, body)
+	})
+	return router
+}
+
+func TestCompression(t *testing.T) {
+	cfg := middleware.CompressionConfig{MinSize: 1024, EnableBrotli: true}
+	largeBody := strings.Repeat("compressible payload ", 200)
+
+	t.Run("should use brotli when the client prefers br", func(t *testing.T) {
+		router := newCompressionRouter(cfg, largeBody)
+
+		req := httptest.NewRequest("GET", "/payload", nil)
+		req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "br", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Less(t, w.Body.Len(), len(largeBody))
+
+		decoded, err := io.ReadAll(brotli.NewReader(w.Body))
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(decoded))
+	})
+
+	t.Run("should fall back to gzip when the client does not support br", func(t *testing.T) {
+		router := newCompressionRouter(cfg, largeBody)
+
+		req := httptest.NewRequest("GET", "/payload", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(decoded))
+	})
+
+	t.Run("should use gzip when brotli is disabled", func(t *testing.T) {
+		router := newCompressionRouter(middleware.CompressionConfig{MinSize: 1024}, largeBody)
+
+		req := httptest.NewRequest("GET", "/payload", nil)
+		req.Header.Set("Accept-Encoding", "br, gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("should leave sub-threshold bodies uncompressed", func(t *testing.T) {
+		router := newCompressionRouter(cfg, "tiny")
+
+		req := httptest.NewRequest("GET", "/payload", nil)
+		req.Header.Set("Accept-Encoding", "br, gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "tiny", w.Body.String())
+	})
+
+	t.Run("should not compress without Accept-Encoding", func(t *testing.T) {
+		router := newCompressionRouter(cfg, largeBody)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/payload", nil))
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("should use deflate when it is the only supported encoding", func(t *testing.T) {
+		router := newCompressionRouter(cfg, largeBody)
+
+		req := httptest.NewRequest("GET", "/payload", nil)
+		req.Header.Set("Accept-Encoding", "deflate")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "deflate", w.Header().Get("Content-Encoding"))
+
+		reader, err := zlib.NewReader(w.Body)
+		require.NoError(t, err)
+		decoded, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, largeBody, string(decoded))
+	})
+
+	t.Run("should compress a JSON list into a body that decodes back to valid JSON", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Compression(cfg))
+		router.GET("/cities", func(c *gin.Context) {
+			cities := make([]gin.H, 100)
+			for i := range cities {
+				cities[i] = gin.H{"id": i + 1, "name": fmt.Sprintf("City %d", i+1)}
+			}
+			c.JSON(http.StatusOK, gin.H{"data": cities})
+		})
+
+		req := httptest.NewRequest("GET", "/cities", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		var response struct {
+			Data []struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.NewDecoder(reader).Decode(&response))
+		assert.Len(t, response.Data, 100)
+		assert.Equal(t, "City 1", response.Data[0].Name)
+	})
+
+	t.Run("should not recompress already compressed content types", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Compression(cfg))
+		router.GET("/photo", func(c *gin.Context) {
+			c.Data(http.StatusOK, "image/png", []byte(largeBody))
+		})
+
+		req := httptest.NewRequest("GET", "/photo", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		name           string
+		acceptEncoding string
+		enableBrotli   bool
+		expected       string
+	}{
+		{"empty header", "", true, ""},
+		{"br preferred", "gzip, br", true, "br"},
+		{"br disabled", "gzip, br", false, "gzip"},
+		{"gzip weighted higher", "br;q=0.5, gzip;q=1.0", true, "gzip"},
+		{"br explicitly refused", "br;q=0, gzip", true, "gzip"},
+		{"wildcard", "*", true, "br"},
+		{"unsupported only", "identity", true, ""},
+		{"deflate only", "deflate", true, "deflate"},
+		{"gzip wins tie with deflate", "deflate, gzip", false, "gzip"},
+		{"deflate weighted higher", "gzip;q=0.5, deflate", true, "deflate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, middleware.NegotiateEncoding(tt.acceptEncoding, tt.enableBrotli))
+		})
+	}
+}
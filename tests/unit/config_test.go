@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"app/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	const dbURL = "postgres://postgres@localhost:5432/myapp?sslmode=disable"
+	longSecret := strings.Repeat("s", config.MinProductionJWTSecretLength)
+
+	tests := []struct {
+		name        string
+		cfg         config.Config
+		errContains []string
+	}{
+		{
+			name: "valid development config",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: "short", Environment: "development"},
+		},
+		{
+			name: "valid production config",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: longSecret, Environment: "production"},
+		},
+		{
+			name:        "missing database url",
+			cfg:         config.Config{JWTSecret: longSecret},
+			errContains: []string{"DATABASE_URL is required"},
+		},
+		{
+			name:        "missing jwt secret",
+			cfg:         config.Config{DatabaseURL: dbURL},
+			errContains: []string{"JWT_SECRET is required"},
+		},
+		{
+			name:        "short jwt secret in production",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "too-short", Environment: "production"},
+			errContains: []string{"at least 32 bytes"},
+		},
+		{
+			name:        "webhook url without a secret",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", WebhookURL: "https://example.com/hooks"},
+			errContains: []string{"WEBHOOK_SECRET is required"},
+		},
+		{
+			name: "valid jwt key set",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: "short", JWTKeys: "new:a,old:b", JWTActiveKID: "new"},
+		},
+		{
+			name:        "active kid missing from jwt keys",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", JWTKeys: "old:b", JWTActiveKID: "new"},
+			errContains: []string{`JWT_ACTIVE_KID "new" is not in JWT_KEYS`},
+		},
+		{
+			name:        "short jwt key in production",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: longSecret, Environment: "production", JWTKeys: "new:" + longSecret + ",old:short", JWTActiveKID: "new"},
+			errContains: []string{`JWT_KEYS key "old" must be at least 32 bytes`},
+		},
+		{
+			name: "body capture in development",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: "short", Features: config.Features{DebugCaptureBodies: true}},
+		},
+		{
+			name:        "body capture in production",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: longSecret, Environment: "production", Features: config.Features{DebugCaptureBodies: true}},
+			errContains: []string{"DEBUG_CAPTURE_BODIES must not be enabled in production"},
+		},
+		{
+			name: "argon2id password hasher",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: "short", PasswordHasher: "argon2id"},
+		},
+		{
+			name:        "unknown password hasher",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", PasswordHasher: "md5"},
+			errContains: []string{`PASSWORD_HASHER must be bcrypt or argon2id, got "md5"`},
+		},
+		{
+			name:        "unknown timestamp format",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", TimestampFormat: "iso"},
+			errContains: []string{`TIMESTAMP_FORMAT must be rfc3339 or unix_ms, got "iso"`},
+		},
+		{
+			name: "custom api base path",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: "short", APIBasePath: "/gateway/api"},
+		},
+		{
+			name:        "api base path with a trailing slash",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", APIBasePath: "/gateway/"},
+			errContains: []string{`API_BASE_PATH must start with / and not end with one, got "/gateway/"`},
+		},
+		{
+			name:        "relative api base path",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", APIBasePath: "api"},
+			errContains: []string{`API_BASE_PATH must start with / and not end with one, got "api"`},
+		},
+		{
+			name: "trusted proxy ips and cidrs",
+			cfg:  config.Config{DatabaseURL: dbURL, JWTSecret: "short", TrustedProxies: []string{"127.0.0.1", "::1", "10.0.0.0/8"}},
+		},
+		{
+			name:        "invalid trusted proxy",
+			cfg:         config.Config{DatabaseURL: dbURL, JWTSecret: "short", TrustedProxies: []string{"10.0.0.0/33"}},
+			errContains: []string{`TRUSTED_PROXIES entry "10.0.0.0/33" is not an IP or CIDR`},
+		},
+		{
+			name:        "reports every problem",
+			cfg:         config.Config{Environment: "production"},
+			errContains: []string{"DATABASE_URL is required", "JWT_SECRET is required"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+
+			if len(tt.errContains) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			for _, expected := range tt.errContains {
+				assert.Contains(t, err.Error(), expected)
+			}
+		})
+	}
+}
+
+func TestConfig_Features(t *testing.T) {
+	unsetFlags := func(t *testing.T) {
+		for _, env := range []string{"APP_ENV", "ENABLE_SCHEDULER", "ENABLE_METRICS", "ENABLE_SWAGGER", "DEBUG_CAPTURE_BODIES", "REQUIRE_EMAIL_VERIFICATION", "JSON_STRING_IDS"} {
+			t.Setenv(env, "")
+		}
+	}
+
+	t.Run("should use defaults when flags are unset", func(t *testing.T) {
+		unsetFlags(t)
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+
+		assert.Equal(t, config.Features{EnableScheduler: true, EnableSwagger: true}, cfg.Features)
+	})
+
+	t.Run("should turn swagger off by default in production", func(t *testing.T) {
+		unsetFlags(t)
+		t.Setenv("APP_ENV", "production")
+
+		cfg, err := config.Load()
+		require.NoError(t, err)
+
+		assert.False(t, cfg.Features.EnableSwagger)
+
+		t.Setenv("ENABLE_SWAGGER", "true")
+		cfg, err = config.Load()
+		require.NoError(t, err)
+
+		assert.True(t, cfg.Features.EnableSwagger)
+	})
+
+	tests := []struct {
+		env      string
+		value    string
+		expected config.Features
+	}{
+		{env: "ENABLE_SCHEDULER", value: "false", expected: config.Features{EnableSwagger: true}},
+		{env: "ENABLE_METRICS", value: "true", expected: config.Features{EnableScheduler: true, EnableMetrics: true, EnableSwagger: true}},
+		{env: "ENABLE_SWAGGER", value: "false", expected: config.Features{EnableScheduler: true}},
+		{env: "DEBUG_CAPTURE_BODIES", value: "true", expected: config.Features{EnableScheduler: true, EnableSwagger: true, DebugCaptureBodies: true}},
+		{env: "REQUIRE_EMAIL_VERIFICATION", value: "1", expected: config.Features{EnableScheduler: true, EnableSwagger: true, RequireEmailVerification: true}},
+		{env: "JSON_STRING_IDS", value: "true", expected: config.Features{EnableScheduler: true, EnableSwagger: true, JSONStringIDs: true}},
+		{env: "ENABLE_METRICS", value: "not-a-bool", expected: config.Features{EnableScheduler: true, EnableSwagger: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			unsetFlags(t)
+			t.Setenv(tt.env, tt.value)
+
+			cfg, err := config.Load()
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expected, cfg.Features)
+		})
+	}
+}
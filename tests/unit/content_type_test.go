@@ -0,0 +1,60 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequireJSON())
+	router.Any("/items", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	request := func(method, contentType, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/items", strings.NewReader(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should reject bodies that aren't JSON with 415", func(t *testing.T) {
+		cases := []struct{ method, contentType string }{
+			{http.MethodPost, "application/x-www-form-urlencoded"},
+			{http.MethodPut, "text/plain"},
+			{http.MethodPatch, ""},
+			{http.MethodPost, "multipart/form-data; boundary=x"},
+		}
+
+		for _, tc := range cases {
+			w := request(tc.method, tc.contentType, "title=Hello")
+
+			assert.Equal(t, http.StatusUnsupportedMediaType, w.Code, tc)
+			assert.Contains(t, w.Body.String(), `"error_key":"unsupported_media_type"`)
+		}
+	})
+
+	t.Run("should pass JSON through", func(t *testing.T) {
+		for _, contentType := range []string{"application/json", "application/json; charset=utf-8", "Application/JSON"} {
+			assert.Equal(t, http.StatusNoContent, request(http.MethodPost, contentType, `{"title":"Hello"}`).Code, contentType)
+		}
+	})
+
+	t.Run("should allow empty bodies and other methods", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, request(http.MethodPost, "", "").Code)
+		assert.Equal(t, http.StatusNoContent, request(http.MethodDelete, "text/plain", "ignored").Code)
+		assert.Equal(t, http.StatusNoContent, request(http.MethodGet, "", "").Code)
+	})
+}
@@ -0,0 +1,90 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"app/internal/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingPool stands in for one database pool and records which statements reached it
+type recordingPool struct {
+	statements []string
+	begins     int
+}
+
+func (p *recordingPool) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	p.statements = append(p.statements, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (p *recordingPool) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	p.statements = append(p.statements, sql)
+	return nil, errInjected
+}
+
+func (p *recordingPool) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	p.statements = append(p.statements, sql)
+	return errRow{}
+}
+
+func (p *recordingPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	p.begins++
+	return nil, errInjected
+}
+
+func TestRouter(t *testing.T) {
+	ctx := context.Background()
+
+	newRouted := func() (*recordingPool, *recordingPool, *db.Queries) {
+		primary, replica := &recordingPool{}, &recordingPool{}
+		return primary, replica, db.New(db.NewRouter(primary, replica))
+	}
+
+	t.Run("should send reads to the replica", func(t *testing.T) {
+		primary, replica, queries := newRouted()
+
+		_, _ = queries.GetUserByID(ctx, 1)
+		_, _ = queries.ListExamplesForUser(ctx, 1)
+		_, _ = queries.CountExamplesForUser(ctx, 1)
+		_, _ = queries.SearchExamplesForUser(ctx, db.SearchExamplesForUserParams{UserID: 1, Query: "report"})
+		_, _ = queries.SumUploadSizesByUserID(ctx, 1)
+
+		assert.Len(t, replica.statements, 5)
+		assert.Empty(t, primary.statements)
+	})
+
+	t.Run("should send writes to the primary", func(t *testing.T) {
+		primary, replica, queries := newRouted()
+
+		_, _ = queries.CreateUser(ctx, db.CreateUserParams{Email: "user@example.com", Name: "User", Password: "hashed"})
+		_ = queries.RevokeAllUserRefreshTokens(ctx, 1)
+		_ = queries.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{ID: 1, Password: "hashed"})
+
+		assert.Len(t, primary.statements, 3)
+		assert.Empty(t, replica.statements)
+	})
+
+	t.Run("should begin transactions on the primary", func(t *testing.T) {
+		primary, replica, queries := newRouted()
+
+		err := db.WithTx(ctx, queries.TxBeginner(), func(q *db.Queries) error { return nil })
+
+		assert.ErrorIs(t, err, errInjected)
+		assert.Equal(t, 1, primary.begins)
+		assert.Equal(t, 0, replica.begins)
+	})
+}
+
+func TestIsReadQuery(t *testing.T) {
+	assert.True(t, db.IsReadQuery("-- name: GetUserByID :one\nSELECT 1"))
+	assert.True(t, db.IsReadQuery("-- name: ListCities :many\nSELECT 1"))
+	assert.False(t, db.IsReadQuery("-- name: UpdateUser :one\nUPDATE users SET name = $2"))
+	assert.False(t, db.IsReadQuery("-- name: InsertCityIfNotExists :one\nINSERT INTO cities"))
+	// Hand-written SQL has no name to go by, so it stays on the primary
+	assert.False(t, db.IsReadQuery("SELECT pg_sleep(1)"))
+}
@@ -0,0 +1,255 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal/db"
+	"app/internal/errs"
+	"app/internal/example"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractDomainError_ContextCancellation(t *testing.T) {
+	t.Run("should map context.Canceled to client closed request", func(t *testing.T) {
+		domainErr := errs.ExtractDomainError(context.Canceled)
+
+		assert.Equal(t, errs.StatusClientClosedRequest, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyRequestCancelled, domainErr.Key)
+	})
+
+	t.Run("should map context.DeadlineExceeded to request timeout", func(t *testing.T) {
+		domainErr := errs.ExtractDomainError(fmt.Errorf("query failed: %w", context.DeadlineExceeded))
+
+		assert.Equal(t, http.StatusServiceUnavailable, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyRequestTimeout, domainErr.Key)
+	})
+
+	t.Run("should see through internal errors wrapping a cancellation", func(t *testing.T) {
+		err := errs.WrapInternal(errs.ErrKeyInternalError, "failed to list examples", context.Canceled)
+
+		domainErr := errs.ExtractDomainError(err)
+
+		assert.Equal(t, errs.StatusClientClosedRequest, domainErr.Status)
+		assert.ErrorIs(t, domainErr, context.Canceled)
+	})
+
+	t.Run("should still map other errors to internal error", func(t *testing.T) {
+		domainErr := errs.ExtractDomainError(errors.New("connection refused"))
+
+		assert.Equal(t, http.StatusInternalServerError, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyInternalError, domainErr.Key)
+	})
+}
+
+func TestErrorHandler_ContextCancellation(t *testing.T) {
+	newRouter := func(handlerErr error) (*gin.Engine, func() string) {
+		gin.SetMode(gin.TestMode)
+		log, buf := helpers.NewBufferLogger()
+
+		router := gin.New()
+		router.Use(middleware.ErrorHandler(log))
+		router.GET("/slow", func(c *gin.Context) {
+			_ = c.Error(handlerErr)
+		})
+		return router, buf.String
+	}
+
+	t.Run("should not log a cancelled request as a server error", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		router, logs := newRouter(errs.WrapInternal(errs.ErrKeyInternalError, "failed to query", ctx.Err()))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil).WithContext(ctx))
+
+		assert.Equal(t, errs.StatusClientClosedRequest, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyRequestCancelled)
+		assert.NotContains(t, logs(), "HTTP server error")
+		assert.NotContains(t, logs(), "level=ERROR")
+		assert.Contains(t, logs(), "HTTP request cancelled by client")
+	})
+
+	t.Run("should still log genuine server errors", func(t *testing.T) {
+		router, logs := newRouter(errs.WrapInternal(errs.ErrKeyInternalError, "failed to query", errors.New("connection refused")))
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Contains(t, logs(), "HTTP server error")
+	})
+}
+
+// cancelledDB fails every statement with the error of its context, like pgx does once the
+// client has disconnected
+type cancelledDB struct{}
+
+func (cancelledDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, ctx.Err()
+}
+
+func (cancelledDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return nil, ctx.Err()
+}
+
+func (cancelledDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return cancelledRow{err: ctx.Err()}
+}
+
+type cancelledRow struct{ err error }
+
+func (r cancelledRow) Scan(...interface{}) error { return r.err }
+
+func TestCancelledListRequest(t *testing.T) {
+	t.Run("should not log a list call cancelled by the client as a server error", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		log, buf := helpers.NewBufferLogger()
+
+		handler := example.NewHandler(example.NewExampleService(db.New(cancelledDB{})), log, []byte("secret"), "/share")
+		router := gin.New()
+		router.Use(middleware.RequestLogging(log))
+		router.Use(middleware.ErrorHandler(log))
+		router.GET("/examples", func(c *gin.Context) {
+			c.Set("user_id", int32(42))
+			c.Next()
+		}, handler.ListExamples)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/examples", nil).WithContext(ctx))
+
+		assert.Equal(t, errs.StatusClientClosedRequest, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyRequestCancelled)
+		assert.Contains(t, buf.String(), "Failed to list examples")
+		assert.NotContains(t, buf.String(), "level=ERROR")
+	})
+
+	t.Run("should map cancellation wrapped by a service to request_cancelled", func(t *testing.T) {
+		domainErr := errs.WrapInternal(errs.ErrKeyInternalError, "failed to list examples", context.Canceled)
+
+		assert.Equal(t, errs.StatusClientClosedRequest, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyRequestCancelled, domainErr.Key)
+		assert.ErrorIs(t, domainErr, context.Canceled)
+	})
+}
+
+func TestErrorHandler_DomainErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	log, buf := helpers.NewBufferLogger()
+
+	router := gin.New()
+	router.Use(middleware.ErrorHandler(log))
+	router.GET("/not-found", func(c *gin.Context) {
+		_ = c.Error(errs.NewNotFoundError(errs.ErrKeyExampleNotFound, "Example not found"))
+		c.Abort()
+	})
+	router.GET("/wrapped", func(c *gin.Context) {
+		_ = c.Error(fmt.Errorf("loading example: %w", errs.NewNotFoundError(errs.ErrKeyExampleNotFound, "Example not found")))
+	})
+	router.GET("/plain", func(c *gin.Context) {
+		_ = c.Error(errors.New("connection refused"))
+	})
+	router.GET("/responded", func(c *gin.Context) {
+		_ = c.Error(errs.NewNotFoundError(errs.ErrKeyExampleNotFound, "Example not found"))
+		c.JSON(http.StatusAccepted, gin.H{"data": "ok"})
+	})
+
+	request := func(path string) (*httptest.ResponseRecorder, errs.ErrorResponse) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+
+		var body errs.ErrorResponse
+		_ = json.Unmarshal(w.Body.Bytes(), &body)
+		return w, body
+	}
+
+	t.Run("should respond with the domain status and key", func(t *testing.T) {
+		w, body := request("/not-found")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+		assert.Equal(t, errs.ErrKeyExampleNotFound, body.ErrorKey)
+		assert.Equal(t, http.StatusNotFound, body.Status)
+		assert.Equal(t, "Example not found", body.Message)
+		assert.NotContains(t, buf.String(), "HTTP server error")
+	})
+
+	t.Run("should find a wrapped domain error", func(t *testing.T) {
+		w, body := request("/wrapped")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, errs.ErrKeyExampleNotFound, body.ErrorKey)
+	})
+
+	t.Run("should hide other errors behind a 500", func(t *testing.T) {
+		w, body := request("/plain")
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, errs.ErrKeyInternalError, body.ErrorKey)
+		assert.NotContains(t, w.Body.String(), "connection refused")
+		assert.Contains(t, buf.String(), "HTTP server error")
+	})
+
+	t.Run("should keep a response the handler already wrote", func(t *testing.T) {
+		w, _ := request("/responded")
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.JSONEq(t, `{"data":"ok"}`, w.Body.String())
+	})
+}
+
+func TestFromPgError(t *testing.T) {
+	t.Run("should map a foreign key violation to 400 with the constraint", func(t *testing.T) {
+		pgErr := &pgconn.PgError{Code: pgerrcode.ForeignKeyViolation, ConstraintName: "examples_user_id_fkey"}
+
+		domainErr := errs.FromPgError(fmt.Errorf("insert failed: %w", pgErr))
+
+		require.NotNil(t, domainErr)
+		assert.Equal(t, http.StatusBadRequest, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyDBForeignKeyViolation, domainErr.Key)
+		assert.Equal(t, "examples_user_id_fkey", domainErr.Details["constraint"])
+		assert.NotContains(t, domainErr.Details, "column")
+		assert.ErrorIs(t, domainErr, pgErr)
+	})
+
+	t.Run("should map a unique violation to 409", func(t *testing.T) {
+		domainErr := errs.FromPgError(&pgconn.PgError{Code: pgerrcode.UniqueViolation, ConstraintName: "users_email_key"})
+
+		require.NotNil(t, domainErr)
+		assert.Equal(t, http.StatusConflict, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyDBUniqueViolation, domainErr.Key)
+		assert.Equal(t, "users_email_key", domainErr.Details["constraint"])
+	})
+
+	t.Run("should include the column of a not null violation", func(t *testing.T) {
+		domainErr := errs.FromPgError(&pgconn.PgError{Code: pgerrcode.NotNullViolation, ColumnName: "title"})
+
+		require.NotNil(t, domainErr)
+		assert.Equal(t, http.StatusBadRequest, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyDBNotNullViolation, domainErr.Key)
+		assert.Equal(t, "title", domainErr.Details["column"])
+	})
+
+	t.Run("should return nil for other errors", func(t *testing.T) {
+		assert.Nil(t, errs.FromPgError(&pgconn.PgError{Code: pgerrcode.CheckViolation}))
+		assert.Nil(t, errs.FromPgError(errors.New("boom")))
+		assert.Nil(t, errs.FromPgError(nil))
+	})
+}
@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeakETag(t *testing.T) {
+	etag := internal.WeakETag(int32(1), int64(1700000000))
+
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, etag, internal.WeakETag(int32(1), int64(1700000000)))
+	assert.NotEqual(t, etag, internal.WeakETag(int32(1), int64(1700000001)))
+	// Parts are delimited, so shifting digits between them changes the ETag
+	assert.NotEqual(t, internal.WeakETag("12", "3"), internal.WeakETag("1", "23"))
+}
+
+func TestNotModified(t *testing.T) {
+	const etag = `W/"abc"`
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/item", func(c *gin.Context) {
+		if internal.NotModified(c, etag) {
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"data": "item"})
+	})
+
+	request := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/item", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should send the body and ETag without If-None-Match", func(t *testing.T) {
+		w := request("")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+		assert.JSONEq(t, `{"data":"item"}`, w.Body.String())
+	})
+
+	t.Run("should answer 304 with an empty body for a matching ETag", func(t *testing.T) {
+		for _, ifNoneMatch := range []string{etag, `"abc"`, `W/"other", W/"abc"`, "*"} {
+			w := request(ifNoneMatch)
+
+			assert.Equal(t, http.StatusNotModified, w.Code, ifNoneMatch)
+			assert.Equal(t, etag, w.Header().Get("ETag"))
+			assert.Empty(t, w.Body.String())
+		}
+	})
+
+	t.Run("should send the body for a stale ETag", func(t *testing.T) {
+		w := request(`W/"stale"`)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEmpty(t, w.Body.String())
+	})
+}
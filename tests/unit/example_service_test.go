@@ -2,9 +2,11 @@ package unit
 
 import (
 	"context"
+	"net/http"
 	"testing"
 
 	"app/internal/db"
+	"app/internal/errs"
 	"app/internal/example"
 	"app/tests/helpers"
 
@@ -49,6 +51,67 @@ func TestExampleService_CreateExample(t *testing.T) {
 			assert.False(t, createdExample.Description.Valid)
 		})
 	})
+
+	t.Run("should return 400 with the constraint when the user does not exist", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := example.NewExampleService(queries)
+
+			createdExample, err := service.CreateExample(ctx, 999999, "Test Title", "")
+
+			var domainErr *errs.DomainError
+			require.ErrorAs(t, err, &domainErr)
+			assert.Nil(t, createdExample)
+			assert.Equal(t, http.StatusBadRequest, domainErr.Status)
+			assert.Equal(t, errs.ErrKeyDBForeignKeyViolation, domainErr.Key)
+			assert.Equal(t, "examples_user_id_fkey", domainErr.Details["constraint"])
+		})
+	})
+}
+
+func TestExampleService_CreateExamplesBatch(t *testing.T) {
+	t.Run("should create every example in order", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			service := example.NewExampleService(queries)
+
+			created, err := service.CreateExamplesBatch(ctx, user.ID, []example.ExampleInput{
+				{Title: "First", Description: "One"},
+				{Title: "Second"},
+			})
+
+			require.NoError(t, err)
+			require.Len(t, created, 2)
+			assert.Equal(t, "First", created[0].Title)
+			assert.Equal(t, "One", created[0].Description.String)
+			assert.Equal(t, "Second", created[1].Title)
+			assert.False(t, created[1].Description.Valid, "empty description should be stored as NULL")
+			for _, ex := range created {
+				assert.True(t, ex.ID > 0)
+				assert.Equal(t, user.ID, ex.UserID)
+			}
+		})
+	})
+
+	t.Run("should reject batches over the limit without inserting", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			service := example.NewExampleService(queries)
+
+			items := make([]example.ExampleInput, example.MaxExamplesBatchSize+1)
+			for i := range items {
+				items[i] = example.ExampleInput{Title: "Item"}
+			}
+
+			created, err := service.CreateExamplesBatch(ctx, user.ID, items)
+
+			assert.ErrorIs(t, err, example.ErrBatchTooLarge)
+			assert.Nil(t, created)
+
+			count, err := queries.CountExamplesForUser(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Zero(t, count)
+		})
+	})
 }
 
 func TestExampleService_GetExample(t *testing.T) {
@@ -115,7 +178,7 @@ func TestExampleService_UpdateExample(t *testing.T) {
 			service := example.NewExampleService(queries)
 
 			// Test: Update example
-			updatedExample, err := service.UpdateExample(ctx, testExample.ID, user.ID, "Updated Title", "Updated Description")
+			updatedExample, err := service.UpdateExample(ctx, testExample.ID, user.ID, "Updated Title", "Updated Description", nil)
 
 			// Assert: Verify result
 			require.NoError(t, err)
@@ -133,7 +196,7 @@ func TestExampleService_UpdateExample(t *testing.T) {
 			service := example.NewExampleService(queries)
 
 			// Test: Update non-existent example
-			result, err := service.UpdateExample(ctx, 99999, user.ID, "Title", "Description")
+			result, err := service.UpdateExample(ctx, 99999, user.ID, "Title", "Description", nil)
 
 			// Assert: Should return error
 			assert.Error(t, err)
@@ -235,7 +298,7 @@ func TestExampleService_ListExamplesPaginated(t *testing.T) {
 			service := example.NewExampleService(queries)
 
 			// Test: List paginated examples
-			result, err := service.ListExamplesPaginated(ctx, user.ID, 1, 3)
+			result, err := service.ListExamplesPaginated(ctx, user.ID, 1, 3, example.ExampleListFilter{})
 
 			// Assert: Verify result
 			require.NoError(t, err)
@@ -257,7 +320,7 @@ func TestExampleService_ListExamplesPaginated(t *testing.T) {
 			service := example.NewExampleService(queries)
 
 			// Test: Get second page
-			result, err := service.ListExamplesPaginated(ctx, user.ID, 2, 3)
+			result, err := service.ListExamplesPaginated(ctx, user.ID, 2, 3, example.ExampleListFilter{})
 
 			// Assert: Verify result
 			require.NoError(t, err)
@@ -276,7 +339,7 @@ func TestExampleService_ListExamplesPaginated(t *testing.T) {
 			service := example.NewExampleService(queries)
 
 			// Test: List with invalid page
-			result, err := service.ListExamplesPaginated(ctx, user.ID, 0, 10)
+			result, err := service.ListExamplesPaginated(ctx, user.ID, 0, 10, example.ExampleListFilter{})
 
 			// Assert: Should return error
 			assert.Error(t, err)
@@ -291,7 +354,7 @@ func TestExampleService_ListExamplesPaginated(t *testing.T) {
 			service := example.NewExampleService(queries)
 
 			// Test: List with invalid page size
-			result, err := service.ListExamplesPaginated(ctx, user.ID, 1, 101)
+			result, err := service.ListExamplesPaginated(ctx, user.ID, 1, 101, example.ExampleListFilter{})
 
 			// Assert: Should return error
 			assert.Error(t, err)
@@ -299,3 +362,53 @@ func TestExampleService_ListExamplesPaginated(t *testing.T) {
 		})
 	})
 }
+
+func TestExampleService_SearchExamples(t *testing.T) {
+	t.Run("should match title and description words and rank title matches first", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			service := example.NewExampleService(queries)
+
+			_, err := service.CreateExample(ctx, user.ID, "Gardening tips", "Tomatoes need plenty of sun")
+			require.NoError(t, err)
+			_, err = service.CreateExample(ctx, user.ID, "Weekend plans", "Maybe some database tuning")
+			require.NoError(t, err)
+			_, err = service.CreateExample(ctx, user.ID, "Database tuning", "Indexes, vacuum and database statistics")
+			require.NoError(t, err)
+
+			result, err := service.SearchExamples(ctx, user.ID, "database", 1, 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(2), result.Total)
+			require.Len(t, result.Data, 2)
+			assert.Equal(t, "Database tuning", result.Data[0].Title)
+			assert.Equal(t, "Weekend plans", result.Data[1].Title)
+		})
+	})
+
+	t.Run("should only search the user's own examples", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			service := example.NewExampleService(queries)
+
+			_, err := service.CreateExample(ctx, other.ID, "Database tuning", "")
+			require.NoError(t, err)
+
+			result, err := service.SearchExamples(ctx, user.ID, "database", 1, 10)
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(0), result.Total)
+			assert.Empty(t, result.Data)
+		})
+	})
+
+	t.Run("should reject a blank query", func(t *testing.T) {
+		service := example.NewExampleService(nil)
+
+		result, err := service.SearchExamples(context.Background(), 1, "   ", 1, 10)
+
+		assert.ErrorIs(t, err, example.ErrSearchQueryRequired)
+		assert.Nil(t, result)
+	})
+}
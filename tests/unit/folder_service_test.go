@@ -0,0 +1,58 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"app/internal/db"
+	"app/internal/folders"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderService_RootFolder(t *testing.T) {
+	t.Run("should create the root folder once", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := folders.NewFolderService(queries)
+			user := helpers.CreateTestUser(t, ctx, tx)
+
+			first, err := service.RootFolder(ctx, user.ID)
+			require.NoError(t, err)
+			second, err := service.RootFolder(ctx, user.ID)
+			require.NoError(t, err)
+
+			assert.True(t, first.IsRoot)
+			assert.Equal(t, first.ID, second.ID)
+		})
+	})
+}
+
+func TestFolderService_DeleteFolder(t *testing.T) {
+	t.Run("should refuse to delete the root folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := folders.NewFolderService(queries)
+			user := helpers.CreateTestUser(t, ctx, tx)
+			root := helpers.GetTestRootFolder(t, ctx, tx, user.ID)
+
+			err := service.DeleteFolder(ctx, root.ID, user.ID)
+
+			assert.ErrorIs(t, err, folders.ErrFolderRootProtected)
+		})
+	})
+
+	t.Run("should not delete another user's folder", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			service := folders.NewFolderService(queries)
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			folder := helpers.CreateTestFolder(t, ctx, tx, other.ID, "Theirs")
+
+			err := service.DeleteFolder(ctx, folder.ID, user.ID)
+
+			assert.ErrorIs(t, err, folders.ErrFolderNotFound)
+		})
+	})
+}
@@ -0,0 +1,203 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"app/internal/cache"
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotency(t *testing.T) {
+	// newRouter counts handler runs; the status query parameter lets a test force a response code
+	newRouter := func(store cache.Cache) (*gin.Engine, *int) {
+		gin.SetMode(gin.TestMode)
+		calls := 0
+		router := gin.New()
+		router.POST("/items", func(c *gin.Context) {
+			if userID := c.GetHeader("X-User"); userID != "" {
+				id, _ := strconv.Atoi(userID)
+				c.Set("user_id", int32(id))
+			}
+			c.Next()
+		}, middleware.Idempotency(store), func(c *gin.Context) {
+			calls++
+			status := http.StatusCreated
+			if forced := c.Query("status"); forced != "" {
+				status, _ = strconv.Atoi(forced)
+			}
+			c.JSON(status, gin.H{"call": calls})
+		})
+		return router, &calls
+	}
+
+	send := func(router *gin.Engine, target, key, user, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(middleware.IdempotencyKeyHeader, key)
+		}
+		if user != "" {
+			req.Header.Set("X-User", user)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should replay the first response for a repeated key", func(t *testing.T) {
+		router, calls := newRouter(cache.NewMemoryCache())
+
+		first := send(router, "/items", "key-1", "1", `{"title":"a"}`)
+		second := send(router, "/items", "key-1", "1", `{"title":"a"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusCreated, second.Code)
+		assert.Equal(t, first.Body.String(), second.Body.String())
+		assert.Equal(t, first.Header().Get("Content-Type"), second.Header().Get("Content-Type"))
+		assert.Empty(t, first.Header().Get(middleware.IdempotentReplayedHeader))
+		assert.Equal(t, "true", second.Header().Get(middleware.IdempotentReplayedHeader))
+	})
+
+	t.Run("should run the handler every time without a key", func(t *testing.T) {
+		router, calls := newRouter(cache.NewMemoryCache())
+
+		send(router, "/items", "", "1", `{}`)
+		send(router, "/items", "", "1", `{}`)
+
+		assert.Equal(t, 2, *calls)
+	})
+
+	t.Run("should scope keys to the user", func(t *testing.T) {
+		router, calls := newRouter(cache.NewMemoryCache())
+
+		send(router, "/items", "shared", "1", `{}`)
+		second := send(router, "/items", "shared", "2", `{}`)
+
+		assert.Equal(t, 2, *calls)
+		assert.Empty(t, second.Header().Get(middleware.IdempotentReplayedHeader))
+	})
+
+	t.Run("should reject a key reused with a different body", func(t *testing.T) {
+		router, calls := newRouter(cache.NewMemoryCache())
+
+		send(router, "/items", "key-1", "1", `{"title":"a"}`)
+		second := send(router, "/items", "key-1", "1", `{"title":"b"}`)
+
+		assert.Equal(t, 1, *calls)
+		assert.Equal(t, http.StatusUnprocessableEntity, second.Code)
+		assert.Contains(t, second.Body.String(), errs.ErrKeyIdempotencyKeyReused)
+	})
+
+	t.Run("should return 409 while the first request is still running", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		started := make(chan struct{})
+		release := make(chan struct{})
+		router := gin.New()
+		router.POST("/items", middleware.Idempotency(cache.NewMemoryCache()), func(c *gin.Context) {
+			close(started)
+			<-release
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		done := make(chan *httptest.ResponseRecorder)
+		go func() {
+			done <- send(router, "/items", "key-1", "", `{}`)
+		}()
+		<-started
+
+		resp := send(router, "/items", "key-1", "", `{}`)
+		close(release)
+
+		assert.Equal(t, http.StatusConflict, resp.Code)
+		assert.Contains(t, resp.Body.String(), errs.ErrKeyIdempotencyInProgress)
+		assert.Equal(t, http.StatusCreated, (<-done).Code)
+	})
+
+	t.Run("should run the handler once for concurrent requests with the same key", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		store := newBarrierCache(2)
+		var calls atomic.Int32
+		router := gin.New()
+		router.POST("/items", middleware.Idempotency(store), func(c *gin.Context) {
+			calls.Add(1)
+			time.Sleep(20 * time.Millisecond)
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+
+		responses := make(chan *httptest.ResponseRecorder, 2)
+		for range 2 {
+			go func() {
+				responses <- send(router, "/items", "key-1", "", `{}`)
+			}()
+		}
+
+		codes := []int{(<-responses).Code, (<-responses).Code}
+		assert.EqualValues(t, 1, calls.Load())
+		assert.ElementsMatch(t, []int{http.StatusCreated, http.StatusConflict}, codes)
+	})
+
+	t.Run("should not store server errors so the client can retry", func(t *testing.T) {
+		router, calls := newRouter(cache.NewMemoryCache())
+
+		first := send(router, "/items?status=500", "key-1", "1", `{}`)
+		second := send(router, "/items?status=500", "key-1", "1", `{}`)
+
+		assert.Equal(t, http.StatusInternalServerError, first.Code)
+		assert.Equal(t, 2, *calls)
+		assert.Empty(t, second.Header().Get(middleware.IdempotentReplayedHeader))
+	})
+
+	t.Run("should reject overlong keys", func(t *testing.T) {
+		router, calls := newRouter(cache.NewMemoryCache())
+
+		resp := send(router, "/items", strings.Repeat("k", 256), "1", `{}`)
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, 0, *calls)
+	})
+}
+
+// barrierCache holds back the result of the first cache lookup of each of n requests until
+// all n have made one, so none of them sees a claim another made after looking up the key
+type barrierCache struct {
+	cache.Cache
+	arrived sync.WaitGroup
+	waiting atomic.Int32
+	n       int32
+}
+
+func newBarrierCache(n int) *barrierCache {
+	c := &barrierCache{Cache: cache.NewMemoryCache(), n: int32(n)}
+	c.arrived.Add(n)
+	return c
+}
+
+func (c *barrierCache) wait() {
+	if c.waiting.Add(1) <= c.n {
+		c.arrived.Done()
+		c.arrived.Wait()
+	}
+}
+
+func (c *barrierCache) Get(ctx context.Context, key string, dest interface{}) error {
+	err := c.Cache.Get(ctx, key, dest)
+	c.wait()
+	return err
+}
+
+func (c *barrierCache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	stored, err := c.Cache.SetNX(ctx, key, value, ttl)
+	c.wait()
+	return stored, err
+}
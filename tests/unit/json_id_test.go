@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"app/internal"
+	"app/internal/auth"
+	"app/internal/example"
+	"app/internal/uploads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONID_Marshal(t *testing.T) {
+	t.Run("should serialize as a number by default", func(t *testing.T) {
+		body, err := json.Marshal(internal.JSONID(42))
+		require.NoError(t, err)
+		assert.Equal(t, `42`, string(body))
+	})
+
+	t.Run("should serialize as a quoted string when the flag is on", func(t *testing.T) {
+		internal.SetStringIDs(true)
+		t.Cleanup(func() { internal.SetStringIDs(false) })
+
+		body, err := json.Marshal(internal.JSONID(9007199254740993))
+		require.NoError(t, err)
+		assert.Equal(t, `"9007199254740993"`, string(body))
+	})
+}
+
+func TestJSONID_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected internal.JSONID
+	}{
+		{name: "number", input: `42`, expected: 42},
+		{name: "string", input: `"42"`, expected: 42},
+		{name: "beyond float precision", input: `"9007199254740993"`, expected: 9007199254740993},
+		{name: "null", input: `null`, expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run("should decode "+tt.name, func(t *testing.T) {
+			var id internal.JSONID
+			require.NoError(t, json.Unmarshal([]byte(tt.input), &id))
+			assert.Equal(t, tt.expected, id)
+		})
+	}
+
+	t.Run("should reject anything that isn't an integer", func(t *testing.T) {
+		var id internal.JSONID
+		assert.Error(t, json.Unmarshal([]byte(`"abc"`), &id))
+		assert.Error(t, json.Unmarshal([]byte(`1.5`), &id))
+	})
+}
+
+func TestJSONID_Responses(t *testing.T) {
+	responses := map[string]struct {
+		value any
+		keys  []string
+	}{
+		"example": {value: example.ExampleResponse{ID: 1, UserID: 2}, keys: []string{"id", "user_id"}},
+		"upload":  {value: uploads.UploadResponse{ID: 1, UserID: 2, FolderID: 3}, keys: []string{"id", "user_id", "folder_id"}},
+		"user":    {value: auth.UserResponse{ID: 1}, keys: []string{"id"}},
+	}
+
+	decode := func(t *testing.T, value any) map[string]any {
+		body, err := json.Marshal(value)
+		require.NoError(t, err)
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal(body, &fields))
+		return fields
+	}
+
+	for name, response := range responses {
+		t.Run("should serialize "+name+" IDs as numbers when the flag is off", func(t *testing.T) {
+			fields := decode(t, response.value)
+			for _, key := range response.keys {
+				assert.IsType(t, float64(0), fields[key], key)
+			}
+		})
+
+		t.Run("should serialize "+name+" IDs as strings when the flag is on", func(t *testing.T) {
+			internal.SetStringIDs(true)
+			t.Cleanup(func() { internal.SetStringIDs(false) })
+
+			fields := decode(t, response.value)
+			for _, key := range response.keys {
+				assert.IsType(t, "", fields[key], key)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"testing"
+
+	"app/config"
+	"app/internal/auth"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthService_VerifyJWTKeyRotation(t *testing.T) {
+	const (
+		currentSecret  = "current-secret"
+		previousSecret = "previous-secret"
+		legacySecret   = "legacy-secret"
+	)
+
+	keySet, err := auth.NewJWTKeySet("2024-06", map[string][]byte{
+		"2024-06": []byte(currentSecret),
+		"2024-01": []byte(previousSecret),
+	}, []byte(legacySecret))
+	require.NoError(t, err)
+	service := auth.NewAuthServiceWithKeys(nil, nil, keySet, helpers.GetTestLogger(t))
+
+	t.Run("should accept a token signed with the active key", func(t *testing.T) {
+		token, err := service.VerifyJWT(helpers.SignTestTokenWithKID(t, "2024-06", currentSecret, 7))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), token.Claims.(*middleware.Claims).UserID)
+	})
+
+	t.Run("should accept a token signed with the previous key", func(t *testing.T) {
+		token, err := service.VerifyJWT(helpers.SignTestTokenWithKID(t, "2024-01", previousSecret, 7))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), token.Claims.(*middleware.Claims).UserID)
+	})
+
+	t.Run("should reject a token with an unknown kid", func(t *testing.T) {
+		_, err := service.VerifyJWT(helpers.SignTestTokenWithKID(t, "2023-01", currentSecret, 7))
+
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("should reject a token whose kid names a different key", func(t *testing.T) {
+		_, err := service.VerifyJWT(helpers.SignTestTokenWithKID(t, "2024-06", previousSecret, 7))
+
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("should verify tokens without a kid with the legacy key", func(t *testing.T) {
+		_, err := service.VerifyJWT(helpers.SignTestToken(t, legacySecret, 7))
+		assert.NoError(t, err)
+
+		_, err = service.VerifyJWT(helpers.SignTestToken(t, currentSecret, 7))
+		assert.ErrorIs(t, err, auth.ErrInvalidToken)
+	})
+
+	t.Run("should require the active key to be in the set", func(t *testing.T) {
+		_, err := auth.NewJWTKeySet("missing", map[string][]byte{"2024-06": []byte(currentSecret)}, nil)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNewAuthServiceFromConfig_JWTKeys(t *testing.T) {
+	cfg := &config.Config{
+		JWTSecret:    "legacy-secret",
+		JWTKeys:      "new:new-secret,old:old-secret",
+		JWTActiveKID: "new",
+	}
+
+	service, err := auth.NewAuthServiceFromConfig(cfg, nil, nil, nil, helpers.GetTestLogger(t))
+	require.NoError(t, err)
+
+	_, err = service.VerifyJWT(helpers.SignTestTokenWithKID(t, "old", "old-secret", 1))
+	assert.NoError(t, err)
+	_, err = service.VerifyJWT(helpers.SignTestToken(t, "legacy-secret", 1))
+	assert.NoError(t, err)
+}
+
+func TestParseJWTKeys(t *testing.T) {
+	t.Run("should parse id:secret pairs", func(t *testing.T) {
+		keys, err := config.ParseJWTKeys(" new:secret:with:colons , old:old-secret,")
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"new": "secret:with:colons", "old": "old-secret"}, keys)
+	})
+
+	t.Run("should reject malformed entries without leaking the secret", func(t *testing.T) {
+		for _, spec := range []string{"hunter2", ":hunter2", "kid:", "a:hunter2,a:hunter2"} {
+			_, err := config.ParseJWTKeys(spec)
+
+			require.Error(t, err, spec)
+			assert.NotContains(t, err.Error(), "hunter2", spec)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type localeTestRequest struct {
+	Name  string `json:"name" binding:"required,min=3"`
+	Email string `json:"email" binding:"required,email"`
+}
+
+func newLocaleTestRouter(defaultLocale string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Locale(defaultLocale))
+	router.POST("/validate", func(c *gin.Context) {
+		var req localeTestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errs.RespondWithValidationError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+	return router
+}
+
+func postValidation(t *testing.T, router *gin.Engine, acceptLanguage, body string) errs.ValidationErrorResponse {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if acceptLanguage != "" {
+		req.Header.Set("Accept-Language", acceptLanguage)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var response errs.ValidationErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	cases := []struct {
+		name     string
+		header   string
+		fallback string
+		expected string
+	}{
+		{"empty header uses fallback", "", "en", "en"},
+		{"exact match", "es", "en", "es"},
+		{"region subtag matches base language", "es-MX", "en", "es"},
+		{"highest weight wins", "en;q=0.5, es;q=0.9", "en", "es"},
+		{"unsupported languages are skipped", "fr-FR, de;q=0.9, es;q=0.1", "en", "es"},
+		{"nothing supported uses fallback", "fr, de", "es", "es"},
+		{"zero weight is ignored", "es;q=0, en;q=0.1", "es", "en"},
+		{"header order breaks ties", "es, en", "en", "es"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, errs.ParseAcceptLanguage(tc.header, tc.fallback))
+		})
+	}
+}
+
+func TestLocaleMiddleware(t *testing.T) {
+	t.Run("should store the resolved locale and advertise it", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Locale("en"))
+		router.GET("/locale", func(c *gin.Context) {
+			c.String(http.StatusOK, middleware.GetLocaleFromContext(c))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/locale", nil)
+		req.Header.Set("Accept-Language", "es-ES,es;q=0.9")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, "es", w.Body.String())
+		assert.Equal(t, "es", w.Header().Get("Content-Language"))
+	})
+
+	t.Run("should ignore an unsupported configured default", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Locale("xx"))
+		router.GET("/locale", func(c *gin.Context) {
+			c.String(http.StatusOK, middleware.GetLocaleFromContext(c))
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/locale", nil))
+
+		assert.Equal(t, errs.DefaultLocale, w.Body.String())
+	})
+}
+
+func TestRespondWithValidationError_Localized(t *testing.T) {
+	router := newLocaleTestRouter("en")
+	body := `{"name":"ab"}`
+
+	english := postValidation(t, router, "en-US", body)
+	spanish := postValidation(t, router, "es", body)
+
+	t.Run("should keep error keys identical across locales", func(t *testing.T) {
+		assert.Equal(t, english.Errors, spanish.Errors)
+		assert.Equal(t, english.ErrorKey, spanish.ErrorKey)
+		assert.Equal(t, []string{"validation.email.required"}, english.Errors["email"])
+	})
+
+	t.Run("should translate the messages", func(t *testing.T) {
+		assert.Equal(t, "The given data was invalid.", english.Message)
+		assert.Equal(t, "Los datos proporcionados no son válidos.", spanish.Message)
+		assert.NotEqual(t, english.Message, spanish.Message)
+
+		assert.Equal(t, []string{"The email field is required."}, english.Messages["email"])
+		assert.Equal(t, []string{"El campo email es obligatorio."}, spanish.Messages["email"])
+		assert.Equal(t, []string{"The name must be at least 3 characters."}, english.Messages["name"])
+		assert.Equal(t, []string{"El campo name debe tener al menos 3 caracteres."}, spanish.Messages["name"])
+	})
+
+	t.Run("should use the configured default without Accept-Language", func(t *testing.T) {
+		response := postValidation(t, newLocaleTestRouter("es"), "", body)
+
+		assert.Equal(t, spanish.Message, response.Message)
+	})
+
+	t.Run("should localize malformed body messages", func(t *testing.T) {
+		english := postValidation(t, router, "en", `{"name":`)
+		spanish := postValidation(t, router, "es", `{"name":`)
+
+		assert.Equal(t, english.Errors, spanish.Errors)
+		assert.Equal(t, []string{"The request body is invalid or malformed."}, english.Messages["body"])
+		assert.Equal(t, []string{"El cuerpo de la solicitud no es válido."}, spanish.Messages["body"])
+	})
+}
+
+type strongPasswordTestRequest struct {
+	Password string `json:"password" binding:"required,strongpassword"`
+}
+
+func TestStrongPasswordValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Locale("en"))
+	router.POST("/validate", func(c *gin.Context) {
+		var req strongPasswordTestRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errs.RespondWithValidationError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	post := func(password string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(strongPasswordTestRequest{Password: password})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(string(body)))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("should reject weak passwords with the strongpassword key", func(t *testing.T) {
+		for _, password := range []string{"abc123", "password", "12345678", "ab1"} {
+			w := post(password)
+			require.Equal(t, http.StatusBadRequest, w.Code, password)
+
+			var response errs.ValidationErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, []string{"validation.password.strongpassword"}, response.Errors["password"], password)
+			assert.Equal(t, []string{"The password must be at least 8 characters and contain a letter and a digit."}, response.Messages["password"])
+		}
+	})
+
+	t.Run("should accept strong passwords", func(t *testing.T) {
+		for _, password := range []string{"password123", "1234567a", "pässwörd9"} {
+			assert.Equal(t, http.StatusNoContent, post(password).Code, password)
+		}
+	})
+}
@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"app/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	t.Run("should keep the first records of each key and count the rest", func(t *testing.T) {
+		sampler := logger.NewSampler(3)
+
+		for i := 0; i < 3; i++ {
+			ok, dropped := sampler.Allow("/examples")
+			assert.True(t, ok, "record %d", i)
+			assert.Zero(t, dropped)
+		}
+		for i := 0; i < 4; i++ {
+			ok, _ := sampler.Allow("/examples")
+			assert.False(t, ok)
+		}
+
+		ok, _ := sampler.Allow("/uploads")
+		assert.True(t, ok, "keys have their own budget")
+	})
+
+	t.Run("should refill over time and report what was dropped", func(t *testing.T) {
+		sampler := logger.NewSampler(10)
+
+		for i := 0; i < 12; i++ {
+			sampler.Allow("/examples")
+		}
+		time.Sleep(150 * time.Millisecond) // refills one record at 10 a second
+
+		ok, dropped := sampler.Allow("/examples")
+		assert.True(t, ok)
+		assert.EqualValues(t, 2, dropped)
+	})
+
+	t.Run("should keep everything when disabled", func(t *testing.T) {
+		sampler := logger.NewSampler(0)
+		assert.Nil(t, sampler)
+
+		for i := 0; i < 100; i++ {
+			ok, _ := sampler.Allow("/examples")
+			assert.True(t, ok)
+		}
+	})
+}
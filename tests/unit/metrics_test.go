@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/internal/cache"
+	"app/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMetricsRouter(m *metrics.Metrics) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(m.Middleware())
+	router.GET("/metrics", m.Handler())
+	router.GET("/examples/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	return router
+}
+
+func scrapeMetrics(t *testing.T, router *gin.Engine) string {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestMetrics(t *testing.T) {
+	t.Run("should count requests by method, route template and status", func(t *testing.T) {
+		m := metrics.New()
+		router := newMetricsRouter(m)
+
+		for _, id := range []string{"1", "2"} {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/examples/"+id, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/nope/123", nil))
+
+		output := scrapeMetrics(t, router)
+		assert.Contains(t, output, `http_requests_total{method="GET",route="/examples/:id",status="200"} 2`)
+		assert.Contains(t, output, `http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+		assert.Contains(t, output, `http_request_duration_seconds_count{method="GET",route="/examples/:id",status="200"} 2`)
+		assert.NotContains(t, output, "/nope/123")
+	})
+
+	t.Run("should count cache hits and misses", func(t *testing.T) {
+		ctx := context.Background()
+		m := metrics.New()
+		c := cache.NewInstrumentedCache(cache.NewMemoryCache(), m)
+
+		var value string
+		assert.ErrorIs(t, c.Get(ctx, "missing", &value), cache.ErrKeyNotFound)
+		require.NoError(t, c.Remember(ctx, "key", time.Minute, func() (interface{}, error) {
+			return "computed", nil
+		}, &value))
+		require.NoError(t, c.Remember(ctx, "key", time.Minute, func() (interface{}, error) {
+			t.Fatal("callback should not run on a hit")
+			return nil, nil
+		}, &value))
+		assert.Equal(t, "computed", value)
+
+		output := scrapeMetrics(t, newMetricsRouter(m))
+		assert.Contains(t, output, `cache_lookups_total{result="hit"} 1`)
+		assert.Contains(t, output, `cache_lookups_total{result="miss"} 2`)
+	})
+}
@@ -0,0 +1,103 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"app/internal/db"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadMigrations(t *testing.T) {
+	t.Run("should list migrations in version order and spot missing downs", func(t *testing.T) {
+		dir := t.TempDir()
+		seed := map[string]string{
+			"010_add_index.sql": "-- +goose Up\nCREATE INDEX idx_a ON a(id);\n\n-- +goose Down\n-- +goose StatementBegin\n-- nothing to undo yet\n-- +goose StatementEnd\n",
+			"002_add_b.sql":     "-- +goose Up\nCREATE TABLE b (id INT);\n",
+			"001_create_a.sql":  "-- +goose Up\nCREATE TABLE a (id INT);\n\n-- +goose Down\nDROP TABLE a;\n",
+			"README.md":         "not a migration",
+		}
+		for name, content := range seed {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		}
+
+		files, err := db.ReadMigrations(dir)
+
+		require.NoError(t, err)
+		assert.Equal(t, []db.MigrationFile{
+			{Version: 1, Name: "001_create_a.sql", HasDown: true},
+			{Version: 2, Name: "002_add_b.sql", HasDown: false},
+			{Version: 10, Name: "010_add_index.sql", HasDown: false},
+		}, files)
+	})
+
+	t.Run("should find a down in every migration of the repo", func(t *testing.T) {
+		files, err := db.ReadMigrations(filepath.Join("..", "..", "migrations"))
+
+		require.NoError(t, err)
+		require.NotEmpty(t, files)
+		assert.Empty(t, db.CheckMigrations(0, files).MissingDown)
+	})
+}
+
+func TestCheckMigrations(t *testing.T) {
+	files := []db.MigrationFile{
+		{Version: 1, Name: "001_create_a.sql", HasDown: true},
+		{Version: 2, Name: "002_add_b.sql", HasDown: false},
+		{Version: 3, Name: "003_add_c.sql", HasDown: true},
+	}
+
+	t.Run("should be up to date at the latest version", func(t *testing.T) {
+		status := db.CheckMigrations(3, files)
+
+		assert.True(t, status.UpToDate())
+		assert.False(t, status.Ahead())
+		assert.Empty(t, status.Pending)
+		assert.Equal(t, []db.MigrationFile{files[1]}, status.MissingDown)
+	})
+
+	t.Run("should list pending migrations when the database is behind", func(t *testing.T) {
+		status := db.CheckMigrations(1, files)
+
+		assert.False(t, status.UpToDate())
+		assert.Equal(t, int64(3), status.LatestVersion)
+		assert.Equal(t, files[1:], status.Pending)
+	})
+
+	t.Run("should report a database ahead of the files", func(t *testing.T) {
+		status := db.CheckMigrations(4, files)
+
+		assert.False(t, status.UpToDate())
+		assert.True(t, status.Ahead())
+		assert.Empty(t, status.Pending)
+	})
+}
+
+func TestDiffSchemas(t *testing.T) {
+	expected := db.Schema{
+		"column users.id":    "integer NOT NULL",
+		"column users.email": "character varying NOT NULL",
+		"index users_pkey":   "CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id)",
+	}
+
+	t.Run("should match identical schemas", func(t *testing.T) {
+		assert.Empty(t, db.DiffSchemas(expected, expected))
+	})
+
+	t.Run("should report missing, changed and unexpected objects", func(t *testing.T) {
+		actual := db.Schema{
+			"column users.id":       "integer NOT NULL",
+			"column users.email":    "character varying",
+			"column users.nickname": "text",
+		}
+
+		assert.Equal(t, []string{
+			"changed column users.email: character varying, migrations give character varying NOT NULL",
+			"missing index users_pkey (CREATE UNIQUE INDEX users_pkey ON public.users USING btree (id))",
+			"unexpected column users.nickname (text)",
+		}, db.DiffSchemas(expected, actual))
+	})
+}
@@ -0,0 +1,301 @@
+package unit
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal"
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaginationOffset(t *testing.T) {
+	t.Run("should compute the offset", func(t *testing.T) {
+		offset, err := internal.PaginationOffset(3, 20)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(40), offset)
+	})
+
+	t.Run("should reject a page whose offset overflows int32", func(t *testing.T) {
+		// (MaxInt32 - 1) * 100 wraps around in int32 arithmetic
+		_, err := internal.PaginationOffset(math.MaxInt32, 100)
+
+		assert.ErrorIs(t, err, internal.ErrPageTooDeep)
+	})
+}
+
+func TestGetPaginationParamsFromContext_MaxOffset(t *testing.T) {
+	parse := func(target string, handlers ...gin.HandlerFunc) (middleware.PaginationParams, error) {
+		gin.SetMode(gin.TestMode)
+		var params middleware.PaginationParams
+		var parseErr error
+
+		router := gin.New()
+		router.Use(handlers...)
+		router.GET("/items", func(c *gin.Context) {
+			params, parseErr = middleware.GetPaginationParamsFromContext(c, 20, 1, 100)
+		})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+
+		return params, parseErr
+	}
+
+	t.Run("should accept a page within the default max offset", func(t *testing.T) {
+		params, err := parse("/items?page=3&page_size=50")
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), params.Page)
+	})
+
+	t.Run("should reject a page that would overflow int32", func(t *testing.T) {
+		_, err := parse("/items?page=2147483647&page_size=100")
+
+		assert.ErrorIs(t, err, internal.ErrPageTooDeep)
+	})
+
+	t.Run("should reject a page beyond the default max offset", func(t *testing.T) {
+		_, err := parse("/items?page=1000000")
+
+		require.ErrorIs(t, err, internal.ErrPageTooDeep)
+		domainErr := errs.ExtractDomainError(err)
+		assert.Equal(t, http.StatusBadRequest, domainErr.Status)
+		assert.Equal(t, errs.ErrKeyValidationPageTooDeep, domainErr.Key)
+		assert.Equal(t, middleware.DefaultMaxPaginationOffset, domainErr.Details["max_offset"])
+	})
+
+	t.Run("should honour a configured max offset", func(t *testing.T) {
+		_, err := parse("/items?page=3&page_size=10", middleware.PaginationLimits(10))
+		assert.ErrorIs(t, err, internal.ErrPageTooDeep)
+
+		_, err = parse("/items?page=2&page_size=10", middleware.PaginationLimits(10))
+		assert.NoError(t, err)
+	})
+
+	t.Run("should keep invalid page errors as bad requests", func(t *testing.T) {
+		_, err := parse("/items?page=0")
+		assert.ErrorIs(t, err, middleware.ErrInvalidPageParameter)
+
+		_, err = parse("/items?page_size=500")
+		assert.ErrorIs(t, err, middleware.ErrInvalidPageSize)
+		assert.Contains(t, err.Error(), "must be between 1 and 100")
+	})
+}
+
+func TestSetPaginationHeaders(t *testing.T) {
+	headersFor := func(target string, meta internal.PaginationMeta) http.Header {
+		gin.SetMode(gin.TestMode)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+
+		internal.SetPaginationHeaders(c, meta)
+		return w.Header()
+	}
+
+	t.Run("should link next, prev and last from a middle page", func(t *testing.T) {
+		header := headersFor("/api/v1/examples?page=3&page_size=10&created_after=2024-01-01T00:00:00Z",
+			internal.NewPaginationMeta(95, 3, 10))
+
+		assert.Equal(t, "95", header.Get("X-Total-Count"))
+		assert.Equal(t, "3", header.Get("X-Page"))
+		assert.Equal(t, "10", header.Get("X-Per-Page"))
+
+		link := header.Get("Link")
+		assert.Contains(t, link, `</api/v1/examples?created_after=2024-01-01T00%3A00%3A00Z&page=4&page_size=10>; rel="next"`)
+		assert.Contains(t, link, `</api/v1/examples?created_after=2024-01-01T00%3A00%3A00Z&page=2&page_size=10>; rel="prev"`)
+		assert.Contains(t, link, `</api/v1/examples?created_after=2024-01-01T00%3A00%3A00Z&page=10&page_size=10>; rel="last"`)
+	})
+
+	t.Run("should omit prev on the first page and next on the last", func(t *testing.T) {
+		first := headersFor("/api/v1/examples", internal.NewPaginationMeta(30, 1, 20)).Get("Link")
+		assert.Contains(t, first, `rel="next"`)
+		assert.NotContains(t, first, `rel="prev"`)
+
+		last := headersFor("/api/v1/examples?page=2", internal.NewPaginationMeta(30, 2, 20)).Get("Link")
+		assert.NotContains(t, last, `rel="next"`)
+		assert.Contains(t, last, `</api/v1/examples?page=1>; rel="prev"`)
+		assert.Contains(t, last, `</api/v1/examples?page=2>; rel="last"`)
+	})
+
+	t.Run("should treat a page past the end as the last page", func(t *testing.T) {
+		header := headersFor("/api/v1/examples?page=9", internal.NewPaginationMeta(45, 9, 20))
+
+		assert.Equal(t, "3", header.Get("X-Page"))
+		assert.Equal(t, `</api/v1/examples?page=2>; rel="prev", </api/v1/examples?page=3>; rel="last"`, header.Get("Link"))
+	})
+
+	t.Run("should only link last without results", func(t *testing.T) {
+		link := headersFor("/api/v1/examples", internal.NewPaginationMeta(0, 1, 20)).Get("Link")
+
+		assert.Equal(t, `</api/v1/examples?page=1>; rel="last"`, link)
+	})
+}
+
+func TestNewPaginationMeta(t *testing.T) {
+	tests := []struct {
+		name     string
+		total    int64
+		page     int32
+		pageSize int32
+		expected internal.PaginationMeta
+	}{
+		{
+			name:     "zero results",
+			total:    0,
+			page:     1,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 0, CurrentPage: 1, LastPage: 1, PerPage: 20, TotalPages: 0},
+		},
+		{
+			name:     "zero results on a later page",
+			total:    0,
+			page:     3,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 0, CurrentPage: 3, LastPage: 1, PerPage: 20, TotalPages: 0},
+		},
+		{
+			name:     "single partial page",
+			total:    5,
+			page:     1,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 5, CurrentPage: 1, LastPage: 1, PerPage: 20, TotalPages: 1, From: 1, To: 5},
+		},
+		{
+			name:     "exact multiple, middle page",
+			total:    60,
+			page:     2,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 60, CurrentPage: 2, LastPage: 3, PerPage: 20, TotalPages: 3, From: 21, To: 40, HasNext: true, HasPrev: true},
+		},
+		{
+			name:     "exact multiple, last page",
+			total:    60,
+			page:     3,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 60, CurrentPage: 3, LastPage: 3, PerPage: 20, TotalPages: 3, From: 41, To: 60, HasPrev: true},
+		},
+		{
+			name:     "partial last page",
+			total:    45,
+			page:     3,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 45, CurrentPage: 3, LastPage: 3, PerPage: 20, TotalPages: 3, From: 41, To: 45, HasPrev: true},
+		},
+		{
+			name:     "first of several pages",
+			total:    45,
+			page:     1,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 45, CurrentPage: 1, LastPage: 3, PerPage: 20, TotalPages: 3, From: 1, To: 20, HasNext: true},
+		},
+		{
+			name:     "page past the end is clamped",
+			total:    45,
+			page:     9,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 45, CurrentPage: 3, LastPage: 3, PerPage: 20, TotalPages: 3, From: 41, To: 45, HasPrev: true},
+		},
+		{
+			name:     "page below 1 is clamped",
+			total:    45,
+			page:     0,
+			pageSize: 20,
+			expected: internal.PaginationMeta{Total: 45, CurrentPage: 1, LastPage: 3, PerPage: 20, TotalPages: 3, From: 1, To: 20, HasNext: true},
+		},
+		{
+			name:     "invalid page size",
+			total:    45,
+			page:     1,
+			pageSize: 0,
+			expected: internal.PaginationMeta{Total: 45, CurrentPage: 1, LastPage: 1, PerPage: 0, TotalPages: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, internal.NewPaginationMeta(tt.total, tt.page, tt.pageSize))
+		})
+	}
+
+	t.Run("should not overflow with huge totals", func(t *testing.T) {
+		meta := internal.NewPaginationMeta(math.MaxInt64, math.MaxInt32, 100)
+
+		assert.Equal(t, int32(math.MaxInt32), meta.TotalPages)
+		assert.Equal(t, int32(math.MaxInt32), meta.CurrentPage)
+		assert.Equal(t, int64(math.MaxInt32)*100, meta.To)
+	})
+}
+
+func TestCursor(t *testing.T) {
+	t.Run("should round-trip an ID", func(t *testing.T) {
+		id, err := internal.DecodeCursor(internal.EncodeCursor(42))
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(42), id)
+	})
+
+	t.Run("should reject malformed cursors", func(t *testing.T) {
+		for _, cursor := range []string{"not base64!", "YWJj", internal.EncodeCursor(0), "OTk5OTk5OTk5OTk"} {
+			_, err := internal.DecodeCursor(cursor)
+			assert.ErrorIs(t, err, internal.ErrInvalidCursor, "cursor %q", cursor)
+		}
+	})
+}
+
+func TestGetCursorParamsFromContext(t *testing.T) {
+	parse := func(target string) (bool, middleware.CursorParams, error) {
+		gin.SetMode(gin.TestMode)
+		var wantsCursor bool
+		var params middleware.CursorParams
+		var parseErr error
+
+		router := gin.New()
+		router.GET("/items", func(c *gin.Context) {
+			wantsCursor = middleware.WantsCursorPagination(c)
+			params, parseErr = middleware.GetCursorParamsFromContext(c, 20, 1, 100)
+		})
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", target, nil))
+
+		return wantsCursor, params, parseErr
+	}
+
+	t.Run("should start from the first page on an empty cursor", func(t *testing.T) {
+		wantsCursor, params, err := parse("/items?cursor=")
+
+		require.NoError(t, err)
+		assert.True(t, wantsCursor)
+		assert.Nil(t, params.AfterID)
+		assert.Equal(t, int32(20), params.Limit)
+	})
+
+	t.Run("should decode the cursor and limit", func(t *testing.T) {
+		_, params, err := parse("/items?cursor=" + internal.EncodeCursor(17) + "&limit=5")
+
+		require.NoError(t, err)
+		require.NotNil(t, params.AfterID)
+		assert.Equal(t, int32(17), *params.AfterID)
+		assert.Equal(t, int32(5), params.Limit)
+	})
+
+	t.Run("should not switch to cursor pagination without a cursor", func(t *testing.T) {
+		wantsCursor, _, _ := parse("/items?page=2")
+
+		assert.False(t, wantsCursor)
+	})
+
+	t.Run("should reject a bad cursor or limit", func(t *testing.T) {
+		_, _, err := parse("/items?cursor=garbage")
+		assert.ErrorIs(t, err, internal.ErrInvalidCursor)
+
+		_, _, err = parse("/items?cursor=&limit=101")
+		assert.ErrorIs(t, err, middleware.ErrInvalidLimit)
+		assert.Equal(t, http.StatusBadRequest, errs.ExtractDomainError(err).Status)
+	})
+}
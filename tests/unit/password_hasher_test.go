@@ -0,0 +1,133 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"app/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fastArgon2id keeps the tests quick, the defaults take 64 MiB per hash
+var fastArgon2id = auth.Argon2idHasher{Memory: 1024, Iterations: 1, Parallelism: 1}
+
+func TestPasswordHashers(t *testing.T) {
+	hashers := map[string]auth.PasswordHasher{
+		"bcrypt":   auth.BcryptHasher{Cost: 4},
+		"argon2id": fastArgon2id,
+	}
+
+	for name, hasher := range hashers {
+		t.Run(name, func(t *testing.T) {
+			hash, err := hasher.Hash("password123")
+			require.NoError(t, err)
+			assert.NotContains(t, hash, "password123")
+
+			ok, err := hasher.Verify("password123", hash)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = hasher.Verify("wrong-password", hash)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			again, err := hasher.Hash("password123")
+			require.NoError(t, err)
+			assert.NotEqual(t, hash, again, "hashes should be salted")
+		})
+	}
+
+	t.Run("should store the argon2id parameters in the hash", func(t *testing.T) {
+		hash, err := fastArgon2id.Hash("password123")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$"), hash)
+	})
+
+	t.Run("should reject a malformed argon2id hash", func(t *testing.T) {
+		_, err := fastArgon2id.Verify("password123", "$argon2id$v=19$m=1024")
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject argon2id parameters that can't or shouldn't be computed", func(t *testing.T) {
+		hash, err := fastArgon2id.Hash("password123")
+		require.NoError(t, err)
+		saltAndKey := strings.SplitN(hash, "$", 5)[4]
+
+		for _, params := range []string{"m=65536,t=3,p=0", "m=65536,t=0,p=4", "m=0,t=3,p=4", "m=4194304,t=3,p=4"} {
+			tampered := "$argon2id$v=19$" + params + "$" + saltAndKey
+
+			assert.NotPanics(t, func() {
+				ok, err := fastArgon2id.Verify("password123", tampered)
+				assert.Error(t, err, params)
+				assert.False(t, ok, params)
+			})
+		}
+	})
+}
+
+func TestMultiHasher(t *testing.T) {
+	bcryptHash, err := auth.BcryptHasher{}.Hash("password123")
+	require.NoError(t, err)
+	argon2idHash, err := fastArgon2id.Hash("password123")
+	require.NoError(t, err)
+
+	t.Run("should hash with the configured algorithm", func(t *testing.T) {
+		bcryptHasher, err := auth.NewPasswordHasher(auth.HasherBcrypt)
+		require.NoError(t, err)
+		hash, err := bcryptHasher.Hash("password123")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(hash, "$2a$"), hash)
+
+		argon2idHasher, err := auth.NewPasswordHasher(auth.HasherArgon2id)
+		require.NoError(t, err)
+		hash, err = argon2idHasher.Hash("password123")
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(hash, "$argon2id$"), hash)
+	})
+
+	t.Run("should verify hashes of either algorithm whichever is configured", func(t *testing.T) {
+		for _, name := range []string{auth.HasherBcrypt, auth.HasherArgon2id} {
+			hasher, err := auth.NewPasswordHasher(name)
+			require.NoError(t, err)
+
+			for _, hash := range []string{bcryptHash, argon2idHash} {
+				ok, err := hasher.Verify("password123", hash)
+				require.NoError(t, err)
+				assert.True(t, ok, "%s should verify %s", name, hash)
+
+				ok, err = hasher.Verify("wrong-password", hash)
+				require.NoError(t, err)
+				assert.False(t, ok)
+			}
+		}
+	})
+
+	t.Run("should ask for a rehash when the algorithm or parameters changed", func(t *testing.T) {
+		argon2idHasher, err := auth.NewPasswordHasher(auth.HasherArgon2id)
+		require.NoError(t, err)
+		assert.True(t, argon2idHasher.NeedsRehash(bcryptHash))
+		assert.True(t, argon2idHasher.NeedsRehash(argon2idHash), "weaker parameters than the defaults")
+
+		current, err := argon2idHasher.Hash("password123")
+		require.NoError(t, err)
+		assert.False(t, argon2idHasher.NeedsRehash(current))
+
+		bcryptHasher, err := auth.NewPasswordHasher("")
+		require.NoError(t, err)
+		assert.False(t, bcryptHasher.NeedsRehash(bcryptHash))
+		assert.True(t, bcryptHasher.NeedsRehash(argon2idHash))
+	})
+
+	t.Run("should reject unknown algorithms and hashes", func(t *testing.T) {
+		_, err := auth.NewPasswordHasher("md5")
+		assert.Error(t, err)
+
+		hasher, err := auth.NewPasswordHasher(auth.HasherBcrypt)
+		require.NoError(t, err)
+		_, err = hasher.Verify("password123", "5f4dcc3b5aa765d61d8327deb882cf99")
+		assert.ErrorIs(t, err, auth.ErrUnknownPasswordHash)
+	})
+}
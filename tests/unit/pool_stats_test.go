@@ -0,0 +1,47 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"app/internal/db"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogPoolStats(t *testing.T) {
+	// Pools connect lazily, so stats are available without a database
+	pool, err := pgxpool.New(context.Background(), "postgres://postgres@127.0.0.1:1/app?pool_max_conns=7")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	log, buf := helpers.NewBufferLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		db.LogPoolStats(ctx, "primary", pool, log, 5*time.Millisecond)
+		close(done)
+	}()
+
+	time.Sleep(30 * time.Millisecond)
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("LogPoolStats didn't stop when its context was cancelled")
+	}
+
+	output := buf.String()
+	assert.GreaterOrEqual(t, strings.Count(output, `msg="Database pool stats"`), 1)
+	assert.Contains(t, output, "level=DEBUG")
+	assert.Contains(t, output, "pool=primary")
+	assert.Contains(t, output, "max_conns=7")
+	assert.Contains(t, output, "acquired_conns=0")
+	assert.Contains(t, output, "idle_conns=0")
+	assert.Contains(t, output, "avg_acquire_wait=0s")
+}
@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProblemTestRouter(enabled bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ProblemJSON(enabled))
+	router.GET("/examples/:id", func(c *gin.Context) {
+		errs.RespondWithError(c, errs.NewNotFoundError(errs.ErrKeyExampleNotFound, "Example not found").
+			WithDetails(map[string]interface{}{"id": c.Param("id")}))
+	})
+	return router
+}
+
+func requestProblem(router *gin.Engine, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/examples/42", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRespondWithError_Format(t *testing.T) {
+	t.Run("should keep the default format", func(t *testing.T) {
+		w := requestProblem(newProblemTestRouter(false), "application/json")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+
+		var response errs.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, errs.ErrKeyExampleNotFound, response.ErrorKey)
+		assert.Equal(t, "Example not found", response.Message)
+		assert.Equal(t, http.StatusNotFound, response.Status)
+		assert.NotEmpty(t, response.Timestamp)
+	})
+
+	t.Run("should return problem+json when the client asks for it", func(t *testing.T) {
+		w := requestProblem(newProblemTestRouter(false), "application/problem+json, application/json;q=0.5")
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Equal(t, errs.ContentTypeProblemJSON, w.Header().Get("Content-Type"))
+
+		var problem errs.ProblemDetails
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+		assert.Equal(t, "urn:problem-type:examples.not_found", problem.Type)
+		assert.Equal(t, "Not Found", problem.Title)
+		assert.Equal(t, http.StatusNotFound, problem.Status)
+		assert.Equal(t, "Example not found", problem.Detail)
+		assert.Equal(t, "/examples/42", problem.Instance)
+		assert.Equal(t, errs.ErrKeyExampleNotFound, problem.ErrorKey)
+		assert.Equal(t, "42", problem.Details["id"])
+	})
+
+	t.Run("should return problem+json for every client when enabled", func(t *testing.T) {
+		w := requestProblem(newProblemTestRouter(true), "")
+
+		assert.Equal(t, errs.ContentTypeProblemJSON, w.Header().Get("Content-Type"))
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Contains(t, body, "type")
+		assert.Contains(t, body, "title")
+		assert.NotContains(t, body, "timestamp")
+	})
+
+	t.Run("should ignore problem+json with zero weight", func(t *testing.T) {
+		w := requestProblem(newProblemTestRouter(false), "application/problem+json;q=0")
+
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+}
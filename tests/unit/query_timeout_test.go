@@ -0,0 +1,74 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"app/config"
+	"app/internal/db"
+	"app/tests"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPoolConfig_StatementTimeout(t *testing.T) {
+	t.Run("should set statement_timeout from config", func(t *testing.T) {
+		poolConfig, err := db.NewPoolConfig(&config.Config{
+			DatabaseURL:    "postgres://postgres@localhost:5432/app",
+			DBQueryTimeout: 5 * time.Second,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "5000", poolConfig.ConnConfig.RuntimeParams["statement_timeout"])
+	})
+
+	t.Run("should leave queries unbounded when the timeout is 0", func(t *testing.T) {
+		poolConfig, err := db.NewPoolConfig(&config.Config{
+			DatabaseURL: "postgres://postgres@localhost:5432/app",
+		})
+		require.NoError(t, err)
+
+		assert.NotContains(t, poolConfig.ConnConfig.RuntimeParams, "statement_timeout")
+	})
+}
+
+func TestIsQueryTimeout(t *testing.T) {
+	assert.True(t, db.IsQueryTimeout(&pgconn.PgError{Code: "57014"}))
+	assert.True(t, db.IsQueryTimeout(fmt.Errorf("query failed: %w", context.DeadlineExceeded)))
+	assert.False(t, db.IsQueryTimeout(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, db.IsQueryTimeout(errors.New("connection refused")))
+}
+
+func TestQueryTimeout_SlowQuery(t *testing.T) {
+	t.Run("should cancel a statement that exceeds statement_timeout", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			require.NoError(t, queries.SetLocalStatementTimeout(ctx, 50*time.Millisecond))
+
+			started := time.Now()
+			_, err := tx.Exec(ctx, "SELECT pg_sleep(2)")
+
+			require.Error(t, err)
+			assert.True(t, db.IsQueryTimeout(err), "unexpected error: %v", err)
+			assert.Less(t, time.Since(started), time.Second)
+		})
+	})
+
+	t.Run("should cancel a query when its context deadline passes", func(t *testing.T) {
+		ctx, cancel := db.WithQueryTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		started := time.Now()
+		_, err := tests.GetTestDBPool().Exec(ctx, "SELECT pg_sleep(2)")
+
+		require.Error(t, err)
+		assert.True(t, db.IsQueryTimeout(err), "unexpected error: %v", err)
+		assert.Less(t, time.Since(started), time.Second)
+	})
+}
@@ -0,0 +1,78 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	t.Run("should allow up to the limit and then reject", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(3, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			allowed, _ := limiter.Allow("client")
+			assert.True(t, allowed, "request %d", i+1)
+		}
+
+		allowed, retryAfter := limiter.Allow("client")
+		assert.False(t, allowed)
+		assert.Greater(t, retryAfter, time.Duration(0))
+		assert.LessOrEqual(t, retryAfter, time.Minute)
+	})
+
+	t.Run("should count keys separately", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(1, time.Minute)
+
+		allowed, _ := limiter.Allow("a")
+		assert.True(t, allowed)
+		allowed, _ = limiter.Allow("b")
+		assert.True(t, allowed)
+		allowed, _ = limiter.Allow("a")
+		assert.False(t, allowed)
+	})
+
+	t.Run("should reset once the window has passed", func(t *testing.T) {
+		limiter := middleware.NewRateLimiter(1, 20*time.Millisecond)
+
+		allowed, _ := limiter.Allow("client")
+		assert.True(t, allowed)
+		allowed, _ = limiter.Allow("client")
+		assert.False(t, allowed)
+
+		time.Sleep(30 * time.Millisecond)
+
+		allowed, _ = limiter.Allow("client")
+		assert.True(t, allowed)
+	})
+}
+
+func TestRateLimit_Middleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/limited", middleware.RateLimit(middleware.NewRateLimiter(1, time.Minute)), func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, request("10.0.0.1:1000").Code)
+
+	limited := request("10.0.0.1:1001")
+	assert.Equal(t, http.StatusTooManyRequests, limited.Code)
+	assert.Equal(t, "60", limited.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusNoContent, request("10.0.0.2:1000").Code, "other clients keep their own budget")
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"app/config"
+	appredis "app/internal/redis"
+	"app/tests/helpers"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedisConnection_PoolConfig(t *testing.T) {
+	server := miniredis.RunT(t)
+
+	t.Setenv("REDIS_URL", "redis://"+server.Addr()+"/0")
+	t.Setenv("REDIS_POOL_SIZE", "7")
+	t.Setenv("REDIS_MIN_IDLE", "2")
+	t.Setenv("REDIS_MAX_IDLE", "4")
+	t.Setenv("REDIS_CONN_MAX_IDLE_TIME", "90s")
+	t.Setenv("REDIS_READ_TIMEOUT", "750ms")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	log, _ := helpers.NewBufferLogger()
+	client, err := appredis.NewConnection(cfg, log)
+	require.NoError(t, err)
+	defer client.Close()
+
+	t.Run("should propagate config into the client options", func(t *testing.T) {
+		opt := client.Options()
+
+		assert.Equal(t, 7, opt.PoolSize)
+		assert.Equal(t, 2, opt.MinIdleConns)
+		assert.Equal(t, 4, opt.MaxIdleConns)
+		assert.Equal(t, 90*time.Second, opt.ConnMaxIdleTime)
+		assert.Equal(t, 750*time.Millisecond, opt.ReadTimeout)
+		// Unset variables keep their defaults
+		assert.Equal(t, 5*time.Minute, opt.ConnMaxLifetime)
+		assert.Equal(t, 3*time.Second, opt.WriteTimeout)
+	})
+
+	t.Run("should expose pool stats", func(t *testing.T) {
+		ctx := context.Background()
+		before := appredis.GetPoolStats(client)
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, client.Set(ctx, "key", i, time.Minute).Err())
+		}
+
+		stats := appredis.GetPoolStats(client)
+		assert.Greater(t, stats.TotalConns, uint32(0))
+		assert.Greater(t, stats.Hits, before.Hits, "reused connections should count as hits")
+		assert.LessOrEqual(t, stats.IdleConns, stats.TotalConns)
+		assert.Zero(t, stats.Timeouts)
+	})
+}
+
+func TestRedisConnection_RetriesUntilAvailable(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	t.Setenv("REDIS_URL", "redis://"+addr+"/0")
+	t.Setenv("CONNECT_MAX_ATTEMPTS", "5")
+	t.Setenv("CONNECT_MAX_DELAY", "1s")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	// Redis comes up while the first retry is waiting
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = server.StartAddr(addr)
+	}()
+
+	log, buf := helpers.NewBufferLogger()
+	client, err := appredis.NewConnection(cfg, log)
+	require.NoError(t, err)
+	defer client.Close()
+
+	assert.Contains(t, buf.String(), "Redis not reachable yet, retrying")
+	assert.NoError(t, client.Ping(context.Background()).Err())
+}
+
+func TestRedisConnection_GivesUp(t *testing.T) {
+	server := miniredis.RunT(t)
+	addr := server.Addr()
+	server.Close()
+
+	t.Setenv("REDIS_URL", "redis://"+addr+"/0")
+	t.Setenv("CONNECT_MAX_ATTEMPTS", "2")
+	t.Setenv("CONNECT_MAX_DELAY", "10ms")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+
+	log, buf := helpers.NewBufferLogger()
+	_, err = appredis.NewConnection(cfg, log)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gave up after 2 attempts")
+	assert.Equal(t, 1, strings.Count(buf.String(), "Redis not reachable yet"))
+}
@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal/logger"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestIDTransport(t *testing.T) {
+	received := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Get(logger.RequestIDHeader)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: logger.NewRequestIDTransport(nil)}
+	send := func(t *testing.T, req *http.Request) string {
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return <-received
+	}
+
+	t.Run("should send the request ID stored in the context", func(t *testing.T) {
+		ctx := logger.WithRequestID(context.Background(), "req-123")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, "req-123", send(t, req))
+		assert.Empty(t, req.Header.Get(logger.RequestIDHeader), "the caller's request should not be modified")
+	})
+
+	t.Run("should keep a request ID set by the caller", func(t *testing.T) {
+		ctx := logger.WithRequestID(context.Background(), "req-123")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set(logger.RequestIDHeader, "explicit")
+
+		assert.Equal(t, "explicit", send(t, req))
+	})
+
+	t.Run("should send no header without a request ID", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+
+		assert.Empty(t, send(t, req))
+	})
+}
@@ -0,0 +1,219 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/logger"
+	"app/internal/middleware"
+	"app/tests/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestLoggingRouter(t *testing.T) (*gin.Engine, func() string) {
+	gin.SetMode(gin.TestMode)
+	log, buf := helpers.NewBufferLogger()
+
+	router := gin.New()
+	router.Use(middleware.RequestLogging(log))
+	router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/examples/:id", func(c *gin.Context) {
+		c.Set("user_id", int32(42))
+		c.String(http.StatusOK, "hello")
+	})
+	router.GET("/public", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	return router, buf.String
+}
+
+func TestRequestLogging(t *testing.T) {
+	t.Run("should log route template, response size and user id", func(t *testing.T) {
+		router, logs := newRequestLoggingRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/examples/123", nil))
+
+		output := logs()
+		assert.Contains(t, output, `msg="HTTP request completed"`)
+		assert.Contains(t, output, "route=/examples/:id")
+		assert.Contains(t, output, "response_bytes=5")
+		assert.Contains(t, output, "user_id=42")
+		assert.Contains(t, output, "status=200")
+		assert.NotContains(t, output, "/examples/123", "raw paths should not be logged")
+	})
+
+	t.Run("should omit user id for anonymous requests", func(t *testing.T) {
+		router, logs := newRequestLoggingRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/public", nil))
+
+		output := logs()
+		assert.Contains(t, output, "route=/public")
+		assert.Contains(t, output, "response_bytes=0")
+		assert.NotContains(t, output, "user_id=")
+	})
+
+	t.Run("should log unmatched requests without their raw path", func(t *testing.T) {
+		router, logs := newRequestLoggingRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/users/99/secret", nil))
+
+		output := logs()
+		assert.Contains(t, output, "route=unmatched")
+		assert.Contains(t, output, "status=404")
+		assert.NotContains(t, output, "/users/99/secret")
+	})
+
+	t.Run("should skip health checks", func(t *testing.T) {
+		router, logs := newRequestLoggingRouter(t)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/health", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, logs())
+	})
+}
+
+func TestSampledRequestLogging(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("should drop successful lines past the limit but keep every error", func(t *testing.T) {
+		log, buf := helpers.NewBufferLogger()
+
+		router := gin.New()
+		router.Use(middleware.SampledRequestLogging(log, logger.NewSampler(5)))
+		router.GET("/examples/:id", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		router.GET("/broken", func(c *gin.Context) {
+			_ = c.Error(errors.New("boom"))
+			c.Status(http.StatusInternalServerError)
+		})
+
+		const requests = 50
+		for i := 0; i < requests; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", fmt.Sprintf("/examples/%d", i), nil))
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/broken", nil))
+		}
+
+		output := buf.String()
+		completed := strings.Count(output, `msg="HTTP request completed"`)
+		assert.Less(t, completed, requests, "successful requests should be sampled")
+		assert.GreaterOrEqual(t, completed, 5, "the first lines of each second are kept")
+		assert.Equal(t, requests, strings.Count(output, `msg="HTTP request failed"`), "errors are never sampled")
+	})
+
+	t.Run("should log everything without a sampler", func(t *testing.T) {
+		log, buf := helpers.NewBufferLogger()
+
+		router := gin.New()
+		router.Use(middleware.SampledRequestLogging(log, nil))
+		router.GET("/public", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		for i := 0; i < 20; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/public", nil))
+		}
+
+		assert.Equal(t, 20, strings.Count(buf.String(), `msg="HTTP request completed"`))
+	})
+}
+
+// lookupService stands in for a service a couple of calls below the handler
+type lookupService struct {
+	log *logger.Logger
+}
+
+func (s lookupService) Find(ctx context.Context, id string) {
+	s.load(ctx, id)
+}
+
+func (s lookupService) load(ctx context.Context, id string) {
+	s.log.With("component", "lookup").WarnContext(ctx, "Cache miss", "id", id)
+}
+
+func TestRequestID_LogCorrelation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newRouter := func() (*gin.Engine, *bytes.Buffer) {
+		buf := &bytes.Buffer{}
+		log := logger.NewWithHandler(slog.NewJSONHandler(buf, nil))
+		service := lookupService{log: log}
+
+		router := gin.New()
+		router.Use(middleware.RequestID(log))
+		router.Use(middleware.RequestLogging(log))
+		router.GET("/examples/:id", func(c *gin.Context) {
+			service.Find(c.Request.Context(), c.Param("id"))
+			c.Status(http.StatusNoContent)
+		})
+		return router, buf
+	}
+
+	logLines := func(t *testing.T, buf *bytes.Buffer) []map[string]any {
+		var lines []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var record map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &record))
+			lines = append(lines, record)
+		}
+		return lines
+	}
+
+	t.Run("should add the request id to logs deep in the call chain", func(t *testing.T) {
+		router, buf := newRouter()
+
+		req := httptest.NewRequest("GET", "/examples/7", nil)
+		req.Header.Set("X-Request-ID", "req-123")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 2)
+		assert.Equal(t, "Cache miss", lines[0]["msg"])
+		assert.Equal(t, "lookup", lines[0]["component"])
+		for _, record := range lines {
+			assert.Equal(t, "req-123", record[logger.RequestIDKey], "record %v", record)
+		}
+	})
+
+	t.Run("should log the generated id sent back to the client", func(t *testing.T) {
+		router, buf := newRouter()
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/examples/7", nil))
+
+		requestID := w.Header().Get("X-Request-ID")
+		require.NotEmpty(t, requestID)
+		for _, record := range logLines(t, buf) {
+			assert.Equal(t, requestID, record[logger.RequestIDKey])
+		}
+	})
+
+	t.Run("should omit the attribute without a request id", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		log := logger.NewWithHandler(slog.NewJSONHandler(buf, nil))
+
+		log.InfoContext(context.Background(), "Background work")
+
+		assert.NotContains(t, buf.String(), logger.RequestIDKey)
+	})
+}
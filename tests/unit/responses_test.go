@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"app/internal"
+	"app/internal/example"
+	"app/internal/uploads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The module envelopes are aliases of the generic ones, so these only compile while that holds
+var (
+	_ example.ExampleDataResponse       = internal.DataResponse[*example.ExampleResponse]{}
+	_ example.ExamplesListResponse      = internal.DataResponse[[]example.ExampleResponse]{}
+	_ example.PaginatedExamplesResponse = internal.PaginatedResponse[example.ExampleResponse]{}
+	_ uploads.UploadDataResponse        = internal.DataResponse[*uploads.UploadResponse]{}
+	_ uploads.PaginatedUploadsResponse  = internal.PaginatedResponse[uploads.UploadResponse]{}
+	_ internal.DataResponse[string]     = internal.DataResponse[string]{Data: "ok"}
+	_ internal.DataResponse[map[string]int]
+)
+
+func TestDataResponse_JSON(t *testing.T) {
+	t.Run("should wrap a value in data", func(t *testing.T) {
+		body, err := json.Marshal(internal.DataResponse[*example.ExampleResponse]{
+			Data: &example.ExampleResponse{ID: 1, UserID: 2, Title: "t", CreatedAt: "c", UpdatedAt: "u"},
+		})
+		require.NoError(t, err)
+
+		assert.JSONEq(t, `{"data":{"id":1,"user_id":2,"title":"t","description":"","public":false,"created_at":"c","updated_at":"u"}}`, string(body))
+	})
+
+	t.Run("should encode a nil value as null", func(t *testing.T) {
+		body, err := json.Marshal(uploads.UploadDataResponse{})
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"data":null}`, string(body))
+	})
+
+	t.Run("should match the hand-written envelope byte for byte", func(t *testing.T) {
+		items := []uploads.UploadResponse{{ID: 1, Type: "image", Caption: "c"}}
+
+		generic, err := json.Marshal(uploads.UploadsListResponse{Data: items})
+		require.NoError(t, err)
+		handWritten, err := json.Marshal(struct {
+			Data []uploads.UploadResponse `json:"data"`
+		}{Data: items})
+		require.NoError(t, err)
+
+		assert.Equal(t, string(handWritten), string(generic))
+	})
+}
+
+func TestPaginatedResponse_JSON(t *testing.T) {
+	meta := internal.NewPaginationMeta(3, 1, 2)
+	items := []example.ExampleResponse{{ID: 1}, {ID: 2}}
+
+	generic, err := json.Marshal(example.PaginatedExamplesResponse{Data: items, Pagination: meta})
+	require.NoError(t, err)
+	handWritten, err := json.Marshal(struct {
+		Data       []example.ExampleResponse `json:"data"`
+		Pagination internal.PaginationMeta   `json:"pagination"`
+	}{Data: items, Pagination: meta})
+	require.NoError(t, err)
+
+	assert.Equal(t, string(handWritten), string(generic))
+}
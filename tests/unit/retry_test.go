@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/internal/retry"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := retry.Backoff{Initial: 500 * time.Millisecond, Max: 3 * time.Second}
+
+	expected := []time.Duration{
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		3 * time.Second,
+		3 * time.Second,
+	}
+	for i, want := range expected {
+		assert.Equal(t, want, backoff.Delay(i+1), "attempt %d", i+1)
+	}
+
+	assert.Equal(t, 500*time.Millisecond, retry.ConnectBackoff(10*time.Second).Delay(1))
+	assert.Equal(t, 10*time.Second, retry.ConnectBackoff(10*time.Second).Delay(100))
+}
+
+func TestRetryDo(t *testing.T) {
+	backoff := retry.Backoff{Initial: time.Millisecond, Max: 4 * time.Millisecond}
+	errDown := errors.New("connection refused")
+
+	t.Run("should retry until fn succeeds", func(t *testing.T) {
+		calls := 0
+		var delays []time.Duration
+
+		err := retry.Do(context.Background(), 5, backoff,
+			func(ctx context.Context) error {
+				calls++
+				if calls < 3 {
+					return errDown
+				}
+				return nil
+			},
+			func(attempt int, delay time.Duration, err error) {
+				assert.ErrorIs(t, err, errDown)
+				delays = append(delays, delay)
+			},
+		)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, delays)
+	})
+
+	t.Run("should return the last error once attempts run out", func(t *testing.T) {
+		calls := 0
+		err := retry.Do(context.Background(), 3, backoff, func(ctx context.Context) error {
+			calls++
+			return errDown
+		}, nil)
+
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should make one attempt when attempts is below 1", func(t *testing.T) {
+		calls := 0
+		err := retry.Do(context.Background(), 0, backoff, func(ctx context.Context) error {
+			calls++
+			return errDown
+		}, nil)
+
+		assert.ErrorIs(t, err, errDown)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should stop waiting when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		err := retry.Do(ctx, 5, retry.Backoff{Initial: time.Hour}, func(ctx context.Context) error {
+			cancel()
+			return errDown
+		}, nil)
+
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,66 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRolesContext(userID int32, roles ...string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Set("user_id", userID)
+	c.Set("user_roles", roles)
+	return c
+}
+
+func TestOwnerOrAdmin(t *testing.T) {
+	ownerLookup := func(ownerID int32) (middleware.OwnerLookup, *bool) {
+		called := false
+		return func(ctx context.Context) (int32, error) {
+			called = true
+			return ownerID, nil
+		}, &called
+	}
+
+	t.Run("should scope a regular user to themselves", func(t *testing.T) {
+		c := newRolesContext(7)
+		lookup, called := ownerLookup(42)
+
+		ownerID, err := middleware.OwnerOrAdmin(c, 7, lookup)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), ownerID)
+		assert.False(t, *called, "regular users should never reach the owner lookup")
+	})
+
+	t.Run("should scope an admin to the resource owner", func(t *testing.T) {
+		c := newRolesContext(1, middleware.RoleAdmin)
+		lookup, called := ownerLookup(42)
+
+		ownerID, err := middleware.OwnerOrAdmin(c, 1, lookup)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(42), ownerID)
+		assert.True(t, *called)
+	})
+
+	t.Run("should return the lookup error for admins", func(t *testing.T) {
+		c := newRolesContext(1, middleware.RoleAdmin)
+		notFound := errors.New("not found")
+
+		_, err := middleware.OwnerOrAdmin(c, 1, func(ctx context.Context) (int32, error) {
+			return 0, notFound
+		})
+
+		assert.ErrorIs(t, err, notFound)
+	})
+}
@@ -0,0 +1,153 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"app/config"
+	"app/internal/db"
+	"app/internal/scheduler"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingJob records how many times it was executed
+type countingJob struct {
+	runs int
+	err  error
+}
+
+func (j *countingJob) Execute(ctx context.Context) error {
+	j.runs++
+	return j.err
+}
+
+func (j *countingJob) Name() string {
+	return "counting-job"
+}
+
+func (j *countingJob) Description() string {
+	return "Counts executions for tests"
+}
+
+func newTestScheduler(t *testing.T) *scheduler.Scheduler {
+	return scheduler.NewScheduler(&scheduler.Dependencies{
+		Config: &config.Config{AppName: "TestApp", Environment: "test"},
+		Logger: helpers.GetTestLogger(t),
+	})
+}
+
+func TestScheduler_RunJob(t *testing.T) {
+	t.Run("should run the registered example job", func(t *testing.T) {
+		log, buf := helpers.NewBufferLogger()
+		s := scheduler.NewScheduler(&scheduler.Dependencies{
+			Config: &config.Config{AppName: "TestApp", Environment: "test"},
+			Logger: log,
+		})
+		require.NoError(t, s.RegisterJobs())
+		buf.Reset()
+
+		err := s.RunJob(context.Background(), "example-job")
+
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), `msg="Example job executed successfully" app_name=TestApp environment=test`)
+		assert.Contains(t, buf.String(), `msg="Example cron job completed"`)
+	})
+
+	t.Run("should execute job synchronously", func(t *testing.T) {
+		s := newTestScheduler(t)
+		job := &countingJob{}
+		require.NoError(t, s.AddJob("@every 1h", job))
+
+		err := s.RunJob(context.Background(), job.Name())
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, job.runs)
+	})
+
+	t.Run("should return job error", func(t *testing.T) {
+		s := newTestScheduler(t)
+		job := &countingJob{err: errors.New("boom")}
+		require.NoError(t, s.AddJob("@every 1h", job))
+
+		err := s.RunJob(context.Background(), job.Name())
+
+		assert.EqualError(t, err, "boom")
+		assert.Equal(t, 1, job.runs)
+	})
+
+	t.Run("should return not found for unknown job", func(t *testing.T) {
+		s := newTestScheduler(t)
+		require.NoError(t, s.RegisterJobs())
+
+		err := s.RunJob(context.Background(), "missing-job")
+
+		assert.Equal(t, scheduler.ErrJobNotFound, err)
+	})
+
+	t.Run("should reject duplicate job names", func(t *testing.T) {
+		s := newTestScheduler(t)
+		require.NoError(t, s.AddJob("@every 1h", &countingJob{}))
+
+		err := s.AddJob("@every 1h", &countingJob{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestScheduler_JobRuns(t *testing.T) {
+	newRecordingScheduler := func(t *testing.T, queries *db.Queries) *scheduler.Scheduler {
+		return scheduler.NewScheduler(&scheduler.Dependencies{
+			Config:  &config.Config{AppName: "TestApp", Environment: "test"},
+			Queries: queries,
+			Logger:  helpers.GetTestLogger(t),
+		})
+	}
+
+	t.Run("should record a completed run of the example job", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			s := newRecordingScheduler(t, queries)
+			require.NoError(t, s.RegisterJobs())
+
+			require.NoError(t, s.RunJob(ctx, "example-job"))
+
+			runs, err := s.ListJobRuns(ctx, "example-job", 10)
+			require.NoError(t, err)
+			require.Len(t, runs, 1)
+			assert.Equal(t, "example-job", runs[0].JobName)
+			assert.Equal(t, scheduler.JobRunStatusSucceeded, runs[0].Status)
+			assert.True(t, runs[0].FinishedAt.Valid, "finished_at should be set")
+			assert.True(t, runs[0].DurationMs.Valid, "duration_ms should be set")
+			assert.False(t, runs[0].Error.Valid)
+		})
+	})
+
+	t.Run("should record the error of a failed run", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			s := newRecordingScheduler(t, queries)
+			job := &countingJob{err: errors.New("boom")}
+			require.NoError(t, s.AddJob("@every 1h", job))
+
+			assert.EqualError(t, s.RunJob(ctx, job.Name()), "boom")
+
+			runs, err := s.ListJobRuns(ctx, job.Name(), 10)
+			require.NoError(t, err)
+			require.Len(t, runs, 1)
+			assert.Equal(t, scheduler.JobRunStatusFailed, runs[0].Status)
+			assert.Equal(t, "boom", runs[0].Error.String)
+			assert.True(t, runs[0].FinishedAt.Valid)
+		})
+	})
+
+	t.Run("should return not found for unknown job", func(t *testing.T) {
+		s := newTestScheduler(t)
+
+		_, err := s.ListJobRuns(context.Background(), "missing-job", 10)
+
+		assert.Equal(t, scheduler.ErrJobNotFound, err)
+	})
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	request := func(config middleware.SecurityHeadersConfig, handler gin.HandlerFunc) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.Use(middleware.SecurityHeaders(config))
+		router.GET("/test", handler)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/test", nil))
+		return w
+	}
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"ok": true}) }
+
+	t.Run("should set the default headers without HSTS", func(t *testing.T) {
+		w := request(middleware.SecurityHeadersConfig{}, ok)
+
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "DENY", w.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "strict-origin-when-cross-origin", w.Header().Get("Referrer-Policy"))
+		assert.Empty(t, w.Header().Get(middleware.HSTSHeader))
+	})
+
+	t.Run("should add HSTS when enabled", func(t *testing.T) {
+		w := request(middleware.SecurityHeadersConfig{HSTS: true}, ok)
+		assert.Equal(t, middleware.DefaultHSTSValue, w.Header().Get(middleware.HSTSHeader))
+
+		w = request(middleware.SecurityHeadersConfig{HSTS: true, HSTSValue: "max-age=60"}, ok)
+		assert.Equal(t, "max-age=60", w.Header().Get(middleware.HSTSHeader))
+	})
+
+	t.Run("should send a custom header set instead of the defaults", func(t *testing.T) {
+		w := request(middleware.SecurityHeadersConfig{Headers: map[string]string{
+			"X-Frame-Options":            "SAMEORIGIN",
+			"Permissions-Policy":         "camera=()",
+			"X-Content-Type-Options":     "",
+			"Cross-Origin-Opener-Policy": "same-origin",
+		}}, ok)
+
+		assert.Equal(t, "SAMEORIGIN", w.Header().Get("X-Frame-Options"))
+		assert.Equal(t, "camera=()", w.Header().Get("Permissions-Policy"))
+		assert.Equal(t, "same-origin", w.Header().Get("Cross-Origin-Opener-Policy"))
+		assert.Empty(t, w.Header().Get("X-Content-Type-Options"))
+		assert.Empty(t, w.Header().Get("Referrer-Policy"))
+	})
+
+	t.Run("should set the headers on aborted responses", func(t *testing.T) {
+		w := request(middleware.SecurityHeadersConfig{}, func(c *gin.Context) {
+			c.AbortWithStatus(http.StatusForbidden)
+		})
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	})
+}
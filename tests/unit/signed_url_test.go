@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignedURL(t *testing.T) {
+	secret := []byte("signing-secret")
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/exports/:id", middleware.SignedURL(secret), func(c *gin.Context) {
+		c.String(http.StatusOK, "export "+c.Param("id"))
+	})
+
+	request := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", target, nil))
+		return w
+	}
+
+	t.Run("should allow a valid link", func(t *testing.T) {
+		link, err := middleware.SignURL(secret, "/exports/42", time.Now().Add(24*time.Hour))
+		require.NoError(t, err)
+
+		w := request(link)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "export 42", w.Body.String())
+	})
+
+	t.Run("should keep existing query params and ignore the host", func(t *testing.T) {
+		link, err := middleware.SignURL(secret, "https://files.example.com/exports/42?format=csv", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "csv", parsed.Query().Get("format"))
+
+		w := request(parsed.RequestURI())
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("should reject an expired link", func(t *testing.T) {
+		link, err := middleware.SignURL(secret, "/exports/42", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		w := request(link)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeySignedURLExpired)
+	})
+
+	t.Run("should reject a tampered signature", func(t *testing.T) {
+		link, err := middleware.SignURL(secret, "/exports/42", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		parsed, _ := url.Parse(link)
+		query := parsed.Query()
+		signature := query.Get(middleware.SignedURLSignatureParam)
+		lastChar := "0"
+		if signature[len(signature)-1] == '0' {
+			lastChar = "1"
+		}
+		query.Set(middleware.SignedURLSignatureParam, signature[:len(signature)-1]+lastChar)
+		parsed.RawQuery = query.Encode()
+
+		w := request(parsed.String())
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeySignedURLInvalid)
+	})
+
+	t.Run("should reject a link signed for another path", func(t *testing.T) {
+		link, err := middleware.SignURL(secret, "/exports/1", time.Now().Add(time.Hour))
+		require.NoError(t, err)
+
+		parsed, _ := url.Parse(link)
+		parsed.Path = "/exports/2"
+
+		w := request(parsed.String())
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("should reject an extended expiry", func(t *testing.T) {
+		link, err := middleware.SignURL(secret, "/exports/42", time.Now().Add(-time.Minute))
+		require.NoError(t, err)
+
+		parsed, _ := url.Parse(link)
+		query := parsed.Query()
+		query.Set(middleware.SignedURLExpiresParam, "9999999999")
+		parsed.RawQuery = query.Encode()
+
+		w := request(parsed.String())
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeySignedURLInvalid)
+	})
+
+	t.Run("should reject a link without signature", func(t *testing.T) {
+		w := request("/exports/42")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
@@ -0,0 +1,143 @@
+package unit
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/internal"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamResult struct {
+	written  int64
+	err      error
+	duration time.Duration
+}
+
+// newStreamingServer streams size bytes per request with a short server WriteTimeout,
+// reporting how each stream ended on the returned channel
+func newStreamingServer(t *testing.T, size int, chunkTimeout time.Duration) (*httptest.Server, <-chan streamResult) {
+	results := make(chan streamResult, 1)
+	payload := bytes.Repeat([]byte("x"), size)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		w.Header().Set("Content-Type", "application/octet-stream")
+		written, err := internal.StreamWithDeadline(w, bytes.NewReader(payload), chunkTimeout)
+		results <- streamResult{written: written, err: err, duration: time.Since(started)}
+	}))
+	server.Config.WriteTimeout = 200 * time.Millisecond
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server, results
+}
+
+func openStream(t *testing.T, server *httptest.Server) net.Conn {
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	_, err = io.WriteString(conn, "GET /download HTTP/1.1\r\nHost: test\r\nConnection: close\r\n\r\n")
+	require.NoError(t, err)
+	return conn
+}
+
+func TestStreamWithDeadline(t *testing.T) {
+	t.Run("should let a steady slow reader finish past the server write timeout", func(t *testing.T) {
+		size := 16 << 20
+		server, results := newStreamingServer(t, size, time.Second)
+		conn := openStream(t, server)
+
+		// Read in paced bursts so the stream outgrows the socket buffers and runs well past WriteTimeout
+		buffer := make([]byte, 64*1024)
+		for {
+			_, err := conn.Read(buffer)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		result := <-results
+		assert.NoError(t, result.err)
+		assert.Equal(t, int64(size), result.written)
+		assert.Greater(t, result.duration, 200*time.Millisecond, "stream should outlast the server WriteTimeout")
+	})
+
+	t.Run("should abort a stalled reader", func(t *testing.T) {
+		server, results := newStreamingServer(t, 64<<20, 100*time.Millisecond)
+		openStream(t, server)
+
+		// The client never reads, so once socket buffers fill the next chunk misses its deadline
+		select {
+		case result := <-results:
+			require.Error(t, result.err)
+			assert.Less(t, result.written, int64(64<<20))
+		case <-time.After(10 * time.Second):
+			t.Fatal("stalled stream was not aborted")
+		}
+	})
+
+	t.Run("should stream to writers without deadline support", func(t *testing.T) {
+		w := httptest.NewRecorder()
+
+		written, err := internal.StreamWithDeadline(w, bytes.NewReader([]byte("hello")), time.Second)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), written)
+		assert.Equal(t, "hello", w.Body.String())
+	})
+}
+
+func TestExtendWriteDeadline(t *testing.T) {
+	// newSlowBodyServer answers after a delay standing in for a slow request body, with a
+	// server WriteTimeout shorter than that delay
+	newSlowBodyServer := func(t *testing.T, timeout time.Duration) *httptest.Server {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, internal.ExtendWriteDeadline(w, timeout))
+			time.Sleep(300 * time.Millisecond)
+			_, _ = io.WriteString(w, "stored")
+		}))
+		server.Config.WriteTimeout = 100 * time.Millisecond
+		server.Start()
+		t.Cleanup(server.Close)
+		return server
+	}
+
+	t.Run("should answer past the server write timeout", func(t *testing.T) {
+		server := newSlowBodyServer(t, 5*time.Second)
+
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "stored", string(body))
+	})
+
+	t.Run("should clear the deadline for a zero timeout", func(t *testing.T) {
+		server := newSlowBodyServer(t, 0)
+
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "stored", string(body))
+	})
+
+	t.Run("should ignore writers without deadline support", func(t *testing.T) {
+		assert.NoError(t, internal.ExtendWriteDeadline(httptest.NewRecorder(), time.Second))
+	})
+}
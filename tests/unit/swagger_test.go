@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"testing"
+
+	"app/config"
+	"app/docs"
+	"app/internal/swagger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwagger_RegisterRoutes(t *testing.T) {
+	hasSwaggerRoute := func(router *gin.Engine) bool {
+		for _, route := range router.Routes() {
+			if route.Path == "/swagger/*any" {
+				return true
+			}
+		}
+		return false
+	}
+
+	t.Run("should not register the route when disabled", func(t *testing.T) {
+		router := gin.New()
+
+		swagger.RegisterRoutes(router, &config.Config{Environment: "production"})
+
+		assert.False(t, hasSwaggerRoute(router))
+	})
+
+	t.Run("should serve the docs for the configured host and scheme", func(t *testing.T) {
+		router := gin.New()
+
+		swagger.RegisterRoutes(router, &config.Config{
+			Environment: "production",
+			AppURL:      "api.example.com",
+			Features:    config.Features{EnableSwagger: true},
+		})
+
+		assert.True(t, hasSwaggerRoute(router))
+		assert.Equal(t, "api.example.com", docs.SwaggerInfo.Host)
+		assert.Equal(t, []string{"https"}, docs.SwaggerInfo.Schemes)
+	})
+
+	t.Run("should document the API under the configured base path", func(t *testing.T) {
+		router := gin.New()
+
+		swagger.RegisterRoutes(router, &config.Config{
+			APIBasePath: "/gateway/v1",
+			Features:    config.Features{EnableSwagger: true},
+		})
+
+		doc := docs.SwaggerInfo.ReadDoc()
+		assert.Equal(t, "/gateway/v1", docs.SwaggerInfo.BasePath)
+		assert.Contains(t, doc, `"/auth/login"`)
+		assert.NotContains(t, doc, `"/api/v1/auth/login"`)
+	})
+}
+
+func TestSwagger_Schemes(t *testing.T) {
+	assert.Equal(t, []string{"https"}, swagger.Schemes(&config.Config{Environment: "production"}))
+	assert.Equal(t, []string{"http"}, swagger.Schemes(&config.Config{Environment: "development"}))
+}
@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/internal/errs"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTimeoutRouter(timeout time.Duration, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Timeout(timeout))
+	router.GET("/slow", handler)
+	return router
+}
+
+func serveTimeout(router *gin.Engine) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	return w
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	t.Run("should pass fast handlers through", func(t *testing.T) {
+		router := newTimeoutRouter(time.Second, func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+
+		w := serveTimeout(router)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("should cancel the context of a slow handler and respond with request_timeout", func(t *testing.T) {
+		cancelled := false
+		router := newTimeoutRouter(20*time.Millisecond, func(c *gin.Context) {
+			select {
+			case <-c.Request.Context().Done():
+				cancelled = true
+				errs.RespondWithError(c, c.Request.Context().Err())
+			case <-time.After(2 * time.Second):
+				c.JSON(http.StatusOK, gin.H{"ok": true})
+			}
+		})
+
+		w := serveTimeout(router)
+
+		assert.True(t, cancelled, "handler context should be cancelled")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyRequestTimeout)
+	})
+
+	t.Run("should drop a late response from a handler that ignores its context", func(t *testing.T) {
+		router := newTimeoutRouter(10*time.Millisecond, func(c *gin.Context) {
+			time.Sleep(40 * time.Millisecond)
+			c.Header("Content-Type", "text/csv")
+			c.String(http.StatusOK, "id,name\n1,late\n")
+		})
+
+		w := serveTimeout(router)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyRequestTimeout)
+		assert.NotContains(t, w.Body.String(), "late")
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	})
+
+	t.Run("should respond when a timed out handler writes nothing", func(t *testing.T) {
+		router := newTimeoutRouter(10*time.Millisecond, func(c *gin.Context) {
+			<-c.Request.Context().Done()
+		})
+
+		w := serveTimeout(router)
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyRequestTimeout)
+	})
+
+	t.Run("should leave responses started before the deadline alone", func(t *testing.T) {
+		router := newTimeoutRouter(20*time.Millisecond, func(c *gin.Context) {
+			c.Status(http.StatusOK)
+			_, _ = c.Writer.WriteString("first ")
+			time.Sleep(40 * time.Millisecond)
+			_, _ = c.Writer.WriteString("second")
+		})
+
+		w := serveTimeout(router)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "first second", w.Body.String())
+	})
+
+	t.Run("should leave a response buffered by Compression before the deadline alone", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Compression(middleware.CompressionConfig{MinSize: 1024}))
+		router.Use(middleware.Timeout(50 * time.Millisecond))
+		router.GET("/slow", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+			time.Sleep(80 * time.Millisecond)
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("should let a route replace the global limit", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Timeout(20 * time.Millisecond))
+		router.GET("/upload", middleware.Timeout(time.Second), func(c *gin.Context) {
+			time.Sleep(40 * time.Millisecond)
+			if err := c.Request.Context().Err(); err != nil {
+				errs.RespondWithError(c, err)
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/upload", nil))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("should enforce a shorter route limit", func(t *testing.T) {
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(middleware.Timeout(time.Second))
+		router.GET("/upload", middleware.Timeout(10*time.Millisecond), func(c *gin.Context) {
+			<-c.Request.Context().Done()
+		})
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/upload", nil))
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Contains(t, w.Body.String(), errs.ErrKeyRequestTimeout)
+	})
+
+	t.Run("should be disabled with a zero duration", func(t *testing.T) {
+		router := newTimeoutRouter(0, func(c *gin.Context) {
+			_, hasDeadline := c.Request.Context().Deadline()
+			c.JSON(http.StatusOK, gin.H{"deadline": hasDeadline})
+		})
+
+		w := serveTimeout(router)
+
+		assert.JSONEq(t, `{"deadline":false}`, w.Body.String())
+	})
+}
@@ -0,0 +1,97 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"app/internal"
+	"app/internal/example"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTimestamp(t *testing.T) {
+	t.Run("should format valid timestamps as RFC 3339", func(t *testing.T) {
+		ts := pgtype.Timestamp{Time: time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC), Valid: true}
+
+		assert.Equal(t, internal.Timestamp("2024-03-09T14:05:07Z"), internal.FormatTimestamp(ts))
+	})
+
+	t.Run("should return empty string for zero time", func(t *testing.T) {
+		assert.Empty(t, internal.FormatTimestamp(pgtype.Timestamp{Valid: true}))
+	})
+
+	t.Run("should return empty string for NULL timestamps", func(t *testing.T) {
+		ts := pgtype.Timestamp{Time: time.Now(), Valid: false}
+
+		assert.Empty(t, internal.FormatTimestamp(ts))
+	})
+}
+
+func TestFormatTimestampPtr(t *testing.T) {
+	t.Run("should return formatted value for valid timestamps", func(t *testing.T) {
+		ts := pgtype.Timestamp{Time: time.Date(2024, 3, 9, 14, 5, 7, 0, time.UTC), Valid: true}
+
+		formatted := internal.FormatTimestampPtr(ts)
+
+		require.NotNil(t, formatted)
+		assert.Equal(t, internal.Timestamp("2024-03-09T14:05:07Z"), *formatted)
+	})
+
+	t.Run("should return nil for zero and NULL timestamps", func(t *testing.T) {
+		assert.Nil(t, internal.FormatTimestampPtr(pgtype.Timestamp{}))
+		assert.Nil(t, internal.FormatTimestampPtr(pgtype.Timestamp{Valid: true}))
+	})
+
+	t.Run("should serialize NULL timestamps as JSON null", func(t *testing.T) {
+		body, err := json.Marshal(struct {
+			DeletedAt *internal.Timestamp `json:"deleted_at"`
+		}{DeletedAt: internal.FormatTimestampPtr(pgtype.Timestamp{})})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"deleted_at":null}`, string(body))
+	})
+}
+
+func TestTimestampFormat(t *testing.T) {
+	created := time.Date(2024, 3, 9, 14, 5, 7, 250_000_000, time.UTC)
+	// FormatTimestamp reads the format when called, so each case builds its own response
+	response := example.ExampleResponse{ID: 1, UserID: 2, Title: "Example"}
+
+	t.Run("should render RFC 3339 strings by default", func(t *testing.T) {
+		response := response
+		response.CreatedAt = internal.FormatTimestamp(pgtype.Timestamp{Time: created, Valid: true})
+
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		var fields map[string]any
+		require.NoError(t, json.Unmarshal(body, &fields))
+		assert.Equal(t, "2024-03-09T14:05:07Z", fields["created_at"])
+		assert.Equal(t, "", fields["updated_at"])
+	})
+
+	t.Run("should render epoch milliseconds as numbers with unix_ms", func(t *testing.T) {
+		internal.SetTimestampFormat(internal.TimestampUnixMS)
+		t.Cleanup(func() { internal.SetTimestampFormat(internal.TimestampRFC3339) })
+
+		response := response
+		response.CreatedAt = internal.FormatTimestamp(pgtype.Timestamp{Time: created, Valid: true})
+
+		body, err := json.Marshal(response)
+		require.NoError(t, err)
+
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(body, &fields))
+		assert.Equal(t, "1709993107250", string(fields["created_at"]))
+		assert.Equal(t, "null", string(fields["updated_at"]))
+
+		// Assert: the response type decodes its own output
+		var decoded example.ExampleResponse
+		require.NoError(t, json.Unmarshal(body, &decoded))
+		assert.Equal(t, response.CreatedAt, decoded.CreatedAt)
+	})
+}
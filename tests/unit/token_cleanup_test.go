@@ -0,0 +1,52 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/db"
+	"app/internal/scheduler/jobs"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenCleanupJob(t *testing.T) {
+	t.Run("should delete only expired refresh tokens", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			for token, expiresAt := range map[string]time.Time{
+				"expired-token": time.Now().Add(-time.Hour),
+				"valid-token":   time.Now().Add(time.Hour),
+			} {
+				_, err := queries.CreateRefreshToken(ctx, db.CreateRefreshTokenParams{
+					UserID:    user.ID,
+					Token:     token,
+					ExpiresAt: pgtype.Timestamp{Time: expiresAt, Valid: true},
+				})
+				require.NoError(t, err)
+			}
+
+			deleted, err := jobs.NewTokenCleanupJob(queries, helpers.GetTestLogger(t)).Prune(ctx)
+
+			require.NoError(t, err)
+			assert.EqualValues(t, 1, deleted)
+			rows, err := tx.Query(ctx, "SELECT token FROM refresh_tokens WHERE user_id = $1", user.ID)
+			require.NoError(t, err)
+			remaining, err := pgx.CollectRows(rows, pgx.RowTo[string])
+			require.NoError(t, err)
+			assert.Equal(t, []string{"valid-token"}, remaining)
+		})
+	})
+
+	t.Run("should be registered with the scheduler", func(t *testing.T) {
+		s := newTestScheduler(t)
+		require.NoError(t, s.RegisterJobs())
+
+		assert.Contains(t, s.JobNames(), "token-cleanup")
+	})
+}
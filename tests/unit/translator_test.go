@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	"app/internal/errs"
+	"app/internal/example"
+	"app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func respondWithErrorInLocale(t *testing.T, err error, acceptLanguage string) errs.ErrorResponse {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Locale(errs.DefaultLocale))
+	router.GET("/error", func(c *gin.Context) {
+		errs.RespondWithError(c, err)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/error", nil)
+	req.Header.Set("Accept-Language", acceptLanguage)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var response errs.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestRespondWithError_Localized(t *testing.T) {
+	t.Run("should translate the message for Accept-Language: es", func(t *testing.T) {
+		response := respondWithErrorInLocale(t, example.ErrExampleNotFound, "es")
+
+		assert.Equal(t, errs.ErrKeyExampleNotFound, response.ErrorKey)
+		assert.Equal(t, "Ejemplo no encontrado", response.Message)
+	})
+
+	t.Run("should keep the built-in English message", func(t *testing.T) {
+		response := respondWithErrorInLocale(t, example.ErrExampleNotFound, "en-GB")
+
+		assert.Equal(t, "Example not found", response.Message)
+	})
+
+	t.Run("should fall back to the built-in message for keys without a translation", func(t *testing.T) {
+		err := errs.NewBadRequestError(errs.ErrKeyBadRequest, "Invalid example ID")
+
+		response := respondWithErrorInLocale(t, err, "es")
+
+		assert.Equal(t, "Invalid example ID", response.Message)
+	})
+}
+
+func TestTranslator_Catalogs(t *testing.T) {
+	translator := errs.DefaultTranslator()
+	english, ok := translator.Catalog(errs.DefaultLocale)
+	require.True(t, ok)
+
+	assert.Contains(t, translator.Locales(), errs.LocaleSpanish)
+
+	for _, locale := range translator.Locales() {
+		t.Run("should translate every key in "+locale, func(t *testing.T) {
+			catalog, _ := translator.Catalog(locale)
+
+			assert.Equal(t, sortedKeys(english.Errors), sortedKeys(catalog.Errors))
+			assert.Equal(t, sortedKeys(english.Validation), sortedKeys(catalog.Validation))
+		})
+	}
+
+	t.Run("should match the built-in English messages", func(t *testing.T) {
+		for _, domainErr := range []*errs.DomainError{example.ErrExampleNotFound, middleware.ErrSignedURLExpired, middleware.ErrUserNotAuthenticated} {
+			assert.Equal(t, domainErr.Message, english.Errors[domainErr.Key])
+		}
+	})
+}
+
+func TestNewTranslator(t *testing.T) {
+	t.Run("should load one catalog per file", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"locales/en.json": {Data: []byte(`{"errors":{"examples.not_found":"Example not found"}}`)},
+			"locales/de.json": {Data: []byte(`{"errors":{"examples.not_found":"Beispiel nicht gefunden"}}`)},
+		}
+
+		translator, err := errs.NewTranslator(fsys, "locales")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"de", "en"}, translator.Locales())
+		assert.Equal(t, "Beispiel nicht gefunden", translator.Message("de", errs.ErrKeyExampleNotFound, "fallback"))
+		assert.Equal(t, "fallback", translator.Message("fr", errs.ErrKeyExampleNotFound, "fallback"))
+	})
+
+	t.Run("should require the default locale", func(t *testing.T) {
+		fsys := fstest.MapFS{"locales/es.json": {Data: []byte(`{}`)}}
+
+		_, err := errs.NewTranslator(fsys, "locales")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject malformed catalogs", func(t *testing.T) {
+		fsys := fstest.MapFS{"locales/en.json": {Data: []byte(`{"errors":`)}}
+
+		_, err := errs.NewTranslator(fsys, "locales")
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"app/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	// clientIP resolves the client IP of a request from remoteAddr forwarded for 203.0.113.7,
+	// with TRUSTED_PROXIES set to value
+	clientIP := func(t *testing.T, value, remoteAddr string) string {
+		t.Setenv("TRUSTED_PROXIES", value)
+		cfg, err := config.Load()
+		require.NoError(t, err)
+		require.NoError(t, cfg.Validate(), "config should be valid")
+
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		require.NoError(t, router.SetTrustedProxies(cfg.TrustedProxies))
+		router.GET("/ip", func(c *gin.Context) {
+			c.String(http.StatusOK, c.ClientIP())
+		})
+
+		req := httptest.NewRequest("GET", "/ip", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	t.Setenv("DATABASE_URL", "postgres://localhost/test")
+	t.Setenv("JWT_SECRET", "secret")
+
+	t.Run("should ignore X-Forwarded-For without trusted proxies", func(t *testing.T) {
+		assert.Equal(t, "10.0.0.5", clientIP(t, "none", "10.0.0.5:4321"))
+	})
+
+	t.Run("should use the forwarded IP from a trusted proxy", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP(t, "10.0.0.0/8", "10.0.0.5:4321"))
+	})
+
+	t.Run("should only trust loopback by default", func(t *testing.T) {
+		assert.Equal(t, "203.0.113.7", clientIP(t, "", "127.0.0.1:4321"))
+		assert.Equal(t, "10.0.0.5", clientIP(t, "", "10.0.0.5:4321"))
+	})
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	assert.Equal(t, []string{"10.0.0.0/8", "192.168.1.10"}, config.ParseTrustedProxies(" 10.0.0.0/8, ,192.168.1.10 "))
+	assert.Nil(t, config.ParseTrustedProxies("none"))
+}
@@ -0,0 +1,169 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"app/internal/db"
+	"app/internal/example"
+	"app/internal/uploads"
+	"app/tests/helpers"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errInjected = errors.New("injected failure")
+
+// failingTx wraps a transaction and fails any statement containing failOn,
+// to simulate a write failing halfway through a multi-query operation
+type failingTx struct {
+	pgx.Tx
+	failOn string
+}
+
+func (f failingTx) Begin(ctx context.Context) (pgx.Tx, error) {
+	tx, err := f.Tx.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return failingTx{Tx: tx, failOn: f.failOn}, nil
+}
+
+func (f failingTx) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	if strings.Contains(sql, f.failOn) {
+		return errRow{}
+	}
+	return f.Tx.QueryRow(ctx, sql, args...)
+}
+
+func (f failingTx) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	if strings.Contains(sql, f.failOn) {
+		return pgconn.CommandTag{}, errInjected
+	}
+	return f.Tx.Exec(ctx, sql, args...)
+}
+
+type errRow struct{}
+
+func (errRow) Scan(...interface{}) error { return errInjected }
+
+func countUsersWithEmail(t *testing.T, ctx context.Context, tx pgx.Tx, email string) int {
+	var count int
+	require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE email = $1", email).Scan(&count))
+	return count
+}
+
+func TestWithTx(t *testing.T) {
+	createUser := func(ctx context.Context, q *db.Queries, email string) error {
+		_, err := q.CreateUser(ctx, db.CreateUserParams{Email: email, Name: "Tx User", Password: "hashed"})
+		return err
+	}
+
+	t.Run("should commit when fn succeeds", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			err := db.WithTx(ctx, queries.TxBeginner(), func(q *db.Queries) error {
+				return createUser(ctx, q, "commit@example.com")
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, 1, countUsersWithEmail(t, ctx, tx, "commit@example.com"))
+		})
+	})
+
+	t.Run("should roll back earlier writes when fn fails", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			err := db.WithTx(ctx, queries.TxBeginner(), func(q *db.Queries) error {
+				if err := createUser(ctx, q, "rollback@example.com"); err != nil {
+					return err
+				}
+				return errInjected
+			})
+
+			assert.ErrorIs(t, err, errInjected)
+			assert.Equal(t, 0, countUsersWithEmail(t, ctx, tx, "rollback@example.com"))
+		})
+	})
+
+	t.Run("should roll back and re-panic when fn panics", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			assert.PanicsWithValue(t, "boom", func() {
+				_ = db.WithTx(ctx, queries.TxBeginner(), func(q *db.Queries) error {
+					require.NoError(t, createUser(ctx, q, "panic@example.com"))
+					panic("boom")
+				})
+			})
+
+			assert.Equal(t, 0, countUsersWithEmail(t, ctx, tx, "panic@example.com"))
+		})
+	})
+
+	t.Run("should refuse to run without a transaction source", func(t *testing.T) {
+		called := false
+		err := db.WithTx(context.Background(), nil, func(q *db.Queries) error {
+			called = true
+			return nil
+		})
+
+		assert.ErrorIs(t, err, db.ErrNoTxSupport)
+		assert.False(t, called)
+	})
+}
+
+func TestTransactor(t *testing.T) {
+	countUploads := func(t *testing.T, ctx context.Context, tx pgx.Tx, userID int32) int {
+		var count int
+		require.NoError(t, tx.QueryRow(ctx, "SELECT COUNT(*) FROM uploads WHERE user_id = $1", userID).Scan(&count))
+		return count
+	}
+
+	t.Run("should commit writes of services joined with WithTx together", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			helpers.CreateTestExample(t, ctx, tx, user.ID)
+
+			uploadService := uploads.NewUploadService(queries, uploads.DefaultUploadConfig(t.TempDir(), ""))
+			exampleService := example.NewExampleService(queries)
+
+			var transactor db.Transactor = queries
+			err := transactor.InTx(ctx, func(q *db.Queries) error {
+				if _, err := uploadService.WithTx(q).DeleteUserUploads(ctx, user.ID); err != nil {
+					return err
+				}
+				return exampleService.WithTx(q).DeleteUserExamples(ctx, user.ID)
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, 0, countUploads(t, ctx, tx, user.ID))
+		})
+	})
+
+	t.Run("should roll back the first service when the second fails", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			helpers.CreateTestExample(t, ctx, tx, user.ID)
+
+			// Setup: Queries whose example delete fails after the uploads were deleted
+			failing := db.New(failingTx{Tx: tx, failOn: "DELETE FROM examples"})
+			uploadService := uploads.NewUploadService(failing, uploads.DefaultUploadConfig(t.TempDir(), ""))
+			exampleService := example.NewExampleService(failing)
+
+			err := failing.InTx(ctx, func(q *db.Queries) error {
+				if _, err := uploadService.WithTx(q).DeleteUserUploads(ctx, user.ID); err != nil {
+					return err
+				}
+				return exampleService.WithTx(q).DeleteUserExamples(ctx, user.ID)
+			})
+
+			// Assert: The error surfaces and the upload delete was rolled back
+			assert.ErrorIs(t, err, errInjected)
+			assert.Equal(t, 1, countUploads(t, ctx, tx, user.ID))
+		})
+	})
+}
@@ -3,16 +3,26 @@ package unit
 import (
 	"bytes"
 	"context"
+	"errors"
+	"io"
 	"mime/multipart"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"app/internal/db"
+	"app/internal/errs"
 	"app/internal/uploads"
+	"app/tests"
 	"app/tests/helpers"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -37,7 +47,7 @@ func TestUploadService_UploadFile(t *testing.T) {
 			require.NoError(t, err)
 			assert.NotNil(t, upload)
 			assert.Equal(t, user.ID, upload.UserID)
-			assert.Equal(t, user.ID, upload.FolderID)
+			assert.Equal(t, helpers.GetTestRootFolder(t, ctx, tx, user.ID).ID, upload.FolderID)
 			assert.Equal(t, "image", upload.Type)
 			assert.Equal(t, "test.jpg", upload.OriginalFilename)
 			assert.Equal(t, int64(len(fileContent)), upload.FileSize)
@@ -115,6 +125,207 @@ func TestUploadService_UploadFile(t *testing.T) {
 	})
 }
 
+// diskFullWriter writes to a real file but reports ENOSPC once limit bytes have been written
+type diskFullWriter struct {
+	file  *os.File
+	limit int
+}
+
+func (w *diskFullWriter) Write(p []byte) (int, error) {
+	if len(p) > w.limit {
+		n, _ := w.file.Write(p[:w.limit])
+		w.limit -= n
+		return n, &os.PathError{Op: "write", Path: w.file.Name(), Err: syscall.ENOSPC}
+	}
+	n, err := w.file.Write(p)
+	w.limit -= n
+	return n, err
+}
+
+func (w *diskFullWriter) Close() error {
+	return w.file.Close()
+}
+
+func TestUploadService_UploadFileDiskFull(t *testing.T) {
+	t.Run("should remove the partial file and skip the database row when the disk fills", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			// Setup: Writes fail with ENOSPC after a few bytes
+			user := helpers.CreateTestUser(t, ctx, tx)
+			tempDir := t.TempDir()
+			config := uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files")
+			config.CreateFile = func(path string) (io.WriteCloser, error) {
+				file, err := os.Create(path)
+				if err != nil {
+					return nil, err
+				}
+				return &diskFullWriter{file: file, limit: 4}, nil
+			}
+			service := uploads.NewUploadService(queries, config)
+
+			fileHeader := createTestFileHeader(t, "test.jpg", bytes.Repeat([]byte("x"), 1024), "image/jpeg")
+
+			// Test: Upload file
+			upload, err := service.UploadFile(ctx, fileHeader, user.ID)
+
+			// Assert: Storage full error
+			require.Error(t, err)
+			assert.Nil(t, upload)
+			assert.ErrorIs(t, err, syscall.ENOSPC)
+			domainErr := errs.ExtractDomainError(err)
+			assert.Equal(t, errs.ErrKeyUploadStorageFull, domainErr.Key)
+			assert.Equal(t, http.StatusInsufficientStorage, domainErr.Status)
+
+			// Assert: No leftover file on disk
+			leftovers, err := filepath.Glob(filepath.Join(tempDir, "*", "*"))
+			require.NoError(t, err)
+			assert.Empty(t, leftovers)
+
+			// Assert: No database row
+			rows, err := service.ListUploads(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Empty(t, rows)
+		})
+	})
+}
+
+func TestUploadService_UploadFileQuota(t *testing.T) {
+	t.Run("should reject the upload that would exceed the byte quota", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			// Setup: Room for one 20 byte file but not two
+			user := helpers.CreateTestUser(t, ctx, tx)
+			config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+			config.MaxTotalBytesPerUser = 30
+			service := uploads.NewUploadService(queries, config)
+
+			content := bytes.Repeat([]byte("x"), 20)
+
+			// Test: First upload fits, second does not
+			_, err := service.UploadFile(ctx, createTestFileHeader(t, "first.txt", content, "text/plain"), user.ID)
+			require.NoError(t, err)
+
+			upload, err := service.UploadFile(ctx, createTestFileHeader(t, "second.txt", content, "text/plain"), user.ID)
+
+			// Assert: Quota error and only the first upload stored
+			require.Error(t, err)
+			assert.Nil(t, upload)
+			domainErr := errs.ExtractDomainError(err)
+			assert.Equal(t, errs.ErrKeyUploadQuotaExceeded, domainErr.Key)
+			assert.Equal(t, http.StatusRequestEntityTooLarge, domainErr.Status)
+			assert.Equal(t, "bytes", domainErr.Details["quota"])
+
+			rows, err := service.ListUploads(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Len(t, rows, 1)
+		})
+	})
+
+	t.Run("should reject uploads past the file count quota", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+			config.MaxFilesPerUser = 1
+			service := uploads.NewUploadService(queries, config)
+
+			_, err := service.UploadFile(ctx, createTestFileHeader(t, "first.txt", []byte("a"), "text/plain"), user.ID)
+			require.NoError(t, err)
+
+			_, err = service.UploadFile(ctx, createTestFileHeader(t, "second.txt", []byte("b"), "text/plain"), user.ID)
+
+			domainErr := errs.ExtractDomainError(err)
+			assert.Equal(t, errs.ErrKeyUploadQuotaExceeded, domainErr.Key)
+			assert.Equal(t, "files", domainErr.Details["quota"])
+		})
+	})
+
+	t.Run("should check the quota again when storing the upload", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			// Setup: Another upload is stored while this one's file is being written,
+			// after the early quota check already passed
+			user := helpers.CreateTestUser(t, ctx, tx)
+			tempDir := t.TempDir()
+			config := uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files")
+			config.MaxFilesPerUser = 1
+			config.CreateFile = func(path string) (io.WriteCloser, error) {
+				helpers.CreateTestUpload(t, ctx, tx, user.ID)
+				return os.Create(path)
+			}
+			service := uploads.NewUploadService(queries, config)
+
+			upload, err := service.UploadFile(ctx, createTestFileHeader(t, "late.txt", []byte("a"), "text/plain"), user.ID)
+
+			// Assert: Rejected at insert, with its file removed again
+			assert.Nil(t, upload)
+			domainErr := errs.ExtractDomainError(err)
+			assert.Equal(t, errs.ErrKeyUploadQuotaExceeded, domainErr.Key)
+			assert.Equal(t, "files", domainErr.Details["quota"])
+
+			rows, err := service.ListUploads(ctx, user.ID)
+			require.NoError(t, err)
+			assert.Len(t, rows, 1)
+
+			written, err := filepath.Glob(filepath.Join(tempDir, "*", "*"))
+			require.NoError(t, err)
+			assert.Empty(t, written)
+		})
+	})
+
+	t.Run("should count quotas per user", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+			config.MaxFilesPerUser = 1
+			service := uploads.NewUploadService(queries, config)
+
+			_, err := service.UploadFile(ctx, createTestFileHeader(t, "mine.txt", []byte("a"), "text/plain"), user.ID)
+			require.NoError(t, err)
+
+			_, err = service.UploadFile(ctx, createTestFileHeader(t, "theirs.txt", []byte("b"), "text/plain"), other.ID)
+			assert.NoError(t, err)
+		})
+	})
+}
+
+func TestUploadService_GetAllowedTypes(t *testing.T) {
+	t.Run("should validate against the types resolved for the user", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+			var resolvedFor []int32
+			config.GetAllowedTypes = func(ctx context.Context, userID int32) ([]string, error) {
+				resolvedFor = append(resolvedFor, userID)
+				return []string{".jpg", ".png"}, nil
+			}
+			service := uploads.NewUploadService(queries, config)
+
+			// .mp4 is in the static list, but not in the user's
+			video, err := service.UploadFile(ctx, createTestFileHeader(t, "clip.mp4", []byte("video"), "video/mp4"), user.ID)
+			assert.Error(t, err)
+			assert.Nil(t, video)
+
+			image, err := service.UploadFile(ctx, createTestFileHeader(t, "photo.jpg", []byte("image"), "image/jpeg"), user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "photo.jpg", image.OriginalFilename)
+
+			assert.Equal(t, []int32{user.ID, user.ID}, resolvedFor)
+		})
+	})
+
+	t.Run("should fail the upload when the types can't be resolved", func(t *testing.T) {
+		config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+		config.GetAllowedTypes = func(ctx context.Context, userID int32) ([]string, error) {
+			return nil, errors.New("plan lookup failed")
+		}
+		service := uploads.NewUploadService(nil, config)
+
+		upload, err := service.UploadFile(context.Background(), createTestFileHeader(t, "photo.jpg", []byte("image"), "image/jpeg"), 1)
+
+		assert.Nil(t, upload)
+		require.Error(t, err)
+		assert.Equal(t, http.StatusInternalServerError, errs.ExtractDomainError(err).Status)
+	})
+}
+
 func TestUploadService_GetUpload(t *testing.T) {
 	t.Run("should get upload successfully", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
@@ -156,6 +367,93 @@ func TestUploadService_GetUpload(t *testing.T) {
 	})
 }
 
+func TestUploadService_GetUploadVerifyExists(t *testing.T) {
+	t.Run("should return the upload while its file is on disk", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			testUpload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+
+			tempDir := t.TempDir()
+			filePath := filepath.Join(tempDir, testUpload.RelativePath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+			require.NoError(t, os.WriteFile(filePath, []byte("content"), 0o644))
+			service := uploads.NewUploadService(queries, uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files"))
+
+			upload, err := service.GetUploadWithOptions(ctx, testUpload.ID, user.ID, uploads.GetUploadOptions{VerifyExists: true})
+
+			require.NoError(t, err)
+			assert.Equal(t, testUpload.ID, upload.ID)
+			assert.False(t, upload.MissingAt.Valid)
+		})
+	})
+
+	t.Run("should return 410 and flag the upload once its file is deleted", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			testUpload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+
+			tempDir := t.TempDir()
+			filePath := filepath.Join(tempDir, testUpload.RelativePath)
+			require.NoError(t, os.MkdirAll(filepath.Dir(filePath), 0o755))
+			require.NoError(t, os.WriteFile(filePath, []byte("content"), 0o644))
+			service := uploads.NewUploadService(queries, uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files"))
+
+			// Removed behind the service's back, e.g. by an external cleanup
+			require.NoError(t, os.Remove(filePath))
+
+			// Without VerifyExists only the row is read
+			upload, err := service.GetUpload(ctx, testUpload.ID, user.ID)
+			require.NoError(t, err)
+			assert.False(t, upload.MissingAt.Valid)
+
+			upload, err = service.GetUploadWithOptions(ctx, testUpload.ID, user.ID, uploads.GetUploadOptions{VerifyExists: true})
+
+			assert.Nil(t, upload)
+			assert.Equal(t, uploads.ErrUploadFileGone, err)
+			var domainErr *errs.DomainError
+			require.True(t, errors.As(err, &domainErr))
+			assert.Equal(t, http.StatusGone, domainErr.Status)
+			assert.Equal(t, errs.ErrKeyUploadFileGone, domainErr.Key)
+
+			upload, err = service.GetUpload(ctx, testUpload.ID, user.ID)
+			require.NoError(t, err)
+			assert.True(t, upload.MissingAt.Valid)
+		})
+	})
+}
+
+func TestUploadService_MarkUploadMissing(t *testing.T) {
+	t.Run("should keep the time the upload was first found missing", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			testUpload := helpers.CreateTestUpload(t, ctx, tx, user.ID)
+			service := uploads.NewUploadService(queries, uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files"))
+
+			first, err := service.MarkUploadMissing(ctx, testUpload.ID, user.ID)
+			require.NoError(t, err)
+			require.True(t, first.MissingAt.Valid)
+
+			again, err := service.MarkUploadMissing(ctx, testUpload.ID, user.ID)
+			require.NoError(t, err)
+			assert.Equal(t, first.MissingAt, again.MissingAt)
+		})
+	})
+
+	t.Run("should return not found for another user's upload", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUserWithEmail(t, ctx, tx, "other@example.com")
+			testUpload := helpers.CreateTestUpload(t, ctx, tx, other.ID)
+			service := uploads.NewUploadService(queries, uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files"))
+
+			upload, err := service.MarkUploadMissing(ctx, testUpload.ID, user.ID)
+
+			assert.Nil(t, upload)
+			assert.Equal(t, uploads.ErrUploadNotFound, err)
+		})
+	})
+}
+
 func TestUploadService_ListUploads(t *testing.T) {
 	t.Run("should list uploads for user", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
@@ -261,6 +559,170 @@ func TestUploadService_DeleteUpload(t *testing.T) {
 	})
 }
 
+func TestUploadService_Deduplicate(t *testing.T) {
+	t.Run("should store identical bytes once and keep the file until the last upload is deleted", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			tempDir := t.TempDir()
+			config := uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files")
+			config.Deduplicate = true
+			service := uploads.NewUploadService(queries, config)
+
+			fileContent := []byte("same bytes twice")
+			first, err := service.UploadFile(ctx, createTestFileHeader(t, "first.jpg", fileContent, "image/jpeg"), user.ID)
+			require.NoError(t, err)
+			second, err := service.UploadFile(ctx, createTestFileHeader(t, "second.jpg", fileContent, "image/jpeg"), user.ID)
+			require.NoError(t, err)
+
+			// Two rows, one physical file
+			assert.NotEqual(t, first.ID, second.ID)
+			assert.Equal(t, "second.jpg", second.OriginalFilename)
+			assert.Equal(t, first.RelativePath, second.RelativePath)
+			assert.Equal(t, first.Checksum, second.Checksum)
+			assert.Len(t, first.Checksum.String, 64)
+
+			stored, err := os.ReadDir(filepath.Join(tempDir, filepath.Dir(first.RelativePath)))
+			require.NoError(t, err)
+			assert.Len(t, stored, 1)
+
+			filePath := filepath.Join(tempDir, first.RelativePath)
+
+			require.NoError(t, service.DeleteUpload(ctx, first.ID, user.ID))
+			_, err = os.Stat(filePath)
+			assert.NoError(t, err, "File should stay while another upload references it")
+
+			require.NoError(t, service.DeleteUpload(ctx, second.ID, user.ID))
+			_, err = os.Stat(filePath)
+			assert.True(t, os.IsNotExist(err), "File should be removed with its last upload")
+		})
+	})
+
+	t.Run("should keep the file of a duplicate uploaded while the original is deleted", func(t *testing.T) {
+		// Concurrent requests need committed rows, so this runs on the pool instead of a test transaction
+		ctx := context.Background()
+		pool := tests.GetTestDBPool()
+		queries := db.New(pool)
+		user := createCommittedTestUser(t, ctx, pool)
+
+		tempDir := t.TempDir()
+		config := uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files")
+		config.Deduplicate = true
+		service := uploads.NewUploadService(queries, config)
+		fileContent := []byte("shared while deleted")
+
+		for _, deleteFirst := range []bool{true, false} {
+			original, err := service.UploadFile(ctx, createTestFileHeader(t, "original.jpg", fileContent, "image/jpeg"), user.ID)
+			require.NoError(t, err)
+
+			// Setup: Hold the user's upload lock so the delete and the upload queue up behind it
+			// in a known order, then let both through
+			held, err := pool.Begin(ctx)
+			require.NoError(t, err)
+			require.NoError(t, db.New(held).LockUserUploads(ctx, user.ID))
+
+			deleted := make(chan error, 1)
+			uploaded := make(chan *db.Upload, 1)
+			deleteOriginal := func() { deleted <- service.DeleteUpload(ctx, original.ID, user.ID) }
+			uploadDuplicate := func() {
+				upload, err := service.UploadFile(ctx, createTestFileHeader(t, "duplicate.jpg", fileContent, "image/jpeg"), user.ID)
+				assert.NoError(t, err)
+				uploaded <- upload
+			}
+			first, second := uploadDuplicate, deleteOriginal
+			if deleteFirst {
+				first, second = deleteOriginal, uploadDuplicate
+			}
+			go first()
+			time.Sleep(100 * time.Millisecond)
+			go second()
+			time.Sleep(100 * time.Millisecond)
+			require.NoError(t, held.Rollback(ctx))
+
+			// Assert: Whichever went first, the new upload's file is on disk
+			require.NoError(t, <-deleted)
+			duplicate := <-uploaded
+			require.NotNil(t, duplicate)
+			_, err = os.Stat(filepath.Join(tempDir, duplicate.RelativePath))
+			assert.NoError(t, err, "deleteFirst=%v: the duplicate's file should exist", deleteFirst)
+
+			require.NoError(t, service.DeleteUpload(ctx, duplicate.ID, user.ID))
+		}
+	})
+
+	t.Run("should write a new file for different bytes or another user", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			other := helpers.CreateTestUser(t, ctx, tx)
+			config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+			config.Deduplicate = true
+			service := uploads.NewUploadService(queries, config)
+
+			original, err := service.UploadFile(ctx, createTestFileHeader(t, "a.txt", []byte("content"), "text/plain"), user.ID)
+			require.NoError(t, err)
+			changed, err := service.UploadFile(ctx, createTestFileHeader(t, "a.txt", []byte("content, edited"), "text/plain"), user.ID)
+			require.NoError(t, err)
+			otherUsers, err := service.UploadFile(ctx, createTestFileHeader(t, "a.txt", []byte("content"), "text/plain"), other.ID)
+			require.NoError(t, err)
+
+			assert.NotEqual(t, original.RelativePath, changed.RelativePath)
+			assert.NotEqual(t, original.RelativePath, otherUsers.RelativePath)
+			assert.Equal(t, original.Checksum, otherUsers.Checksum)
+		})
+	})
+
+	t.Run("should not share files when disabled", func(t *testing.T) {
+		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
+			user := helpers.CreateTestUser(t, ctx, tx)
+			service := uploads.NewUploadService(queries, uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files"))
+
+			first, err := service.UploadFile(ctx, createTestFileHeader(t, "a.txt", []byte("content"), "text/plain"), user.ID)
+			require.NoError(t, err)
+			second, err := service.UploadFile(ctx, createTestFileHeader(t, "a.txt", []byte("content"), "text/plain"), user.ID)
+			require.NoError(t, err)
+
+			assert.NotEqual(t, first.RelativePath, second.RelativePath)
+			assert.Equal(t, first.Checksum, second.Checksum, "The checksum is stored either way")
+		})
+	})
+}
+
+func TestUploadService_RemoveFiles(t *testing.T) {
+	t.Run("should remove each file once and skip files already gone", func(t *testing.T) {
+		tempDir := t.TempDir()
+		service := uploads.NewUploadService(nil, uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files"))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "shared.jpg"), []byte("shared"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(tempDir, "single.pdf"), []byte("single"), 0644))
+
+		err := service.RemoveFiles([]string{"shared.jpg", "single.pdf", "shared.jpg", "missing.png"})
+
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "shared.jpg"))
+		assert.NoFileExists(t, filepath.Join(tempDir, "single.pdf"))
+	})
+
+	t.Run("should report the paths that could not be removed", func(t *testing.T) {
+		tempDir := t.TempDir()
+		service := uploads.NewUploadService(nil, uploads.DefaultUploadConfig(tempDir, "http://localhost:8181/api/files"))
+		// A non-empty directory can't be removed like a file
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "stuck", "inner"), 0755))
+
+		err := service.RemoveFiles([]string{"stuck"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "stuck")
+	})
+
+	t.Run("should leave files alone without an upload folder", func(t *testing.T) {
+		// Relative paths would otherwise resolve against the working directory
+		t.Chdir(t.TempDir())
+		require.NoError(t, os.WriteFile("kept.jpg", []byte("kept"), 0644))
+		service := uploads.NewUploadService(nil, uploads.DefaultUploadConfig("", ""))
+
+		assert.NoError(t, service.RemoveFiles([]string{"kept.jpg"}))
+		assert.FileExists(t, "kept.jpg")
+	})
+}
+
 func TestUploadService_GetFileType(t *testing.T) {
 	t.Run("should return correct file type for images", func(t *testing.T) {
 		helpers.WithTransaction(t, func(ctx context.Context, tx pgx.Tx, queries *db.Queries) {
@@ -307,6 +769,29 @@ func TestUploadService_GetFileType(t *testing.T) {
 	})
 }
 
+func TestUploadService_ModernFormats(t *testing.T) {
+	config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+	service := uploads.NewUploadService(nil, config)
+
+	t.Run("should detect modern image formats", func(t *testing.T) {
+		for _, name := range []string{"photo.heic", "photo.heif", "photo.avif", "photo.webp", "IMG_0001.HEIC"} {
+			assert.Equal(t, "image", service.GetFileType(name), name)
+		}
+	})
+
+	t.Run("should detect modern video formats", func(t *testing.T) {
+		for _, name := range []string{"clip.mkv", "clip.webm", "CLIP.WEBM"} {
+			assert.Equal(t, "video", service.GetFileType(name), name)
+		}
+	})
+
+	t.Run("should allow modern formats by default", func(t *testing.T) {
+		for _, name := range []string{"photo.heic", "photo.heif", "photo.avif", "clip.mkv", "clip.webm"} {
+			assert.True(t, service.IsValidFileType(name), name)
+		}
+	})
+}
+
 // Helper function to create a test file header
 func createTestFileHeader(t *testing.T, filename string, content []byte, contentType string) *multipart.FileHeader {
 	body := &bytes.Buffer{}
@@ -330,3 +815,147 @@ func createTestFileHeader(t *testing.T, filename string, content []byte, content
 
 	return fileHeader
 }
+
+func TestUploadService_FileExtension(t *testing.T) {
+	tests := []struct {
+		filename string
+		expected string
+	}{
+		{"photo.jpg", ".jpg"},
+		{"IMAGE.JPG", ".jpg"},
+		{"archive.tar.gz", ".gz"},
+		{"photo.jpg.exe", ".exe"},
+		{"README", ""},
+		{"image.", ""},
+		{".env", ""},
+		{".htaccess", ""},
+		{".config.json", ".json"},
+		{"nested/dir/file.PDF", ".pdf"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.filename, func(t *testing.T) {
+			assert.Equal(t, tc.expected, uploads.FileExtension(tc.filename))
+		})
+	}
+}
+
+func TestSanitizeFilename(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		expected string
+	}{
+		{"plain name", "photo.jpg", "photo.jpg"},
+		{"parent directory", "../../etc/passwd", "passwd"},
+		{"windows path", `C:\Users\me\report.pdf`, "report.pdf"},
+		{"newlines", "evil\r\nSet-Cookie: a=b.txt", "evilSet-Cookie: a=b.txt"},
+		{"null byte", "photo.jpg\x00.exe", "photo.jpg.exe"},
+		{"right-to-left override", "invoice\u202Efdp.exe", "invoicefdp.exe"},
+		{"unicode is kept", "résumé 日本.pdf", "résumé 日本.pdf"},
+		{"decomposed unicode becomes NFC", "re\u0301sume\u0301.pdf", "r\u00e9sum\u00e9.pdf"},
+		{"surrounding whitespace", "  notes.txt  ", "notes.txt"},
+		{"nothing left", "../", "file"},
+		{"dot dot", "..", "file"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, uploads.SanitizeFilename(tc.filename))
+		})
+	}
+
+	t.Run("should cut long names to 255 characters keeping the extension", func(t *testing.T) {
+		sanitized := uploads.SanitizeFilename(strings.Repeat("é", 300) + ".jpeg")
+
+		assert.Equal(t, 255, utf8.RuneCountInString(sanitized))
+		assert.True(t, strings.HasSuffix(sanitized, ".jpeg"))
+	})
+}
+
+func TestContentDisposition(t *testing.T) {
+	t.Run("should send ASCII names as both filename and filename*", func(t *testing.T) {
+		assert.Equal(t, `attachment; filename="report.pdf"; filename*=UTF-8''report.pdf`, uploads.ContentDisposition("report.pdf"))
+	})
+
+	t.Run("should percent-encode unicode in filename* with an ASCII fallback", func(t *testing.T) {
+		assert.Equal(t,
+			`attachment; filename="r_sum_ 1.pdf"; filename*=UTF-8''r%C3%A9sum%C3%A9%201.pdf`,
+			uploads.ContentDisposition("résumé 1.pdf"),
+		)
+	})
+
+	t.Run("should never let a stored name break out of the header", func(t *testing.T) {
+		header := uploads.ContentDisposition("../a\"b\r\nX-Injected: 1.txt")
+
+		assert.NotContains(t, header, "\r")
+		assert.NotContains(t, header, "\n")
+		assert.Equal(t, `attachment; filename="a_bX-Injected: 1.txt"; filename*=UTF-8''a%22bX-Injected%3A%201.txt`, header)
+	})
+}
+
+func TestUploadService_IsValidFileType(t *testing.T) {
+	config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+	service := uploads.NewUploadService(nil, config)
+
+	t.Run("should accept allowed extensions regardless of case", func(t *testing.T) {
+		assert.True(t, service.IsValidFileType("photo.jpg"))
+		assert.True(t, service.IsValidFileType("PHOTO.JPG"))
+		assert.True(t, service.IsValidFileType("Photo.Jpeg"))
+	})
+
+	t.Run("should only consider the last extension", func(t *testing.T) {
+		assert.False(t, service.IsValidFileType("archive.tar.gz"))
+		assert.False(t, service.IsValidFileType("photo.jpg.exe"))
+		assert.True(t, service.IsValidFileType("notes.backup.txt"))
+	})
+
+	t.Run("should reject files without extension by default", func(t *testing.T) {
+		assert.False(t, service.IsValidFileType("README"))
+		assert.False(t, service.IsValidFileType("image."))
+		assert.False(t, service.IsValidFileType(".env"))
+	})
+
+	t.Run("should accept files without extension when configured", func(t *testing.T) {
+		config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+		config.AllowNoExtension = true
+		service := uploads.NewUploadService(nil, config)
+
+		assert.True(t, service.IsValidFileType("README"))
+		assert.True(t, service.IsValidFileType(".env"))
+		assert.False(t, service.IsValidFileType("setup.exe"))
+	})
+
+	t.Run("should match config entries regardless of case or leading dot", func(t *testing.T) {
+		config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+		config.AllowedTypes = []string{"JPG", ".PNG"}
+		service := uploads.NewUploadService(nil, config)
+
+		assert.True(t, service.IsValidFileType("photo.jpg"))
+		assert.True(t, service.IsValidFileType("photo.png"))
+		assert.False(t, service.IsValidFileType("photo.gif"))
+	})
+}
+
+func TestUploadService_GenerateRandomName(t *testing.T) {
+	config := uploads.DefaultUploadConfig(t.TempDir(), "http://localhost:8181/api/files")
+	service := uploads.NewUploadService(nil, config)
+
+	assert.True(t, strings.HasSuffix(service.GenerateRandomName("PHOTO.JPG"), ".jpg"))
+	assert.Equal(t, "", filepath.Ext(service.GenerateRandomName(".env")))
+}
+
+// createCommittedTestUser commits a test user other connections can see, deleting it
+// (and its uploads and folders by cascade) when the test ends
+func createCommittedTestUser(t *testing.T, ctx context.Context, pool *pgxpool.Pool) *db.User {
+	tx, err := pool.Begin(ctx)
+	require.NoError(t, err)
+	user := helpers.CreateTestUser(t, ctx, tx)
+	require.NoError(t, tx.Commit(ctx))
+
+	t.Cleanup(func() {
+		_, err := pool.Exec(context.Background(), "DELETE FROM users WHERE id = $1", user.ID)
+		assert.NoError(t, err)
+	})
+	return user
+}
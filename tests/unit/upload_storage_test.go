@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"app/internal/middleware"
+	"app/internal/uploads"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage_GetSignedURL(t *testing.T) {
+	storage := uploads.NewLocalStorage("/api/v1/files", []byte("signing-secret"))
+
+	t.Run("should sign a link under the base path that expires after the ttl", func(t *testing.T) {
+		before := time.Now()
+		link, err := storage.GetSignedURL("12/photo.jpg", 15*time.Minute)
+		require.NoError(t, err)
+
+		parsed, err := url.Parse(link)
+		require.NoError(t, err)
+		assert.Equal(t, "/api/v1/files/12/photo.jpg", parsed.Path)
+		assert.NotEmpty(t, parsed.Query().Get(middleware.SignedURLSignatureParam))
+
+		expires, err := strconv.ParseInt(parsed.Query().Get(middleware.SignedURLExpiresParam), 10, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, before.Add(15*time.Minute).Unix(), expires, 1)
+	})
+
+	t.Run("should give each file its own signature", func(t *testing.T) {
+		first, err := storage.GetSignedURL("12/a.jpg", time.Minute)
+		require.NoError(t, err)
+		second, err := storage.GetSignedURL("12/b.jpg", time.Minute)
+		require.NoError(t, err)
+
+		signature := func(link string) string {
+			_, query, _ := strings.Cut(link, "?")
+			values, _ := url.ParseQuery(query)
+			return values.Get(middleware.SignedURLSignatureParam)
+		}
+		assert.NotEqual(t, signature(first), signature(second))
+	})
+}
@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"app/internal/errs"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validationParamsRequest struct {
+	Status   string `json:"status" binding:"required,oneof=draft published archived"`
+	Name     string `json:"name" binding:"required,min=3"`
+	Priority int    `json:"priority" binding:"max=5"`
+	Email    string `json:"email" binding:"required,email"`
+}
+
+func postValidationParams(t *testing.T, body string) map[string]json.RawMessage {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/validate", func(c *gin.Context) {
+		var req validationParamsRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			errs.RespondWithValidationError(c, err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/validate", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var response map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestFormatValidationError_Params(t *testing.T) {
+	t.Run("should list the allowed values and bounds of failed rules", func(t *testing.T) {
+		response := postValidationParams(t, `{"status": "deleted", "name": "ab", "priority": 9, "email": "user@example.com"}`)
+
+		var params map[string]map[string]any
+		require.NoError(t, json.Unmarshal(response["params"], &params))
+		assert.Equal(t, []any{"draft", "published", "archived"}, params["status"]["oneof"])
+		assert.Equal(t, float64(3), params["name"]["min"])
+		assert.Equal(t, float64(5), params["priority"]["max"])
+		assert.NotContains(t, params, "email")
+
+		// The keys clients already branch on are unchanged
+		var errors map[string][]string
+		require.NoError(t, json.Unmarshal(response["errors"], &errors))
+		assert.Equal(t, []string{"validation.status.oneof"}, errors["status"])
+		assert.Equal(t, []string{"validation.name.min"}, errors["name"])
+	})
+
+	t.Run("should leave params out when no failed rule has one", func(t *testing.T) {
+		response := postValidationParams(t, `{"status": "draft", "name": "Valid", "email": "not-an-email"}`)
+
+		assert.NotContains(t, response, "params")
+		assert.Contains(t, response, "errors")
+	})
+
+	t.Run("should report the strongpassword minimum length", func(t *testing.T) {
+		type passwordRequest struct {
+			Password string `json:"password" binding:"strongpassword"`
+		}
+		err := binding.Validator.ValidateStruct(passwordRequest{Password: "short"})
+		require.Error(t, err)
+
+		response := errs.FormatValidationError(err)
+		assert.Equal(t, map[string]map[string]any{"password": {"strongpassword": errs.StrongPasswordMinLength}}, response.Params)
+	})
+}
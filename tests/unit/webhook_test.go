@@ -0,0 +1,152 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"app/internal/logger"
+	"app/internal/webhook"
+	"app/tests/helpers"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type webhookDelivery struct {
+	body      []byte
+	signature string
+	event     string
+	requestID string
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	const secret = "webhook-secret"
+	log, _ := helpers.NewBufferLogger()
+
+	// receiver answers each delivery with the next status in statuses, then 200
+	receiver := func(t *testing.T, statuses ...int) (*httptest.Server, chan webhookDelivery) {
+		deliveries := make(chan webhookDelivery, 10)
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			deliveries <- webhookDelivery{body: body, signature: r.Header.Get(webhook.SignatureHeader), event: r.Header.Get(webhook.EventHeader), requestID: r.Header.Get(logger.RequestIDHeader)}
+			if call := int(calls.Add(1)); call <= len(statuses) {
+				w.WriteHeader(statuses[call-1])
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server, deliveries
+	}
+
+	t.Run("should forward the request ID of the triggering request", func(t *testing.T) {
+		server, deliveries := receiver(t)
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret}, log)
+
+		dispatcher.Dispatch(logger.WithRequestID(context.Background(), "req-webhook-1"), "upload.created", nil)
+		dispatcher.Wait()
+
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, "req-webhook-1", (<-deliveries).requestID)
+	})
+
+	t.Run("should POST a signed event in the background", func(t *testing.T) {
+		server, deliveries := receiver(t)
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret}, log)
+
+		dispatcher.Dispatch(context.Background(), "upload.created", map[string]interface{}{"id": 42, "original_filename": "photo.jpg"})
+		dispatcher.Wait()
+
+		require.Len(t, deliveries, 1)
+		delivery := <-deliveries
+		assert.Equal(t, "upload.created", delivery.event)
+		assert.Equal(t, webhook.Sign(secret, delivery.body), delivery.signature)
+		assert.NoError(t, webhook.Verify(secret, delivery.body, delivery.signature))
+		assert.ErrorIs(t, webhook.Verify("other-secret", delivery.body, delivery.signature), webhook.ErrInvalidSignature)
+
+		var event struct {
+			Event      string                 `json:"event"`
+			OccurredAt time.Time              `json:"occurred_at"`
+			Data       map[string]interface{} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(delivery.body, &event))
+		assert.Equal(t, "upload.created", event.Event)
+		assert.WithinDuration(t, time.Now(), event.OccurredAt, time.Minute)
+		assert.Equal(t, float64(42), event.Data["id"])
+		assert.Equal(t, "photo.jpg", event.Data["original_filename"])
+	})
+
+	t.Run("should retry server errors with backoff", func(t *testing.T) {
+		server, deliveries := receiver(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret, InitialBackoff: 10 * time.Millisecond}, log)
+
+		started := time.Now()
+		err := dispatcher.Send(context.Background(), webhook.Event{Event: "upload.created"})
+
+		require.NoError(t, err)
+		assert.Len(t, deliveries, 3)
+		// 10ms after the first failure, 20ms after the second
+		assert.GreaterOrEqual(t, time.Since(started), 30*time.Millisecond)
+	})
+
+	t.Run("should give up after MaxAttempts", func(t *testing.T) {
+		server, deliveries := receiver(t, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret, MaxAttempts: 2, InitialBackoff: time.Millisecond}, log)
+
+		err := dispatcher.Send(context.Background(), webhook.Event{Event: "upload.created"})
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "after 2 attempts")
+		assert.Len(t, deliveries, 2)
+	})
+
+	t.Run("should not retry client errors", func(t *testing.T) {
+		server, deliveries := receiver(t, http.StatusBadRequest)
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret, InitialBackoff: time.Millisecond}, log)
+
+		err := dispatcher.Send(context.Background(), webhook.Event{Event: "upload.created"})
+
+		require.Error(t, err)
+		assert.Len(t, deliveries, 1)
+	})
+
+	t.Run("should stop waiting for deliveries when the context is done", func(t *testing.T) {
+		release := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		t.Cleanup(server.Close)
+		t.Cleanup(func() { close(release) })
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret}, log)
+
+		dispatcher.Dispatch(context.Background(), "upload.created", nil)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.ErrorIs(t, dispatcher.WaitContext(ctx), context.DeadlineExceeded)
+	})
+
+	t.Run("should return once deliveries finish within the context", func(t *testing.T) {
+		server, deliveries := receiver(t)
+		dispatcher := webhook.NewDispatcher(webhook.Config{URL: server.URL, Secret: secret}, log)
+
+		dispatcher.Dispatch(context.Background(), "upload.created", nil)
+
+		require.NoError(t, dispatcher.WaitContext(context.Background()))
+		assert.Len(t, deliveries, 1)
+	})
+
+	t.Run("should do nothing without a URL", func(t *testing.T) {
+		dispatcher := webhook.NewDispatcher(webhook.Config{Secret: secret}, log)
+
+		assert.False(t, dispatcher.Enabled())
+		dispatcher.Dispatch(context.Background(), "upload.created", nil)
+		dispatcher.Wait()
+		assert.NoError(t, dispatcher.Send(context.Background(), webhook.Event{Event: "upload.created"}))
+	})
+}